@@ -3,6 +3,7 @@ package command
 import (
 	"context"
 	"fmt"
+	"time"
 
 	cmpinstall "github.com/posener/complete/cmd/install"
 	"github.com/urfave/cli/v2"
@@ -11,12 +12,24 @@ import (
 	"github.com/peak/s5cmd/log/stat"
 	"github.com/peak/s5cmd/parallel"
 	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/strutil"
 )
 
 const (
 	defaultWorkerCount = 256
 	defaultRetryCount  = 10
 
+	defaultRetryFailedDelay = 30 * time.Second
+
+	// defaults of http.DefaultTransport
+	defaultConnectTimeout      = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultKeepAlive           = 30 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+
+	// maxListKeys is the max number of keys of a listing page of S3.
+	maxListKeys = 1000
+
 	appName = "s5cmd"
 )
 
@@ -25,70 +38,406 @@ var app = &cli.App{
 	Usage: "Blazing fast S3 and local filesystem execution tool",
 	Flags: []cli.Flag{
 		&cli.BoolFlag{
-			Name:  "json",
-			Usage: "enable JSON formatted output",
+			Name:    "json",
+			Usage:   "enable JSON formatted output",
+			EnvVars: []string{"S5CMD_JSON"},
 		},
 		&cli.IntFlag{
-			Name:  "numworkers",
-			Value: defaultWorkerCount,
-			Usage: "number of workers execute operation on each object",
+			Name:    "numworkers",
+			Value:   defaultWorkerCount,
+			Usage:   "number of workers execute operation on each object",
+			EnvVars: []string{"S5CMD_NUMWORKERS"},
 		},
 		&cli.IntFlag{
 			Name:    "retry-count",
 			Aliases: []string{"r"},
 			Value:   defaultRetryCount,
 			Usage:   "number of times that a request will be retried for failures",
+			EnvVars: []string{"S5CMD_RETRY_COUNT"},
+		},
+		&cli.DurationFlag{
+			Name:    "job-timeout",
+			Usage:   "cancel a transfer which takes longer than given duration and retry it, e.g. 10m, 0 means no timeout",
+			EnvVars: []string{"S5CMD_JOB_TIMEOUT"},
+		},
+		&cli.IntFlag{
+			Name:    "retry-failed",
+			Usage:   "run the transfers failed with transient errors, such as throttling, again up to given times once the other transfers are completed",
+			EnvVars: []string{"S5CMD_RETRY_FAILED"},
+		},
+		&cli.DurationFlag{
+			Name:    "retry-failed-delay",
+			Value:   defaultRetryFailedDelay,
+			Usage:   "wait for given duration before retrying the failed transfers, doubled for each of the following retries",
+			EnvVars: []string{"S5CMD_RETRY_FAILED_DELAY"},
+		},
+		&cli.IntFlag{
+			Name:    "max-errors",
+			Usage:   "stop running new jobs once given number of jobs have failed, and exit with an error after the running ones are completed",
+			EnvVars: []string{"S5CMD_MAX_ERRORS"},
+		},
+		&cli.DurationFlag{
+			Name:    "deadline",
+			Usage:   "stop running new jobs once given duration is passed, and exit with an error after the running ones are completed, e.g. 2h",
+			EnvVars: []string{"S5CMD_DEADLINE"},
+		},
+		&cli.BoolFlag{
+			Name:    "exit-on-error",
+			Usage:   "cancel all of the jobs and exit at the first failure, except for the skipped objects",
+			EnvVars: []string{"S5CMD_EXIT_ON_ERROR"},
 		},
 		&cli.StringFlag{
-			Name:  "endpoint-url",
-			Usage: "override default S3 host for custom services",
+			Name:    "endpoint-url",
+			Usage:   "override default S3 host for custom services",
+			EnvVars: []string{"S5CMD_ENDPOINT_URL"},
 		},
 		&cli.BoolFlag{
-			Name:  "no-verify-ssl",
-			Usage: "disable SSL certificate verification",
+			Name:    "no-verify-ssl",
+			Usage:   "disable SSL certificate verification",
+			EnvVars: []string{"S5CMD_NO_VERIFY_SSL"},
+		},
+		&cli.StringFlag{
+			Name:    "ca-bundle",
+			Usage:   "path to a PEM encoded certificate bundle to verify SSL certificates of the endpoint",
+			EnvVars: []string{"S5CMD_CA_BUNDLE"},
+		},
+		&cli.StringFlag{
+			Name:    "http-proxy",
+			Usage:   "proxy address for HTTP requests, overrides HTTP_PROXY environment variable",
+			EnvVars: []string{"S5CMD_HTTP_PROXY"},
+		},
+		&cli.StringFlag{
+			Name:    "https-proxy",
+			Usage:   "proxy address for HTTPS requests, overrides HTTPS_PROXY environment variable",
+			EnvVars: []string{"S5CMD_HTTPS_PROXY"},
+		},
+		&cli.StringFlag{
+			Name:    "no-proxy",
+			Usage:   "comma separated list of hosts to be accessed without a proxy, overrides NO_PROXY environment variable",
+			EnvVars: []string{"S5CMD_NO_PROXY"},
+		},
+		&cli.IntFlag{
+			Name:    "max-idle-conns-per-host",
+			Usage:   "maximum number of idle connections kept open per host (default: number of workers)",
+			EnvVars: []string{"S5CMD_MAX_IDLE_CONNS_PER_HOST"},
+		},
+		&cli.IntFlag{
+			Name:    "list-shards",
+			Usage:   "number of concurrent paginations of a wildcard listing, sharded by the directories under its prefix (default: disabled)",
+			EnvVars: []string{"S5CMD_LIST_SHARDS"},
+		},
+		&cli.Int64Flag{
+			Name:    "max-keys",
+			Usage:   "number of keys listed with a single listing request, up to 1000 (default: 1000)",
+			EnvVars: []string{"S5CMD_MAX_KEYS"},
 		},
 		&cli.StringFlag{
-			Name:  "log",
-			Value: "info",
-			Usage: "log level: (debug, info, error)",
+			Name:    "start-after",
+			Usage:   "list the keys after the given key, e.g. to resume an interrupted listing",
+			EnvVars: []string{"S5CMD_START_AFTER"},
 		},
 		&cli.BoolFlag{
-			Name:  "install-completion",
-			Usage: "install completion for your shell",
+			Name:    "cache-listings",
+			Usage:   "keep the listings in memory during the run, so that the commands using the same wildcard list it once",
+			EnvVars: []string{"S5CMD_CACHE_LISTINGS"},
+		},
+		&cli.DurationFlag{
+			Name:    "connect-timeout",
+			Value:   defaultConnectTimeout,
+			Usage:   "maximum amount of time to wait for a connection to be established",
+			EnvVars: []string{"S5CMD_CONNECT_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "read-timeout",
+			Usage:   "maximum amount of time to wait for the response headers after a request is sent, 0 means no timeout",
+			EnvVars: []string{"S5CMD_READ_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "tls-handshake-timeout",
+			Value:   defaultTLSHandshakeTimeout,
+			Usage:   "maximum amount of time to wait for a TLS handshake",
+			EnvVars: []string{"S5CMD_TLS_HANDSHAKE_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "keep-alive",
+			Value:   defaultKeepAlive,
+			Usage:   "interval between TCP keep-alive probes, a negative value disables keep-alive probes",
+			EnvVars: []string{"S5CMD_KEEP_ALIVE"},
+		},
+		&cli.DurationFlag{
+			Name:    "idle-conn-timeout",
+			Value:   defaultIdleConnTimeout,
+			Usage:   "maximum amount of time an idle connection is kept open before it is closed",
+			EnvVars: []string{"S5CMD_IDLE_CONN_TIMEOUT"},
+		},
+		&cli.DurationFlag{
+			Name:    "dns-cache-ttl",
+			Usage:   "cache resolved addresses of endpoints for the given duration, 0 disables caching",
+			EnvVars: []string{"S5CMD_DNS_CACHE_TTL"},
+		},
+		&cli.StringFlag{
+			Name:    "log",
+			Value:   "info",
+			Usage:   "log level: (debug, info, error)",
+			EnvVars: []string{"S5CMD_LOG"},
+		},
+		&cli.StringFlag{
+			Name:    "log-sink",
+			Value:   log.SinkStdout,
+			Usage:   "destination of the logs: (stdout, syslog, journald)",
+			EnvVars: []string{"S5CMD_LOG_SINK"},
+		},
+		&cli.StringFlag{
+			Name:    "log-file",
+			Usage:   "write the logs to given file instead of the standard output",
+			EnvVars: []string{"S5CMD_LOG_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "log-max-size",
+			Usage:   "rotate the log file once it reaches given size, e.g. 100M",
+			EnvVars: []string{"S5CMD_LOG_MAX_SIZE"},
+		},
+		&cli.DurationFlag{
+			Name:    "log-rotate-interval",
+			Usage:   "rotate the log file once it is older than given duration, e.g. 24h",
+			EnvVars: []string{"S5CMD_LOG_ROTATE_INTERVAL"},
+		},
+		&cli.IntFlag{
+			Name:    "log-max-backups",
+			Usage:   "number of the rotated log files to keep, 0 keeps all of them",
+			EnvVars: []string{"S5CMD_LOG_MAX_BACKUPS"},
+		},
+		&cli.StringSliceFlag{
+			Name:    "aws-debug",
+			Usage:   "print the requests of the AWS SDK as debug logs: (http, signing, retries), implies --log debug",
+			EnvVars: []string{"S5CMD_AWS_DEBUG"},
+		},
+		&cli.BoolFlag{
+			Name:    "install-completion",
+			Usage:   "install completion for your shell",
+			EnvVars: []string{"S5CMD_INSTALL_COMPLETION"},
 		},
 		&cli.BoolFlag{
-			Name:  "dry-run",
-			Usage: "fake run; show what commands will be executed without actually executing them",
+			Name:    "dry-run",
+			Usage:   "fake run; show what commands will be executed without actually executing them",
+			EnvVars: []string{"S5CMD_DRY_RUN"},
 		},
 		&cli.BoolFlag{
-			Name:  "stat",
-			Usage: "collect statistics of program execution and display it at the end",
+			Name:    "assume-yes",
+			Aliases: []string{"y"},
+			Usage:   "do not ask for a confirmation of the destructive operations, as if they are confirmed",
+			EnvVars: []string{"S5CMD_ASSUME_YES"},
 		},
 		&cli.BoolFlag{
-			Name:  "no-sign-request",
-			Usage: "do not sign requests: credentials will not be loaded if --no-sign-request is provided",
+			Name:    "no-color",
+			Usage:   "disable colored output, which is enabled if the output is a terminal",
+			EnvVars: []string{"S5CMD_NO_COLOR"},
+		},
+		&cli.BoolFlag{
+			Name:    "only-show-errors",
+			Usage:   "only print the errors and the summaries, not the result of each job",
+			EnvVars: []string{"S5CMD_ONLY_SHOW_ERRORS"},
+		},
+		&cli.BoolFlag{
+			Name:    "stat",
+			Usage:   "collect statistics of program execution and display it at the end",
+			EnvVars: []string{"S5CMD_STAT"},
+		},
+		&cli.StringFlag{
+			Name:    "stat-json",
+			Usage:   "write statistics of the run with counts, bytes, durations and failed jobs to given JSON file at the end",
+			EnvVars: []string{"S5CMD_STAT_JSON"},
+		},
+		&cli.StringFlag{
+			Name:    "notify-url",
+			Usage:   "post a JSON summary of the run with counts, bytes, errors and duration to given url when the run completes",
+			EnvVars: []string{"S5CMD_NOTIFY_URL"},
+		},
+		&cli.DurationFlag{
+			Name:    "progress",
+			Usage:   "print completed and remaining jobs, transferred bytes and throughput to standard error with given interval, e.g. 10s",
+			EnvVars: []string{"S5CMD_PROGRESS"},
+		},
+		&cli.DurationFlag{
+			Name:    "autoscale",
+			Usage:   "shrink the workers while the throughput stays the same, checking with given interval, e.g. 10s",
+			EnvVars: []string{"S5CMD_AUTOSCALE"},
+		},
+		&cli.StringFlag{
+			Name:    "metrics-addr",
+			Usage:   "serve statistics of the run in Prometheus format at /metrics on given address, e.g. :9090",
+			EnvVars: []string{"S5CMD_METRICS_ADDR"},
+		},
+		&cli.StringFlag{
+			Name:    "pprof-addr",
+			Usage:   "serve runtime profiling data at /debug/pprof/ on given address, e.g. localhost:6060",
+			EnvVars: []string{"S5CMD_PPROF_ADDR"},
+		},
+		&cli.StringFlag{
+			Name:    "cpu-profile",
+			Usage:   "write CPU profile of the run to given file",
+			EnvVars: []string{"S5CMD_CPU_PROFILE"},
+		},
+		&cli.StringFlag{
+			Name:    "mem-profile",
+			Usage:   "write heap profile to given file at the end of the run",
+			EnvVars: []string{"S5CMD_MEM_PROFILE"},
+		},
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "read default options from given YAML file instead of ~/.config/s5cmd/config.yaml",
+			EnvVars: []string{"S5CMD_CONFIG"},
+		},
+		&cli.StringFlag{
+			Name:    "config-profile",
+			Usage:   "use the options of given profile of the configuration file",
+			EnvVars: []string{"S5CMD_CONFIG_PROFILE"},
+		},
+		&cli.StringFlag{
+			Name:    "file",
+			Aliases: []string{"f"},
+			Usage:   "run the commands of given file, or of standard input if it is \"-\", as run command does",
+			EnvVars: []string{"S5CMD_FILE"},
+		},
+		&cli.StringFlag{
+			Name:    "failed-jobs-file",
+			Usage:   "record each failed job as a command line to given file, to run the failed jobs again with -f flag",
+			EnvVars: []string{"S5CMD_FAILED_JOBS_FILE"},
+		},
+		&cli.BoolFlag{
+			Name:    "no-sign-request",
+			Usage:   "do not sign requests: credentials will not be loaded if --no-sign-request is provided",
+			EnvVars: []string{"S5CMD_NO_SIGN_REQUEST"},
 		},
 	},
 	Before: func(c *cli.Context) error {
+		runStart = time.Now()
+
 		retryCount := c.Int("retry-count")
 		workerCount := c.Int("numworkers")
+		maxIdleConnsPerHost := c.Int("max-idle-conns-per-host")
 		printJSON := c.Bool("json")
 		logLevel := c.String("log")
+		if len(c.StringSlice("aws-debug")) > 0 {
+			logLevel = "debug"
+		}
 		isStat := c.Bool("stat")
 
 		log.Init(logLevel, printJSON)
+		log.SetQuiet(c.Bool("only-show-errors"))
 		parallel.Init(workerCount)
 
+		if err := setLogSink(c); err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+		log.SetColor(!c.Bool("no-color"))
+
+		// configuration errors are not specific to the command.
+		if configErr != nil {
+			printError("", "", configErr)
+			return configErr
+		}
+
 		if retryCount < 0 {
 			err := fmt.Errorf("retry count cannot be a negative value")
 			printError(givenCommand(c), c.Command.Name, err)
 			return err
 		}
 
-		if isStat {
+		if c.Int("max-errors") < 0 {
+			err := fmt.Errorf("max errors cannot be a negative value")
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+		setMaxErrors(c.Int("max-errors"))
+
+		if c.Int("retry-failed") < 0 {
+			err := fmt.Errorf("retry failed cannot be a negative value")
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+		setExitOnError(c.Bool("exit-on-error"))
+
+		if maxIdleConnsPerHost < 0 {
+			err := fmt.Errorf("max idle connections per host cannot be a negative value")
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+
+		if c.Int("list-shards") < 0 {
+			err := fmt.Errorf("list shards cannot be a negative value")
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+
+		if n := c.Int64("max-keys"); c.IsSet("max-keys") && (n < 1 || n > maxListKeys) {
+			err := fmt.Errorf("max keys must be between 1 and %v", maxListKeys)
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+
+		if _, err := storage.AWSLogLevel(c.StringSlice("aws-debug")); err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+
+		for _, name := range []string{"connect-timeout", "read-timeout", "tls-handshake-timeout", "idle-conn-timeout", "dns-cache-ttl", "log-rotate-interval", "progress", "autoscale", "job-timeout", "retry-failed-delay", "deadline"} {
+			if c.Duration(name) < 0 {
+				err := fmt.Errorf("%v cannot be a negative value", name)
+				printError(givenCommand(c), c.Command.Name, err)
+				return err
+			}
+		}
+
+		// statistics are also collected for the summary and the metrics of
+		// the run.
+		if isStat || c.String("stat-json") != "" || c.String("notify-url") != "" || c.String("metrics-addr") != "" || c.Duration("progress") > 0 || c.Duration("autoscale") > 0 {
 			stat.InitStat()
 		}
 
+		if addr := c.String("metrics-addr"); addr != "" {
+			if err := startMetricsServer(addr); err != nil {
+				printError(givenCommand(c), c.Command.Name, err)
+				return err
+			}
+		}
+
+		if addr := c.String("pprof-addr"); addr != "" {
+			if err := startPprofServer(addr); err != nil {
+				printError(givenCommand(c), c.Command.Name, err)
+				return err
+			}
+		}
+
+		if path := c.String("cpu-profile"); path != "" {
+			if err := startCPUProfile(path); err != nil {
+				printError(givenCommand(c), c.Command.Name, err)
+				return err
+			}
+		}
+
+		if path := c.String("failed-jobs-file"); path != "" {
+			if err := startFailedJobsFile(path); err != nil {
+				printError(givenCommand(c), c.Command.Name, err)
+				return err
+			}
+		}
+
+		if interval := c.Duration("progress"); interval > 0 {
+			startProgress(interval)
+		}
+
+		if interval := c.Duration("autoscale"); interval > 0 {
+			startAutoscale(interval)
+		}
+
+		startResizer()
+
+		if d := c.Duration("deadline"); d > 0 {
+			startDeadline(d)
+		}
+
 		return nil
 	},
 	CommandNotFound: func(c *cli.Context, command string) {
@@ -99,7 +448,15 @@ var app = &cli.App{
 		log.Error(msg)
 
 		// After callback is not called if app exists with cli.Exit.
+		_ = stopCPUProfile()
+		stopMetricsServer()
+		stopPprofServer()
+		stopProgress()
+		stopAutoscale()
+		stopResizer()
 		parallel.Close()
+		stopDeadline()
+		_ = stopFailedJobsFile()
 		log.Close()
 	},
 	Action: func(c *cli.Context) error {
@@ -112,32 +469,139 @@ var app = &cli.App{
 		}
 
 		args := c.Args()
+		if file := c.String("file"); file != "" {
+			return runFile(c, file)
+		}
+
 		if args.Present() {
 			cli.ShowCommandHelp(c, args.First())
-			return cli.Exit("", 1)
+			return cli.Exit("", ExitCommandNotFound)
 		}
 
 		return cli.ShowAppHelp(c)
 	},
 	After: func(c *cli.Context) error {
+		if c.Bool("dry-run") {
+			if summary := dryRunSummary(); len(summary) > 0 {
+				log.Summary(summary)
+			}
+		}
+
 		if c.Bool("stat") {
-			log.Info(stat.Statistics())
+			log.Summary(stat.Statistics())
+			if latencies := stat.LatencyStatistics(); len(latencies) > 0 {
+				log.Summary(latencies)
+			}
 		}
 
+		if err := stopCPUProfile(); err != nil {
+			printError(givenCommand(c), "profile", err)
+		}
+
+		if path := c.String("mem-profile"); path != "" {
+			if err := writeMemProfile(path); err != nil {
+				printError(givenCommand(c), "profile", err)
+			}
+		}
+
+		summary := newRunSummary(givenCommand(c), time.Since(runStart))
+
+		if path := c.String("stat-json"); path != "" {
+			if err := writeRunSummary(path, summary); err != nil {
+				printError(givenCommand(c), "stat", err)
+			}
+		}
+
+		if url := c.String("notify-url"); url != "" {
+			// the run may be canceled, the summary is sent regardless.
+			if err := notify(context.Background(), url, summary); err != nil {
+				printError(givenCommand(c), "notify", err)
+			}
+		}
+
+		stopMetricsServer()
+		stopPprofServer()
+		stopProgress()
+		stopAutoscale()
+		stopResizer()
 		parallel.Close()
+		stopDeadline()
+		if err := stopFailedJobsFile(); err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
 		log.Close()
 		return nil
 	},
 }
 
+// setLogSink sets the destination of the logs of the app. Log files are
+// rotated with the policy of the flags.
+func setLogSink(c *cli.Context) error {
+	path := c.String("log-file")
+	if path == "" {
+		return log.SetSink(c.String("log-sink"))
+	}
+
+	if sink := c.String("log-sink"); sink != log.SinkStdout {
+		return fmt.Errorf("--log-file flag can not be used with %q log sink", sink)
+	}
+
+	var maxSize int64
+	if s := c.String("log-max-size"); s != "" {
+		size, err := strutil.ParseBytes(s)
+		if err != nil {
+			return fmt.Errorf("invalid log max size %q: %w", s, err)
+		}
+		maxSize = size
+	}
+	if maxSize < 0 || c.Int("log-max-backups") < 0 {
+		return fmt.Errorf("log rotation limits cannot be negative values")
+	}
+
+	return log.SetFileSink(path, log.Rotation{
+		MaxSize:    maxSize,
+		Interval:   c.Duration("log-rotate-interval"),
+		MaxBackups: c.Int("log-max-backups"),
+	})
+}
+
 // NewStorageOpts creates storage.Options object from the given context.
 func NewStorageOpts(c *cli.Context) storage.Options {
+	// keep an idle connection for each worker by default, so that workers
+	// don't open a new connection for each request.
+	maxIdleConnsPerHost := c.Int("max-idle-conns-per-host")
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = parallel.WorkerCount()
+	}
+
+	// the debug modes are validated before the command is run.
+	awsLogLevel, _ := storage.AWSLogLevel(c.StringSlice("aws-debug"))
+
 	return storage.Options{
 		MaxRetries:    c.Int("retry-count"),
 		Endpoint:      c.String("endpoint-url"),
 		NoVerifySSL:   c.Bool("no-verify-ssl"),
+		CABundle:      c.String("ca-bundle"),
+		HTTPProxy:     c.String("http-proxy"),
+		HTTPSProxy:    c.String("https-proxy"),
+		NoProxy:       c.String("no-proxy"),
 		DryRun:        c.Bool("dry-run"),
 		NoSignRequest: c.Bool("no-sign-request"),
+
+		MaxIdleConnsPerHost: maxIdleConnsPerHost,
+		ConnectTimeout:      c.Duration("connect-timeout"),
+		ReadTimeout:         c.Duration("read-timeout"),
+		TLSHandshakeTimeout: c.Duration("tls-handshake-timeout"),
+		KeepAlive:           c.Duration("keep-alive"),
+		IdleConnTimeout:     c.Duration("idle-conn-timeout"),
+		DNSCacheTTL:         c.Duration("dns-cache-ttl"),
+
+		ListShards:     c.Int("list-shards"),
+		ListMaxKeys:    c.Int64("max-keys"),
+		ListStartAfter: c.String("start-after"),
+		CacheListings:  c.Bool("cache-listings"),
+
+		AWSLogLevel: awsLogLevel,
 	}
 }
 
@@ -145,15 +609,24 @@ func NewStorageOpts(c *cli.Context) storage.Options {
 func Main(ctx context.Context, args []string) error {
 	app.Commands = []*cli.Command{
 		listCommand,
+		findCommand,
+		treeCommand,
+		statCommand,
 		copyCommand,
 		deleteCommand,
 		moveCommand,
+		composeCommand,
+		watchCommand,
 		makeBucketCommand,
 		removeBucketCommand,
 		selectCommand,
 		sizeCommand,
+		seedCommand,
+		benchCommand,
 		catCommand,
+		tailCommand,
 		runCommand,
+		completionCommand,
 		versionCommand,
 	}
 
@@ -161,5 +634,13 @@ func Main(ctx context.Context, args []string) error {
 		return nil
 	}
 
-	return app.RunContext(ctx, args)
+	markJobErrors(app.Commands)
+
+	configErr = loadConfig(args)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	cancelRun = cancel
+
+	return exitError(ctx, app.RunContext(runCtx, args))
 }
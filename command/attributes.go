@@ -0,0 +1,164 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+// parseKeyValues parses the values of the flag given in key=value format.
+func parseKeyValues(flag string, values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	parsed := map[string]string{}
+	for _, value := range values {
+		i := strings.Index(value, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid --%v %q, expected key=value", flag, value)
+		}
+		parsed[value[:i]] = value[i+1:]
+	}
+	return parsed, nil
+}
+
+// validateAttributeFlags validates the flags setting the attributes of the
+// destination objects.
+func validateAttributeFlags(c *cli.Context, srcurl, dsturl *url.URL) error {
+	for _, flag := range []string{"metadata", "tag"} {
+		if _, err := parseKeyValues(flag, c.StringSlice(flag)); err != nil {
+			return err
+		}
+	}
+
+	switch directive := c.String("metadata-directive"); directive {
+	case "":
+	case storage.MetadataDirectiveCopy, storage.MetadataDirectiveReplace:
+		if !srcurl.IsRemote() || !dsturl.IsRemote() {
+			return fmt.Errorf("--metadata-directive flag can only be used for copies between S3 objects")
+		}
+		if directive == storage.MetadataDirectiveCopy && (c.String("content-type") != "" ||
+			c.String("cache-control") != "" || len(c.StringSlice("metadata")) > 0) {
+			return fmt.Errorf("--metadata-directive COPY can not be used with --content-type, --cache-control or --metadata flags")
+		}
+	default:
+		return fmt.Errorf("unknown metadata directive %q, expected one of %v or %v",
+			directive, storage.MetadataDirectiveCopy, storage.MetadataDirectiveReplace)
+	}
+
+	if c.Bool("copy-acl") {
+		if !srcurl.IsRemote() || !dsturl.IsRemote() {
+			return fmt.Errorf("--copy-acl flag can only be used for copies between S3 objects")
+		}
+		if c.String("acl") != "" {
+			return fmt.Errorf("--acl and --copy-acl flags can not be used together")
+		}
+	}
+	return nil
+}
+
+// setAttributes sets the attributes given by the flags to the metadata of an
+// upload.
+func (c Copy) setAttributes(metadata storage.Metadata) {
+	if c.contentType != "" {
+		metadata.SetContentType(c.contentType)
+	}
+	if c.cacheControl != "" {
+		metadata.SetCacheControl(c.cacheControl)
+	}
+	for key, value := range c.userMetadata {
+		metadata.SetUserDefined(key, value)
+	}
+	for key, value := range c.tags {
+		metadata.SetTag(key, value)
+	}
+}
+
+// copyAttributes sets the attributes of the destination of an S3 copy to the
+// metadata. A server side copy keeps the metadata and the tags of the source
+// unless they are replaced by the flags or the REPLACE directive. The content
+// type, the cache control and the user metadata can only be replaced
+// together, so the ones which are not given are retrieved from the source. A
+// streamed copy creates a new object, so all of the attributes which are not
+// given are retrieved from the source.
+func (c Copy) copyAttributes(ctx context.Context, srcurl *url.URL, metadata storage.Metadata, streamed bool) error {
+	replaceMetadata := c.contentType != "" || c.cacheControl != "" || len(c.userMetadata) > 0 ||
+		c.metadataDirective == storage.MetadataDirectiveReplace
+	if c.storageOpts.DryRun || (!streamed && !replaceMetadata && len(c.tags) == 0) {
+		return nil
+	}
+
+	srcClient, err := storage.NewRemoteClient(ctx, srcurl, c.srcStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	if streamed || replaceMetadata {
+		srcObj, err := srcClient.Stat(ctx, srcurl)
+		if err != nil {
+			return err
+		}
+
+		contentType := c.contentType
+		if contentType == "" {
+			contentType = srcObj.ContentType
+		}
+		metadata.SetContentType(contentType)
+
+		cacheControl := c.cacheControl
+		if cacheControl == "" {
+			cacheControl = srcObj.CacheControl
+		}
+		metadata.SetCacheControl(cacheControl)
+
+		userMetadata := c.userMetadata
+		if len(userMetadata) == 0 {
+			userMetadata = srcObj.UserMetadata
+		}
+		for key, value := range userMetadata {
+			metadata.SetUserDefined(key, value)
+		}
+
+		if !streamed {
+			metadata.SetMetadataDirective(storage.MetadataDirectiveReplace)
+		}
+	}
+
+	tags := c.tags
+	if streamed && len(tags) == 0 {
+		tags, err = srcClient.Tags(ctx, srcurl)
+		if err != nil {
+			return err
+		}
+	}
+	for key, value := range tags {
+		metadata.SetTag(key, value)
+	}
+	return nil
+}
+
+// doCopyACL copies the access control list of the source object to the
+// destination object, which is private after the copy.
+func (c Copy) doCopyACL(ctx context.Context, srcurl, dsturl *url.URL) error {
+	srcClient, err := storage.NewRemoteClient(ctx, srcurl, c.srcStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	acl, err := srcClient.ACL(ctx, srcurl)
+	if err != nil {
+		return err
+	}
+
+	dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+	if err != nil {
+		return err
+	}
+	return dstClient.SetACL(ctx, dsturl, acl)
+}
@@ -1,14 +1,37 @@
 package command
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/posener/complete"
 	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+const (
+	// listings of remote completions are cached for a short time, so the
+	// keystrokes within the same prefix are completed without a request.
+	completionCacheTTL = 30 * time.Second
+
+	// completionTimeout is the maximum time spent for a remote listing.
+	completionTimeout = 3 * time.Second
 )
 
 func adaptCommand(cmd *cli.Command) complete.Command {
 	return complete.Command{
 		Flags: adaptFlags(cmd.Flags),
-		// TODO(ig): add args predictors
+		Args:  complete.PredictOr(complete.PredictFiles("*"), complete.PredictFunc(predictRemote)),
 	}
 }
 
@@ -40,3 +63,158 @@ func maybeAutoComplete() bool {
 	}
 	return complete.New(appName, completionCmd).Complete()
 }
+
+// predictRemote completes the buckets and the keys of a remote url. The keys
+// are listed with a delimiter up to the last separator of the url, so the
+// listing of a prefix is shared by all the keystrokes within it.
+func predictRemote(args complete.Args) []string {
+	if !strings.HasPrefix(args.Last, "s3://") {
+		return nil
+	}
+
+	prefix := completionPrefix(args.Last)
+	opts := completionStorageOpts(strings.Fields(os.Getenv("COMP_LINE")))
+
+	cacheKey := opts.Endpoint + " " + prefix
+	if predictions, ok := readCompletionCache(cacheKey); ok {
+		return predictions
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	predictions, err := listRemoteCompletions(ctx, prefix, opts)
+	if err != nil {
+		return nil
+	}
+	writeCompletionCache(cacheKey, predictions)
+	return predictions
+}
+
+// completionPrefix returns the part of the url which is listed for the
+// completion. It is "s3://" if the bucket name is not completed yet.
+func completionPrefix(s string) string {
+	rest := strings.TrimPrefix(s, "s3://")
+	i := strings.LastIndex(rest, "/")
+	if i < 0 {
+		return "s3://"
+	}
+	return "s3://" + rest[:i+1]
+}
+
+// completionStorageOpts returns the storage options given in the command
+// line which is being completed, or in the environment.
+func completionStorageOpts(args []string) storage.Options {
+	opts := storage.Options{
+		Endpoint: os.Getenv("S5CMD_ENDPOINT_URL"),
+	}
+	for i, arg := range args {
+		switch {
+		case arg == "--endpoint-url" && i+1 < len(args):
+			opts.Endpoint = args[i+1]
+		case strings.HasPrefix(arg, "--endpoint-url="):
+			opts.Endpoint = strings.TrimPrefix(arg, "--endpoint-url=")
+		case arg == "--no-verify-ssl":
+			opts.NoVerifySSL = true
+		case arg == "--no-sign-request":
+			opts.NoSignRequest = true
+		}
+	}
+	return opts
+}
+
+func listRemoteCompletions(ctx context.Context, prefix string, opts storage.Options) ([]string, error) {
+	if prefix == "s3://" {
+		client, err := storage.NewRemoteClient(ctx, &url.URL{}, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		buckets, err := client.ListBuckets(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var predictions []string
+		for _, bucket := range buckets {
+			predictions = append(predictions, "s3://"+bucket.Name+"/")
+		}
+		return predictions, nil
+	}
+
+	srcurl, err := url.New(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := storage.NewRemoteClient(ctx, srcurl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var predictions []string
+	for object := range client.List(ctx, srcurl, false) {
+		if object.Err != nil {
+			if object.Err == storage.ErrNoObjectFound {
+				continue
+			}
+			return nil, object.Err
+		}
+		predictions = append(predictions, object.URL.String())
+	}
+	return predictions, nil
+}
+
+// completionCachePath returns the path of the cache file of given key.
+func completionCachePath(key string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName, "completion", fmt.Sprintf("%x", sha1.Sum([]byte(key)))), nil
+}
+
+// readCompletionCache returns the cached predictions of given key, if they
+// are cached within completionCacheTTL.
+func readCompletionCache(key string) ([]string, bool) {
+	path, err := completionCachePath(key)
+	if err != nil {
+		return nil, false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > completionCacheTTL {
+		return nil, false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var predictions []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		predictions = append(predictions, scanner.Text())
+	}
+	return predictions, scanner.Err() == nil
+}
+
+// writeCompletionCache caches the predictions of given key. Errors are
+// ignored since the cache is only an optimization.
+func writeCompletionCache(key string, predictions []string) {
+	path, err := completionCachePath(key)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	for _, prediction := range predictions {
+		buf.WriteString(prediction + "\n")
+	}
+	_ = ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"testing"
+)
+
+func TestCompletionPrefix(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "empty_bucket", input: "s3://", expected: "s3://"},
+		{name: "partial_bucket", input: "s3://buck", expected: "s3://"},
+		{name: "bucket", input: "s3://bucket/", expected: "s3://bucket/"},
+		{name: "partial_key", input: "s3://bucket/ke", expected: "s3://bucket/"},
+		{name: "nested_partial_key", input: "s3://bucket/a/b/ke", expected: "s3://bucket/a/b/"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := completionPrefix(tc.input); got != tc.expected {
+				t.Errorf("completionPrefix(%q) = %q, expected %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCompletionStorageOpts(t *testing.T) {
+	t.Parallel()
+
+	opts := completionStorageOpts([]string{"s5cmd", "--endpoint-url", "http://localhost:9000", "--no-sign-request", "ls", "s3://bucket/"})
+	if opts.Endpoint != "http://localhost:9000" || !opts.NoSignRequest || opts.NoVerifySSL {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+
+	opts = completionStorageOpts([]string{"s5cmd", "--endpoint-url=http://localhost:9000", "--no-verify-ssl", "ls"})
+	if opts.Endpoint != "http://localhost:9000" || !opts.NoVerifySSL {
+		t.Errorf("unexpected options: %+v", opts)
+	}
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"time"
+
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+)
+
+const (
+	// throughput within this ratio of the best one is considered the same,
+	// so fewer workers can saturate the link equally.
+	autoscaleTolerance = 0.95
+
+	// the workers are shrunk by a quarter at each step.
+	autoscaleShrinkRatio = 0.75
+)
+
+// autoscale shrinks the workers periodically while the throughput stays the
+// same, if --autoscale flag is given. If a shrink decreases the throughput,
+// it is reverted and the workers are not shrunk anymore.
+var autoscale struct {
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// autoscaler is the state of the throughput based shrinking of the workers.
+type autoscaler struct {
+	best       int64
+	lastBytes  int64
+	shrunkFrom int
+	frozen     bool
+}
+
+// next returns the number of workers for the throughput of the last interval,
+// or 0 if the workers should be kept as is.
+func (a *autoscaler) next(throughput int64, workers, busy int) int {
+	// the link can not be saturated if the workers are not busy.
+	if busy < workers || a.frozen {
+		a.shrunkFrom = 0
+		return 0
+	}
+
+	tolerated := int64(float64(a.best) * autoscaleTolerance)
+
+	if a.shrunkFrom > 0 && throughput < tolerated {
+		from := a.shrunkFrom
+		a.shrunkFrom = 0
+		a.frozen = true
+		return from
+	}
+
+	if throughput > a.best {
+		a.best = throughput
+		tolerated = int64(float64(a.best) * autoscaleTolerance)
+	}
+
+	if throughput == 0 || throughput < tolerated {
+		a.shrunkFrom = 0
+		return 0
+	}
+
+	shrunk := int(float64(workers) * autoscaleShrinkRatio)
+	if shrunk < 1 || shrunk == workers {
+		return 0
+	}
+	a.shrunkFrom = workers
+	return shrunk
+}
+
+// startAutoscale starts checking the throughput with given interval.
+func startAutoscale(interval time.Duration) {
+	autoscale.stopCh = make(chan struct{})
+	autoscale.doneCh = make(chan struct{})
+
+	go func() {
+		defer close(autoscale.doneCh)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var a autoscaler
+		last := time.Now()
+		for {
+			select {
+			case <-autoscale.stopCh:
+				return
+			case now := <-ticker.C:
+				bytes := stat.TransferredBytes()
+
+				var throughput int64
+				if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+					throughput = int64(float64(bytes-a.lastBytes) / elapsed)
+				}
+				last, a.lastBytes = now, bytes
+
+				workers := parallel.WorkerCount()
+				if n := a.next(throughput, workers, parallel.BusyWorkerCount()); n > 0 && n != workers {
+					resizeWorkers(n, "autoscale")
+				}
+			}
+		}
+	}()
+}
+
+// stopAutoscale stops checking the throughput. It must be called before the
+// logger is closed.
+func stopAutoscale() {
+	if autoscale.stopCh == nil {
+		return
+	}
+	close(autoscale.stopCh)
+	<-autoscale.doneCh
+}
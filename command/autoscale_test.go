@@ -0,0 +1,29 @@
+package command
+
+import "testing"
+
+func TestAutoscalerNext(t *testing.T) {
+	t.Parallel()
+
+	var a autoscaler
+
+	steps := []struct {
+		name       string
+		throughput int64
+		workers    int
+		busy       int
+		expected   int
+	}{
+		{name: "idle_workers", throughput: 100, workers: 64, busy: 10, expected: 0},
+		{name: "saturated", throughput: 100, workers: 64, busy: 64, expected: 48},
+		{name: "same_throughput", throughput: 98, workers: 48, busy: 48, expected: 36},
+		{name: "decreased_throughput_reverts", throughput: 50, workers: 36, busy: 36, expected: 48},
+		{name: "frozen", throughput: 100, workers: 48, busy: 48, expected: 0},
+	}
+
+	for _, step := range steps {
+		if got := a.next(step.throughput, step.workers, step.busy); got != step.expected {
+			t.Fatalf("%v: next() = %v, expected %v", step.name, got, step.expected)
+		}
+	}
+}
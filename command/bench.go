@@ -0,0 +1,380 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
+)
+
+var benchHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] destination
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Benchmark uploading and downloading 100 objects of 1MiB each
+		 > s5cmd {{.HelpName}} s3://bucket/prefix/
+
+	2. Benchmark 64MiB objects with 32 workers and 16MiB parts
+		 > s5cmd {{.HelpName}} --size 64M --count 100 --workers 32 --part-size 16 s3://bucket/prefix/
+
+	3. Benchmark and keep the uploaded objects
+		 > s5cmd {{.HelpName}} --keep s3://bucket/prefix/
+`
+
+var benchCommand = &cli.Command{
+	Name:               "bench",
+	HelpName:           "bench",
+	Usage:              "measure upload and download throughput and latency",
+	CustomHelpTemplate: benchHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:    "count",
+			Aliases: []string{"n"},
+			Value:   100,
+			Usage:   "number of objects to upload and download",
+		},
+		&cli.StringFlag{
+			Name:  "size",
+			Value: "1M",
+			Usage: "size of each object",
+		},
+		&cli.IntFlag{
+			Name:  "workers",
+			Usage: "number of objects transferred in parallel, defaults to the number of workers",
+		},
+		&cli.BoolFlag{
+			Name:  "keep",
+			Usage: "do not delete the uploaded objects after the benchmark",
+		},
+		&cli.IntFlag{
+			Name:    "concurrency",
+			Aliases: []string{"c"},
+			Value:   defaultCopyConcurrency,
+			Usage:   "number of concurrent parts transferred between host and remote server",
+		},
+		&cli.StringFlag{
+			Name:    "part-size",
+			Aliases: []string{"p"},
+			Value:   strconv.Itoa(defaultPartSize),
+			Usage:   "size of each part transferred between host and remote server, in MiB unless a unit is given, e.g. 100M",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateBenchCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		// validated in Before
+		size, _ := strutil.ParseBytes(c.String("size"))
+		partSize, _ := parsePartSize(c.String("part-size"))
+
+		workers := c.Int("workers")
+		if workers == 0 {
+			workers = parallel.WorkerCount()
+		}
+
+		return Bench{
+			dst:         c.Args().First(),
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+
+			count:       c.Int("count"),
+			size:        size,
+			workers:     workers,
+			keep:        c.Bool("keep"),
+			concurrency: c.Int("concurrency"),
+			partSize:    partSize,
+
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Bench holds bench operation flags and states.
+type Bench struct {
+	dst         string
+	op          string
+	fullCommand string
+
+	count   int
+	size    int64
+	workers int
+	keep    bool
+
+	// s3 options
+	concurrency int
+	partSize    int64
+	storageOpts storage.Options
+}
+
+// Run uploads the objects, downloads them and prints the results of both
+// phases. The objects are deleted at the end unless they are kept.
+func (b Bench) Run(ctx context.Context) error {
+	dsturl, err := url.New(b.dst)
+	if err != nil {
+		printError(b.fullCommand, b.op, err)
+		return err
+	}
+
+	client, err := storage.NewRemoteClient(ctx, dsturl, b.storageOpts)
+	if err != nil {
+		printError(b.fullCommand, b.op, err)
+		return err
+	}
+
+	urls := make([]*url.URL, b.count)
+	width := len(strconv.Itoa(b.count - 1))
+	for i := range urls {
+		urls[i] = dsturl.Join(fmt.Sprintf("s5cmd-bench%0*d", width, i))
+	}
+
+	var merror error
+
+	upload := b.phase(ctx, "upload", urls, func(i int, objurl *url.URL) error {
+		reader := &seedReader{size: b.size, seed: splitmix64(uint64(i))}
+		return client.Put(ctx, reader, objurl, storage.NewMetadata(), b.concurrency, b.partSize)
+	})
+	if upload.err != nil {
+		merror = multierror.Append(merror, upload.err)
+	}
+	log.Summary(upload.message)
+
+	// objects are downloaded only if all of them are uploaded.
+	if upload.err == nil {
+		download := b.phase(ctx, "download", urls, func(_ int, objurl *url.URL) error {
+			_, err := client.Get(ctx, objurl, discardWriterAt{}, b.concurrency, b.partSize)
+			return err
+		})
+		if download.err != nil {
+			merror = multierror.Append(merror, download.err)
+		}
+		log.Summary(download.message)
+	}
+
+	if !b.keep {
+		if err := b.cleanup(ctx, client, urls); err != nil {
+			merror = multierror.Append(merror, err)
+		}
+	}
+
+	return merror
+}
+
+// benchResult is the result of a benchmark phase.
+type benchResult struct {
+	message BenchMessage
+	err     error
+}
+
+// phase runs the operation for each object with the workers of the
+// benchmark and measures its latency.
+func (b Bench) phase(ctx context.Context, name string, urls []*url.URL, fn func(int, *url.URL) error) benchResult {
+	pm := parallel.New(b.workers)
+	waiter := parallel.NewWaiter()
+
+	var (
+		merror    error
+		errDoneCh = make(chan bool)
+
+		mu        sync.Mutex
+		latencies []time.Duration
+	)
+
+	go func() {
+		defer close(errDoneCh)
+		for err := range waiter.Err() {
+			printError(b.fullCommand, b.op, err)
+			merror = multierror.Append(merror, err)
+		}
+	}()
+
+	start := time.Now()
+	for i, objurl := range urls {
+		if ctx.Err() != nil {
+			break
+		}
+
+		i, objurl := i, objurl
+		pm.Run(func() error {
+			opStart := time.Now()
+			if err := fn(i, objurl); err != nil {
+				return &errorpkg.Error{
+					Op:  b.op,
+					Src: objurl,
+					Err: err,
+				}
+			}
+
+			mu.Lock()
+			latencies = append(latencies, time.Since(opStart))
+			mu.Unlock()
+			return nil
+		}, waiter)
+	}
+
+	waiter.Wait()
+	<-errDoneCh
+	pm.Close()
+
+	return benchResult{
+		message: newBenchMessage(name, b.size, time.Since(start), latencies),
+		err:     merror,
+	}
+}
+
+// cleanup deletes the objects of the benchmark.
+func (b Bench) cleanup(ctx context.Context, client *storage.S3, urls []*url.URL) error {
+	urlch := make(chan *url.URL)
+	go func() {
+		defer close(urlch)
+		for _, u := range urls {
+			urlch <- u
+		}
+	}()
+
+	var merror error
+	for obj := range client.MultiDelete(ctx, urlch) {
+		if err := obj.Err; err != nil && !errorpkg.IsCancelation(err) {
+			merror = multierror.Append(merror, err)
+			printError(b.fullCommand, b.op, err)
+		}
+	}
+	return merror
+}
+
+// discardWriterAt is an io.WriterAt which discards the written data.
+type discardWriterAt struct{}
+
+func (discardWriterAt) WriteAt(p []byte, _ int64) (int, error) {
+	return len(p), nil
+}
+
+// BenchMessage is a structure for logging the results of a benchmark phase.
+// Durations are in milliseconds.
+type BenchMessage struct {
+	Operation  string  `json:"operation"`
+	Count      int     `json:"count"`
+	Bytes      int64   `json:"bytes"`
+	Duration   float64 `json:"duration_ms"`
+	Throughput int64   `json:"bytes_per_second"`
+	P50        float64 `json:"p50_ms"`
+	P95        float64 `json:"p95_ms"`
+	P99        float64 `json:"p99_ms"`
+}
+
+// newBenchMessage calculates the throughput and the latency percentiles of
+// the successful operations.
+func newBenchMessage(op string, size int64, elapsed time.Duration, latencies []time.Duration) BenchMessage {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	percentile := func(p float64) float64 {
+		if len(latencies) == 0 {
+			return 0
+		}
+		i := int(math.Ceil(float64(len(latencies))*p/100)) - 1
+		if i < 0 {
+			i = 0
+		}
+		return durationMilliseconds(latencies[i])
+	}
+
+	msg := BenchMessage{
+		Operation: op,
+		Count:     len(latencies),
+		Bytes:     size * int64(len(latencies)),
+		Duration:  durationMilliseconds(elapsed),
+		P50:       percentile(50),
+		P95:       percentile(95),
+		P99:       percentile(99),
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		msg.Throughput = int64(float64(msg.Bytes) / seconds)
+	}
+	return msg
+}
+
+func durationMilliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
+
+// String returns the string representation of BenchMessage.
+func (m BenchMessage) String() string {
+	return fmt.Sprintf(
+		"%v: %d objects, %v in %.2fms, %v/s, p50 %.2fms, p95 %.2fms, p99 %.2fms",
+		m.Operation,
+		m.Count,
+		strutil.HumanizeBytes(m.Bytes),
+		m.Duration,
+		strutil.HumanizeBytes(m.Throughput),
+		m.P50,
+		m.P95,
+		m.P99,
+	)
+}
+
+// JSON returns the JSON representation of BenchMessage.
+func (m BenchMessage) JSON() string {
+	return strutil.JSON(m)
+}
+
+func validateBenchCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only 1 argument")
+	}
+
+	dsturl, err := url.New(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	if !dsturl.IsRemote() || !(dsturl.IsBucket() || dsturl.IsPrefix()) {
+		return fmt.Errorf("destination must be a bucket or a prefix")
+	}
+
+	if dsturl.HasGlob() {
+		return fmt.Errorf("target %q can not contain glob characters", dsturl)
+	}
+
+	if c.Int("count") < 1 {
+		return fmt.Errorf("count must be a positive number")
+	}
+
+	if c.Int("workers") < 0 {
+		return fmt.Errorf("workers can not be a negative number")
+	}
+
+	size, err := strutil.ParseBytes(c.String("size"))
+	if err != nil {
+		return err
+	}
+	if size < 0 {
+		return fmt.Errorf("size can not be a negative number")
+	}
+
+	return validateMultipartFlags(c)
+}
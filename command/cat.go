@@ -25,6 +25,9 @@ Options:
 Examples:
 	1. Print a remote object's content to stdout
 		 > s5cmd {{.HelpName}} s3://bucket/prefix/object
+
+	2. Print the first kilobyte of a remote object to stdout
+		 > s5cmd {{.HelpName}} --range 0-1023 s3://bucket/prefix/object
 `
 
 var catCommand = &cli.Command{
@@ -32,6 +35,12 @@ var catCommand = &cli.Command{
 	HelpName:           "cat",
 	Usage:              "print remote object content",
 	CustomHelpTemplate: catHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "range",
+			Usage: "print only the given byte range of the object, e.g. 0-1023, 1024- or -1024 for the last 1024 bytes",
+		},
+	},
 	Before: func(c *cli.Context) error {
 		err := validateCatCommand(c)
 		if err != nil {
@@ -50,10 +59,14 @@ var catCommand = &cli.Command{
 			return err
 		}
 
+		byteRange, _ := parseByteRange(c.String("range"))
+
 		return Cat{
 			src:         src,
 			op:          op,
 			fullCommand: fullCommand,
+			// flags
+			byteRange: byteRange,
 
 			storageOpts: NewStorageOpts(c),
 		}.Run(c.Context)
@@ -66,6 +79,9 @@ type Cat struct {
 	op          string
 	fullCommand string
 
+	// flags
+	byteRange string
+
 	storageOpts storage.Options
 }
 
@@ -77,7 +93,12 @@ func (c Cat) Run(ctx context.Context) error {
 		return err
 	}
 
-	rc, err := client.Read(ctx, c.src)
+	var rc io.ReadCloser
+	if c.byteRange != "" {
+		rc, err = client.ReadRange(ctx, c.src, c.byteRange)
+	} else {
+		rc, err = client.Read(ctx, c.src)
+	}
 	if err != nil {
 		printError(c.fullCommand, c.op, err)
 		return err
@@ -115,5 +136,9 @@ func validateCatCommand(c *cli.Context) error {
 	if src.HasGlob() {
 		return fmt.Errorf("remote source %q can not contain glob characters", src)
 	}
+
+	if _, err := parseByteRange(c.String("range")); err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,258 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kballard/go-shellquote"
+)
+
+// operators of the command chains.
+const (
+	chainAnd = "&&"
+	chainOr  = "||"
+)
+
+// commandChain is a command, or two command chains joined with an operator.
+// The right chain of "&&" is run if the left one succeeds, and the right
+// chain of "||" is run if the left one fails, as in shell.
+type commandChain struct {
+	fields []string
+
+	op          string
+	left, right *commandChain
+}
+
+// commands returns the commands of the chain in order.
+func (c *commandChain) commands() [][]string {
+	if c.op == "" {
+		return [][]string{c.fields}
+	}
+	return append(c.left.commands(), c.right.commands()...)
+}
+
+// expandVariables replaces the variables in the fields of the commands of
+// the chain.
+func (c *commandChain) expandVariables(vars map[string]string) error {
+	if c.op == "" {
+		return expandFields(c.fields, vars)
+	}
+	if err := c.left.expandVariables(vars); err != nil {
+		return err
+	}
+	return c.right.expandVariables(vars)
+}
+
+// String returns the canonical form of the chain. The operators have the
+// same precedence and are left-associative, so only the chains on the right
+// of an operator are grouped.
+func (c *commandChain) String() string {
+	if c.op == "" {
+		return shellquote.Join(c.fields...)
+	}
+
+	right := c.right.String()
+	if c.right.op != "" {
+		right = "(" + right + ")"
+	}
+	return fmt.Sprintf("%v %v %v", c.left, c.op, right)
+}
+
+// chainToken is either an operator or the text of a command.
+type chainToken struct {
+	op   string
+	text string
+}
+
+// parseChain parses a line of commands joined with "&&" and "||" operators
+// and grouped with parentheses.
+func parseChain(line string) (*commandChain, error) {
+	p := &chainParser{tokens: lexChain(line)}
+
+	chain, err := p.parseChain()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].op)
+	}
+	return chain, nil
+}
+
+// quoteState tracks the quotes and the escapes of a line, as they are parsed
+// by shellquote.
+type quoteState struct {
+	quote   byte
+	escaped bool
+}
+
+// consume advances the state with the next character of the line. It reports
+// whether the character is quoted or escaped, or is a quote or an escape
+// itself, i.e. it is a part of a word regardless of its meaning in the line.
+func (q *quoteState) consume(ch byte) bool {
+	switch {
+	case q.escaped:
+		q.escaped = false
+	case ch == '\\' && q.quote != '\'':
+		q.escaped = true
+	case q.quote != 0:
+		if ch == q.quote {
+			q.quote = 0
+		}
+	case ch == '\'' || ch == '"':
+		q.quote = ch
+	default:
+		return false
+	}
+	return true
+}
+
+// stripComment removes the comment at the end of the line. As in shell, a
+// comment starts with a "#" at the beginning of a word which is not quoted or
+// escaped, so the "#" characters of the keys are kept.
+func stripComment(line string) string {
+	var (
+		q     quoteState
+		blank = true
+	)
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		if q.consume(ch) {
+			blank = false
+			continue
+		}
+		if ch == '#' && blank {
+			return line[:i]
+		}
+		blank = ch == ' ' || ch == '\t'
+	}
+	return line
+}
+
+// lexChain splits the line into the operators and the texts of the commands.
+// The operators in quotes are a part of the commands. An opening parenthesis
+// is an operator only in place of a command, and a closing one only at the
+// end of a word of a group if it doesn't close a parenthesis of the command,
+// so the parentheses in keys, such as "file(1).txt", don't need to be quoted.
+func lexChain(line string) []chainToken {
+	var (
+		tokens []chainToken
+		text   strings.Builder
+		quotes quoteState
+		depth  int
+		// command is set once the text of the current command is started,
+		// and parens is the number of its unclosed parentheses.
+		command bool
+		parens  int
+	)
+
+	flush := func() {
+		if strings.TrimSpace(text.String()) != "" {
+			tokens = append(tokens, chainToken{text: text.String()})
+		}
+		text.Reset()
+		command = false
+		parens = 0
+	}
+
+	isBoundary := func(rest string) bool {
+		return rest == "" || rest[0] == ' ' || rest[0] == '\t' || rest[0] == ')' ||
+			strings.HasPrefix(rest, chainAnd) || strings.HasPrefix(rest, chainOr)
+	}
+
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		rest := line[i:]
+
+		switch {
+		case quotes.consume(ch):
+		case strings.HasPrefix(rest, chainAnd) || strings.HasPrefix(rest, chainOr):
+			flush()
+			tokens = append(tokens, chainToken{op: rest[:2]})
+			i++
+			continue
+		case ch == '(' && !command:
+			flush()
+			tokens = append(tokens, chainToken{op: "("})
+			depth++
+			continue
+		case ch == '(':
+			parens++
+		case ch == ')' && parens > 0:
+			parens--
+		case ch == ')' && depth > 0 && isBoundary(line[i+1:]):
+			flush()
+			tokens = append(tokens, chainToken{op: ")"})
+			depth--
+			continue
+		case ch == ' ' || ch == '\t':
+			text.WriteByte(ch)
+			continue
+		}
+
+		command = true
+		text.WriteByte(ch)
+	}
+	flush()
+	return tokens
+}
+
+// chainParser parses the tokens of a command chain.
+type chainParser struct {
+	tokens []chainToken
+	pos    int
+}
+
+// parseChain parses the commands joined with the operators.
+func (p *chainParser) parseChain() (*commandChain, error) {
+	left, err := p.parseCommand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.pos < len(p.tokens) {
+		op := p.tokens[p.pos].op
+		if op != chainAnd && op != chainOr {
+			break
+		}
+		p.pos++
+
+		right, err := p.parseCommand()
+		if err != nil {
+			return nil, err
+		}
+		left = &commandChain{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseCommand parses a command or a group of commands in parentheses.
+func (p *chainParser) parseCommand() (*commandChain, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("missing command at the end of the line")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.op {
+	case "":
+		fields, err := shellquote.Split(token.text)
+		if err != nil {
+			return nil, err
+		}
+		return &commandChain{fields: fields}, nil
+	case "(":
+		chain, err := p.parseChain()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].op != ")" {
+			return nil, fmt.Errorf("missing %q", ")")
+		}
+		p.pos++
+		return chain, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", token.op)
+	}
+}
@@ -0,0 +1,135 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChain(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		line     string
+		expected string
+		commands [][]string
+		wantErr  bool
+	}{
+		{
+			name:     "single_command",
+			line:     "cp  s3://bucket/a  dir/",
+			expected: "cp s3://bucket/a dir/",
+			commands: [][]string{{"cp", "s3://bucket/a", "dir/"}},
+		},
+		{
+			name:     "and",
+			line:     "cp a s3://bucket/ && rm a",
+			expected: "cp a s3://bucket/ && rm a",
+			commands: [][]string{{"cp", "a", "s3://bucket/"}, {"rm", "a"}},
+		},
+		{
+			name:     "without_spaces",
+			line:     "ls a&&ls b||ls c",
+			expected: "ls a && ls b || ls c",
+			commands: [][]string{{"ls", "a"}, {"ls", "b"}, {"ls", "c"}},
+		},
+		{
+			name:     "left_group_is_not_needed",
+			line:     "(ls a || ls b) && ls c",
+			expected: "ls a || ls b && ls c",
+			commands: [][]string{{"ls", "a"}, {"ls", "b"}, {"ls", "c"}},
+		},
+		{
+			name:     "right_group",
+			line:     "ls a || (ls b && ls c)",
+			expected: "ls a || (ls b && ls c)",
+			commands: [][]string{{"ls", "a"}, {"ls", "b"}, {"ls", "c"}},
+		},
+		{
+			name:     "quoted_operators",
+			line:     `ls "s3://bucket/a && b" && ls 's3://bucket/(c)'`,
+			expected: `ls 's3://bucket/a && b' && ls s3://bucket/\(c\)`,
+			commands: [][]string{{"ls", "s3://bucket/a && b"}, {"ls", "s3://bucket/(c)"}},
+		},
+		{
+			name:     "parentheses_in_keys",
+			line:     "(ls s3://bucket/file(1).txt && ls s3://bucket/(2)) || ls s3://bucket/",
+			expected: `ls s3://bucket/file\(1\).txt && ls s3://bucket/\(2\) || ls s3://bucket/`,
+			commands: [][]string{{"ls", "s3://bucket/file(1).txt"}, {"ls", "s3://bucket/(2)"}, {"ls", "s3://bucket/"}},
+		},
+		{name: "missing_command_at_the_end", line: "ls a &&", wantErr: true},
+		{name: "missing_command_between_operators", line: "ls a && || ls b", wantErr: true},
+		{name: "missing_closing_parenthesis", line: "(ls a && ls b", wantErr: true},
+		{name: "empty_group", line: "ls a && ()", wantErr: true},
+		{name: "unterminated_quote", line: `ls "a && ls b`, wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			chain, err := parseChain(tc.line)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, chain.String())
+			assert.Equal(t, tc.commands, chain.commands())
+		})
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		line     string
+		expected string
+	}{
+		{
+			name:     "no_comment",
+			line:     "cp s3://bucket/a dir/",
+			expected: "cp s3://bucket/a dir/",
+		},
+		{
+			name:     "inline_comment",
+			line:     "cp s3://bucket/a dir/ # copy a",
+			expected: "cp s3://bucket/a dir/ ",
+		},
+		{
+			name:     "comment_line",
+			line:     "# copy a",
+			expected: "",
+		},
+		{
+			name:     "hash_in_word",
+			line:     "cp s3://bucket/a#b dir/",
+			expected: "cp s3://bucket/a#b dir/",
+		},
+		{
+			name:     "single_quoted_hash",
+			line:     "cp 's3://bucket/a #b.txt' dir/ # copy",
+			expected: "cp 's3://bucket/a #b.txt' dir/ ",
+		},
+		{
+			name:     "double_quoted_hash",
+			line:     `cp "s3://bucket/a #b.txt" dir/`,
+			expected: `cp "s3://bucket/a #b.txt" dir/`,
+		},
+		{
+			name:     "escaped_hash",
+			line:     `cp s3://bucket/a\ \#b.txt dir/`,
+			expected: `cp s3://bucket/a\ \#b.txt dir/`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, stripComment(tc.line))
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+)
+
+// checkpointInterval is the interval of writing the buffered entries of a
+// checkpoint to its file.
+const checkpointInterval = time.Second
+
+// kinds of the entries of a checkpoint.
+const (
+	// checkpointCommand is a command of a line which is completed.
+	checkpointCommand = "command"
+
+	// checkpointJob is a transfer of a command which is completed, while the
+	// command itself might not be.
+	checkpointJob = "job"
+)
+
+// runCheckpoint records the completed commands of a command file and the
+// completed transfers of its commands, so a run resumed with --resume flag
+// doesn't run them again. The commands are identified with their lines,
+// numbered from 0 as in the error messages, and their canonical forms, so an
+// edited line is run again. It is safe for concurrent use.
+type runCheckpoint struct {
+	mu      sync.Mutex
+	f       *os.File
+	w       *bufio.Writer
+	entries map[string]struct{}
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// openRunCheckpoint reads the entries of the checkpoint file of a previous
+// run and opens it for appending. The file is created if it does not exist.
+// The entries are buffered, and written to the file periodically until the
+// checkpoint is closed.
+func openRunCheckpoint(path string) (*runCheckpoint, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, partial, err := readRunCheckpoint(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// terminate the partial line, so it is not joined with the next entry.
+	if partial {
+		if _, err := f.WriteString("\n"); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+
+	cp := &runCheckpoint{
+		f:       f,
+		w:       bufio.NewWriter(f),
+		entries: entries,
+		stopCh:  make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go cp.flushPeriodically()
+	return cp, nil
+}
+
+// readRunCheckpoint reads the entries of a checkpoint. A trailing line
+// without a newline is the result of an interrupted write, so it is ignored
+// and reported as partial. Malformed lines are ignored as well, their
+// commands are run again.
+func readRunCheckpoint(r io.Reader) (map[string]struct{}, bool, error) {
+	entries := map[string]struct{}{}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			return entries, line != "", nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		fields, err := shellquote.Split(strings.TrimSuffix(line, "\n"))
+		if err != nil || len(fields) < 3 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue
+		}
+		entries[checkpointKey(fields...)] = struct{}{}
+	}
+}
+
+// checkpointKey returns the key of an entry of given fields.
+func checkpointKey(fields ...string) string {
+	return strings.Join(fields, "\x00")
+}
+
+// has reports whether the entry of given fields is recorded.
+func (cp *runCheckpoint) has(fields ...string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	_, ok := cp.entries[checkpointKey(fields...)]
+	return ok
+}
+
+// add records the entry of given fields.
+func (cp *runCheckpoint) add(fields ...string) error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if _, err := cp.w.WriteString(shellquote.Join(fields...) + "\n"); err != nil {
+		return err
+	}
+	cp.entries[checkpointKey(fields...)] = struct{}{}
+	return nil
+}
+
+// commandDone reports whether the command of the line is completed.
+func (cp *runCheckpoint) commandDone(line int, command string) bool {
+	return cp.has(checkpointCommand, strconv.Itoa(line), command)
+}
+
+// addCommand records that the command of the line is completed.
+func (cp *runCheckpoint) addCommand(line int, command string) error {
+	return cp.add(checkpointCommand, strconv.Itoa(line), command)
+}
+
+// command returns the checkpoint of the transfers of the command of given
+// line.
+func (cp *runCheckpoint) command(line int, command string) *commandCheckpoint {
+	return &commandCheckpoint{
+		checkpoint: cp,
+		line:       strconv.Itoa(line),
+		command:    command,
+	}
+}
+
+// flushPeriodically writes the buffered entries to the file with the
+// interval until the checkpoint is closed.
+func (cp *runCheckpoint) flushPeriodically() {
+	defer close(cp.doneCh)
+
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cp.stopCh:
+			return
+		case <-ticker.C:
+			cp.mu.Lock()
+			_ = cp.w.Flush()
+			cp.mu.Unlock()
+		}
+	}
+}
+
+// Close writes the buffered entries and closes the checkpoint file.
+func (cp *runCheckpoint) Close() error {
+	close(cp.stopCh)
+	<-cp.doneCh
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	err := cp.w.Flush()
+	if cerr := cp.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// commandCheckpoint records the completed transfers of a command of a run.
+type commandCheckpoint struct {
+	checkpoint *runCheckpoint
+	line       string
+	command    string
+}
+
+// has reports whether the transfer of given source is completed.
+func (c *commandCheckpoint) has(src string) bool {
+	return c.checkpoint.has(checkpointJob, c.line, c.command, src)
+}
+
+// add records a completed transfer.
+func (c *commandCheckpoint) add(src string) error {
+	return c.checkpoint.add(checkpointJob, c.line, c.command, src)
+}
+
+type commandCheckpointKey struct{}
+
+// withCommandCheckpoint returns the context of a command run with the
+// checkpoint of its transfers.
+func withCommandCheckpoint(ctx context.Context, c *commandCheckpoint) context.Context {
+	return context.WithValue(ctx, commandCheckpointKey{}, c)
+}
+
+// commandCheckpointFrom returns the checkpoint of the transfers of the
+// command, or nil if the command is not run with a checkpoint.
+func commandCheckpointFrom(ctx context.Context) *commandCheckpoint {
+	if ctx == nil {
+		return nil
+	}
+	c, _ := ctx.Value(commandCheckpointKey{}).(*commandCheckpoint)
+	return c
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "s5cmd-checkpoint")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "commands.checkpoint")
+
+	cp, err := openRunCheckpoint(path)
+	assert.NoError(t, err)
+
+	command := "cp 's3://bucket/*' dir/"
+	assert.NoError(t, cp.command(1, command).add("s3://bucket/file with spaces.txt"))
+	assert.NoError(t, cp.addCommand(0, "ls s3://bucket"))
+	assert.NoError(t, cp.Close())
+
+	// the entry of an interrupted write is ignored.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	assert.NoError(t, err)
+	_, err = f.WriteString("command 1 'cp")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	cp, err = openRunCheckpoint(path)
+	assert.NoError(t, err)
+
+	assert.True(t, cp.commandDone(0, "ls s3://bucket"))
+	assert.False(t, cp.commandDone(1, command))
+	// the commands are identified with their lines.
+	assert.False(t, cp.commandDone(1, "ls s3://bucket"))
+
+	assert.True(t, cp.command(1, command).has("s3://bucket/file with spaces.txt"))
+	assert.False(t, cp.command(2, command).has("s3://bucket/file with spaces.txt"))
+
+	assert.NoError(t, cp.addCommand(1, command))
+	assert.NoError(t, cp.Close())
+
+	cp, err = openRunCheckpoint(path)
+	assert.NoError(t, err)
+	defer cp.Close()
+
+	assert.True(t, cp.commandDone(1, command))
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/peak/s5cmd/storage"
+)
+
+// sameContent reports whether the objects have the same size and checksum. The
+// checksum of a local file is computed in the format of the ETag of the other
+// object. Objects whose checksums can not be compared are not the same, so
+// they are overwritten rather than skipped.
+func sameContent(src, dst *storage.Object, partSize int64) (bool, error) {
+	if src.Size != dst.Size {
+		return false, nil
+	}
+
+	srcTag, err := objectChecksum(src, dst, partSize)
+	if err != nil {
+		return false, err
+	}
+
+	dstTag, err := objectChecksum(dst, src, partSize)
+	if err != nil {
+		return false, err
+	}
+
+	return srcTag != "" && srcTag == dstTag, nil
+}
+
+// objectChecksum returns the ETag of the remote object, or computes the one of
+// the local file in the format of the ETag of the other object. It returns an
+// empty string if the checksum can not be computed.
+func objectChecksum(obj, other *storage.Object, partSize int64) (string, error) {
+	if obj.URL.IsRemote() || obj.URL.IsHTTP() {
+		return strings.Trim(obj.Etag, `"`), nil
+	}
+
+	var parts int64
+	if other.URL.IsRemote() || other.URL.IsHTTP() {
+		etag := strings.Trim(other.Etag, `"`)
+		if etag == "" {
+			return "", nil
+		}
+
+		// ETags of the multipart uploads are suffixed with the number of
+		// parts, which is only reproducible with the same part size.
+		if i := strings.LastIndex(etag, "-"); i >= 0 {
+			n, err := strconv.ParseInt(etag[i+1:], 10, 64)
+			if err != nil || partSize <= 0 || n != (obj.Size+partSize-1)/partSize {
+				return "", nil
+			}
+			parts = n
+		}
+	}
+
+	return fileETag(obj.URL.Absolute(), parts, partSize)
+}
+
+// fileETag computes the ETag of the file as S3 does. The ETag of a file
+// uploaded in parts is the MD5 of the MD5 digests of its parts, suffixed with
+// the number of parts. Otherwise, it is the MD5 of the file.
+func fileETag(path string, parts, partSize int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if parts == 0 {
+		h := md5.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	digests := md5.New()
+	for i := int64(0); i < parts; i++ {
+		h := md5.New()
+		if _, err := io.CopyN(h, f, partSize); err != nil && err != io.EOF {
+			return "", err
+		}
+		digests.Write(h.Sum(nil))
+	}
+	return fmt.Sprintf("%v-%v", hex.EncodeToString(digests.Sum(nil)), parts), nil
+}
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func TestSameContent(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "checksum")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	const content = "0123456789abcdef"
+	path := filepath.Join(dir, "file.txt")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+	md5sum := func(s string) []byte {
+		sum := md5.Sum([]byte(s))
+		return sum[:]
+	}
+	singlePart := hex.EncodeToString(md5sum(content))
+	multipart := hex.EncodeToString(md5sum(string(md5sum(content[:10]))+string(md5sum(content[10:])))) + "-2"
+
+	local := func() *storage.Object {
+		u, err := url.New(path)
+		assert.NoError(t, err)
+		return &storage.Object{URL: u, Size: int64(len(content))}
+	}
+	remote := func(etag string, size int64) *storage.Object {
+		u, err := url.New("s3://bucket/file.txt")
+		assert.NoError(t, err)
+		return &storage.Object{URL: u, Size: size, Etag: etag}
+	}
+
+	testcases := []struct {
+		name     string
+		src      *storage.Object
+		dst      *storage.Object
+		partSize int64
+		expected bool
+	}{
+		{"single_part_match", local(), remote(singlePart, 16), 10, true},
+		{"quoted_etag_match", remote(`"`+singlePart+`"`, 16), local(), 10, true},
+		{"multipart_match", local(), remote(multipart, 16), 10, true},
+		{"multipart_different_part_size", local(), remote(multipart, 16), 5, false},
+		{"checksum_differs", local(), remote(hex.EncodeToString(md5sum("fedcba9876543210")), 16), 10, false},
+		{"size_differs", local(), remote(singlePart, 15), 10, false},
+		{"no_etag", local(), remote("", 16), 10, false},
+		{"remote_match", remote(multipart, 16), remote(multipart, 16), 10, true},
+		{"local_match", local(), local(), 10, true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			same, err := sameContent(tc.src, tc.dst, tc.partSize)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, same)
+		})
+	}
+}
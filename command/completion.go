@@ -0,0 +1,281 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+var completionHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} bash|zsh|fish
+
+Examples:
+	1. Enable completion for the current bash session
+		 > source <(s5cmd {{.HelpName}} bash)
+
+	2. Install completion for zsh
+		 > s5cmd {{.HelpName}} zsh > "${fpath[1]}/_s5cmd"
+
+	3. Install completion for fish
+		 > s5cmd {{.HelpName}} fish > ~/.config/fish/completions/s5cmd.fish
+`
+
+var completionCommand = &cli.Command{
+	Name:               "completion",
+	HelpName:           "completion",
+	Usage:              "print shell completion script",
+	CustomHelpTemplate: completionHelpTemplate,
+	Before: func(c *cli.Context) error {
+		err := validateCompletionCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) error {
+		script := completionScripts[c.Args().First()](completionCommands(), completionFlags(app.Flags))
+		fmt.Print(script)
+		return nil
+	},
+}
+
+// completionScripts are the script generators of the supported shells.
+var completionScripts = map[string]func([]completionCmd, []completionFlag) string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+// completionCmd is a command as seen by the completion scripts.
+type completionCmd struct {
+	names []string
+	usage string
+	flags []completionFlag
+}
+
+// completionFlag is a flag as seen by the completion scripts.
+type completionFlag struct {
+	names      []string
+	usage      string
+	takesValue bool
+}
+
+// option returns the flag name with its dashes.
+func option(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+func completionCommands() []completionCmd {
+	var commands []completionCmd
+	for _, cmd := range app.Commands {
+		commands = append(commands, completionCmd{
+			names: cmd.Names(),
+			usage: cmd.Usage,
+			flags: completionFlags(cmd.Flags),
+		})
+	}
+	return commands
+}
+
+func completionFlags(flags []cli.Flag) []completionFlag {
+	var result []completionFlag
+	for _, flag := range flags {
+		cflag := completionFlag{names: flag.Names()}
+		if docflag, ok := flag.(cli.DocGenerationFlag); ok {
+			cflag.usage = docflag.GetUsage()
+			cflag.takesValue = docflag.TakesValue()
+		}
+		result = append(result, cflag)
+	}
+	return result
+}
+
+func options(flags []completionFlag) string {
+	var names []string
+	for _, flag := range flags {
+		for _, name := range flag.names {
+			names = append(names, option(name))
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// bashCompletion completes command names and flags, and falls back to local
+// paths for the arguments.
+func bashCompletion(commands []completionCmd, globalFlags []completionFlag) string {
+	var names []string
+	for _, cmd := range commands {
+		names = append(names, cmd.names...)
+	}
+
+	var valueFlags []string
+	for _, flag := range globalFlags {
+		if !flag.takesValue {
+			continue
+		}
+		for _, name := range flag.names {
+			valueFlags = append(valueFlags, option(name))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %v\n\n", appName)
+	fmt.Fprintf(&b, "_%v() {\n", appName)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    local cmd=\"\" flags i\n\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	if len(valueFlags) > 0 {
+		fmt.Fprintf(&b, "            %v) ((i++)) ;;\n", strings.Join(valueFlags, "|"))
+	}
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) cmd=\"${COMP_WORDS[i]}\"; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	fmt.Fprintf(&b, "        \"\") flags=%q ;;\n", options(globalFlags))
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "        %v) flags=%q ;;\n", strings.Join(cmd.names, "|"), options(cmd.flags))
+	}
+	b.WriteString("    esac\n\n")
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	b.WriteString("        COMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))\n")
+	b.WriteString("    elif [[ -z \"$cmd\" ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(names, " "))
+	b.WriteString("    else\n")
+	b.WriteString("        COMPREPLY=($(compgen -f -- \"$cur\"))\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "complete -o filenames -F _%v %v\n", appName, appName)
+	return b.String()
+}
+
+// zshQuote quotes s as a single-quoted zsh word.
+func zshQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// zshDescription escapes the characters which have a special meaning in the
+// descriptions of _arguments and _describe specs.
+func zshDescription(s string) string {
+	return strings.NewReplacer(`[`, `\[`, `]`, `\]`, `:`, `\:`).Replace(s)
+}
+
+func zshArguments(flags []completionFlag) []string {
+	var specs []string
+	for _, flag := range flags {
+		for _, name := range flag.names {
+			spec := option(name) + "[" + zshDescription(flag.usage) + "]"
+			if flag.takesValue {
+				spec += ":value:"
+			}
+			specs = append(specs, zshQuote(spec))
+		}
+	}
+	return specs
+}
+
+// zshCompletion completes command names, flags with their descriptions and
+// local paths for the arguments.
+func zshCompletion(commands []completionCmd, globalFlags []completionFlag) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %v\n\n", appName)
+	fmt.Fprintf(&b, "_%v() {\n", appName)
+	b.WriteString("    local curcontext=\"$curcontext\" state line\n")
+	b.WriteString("    local -a commands\n")
+	b.WriteString("    commands=(\n")
+	for _, cmd := range commands {
+		for _, name := range cmd.names {
+			fmt.Fprintf(&b, "        %v\n", zshQuote(name+":"+zshDescription(cmd.usage)))
+		}
+	}
+	b.WriteString("    )\n\n")
+	b.WriteString("    _arguments -C \\\n")
+	for _, spec := range zshArguments(globalFlags) {
+		fmt.Fprintf(&b, "        %v \\\n", spec)
+	}
+	b.WriteString("        '1: :->command' \\\n")
+	b.WriteString("        '*:: :->args'\n\n")
+	b.WriteString("    case $state in\n")
+	b.WriteString("        command)\n")
+	fmt.Fprintf(&b, "            _describe -t commands '%v command' commands\n", appName)
+	b.WriteString("            ;;\n")
+	b.WriteString("        args)\n")
+	b.WriteString("            case $line[1] in\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "                %v)\n", strings.Join(cmd.names, "|"))
+		b.WriteString("                    _arguments \\\n")
+		for _, spec := range zshArguments(cmd.flags) {
+			fmt.Fprintf(&b, "                        %v \\\n", spec)
+		}
+		b.WriteString("                        '*:file:_files'\n")
+		b.WriteString("                    ;;\n")
+	}
+	b.WriteString("            esac\n")
+	b.WriteString("            ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "_%v \"$@\"\n", appName)
+	return b.String()
+}
+
+// fishQuote quotes s as a single-quoted fish word.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func fishFlags(b *strings.Builder, condition string, flags []completionFlag) {
+	for _, flag := range flags {
+		fmt.Fprintf(b, "complete -c %v -n %v", appName, fishQuote(condition))
+		for _, name := range flag.names {
+			if len(name) == 1 {
+				fmt.Fprintf(b, " -s %v", name)
+			} else {
+				fmt.Fprintf(b, " -l %v", name)
+			}
+		}
+		if flag.takesValue {
+			b.WriteString(" -r")
+		}
+		fmt.Fprintf(b, " -d %v\n", fishQuote(flag.usage))
+	}
+}
+
+// fishCompletion completes command names and flags with their descriptions.
+// Local paths are completed by fish for the arguments.
+func fishCompletion(commands []completionCmd, globalFlags []completionFlag) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %v\n\n", appName)
+	for _, cmd := range commands {
+		for _, name := range cmd.names {
+			fmt.Fprintf(&b, "complete -c %v -n '__fish_use_subcommand' -f -a %v -d %v\n",
+				appName, name, fishQuote(cmd.usage))
+		}
+	}
+	b.WriteString("\n")
+	fishFlags(&b, "__fish_use_subcommand", globalFlags)
+	for _, cmd := range commands {
+		b.WriteString("\n")
+		fishFlags(&b, "__fish_seen_subcommand_from "+strings.Join(cmd.names, " "), cmd.flags)
+	}
+	return b.String()
+}
+
+func validateCompletionCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only 1 argument")
+	}
+
+	shell := c.Args().First()
+	if _, ok := completionScripts[shell]; !ok {
+		return fmt.Errorf("unsupported shell %q, expected one of bash, zsh or fish", shell)
+	}
+	return nil
+}
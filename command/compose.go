@@ -0,0 +1,266 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+var composeHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] source [source...] destination
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Merge the sharded output files into a single object, in the order of their keys
+		 > s5cmd {{.HelpName}} s3://bucket/output/part-* s3://bucket/output.csv
+
+	2. Merge the given objects in the given order
+		 > s5cmd {{.HelpName}} s3://bucket/header.csv s3://bucket/rows/* s3://bucket/report.csv
+
+	3. Merge objects into an object with a storage class and a content type
+		 > s5cmd {{.HelpName}} --storage-class STANDARD_IA --content-type text/csv s3://bucket/output/* s3://bucket/output.csv
+`
+
+var composeCommand = &cli.Command{
+	Name:               "compose",
+	HelpName:           "compose",
+	Usage:              "concatenate objects into an object on the server side",
+	CustomHelpTemplate: composeHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "storage-class",
+			Usage: "set storage class for target ('STANDARD','REDUCED_REDUNDANCY','GLACIER','STANDARD_IA','ONEZONE_IA','INTELLIGENT_TIERING','DEEP_ARCHIVE')",
+		},
+		&cli.IntFlag{
+			Name:    "concurrency",
+			Aliases: []string{"c"},
+			Value:   defaultCopyConcurrency,
+			Usage:   "number of concurrent parts composed",
+		},
+		&cli.StringFlag{
+			Name:  "sse",
+			Usage: "perform server side encryption of the data at its destination, e.g. aws:kms",
+		},
+		&cli.StringFlag{
+			Name:  "sse-kms-key-id",
+			Usage: "customer master key (CMK) id for SSE-KMS encryption; leave it out if server-side generated key is desired",
+		},
+		&cli.StringFlag{
+			Name:  "acl",
+			Usage: "set acl for target: defines granted accesses and their types on different accounts/groups",
+		},
+		&cli.StringFlag{
+			Name:  "content-type",
+			Usage: "set the content type of the destination object; the one of the first source is used if not specified",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateComposeCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		args := c.Args().Slice()
+		return Compose{
+			src:         args[:len(args)-1],
+			dst:         args[len(args)-1],
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+			// flags
+			storageClass:     storage.StorageClass(c.String("storage-class")),
+			concurrency:      c.Int("concurrency"),
+			encryptionMethod: c.String("sse"),
+			encryptionKeyID:  c.String("sse-kms-key-id"),
+			acl:              c.String("acl"),
+			contentType:      c.String("content-type"),
+
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Compose holds compose operation flags and states.
+type Compose struct {
+	src         []string
+	dst         string
+	op          string
+	fullCommand string
+
+	// flags
+	storageClass     storage.StorageClass
+	concurrency      int
+	encryptionMethod string
+	encryptionKeyID  string
+	acl              string
+	contentType      string
+
+	storageOpts storage.Options
+}
+
+// Run concatenates the source objects into the destination object.
+func (c Compose) Run(ctx context.Context) error {
+	srcurls, err := newURLs(c.src...)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	dsturl, err := url.New(c.dst)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	objects, err := c.expandSources(ctx, srcurls)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+	if len(objects) == 0 {
+		printError(c.fullCommand, c.op, storage.ErrNoObjectFound)
+		return storage.ErrNoObjectFound
+	}
+
+	client, err := storage.NewRemoteClient(ctx, dsturl, c.storageOpts)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	metadata := storage.NewMetadata().
+		SetContentType(c.contentType).
+		SetStorageClass(string(c.storageClass)).
+		SetSSE(c.encryptionMethod).
+		SetSSEKeyID(c.encryptionKeyID).
+		SetACL(c.acl)
+
+	if c.contentType == "" && !c.storageOpts.DryRun {
+		first, err := client.Stat(ctx, objects[0].URL)
+		if err != nil {
+			printError(c.fullCommand, c.op, err)
+			return err
+		}
+		metadata.SetContentType(first.ContentType)
+	}
+
+	var size int64
+	for _, obj := range objects {
+		size += obj.Size
+	}
+
+	if err := client.Compose(ctx, objects, dsturl, metadata, c.concurrency); err != nil {
+		err = &errorpkg.Error{
+			Op:  c.op,
+			Src: srcurls[0],
+			Dst: dsturl,
+			Err: err,
+		}
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	if c.storageOpts.DryRun {
+		addDryRun(c.op, size)
+	}
+
+	log.Info(log.InfoMessage{
+		Operation:   c.op,
+		Source:      srcurls[0],
+		Destination: dsturl,
+		Object: &storage.Object{
+			Size:         size,
+			StorageClass: c.storageClass,
+		},
+	})
+	return nil
+}
+
+// expandSources returns the source objects in the order of the arguments.
+// The objects matched by a wildcard are sorted by their keys.
+func (c Compose) expandSources(ctx context.Context, srcurls []*url.URL) ([]*storage.Object, error) {
+	var objects []*storage.Object
+	for _, srcurl := range srcurls {
+		client, err := storage.NewRemoteClient(ctx, srcurl, c.storageOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		if !srcurl.HasGlob() {
+			obj, err := client.Stat(ctx, srcurl)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %w", srcurl, err)
+			}
+			objects = append(objects, obj)
+			continue
+		}
+
+		var matched []*storage.Object
+		for obj := range client.List(ctx, srcurl, false) {
+			if obj.Err != nil {
+				return nil, obj.Err
+			}
+			if obj.Type.IsDir() {
+				continue
+			}
+			matched = append(matched, obj)
+		}
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].URL.Path < matched[j].URL.Path
+		})
+		objects = append(objects, matched...)
+	}
+	return objects, nil
+}
+
+func validateComposeCommand(c *cli.Context) error {
+	if c.Args().Len() < 2 {
+		return fmt.Errorf("expected source and destination arguments")
+	}
+
+	args := c.Args().Slice()
+	srcurls, err := newURLs(args[:len(args)-1]...)
+	if err != nil {
+		return err
+	}
+	for _, srcurl := range srcurls {
+		if !srcurl.IsRemote() {
+			return fmt.Errorf("source %q must be a remote object", srcurl)
+		}
+		if (srcurl.IsBucket() || srcurl.IsPrefix()) && !srcurl.HasGlob() {
+			return fmt.Errorf("source argument must contain wildcard character")
+		}
+	}
+
+	dsturl, err := url.New(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+	if !dsturl.IsRemote() || dsturl.IsBucket() || dsturl.IsPrefix() {
+		return fmt.Errorf("target %q must be a remote object", dsturl)
+	}
+	if dsturl.HasGlob() {
+		return fmt.Errorf("target %q can not contain glob characters", dsturl)
+	}
+
+	if c.Int("concurrency") < 1 {
+		return fmt.Errorf("concurrency must be a positive number")
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package command
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v2"
+)
+
+// configErr is the error of loading the configuration file. It is reported
+// once the logger is initialized.
+var configErr error
+
+// config is the configuration file of the default options. Top level keys
+// are global flags, keys with a command name hold the flags of the command
+// and profiles hold named sets of global flags, e.g.
+//
+//	numworkers: 64
+//	log: error
+//	cp:
+//	  part-size: 100
+//	profiles:
+//	  minio:
+//	    endpoint-url: http://localhost:9000
+type config struct {
+	options  map[string]interface{}
+	commands map[string]map[string]interface{}
+	profiles map[string]map[string]interface{}
+}
+
+// defaultConfigPath returns the path of the configuration file which is read
+// if --config flag is not given.
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, appName, "config.yaml")
+}
+
+// loadConfig reads the configuration file given with --config flag, or the
+// default one if it exists, and sets the defaults of the flags of the app
+// and its commands accordingly. Flags given in the command line override
+// the defaults.
+func loadConfig(args []string) error {
+	path, profile := configFlags(args)
+
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			if profile != "" {
+				return fmt.Errorf("config profile %q not found: no configuration file at %q", profile, path)
+			}
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := parseConfig(data)
+	if err != nil {
+		return fmt.Errorf("config %q: %v", path, err)
+	}
+
+	if err := cfg.apply(app.Flags, app.Commands, profile); err != nil {
+		return fmt.Errorf("config %q: %v", path, err)
+	}
+	return nil
+}
+
+// configFlags returns the values of --config and --config-profile flags
+// among the global flags of args, or their environment variables.
+func configFlags(args []string) (path, profile string) {
+	path, profile = os.Getenv("S5CMD_CONFIG"), os.Getenv("S5CMD_CONFIG_PROFILE")
+
+	takesValue := map[string]bool{}
+	for _, flag := range app.Flags {
+		if docflag, ok := flag.(cli.DocGenerationFlag); ok && docflag.TakesValue() {
+			for _, name := range flag.Names() {
+				takesValue[name] = true
+			}
+		}
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		// global flags end with the command name.
+		if !strings.HasPrefix(arg, "-") {
+			break
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if i := strings.Index(name, "="); i >= 0 {
+			name, value, hasValue = name[:i], name[i+1:], true
+		}
+
+		if !hasValue && takesValue[name] && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "config":
+			path = value
+		case "config-profile":
+			profile = value
+		}
+	}
+	return path, profile
+}
+
+func parseConfig(data []byte) (*config, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	cfg := &config{
+		options:  map[string]interface{}{},
+		commands: map[string]map[string]interface{}{},
+		profiles: map[string]map[string]interface{}{},
+	}
+
+	for key, value := range raw {
+		if key == "profiles" {
+			profiles, err := configOptions(key, value)
+			if err != nil {
+				return nil, err
+			}
+			for name, profile := range profiles {
+				options, err := configOptions("profile "+name, profile)
+				if err != nil {
+					return nil, err
+				}
+				cfg.profiles[name] = options
+			}
+			continue
+		}
+
+		if _, ok := value.(map[interface{}]interface{}); ok {
+			options, err := configOptions(key, value)
+			if err != nil {
+				return nil, err
+			}
+			cfg.commands[key] = options
+			continue
+		}
+
+		cfg.options[key] = value
+	}
+	return cfg, nil
+}
+
+// configOptions converts a YAML mapping to options.
+func configOptions(section string, value interface{}) (map[string]interface{}, error) {
+	mapping, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%q must be a mapping", section)
+	}
+
+	options := make(map[string]interface{}, len(mapping))
+	for k, v := range mapping {
+		options[fmt.Sprint(k)] = v
+	}
+	return options, nil
+}
+
+// apply sets the defaults of the flags. Options of the profile override the
+// top level options.
+func (cfg *config) apply(flags []cli.Flag, commands []*cli.Command, profile string) error {
+	if err := setFlagDefaults(flags, cfg.options); err != nil {
+		return err
+	}
+
+	if profile != "" {
+		options, ok := cfg.profiles[profile]
+		if !ok {
+			return fmt.Errorf("profile %q not found", profile)
+		}
+		if err := setFlagDefaults(flags, options); err != nil {
+			return fmt.Errorf("profile %q: %v", profile, err)
+		}
+	}
+
+	for name, options := range cfg.commands {
+		cmd := lookupCommand(commands, name)
+		if cmd == nil {
+			return fmt.Errorf("unknown command %q", name)
+		}
+		if err := setFlagDefaults(cmd.Flags, options); err != nil {
+			return fmt.Errorf("command %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func lookupCommand(commands []*cli.Command, name string) *cli.Command {
+	for _, cmd := range commands {
+		if cmd.HasName(name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+func lookupFlag(flags []cli.Flag, name string) cli.Flag {
+	for _, flag := range flags {
+		for _, flagname := range flag.Names() {
+			if flagname == name {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+func setFlagDefaults(flags []cli.Flag, options map[string]interface{}) error {
+	for name, value := range options {
+		flag := lookupFlag(flags, name)
+		if flag == nil {
+			return fmt.Errorf("unknown option %q", name)
+		}
+		if err := setFlagDefault(flag, value); err != nil {
+			return fmt.Errorf("option %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// setFlagDefault sets the default value of the flag.
+func setFlagDefault(flag cli.Flag, value interface{}) error {
+	s := fmt.Sprint(value)
+
+	switch f := flag.(type) {
+	case *cli.BoolFlag:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		f.Value = v
+	case *cli.IntFlag:
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		f.Value = v
+	case *cli.Int64Flag:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		f.Value = v
+	case *cli.StringFlag:
+		f.Value = s
+	case *cli.DurationFlag:
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		f.Value = v
+	case *cli.StringSliceFlag:
+		var values []string
+		if list, ok := value.([]interface{}); ok {
+			for _, v := range list {
+				values = append(values, fmt.Sprint(v))
+			}
+		} else {
+			values = append(values, s)
+		}
+		f.Value = cli.NewStringSlice(values...)
+	default:
+		return fmt.Errorf("option can not be set in configuration")
+	}
+	return nil
+}
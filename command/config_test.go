@@ -0,0 +1,112 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`
+numworkers: 64
+endpoint-url: https://s3.example.com
+progress: 10s
+cp:
+  part-size: 100
+  c: 20
+profiles:
+  minio:
+    endpoint-url: http://localhost:9000
+    no-verify-ssl: true
+`)
+
+	workers := &cli.IntFlag{Name: "numworkers", Value: 256}
+	endpoint := &cli.StringFlag{Name: "endpoint-url"}
+	noVerifySSL := &cli.BoolFlag{Name: "no-verify-ssl"}
+	progress := &cli.DurationFlag{Name: "progress"}
+	partSize := &cli.IntFlag{Name: "part-size", Value: 50}
+	concurrency := &cli.IntFlag{Name: "concurrency", Aliases: []string{"c"}, Value: 5}
+
+	flags := []cli.Flag{workers, endpoint, noVerifySSL, progress}
+	commands := []*cli.Command{
+		{Name: "cp", Flags: []cli.Flag{partSize, concurrency}},
+	}
+
+	cfg, err := parseConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := cfg.apply(flags, commands, "minio"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if workers.Value != 64 {
+		t.Errorf("numworkers = %v, expected 64", workers.Value)
+	}
+	if endpoint.Value != "http://localhost:9000" {
+		t.Errorf("endpoint-url = %v, expected the one of the profile", endpoint.Value)
+	}
+	if !noVerifySSL.Value {
+		t.Errorf("no-verify-ssl is not set by the profile")
+	}
+	if progress.Value != 10*time.Second {
+		t.Errorf("progress = %v, expected 10s", progress.Value)
+	}
+	if partSize.Value != 100 {
+		t.Errorf("part-size = %v, expected 100", partSize.Value)
+	}
+	if concurrency.Value != 20 {
+		t.Errorf("concurrency = %v, expected 20 set by its alias", concurrency.Value)
+	}
+
+	if err := cfg.apply(flags, commands, "missing"); err == nil {
+		t.Errorf("expected error for missing profile")
+	}
+}
+
+func TestConfigFlags(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		args            []string
+		expectedPath    string
+		expectedProfile string
+	}{
+		{
+			name: "no_config",
+			args: []string{"s5cmd", "ls"},
+		},
+		{
+			name:            "separate_values",
+			args:            []string{"s5cmd", "--numworkers", "8", "--config", "a.yaml", "--config-profile", "p", "ls"},
+			expectedPath:    "a.yaml",
+			expectedProfile: "p",
+		},
+		{
+			name:         "inline_value",
+			args:         []string{"s5cmd", "--config=a.yaml", "ls"},
+			expectedPath: "a.yaml",
+		},
+		{
+			name: "command_flag",
+			args: []string{"s5cmd", "cp", "--config", "a.yaml", "src", "dst"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, profile := configFlags(tc.args)
+			if path != tc.expectedPath || profile != tc.expectedProfile {
+				t.Errorf("configFlags() = %q, %q, expected %q, %q", path, profile, tc.expectedPath, tc.expectedProfile)
+			}
+		})
+	}
+}
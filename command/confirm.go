@@ -0,0 +1,186 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/storage"
+)
+
+const (
+	// confirmCountLimit is the number of objects counted before asking for a
+	// confirmation. Listing of more objects is not waited for.
+	confirmCountLimit = 1000
+
+	// confirmSampleSize is the number of objects shown in a confirmation.
+	confirmSampleSize = 5
+)
+
+// errNotConfirmed is the error of the operations which are not confirmed by
+// the user.
+var errNotConfirmed = fmt.Errorf("operation is not confirmed")
+
+// promptInput and promptOutput are the terminal the confirmations are asked
+// on.
+var (
+	promptInput  io.Reader = os.Stdin
+	promptOutput io.Writer = os.Stderr
+)
+
+// promptsDisabled is set if the commands can not ask for a confirmation, e.g.
+// they are run by a run command.
+var promptsDisabled int32
+
+func disablePrompts() {
+	atomic.StoreInt32(&promptsDisabled, 1)
+}
+
+// shouldConfirm reports whether the command asks for a confirmation before a
+// destructive operation. Commands are not confirmed if they are run with
+// --assume-yes flag or their own --force flag, are not interactive, or are
+// dry runs.
+func shouldConfirm(c *cli.Context) bool {
+	if c.Bool("assume-yes") || c.Bool("force") || c.Bool("dry-run") {
+		return false
+	}
+	return isInteractive()
+}
+
+// isInteractive reports whether the user can be asked for a confirmation,
+// i.e. the standard input and the standard error are terminals.
+var isInteractive = func() bool {
+	if atomic.LoadInt32(&promptsDisabled) == 1 {
+		return false
+	}
+	return isTerminal(os.Stdin) && isTerminal(os.Stderr)
+}
+
+func isTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return st.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmObjects shows the number and a sample of the objects matched by the
+// command, and asks the user whether the command is run for them. Up to confirmCountLimit objects
+// are listed before asking. If the command is confirmed, the returned channel
+// has all of the objects of the given one.
+func confirmObjects(
+	ctx context.Context,
+	w io.Writer,
+	r io.Reader,
+	command string,
+	objch <-chan *storage.Object,
+) (<-chan *storage.Object, error) {
+	var (
+		buffered []*storage.Object
+		sample   []*storage.Object
+		count    int
+		more     bool
+	)
+	for object := range objch {
+		buffered = append(buffered, object)
+		if object.Err != nil || object.Type.IsDir() {
+			continue
+		}
+
+		count++
+		if len(sample) < confirmSampleSize {
+			sample = append(sample, object)
+		}
+		if count == confirmCountLimit {
+			more = true
+			break
+		}
+	}
+
+	// there is nothing to confirm.
+	if count == 0 {
+		return replayObjects(buffered, nil), nil
+	}
+
+	matches := fmt.Sprintf("%v objects", count)
+	switch {
+	case count == 1:
+		matches = "1 object"
+	case more:
+		matches = fmt.Sprintf("more than %v objects", count)
+	}
+	fmt.Fprintf(w, "%q matches %v:\n", command, matches)
+	for _, object := range sample {
+		fmt.Fprintf(w, "    %v\n", object.URL)
+	}
+	if count > len(sample) {
+		fmt.Fprintln(w, "    ...")
+	}
+	fmt.Fprint(w, "Are you sure? [y/N]: ")
+
+	answer, err := readAnswer(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+	default:
+		return nil, errNotConfirmed
+	}
+
+	if !more {
+		objch = nil
+	}
+	return replayObjects(buffered, objch), nil
+}
+
+// replayObjects sends the buffered objects, and then the remaining objects of
+// the channel if it is not nil.
+func replayObjects(buffered []*storage.Object, objch <-chan *storage.Object) <-chan *storage.Object {
+	out := make(chan *storage.Object)
+	go func() {
+		defer close(out)
+		for _, object := range buffered {
+			out <- object
+		}
+		if objch == nil {
+			return
+		}
+		for object := range objch {
+			out <- object
+		}
+	}()
+	return out
+}
+
+// readAnswer reads a line of the answer. The read is abandoned if the context
+// is canceled, e.g. the user interrupts the prompt.
+func readAnswer(ctx context.Context, r io.Reader) (string, error) {
+	type result struct {
+		answer string
+		err    error
+	}
+
+	resultch := make(chan result, 1)
+	go func() {
+		answer, err := bufio.NewReader(r).ReadString('\n')
+		if err == io.EOF {
+			err = nil
+		}
+		resultch <- result{answer: strings.TrimSpace(answer), err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case res := <-resultch:
+		return res.answer, res.err
+	}
+}
@@ -0,0 +1,270 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func listedObjects(t *testing.T, n int) <-chan *storage.Object {
+	t.Helper()
+
+	objch := make(chan *storage.Object)
+	go func() {
+		defer close(objch)
+		for i := 0; i < n; i++ {
+			u, err := url.New(fmt.Sprintf("s3://bucket/file%v.txt", i))
+			assert.NoError(t, err)
+			objch <- &storage.Object{URL: u}
+		}
+	}()
+	return objch
+}
+
+func collectObjects(objch <-chan *storage.Object) []string {
+	var keys []string
+	for object := range objch {
+		keys = append(keys, object.URL.String())
+	}
+	return keys
+}
+
+func TestConfirmObjects(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name           string
+		count          int
+		answer         string
+		expectedPrompt string
+		expectedErr    error
+	}{
+		{
+			name:   "confirmed",
+			count:  2,
+			answer: "y\n",
+			expectedPrompt: `"rm s3://bucket/*" matches 2 objects:
+    s3://bucket/file0.txt
+    s3://bucket/file1.txt
+Are you sure? [y/N]: `,
+		},
+		{
+			name:   "confirmed_more_than_limit",
+			count:  confirmCountLimit + 500,
+			answer: "yes\n",
+			expectedPrompt: `"rm s3://bucket/*" matches more than 1000 objects:
+    s3://bucket/file0.txt
+    s3://bucket/file1.txt
+    s3://bucket/file2.txt
+    s3://bucket/file3.txt
+    s3://bucket/file4.txt
+    ...
+Are you sure? [y/N]: `,
+		},
+		{
+			name:   "declined",
+			count:  1,
+			answer: "n\n",
+			expectedPrompt: `"rm s3://bucket/*" matches 1 object:
+    s3://bucket/file0.txt
+Are you sure? [y/N]: `,
+			expectedErr: errNotConfirmed,
+		},
+		{
+			name:   "declined_by_default",
+			count:  1,
+			answer: "\n",
+			expectedPrompt: `"rm s3://bucket/*" matches 1 object:
+    s3://bucket/file0.txt
+Are you sure? [y/N]: `,
+			expectedErr: errNotConfirmed,
+		},
+		{
+			name:  "no_objects",
+			count: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var prompt bytes.Buffer
+			objch, err := confirmObjects(
+				context.Background(),
+				&prompt,
+				strings.NewReader(tc.answer),
+				"rm s3://bucket/*",
+				listedObjects(t, tc.count),
+			)
+
+			assert.Equal(t, tc.expectedPrompt, prompt.String())
+			assert.Equal(t, tc.expectedErr, err)
+			if err != nil {
+				return
+			}
+
+			// all of the objects are sent after the confirmation.
+			keys := collectObjects(objch)
+			assert.Len(t, keys, tc.count)
+			if tc.count > 0 {
+				assert.Equal(t, "s3://bucket/file0.txt", keys[0])
+			}
+		})
+	}
+}
+
+func TestConfirmObjectsCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var prompt bytes.Buffer
+	_, err := confirmObjects(ctx, &prompt, blockingReader{}, "rm s3://bucket/*", listedObjects(t, 1))
+	assert.Equal(t, context.Canceled, err)
+}
+
+// blockingReader is a reader of a user who never answers.
+type blockingReader struct{}
+
+func (blockingReader) Read([]byte) (int, error) {
+	select {}
+}
+
+func TestShouldConfirmDelete(t *testing.T) {
+	// isInteractive is replaced, the test is not run in parallel.
+	interactive := isInteractive
+	defer func() { isInteractive = interactive }()
+
+	testcases := []struct {
+		name        string
+		args        []string
+		interactive bool
+		expected    bool
+	}{
+		{"wildcard", []string{"s3://bucket/*"}, true, true},
+		{"no_wildcard", []string{"s3://bucket/object"}, true, false},
+		{"not_interactive", []string{"s3://bucket/*"}, false, false},
+		{"assume_yes", []string{"--assume-yes", "s3://bucket/*"}, true, false},
+		{"force", []string{"--force", "s3://bucket/*"}, true, false},
+		{"dry_run", []string{"--dry-run", "s3://bucket/*"}, true, false},
+		{"move_wildcard", []string{"s3://bucket/*", "dir/"}, true, true},
+		{"move_force", []string{"--force", "s3://bucket/*", "dir/"}, true, false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			isInteractive = func() bool { return tc.interactive }
+
+			flagset := flag.NewFlagSet("rm", flag.ContinueOnError)
+			flagset.Bool("assume-yes", false, "")
+			flagset.Bool("force", false, "")
+			flagset.Bool("dry-run", false, "")
+			assert.NoError(t, flagset.Parse(tc.args))
+
+			ctx := cli.NewContext(app, flagset, nil)
+			assert.Equal(t, tc.expected, shouldConfirmDelete(ctx))
+		})
+	}
+}
+
+// answerPrompts replaces the terminal of the confirmations with the given
+// answer. The returned function restores it.
+func answerPrompts(answer string, prompt *bytes.Buffer) func() {
+	input, output := promptInput, promptOutput
+	promptInput, promptOutput = strings.NewReader(answer), prompt
+	return func() { promptInput, promptOutput = input, output }
+}
+
+// tempFiles creates a directory with the given files.
+func tempFiles(t *testing.T, names ...string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "s5cmd-confirm")
+	assert.NoError(t, err)
+	for _, name := range names {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("content"), 0644))
+	}
+	return dir
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestFindDeleteConfirmation(t *testing.T) {
+	log.Init("error", false)
+
+	dir := tempFiles(t, "a.txt", "b.txt")
+	defer os.RemoveAll(dir)
+
+	find := Find{
+		src:         filepath.Join(dir, "*"),
+		op:          "find",
+		fullCommand: "find --delete " + dir,
+		delete:      true,
+		confirm:     true,
+	}
+
+	// the objects are not deleted unless the command is confirmed.
+	var prompt bytes.Buffer
+	restore := answerPrompts("n\n", &prompt)
+	err := find.Run(context.Background())
+	restore()
+
+	assert.Equal(t, errNotConfirmed, err)
+	assert.Contains(t, prompt.String(), "matches 2 objects")
+	assert.True(t, fileExists(filepath.Join(dir, "a.txt")))
+	assert.True(t, fileExists(filepath.Join(dir, "b.txt")))
+
+	restore = answerPrompts("y\n", &prompt)
+	err = find.Run(context.Background())
+	restore()
+
+	assert.NoError(t, err)
+	assert.False(t, fileExists(filepath.Join(dir, "a.txt")))
+	assert.False(t, fileExists(filepath.Join(dir, "b.txt")))
+}
+
+func TestMoveConfirmation(t *testing.T) {
+	log.Init("error", false)
+
+	src := tempFiles(t, "a.txt", "b.txt")
+	defer os.RemoveAll(src)
+
+	move := Copy{
+		src:          filepath.Join(src, "*"),
+		dsts:         []string{"s3://bucket/prefix/"},
+		op:           "mv",
+		fullCommand:  "mv " + src + "/* s3://bucket/prefix/",
+		deleteSource: true,
+		confirm:      true,
+	}
+
+	// the sources are not moved unless the command is confirmed.
+	var prompt bytes.Buffer
+	restore := answerPrompts("n\n", &prompt)
+	err := move.Run(context.Background())
+	restore()
+
+	assert.Equal(t, errNotConfirmed, err)
+	assert.Contains(t, prompt.String(), "matches 2 objects")
+	assert.True(t, fileExists(filepath.Join(src, "a.txt")))
+	assert.True(t, fileExists(filepath.Join(src, "b.txt")))
+}
@@ -8,8 +8,11 @@ import (
 	"mime"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/urfave/cli/v2"
@@ -20,19 +23,23 @@ import (
 	"github.com/peak/s5cmd/parallel"
 	"github.com/peak/s5cmd/storage"
 	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
 )
 
 const (
 	defaultCopyConcurrency = 5
 	defaultPartSize        = 50 // MiB
 	megabytes              = 1024 * 1024
+
+	// maxUploadParts is the maximum number of parts of a multipart upload.
+	maxUploadParts = 10000
 )
 
 var copyHelpTemplate = `Name:
 	{{.HelpName}} - {{.Usage}}
 
 Usage:
-	{{.HelpName}} [options] source destination
+	{{.HelpName}} [options] source destination [destination...]
 
 Options:
 	{{range .VisibleFlags}}{{.}}
@@ -79,6 +86,70 @@ Examples:
 	
 	14. Force transfer of GLACIER objects with a prefix whether they are restored or not
 		> s5cmd {{.HelpName}} --force-glacier-transfer s3://bucket/prefix/* target-directory/
+
+	15. Copy S3 objects between accounts using different credentials for source and destination
+		> s5cmd {{.HelpName}} --source-profile account-a --destination-profile account-b s3://bucket/prefix/* s3://target-bucket/prefix/
+
+	16. Migrate S3 objects from an S3 compatible service to AWS S3
+		> s5cmd {{.HelpName}} --source-endpoint-url https://minio.example.com s3://bucket/prefix/* s3://target-bucket/prefix/
+
+	17. Mirror a file served over HTTPS to S3
+		> s5cmd {{.HelpName}} https://example.com/datasets/data.csv.gz s3://bucket/datasets/
+
+	18. Download S3 objects and make sure they are written to disk before they are reported
+		> s5cmd {{.HelpName}} --fsync s3://bucket/prefix/* target-directory/
+
+	19. Download disk images without allocating space for blocks of zeros
+		> s5cmd {{.HelpName}} --sparse s3://bucket/images/*.img target-directory/
+
+	20. Upload files with their modification times and restore them on download
+		> s5cmd {{.HelpName}} --preserve-timestamps dir/ s3://bucket/prefix/
+		> s5cmd {{.HelpName}} --preserve-timestamps s3://bucket/prefix/* target-directory/
+
+	21. Back up a directory with its permissions and ownership and restore it as root
+		> s5cmd {{.HelpName}} --preserve-permissions /etc/ s3://bucket/backup/etc/
+		> sudo s5cmd {{.HelpName}} --preserve-permissions s3://bucket/backup/etc/* /etc/
+
+	22. Upload symbolic links as links and recreate them on download
+		> s5cmd {{.HelpName}} --preserve-symlinks dir/ s3://bucket/prefix/
+		> s5cmd {{.HelpName}} --preserve-symlinks s3://bucket/prefix/* target-directory/
+
+	23. Upload a directory with its empty sub-directories and recreate them on download
+		> s5cmd {{.HelpName}} --keep-empty-dirs dir/ s3://bucket/prefix/
+		> s5cmd {{.HelpName}} --keep-empty-dirs s3://bucket/prefix/* target-directory/
+
+	24. Download objects without their first path component, 's3://bucket/logs/2020/01/a.gz' is saved as 'target-directory/01/a.gz'
+		> s5cmd {{.HelpName}} --strip-components 1 s3://bucket/logs/* target-directory/
+
+	25. Upload files and record the uploaded objects to a CSV file
+		> s5cmd {{.HelpName}} --manifest uploaded.csv dir/ s3://bucket/prefix/
+
+	26. Download objects and skip the ones downloaded by a previous, interrupted run of the same command
+		> s5cmd {{.HelpName}} --journal download.journal s3://bucket/prefix/* target-directory/
+
+	27. Upload files and run a command for each uploaded and failed file
+		> s5cmd {{.HelpName}} --on-success 'echo "$S5CMD_DESTINATION $S5CMD_SIZE" >> done.txt' --on-failure 'echo "$S5CMD_SOURCE: $S5CMD_ERROR" >&2' dir/ s3://bucket/prefix/
+
+	28. Download the smallest objects first
+		> s5cmd {{.HelpName}} --scheduling smallest-first s3://bucket/prefix/* target-directory/
+
+	29. Upload only the files whose contents differ from the existing objects, so a rerun completes an interrupted upload
+		> s5cmd {{.HelpName}} --if-content-differ dir/ s3://bucket/prefix/
+
+	30. Upload a file only if the object doesn't exist, even if it is uploaded concurrently by another producer
+		> s5cmd {{.HelpName}} --if-none-match report.csv s3://bucket/reports/
+
+	31. Fix the headers of existing objects in place by copying them onto themselves
+		> s5cmd {{.HelpName}} --metadata-directive REPLACE --content-type text/html --cache-control max-age=3600 s3://bucket/site/*.html s3://bucket/site/
+
+	32. Upload files to multiple buckets, reading each file once
+		> s5cmd {{.HelpName}} dir/ s3://bucket/prefix/ s3://bucket2/prefix/ s3://bucket3/prefix/
+
+	33. Download only the first megabyte of a large object
+		> s5cmd {{.HelpName}} --range 0-1048575 s3://bucket/logs/huge.log sample.log
+
+	34. Search the compressed logs for errors without writing them to the disk
+		> s5cmd {{.HelpName}} --exec "zgrep ERROR" "s3://bucket/logs/*.gz"
 `
 
 var copyCommandFlags = []cli.Flag{
@@ -97,15 +168,67 @@ var copyCommandFlags = []cli.Flag{
 		Aliases: []string{"u"},
 		Usage:   "only overwrite destination if source modtime is newer",
 	},
+	&cli.BoolFlag{
+		Name:  "if-content-differ",
+		Usage: "only overwrite destination if size or checksum (ETag) differs",
+	},
+	&cli.BoolFlag{
+		Name:  "if-none-match",
+		Usage: "only upload if the destination doesn't exist at the time of the write, with a conditional write of S3",
+	},
 	&cli.BoolFlag{
 		Name:    "flatten",
 		Aliases: []string{"f"},
 		Usage:   "flatten directory structure of source, starting from the first wildcard",
 	},
+	&cli.StringFlag{
+		Name:  "files-from",
+		Usage: "read keys or paths relative to source, one per line, from given file instead of listing the source. use - for standard input",
+	},
+	&cli.StringFlag{
+		Name:  "destinations-file",
+		Usage: "read additional destinations, one per line, from given file to copy the source to all of them",
+	},
+	&cli.StringFlag{
+		Name:  "inventory",
+		Usage: "read keys of source bucket from the S3 Inventory report with given manifest.json url instead of listing the bucket",
+	},
+	&cli.StringFlag{
+		Name:  "manifest",
+		Usage: "record source, destination, size, etag and timestamp of each transferred object to given CSV file",
+	},
+	&cli.StringFlag{
+		Name:  "journal",
+		Usage: "record completed transfers to given file and skip the ones recorded by previous runs",
+	},
+	&cli.StringFlag{
+		Name:  "on-success",
+		Usage: "run given shell command after each successful transfer, the transfer is described with S5CMD_* environment variables",
+	},
+	&cli.StringFlag{
+		Name:  "on-failure",
+		Usage: "run given shell command after each failed transfer, the transfer is described with S5CMD_* environment variables",
+	},
+	&cli.StringFlag{
+		Name:  "exec",
+		Usage: "stream each remote object into the standard input of given shell command instead of downloading it, no destination is given",
+	},
+	&cli.IntFlag{
+		Name:  "strip-components",
+		Usage: "strip given number of leading path components of source, starting from the first wildcard",
+	},
+	&cli.BoolFlag{
+		Name:  "follow-symlinks",
+		Usage: "follow symbolic links and upload their targets, default behavior",
+	},
 	&cli.BoolFlag{
 		Name:  "no-follow-symlinks",
 		Usage: "do not follow symbolic links",
 	},
+	&cli.BoolFlag{
+		Name:  "preserve-symlinks",
+		Usage: "upload symbolic links as objects with the link target in metadata and recreate them on download",
+	},
 	&cli.StringFlag{
 		Name:  "storage-class",
 		Usage: "set storage class for target ('STANDARD','REDUCED_REDUNDANCY','GLACIER','STANDARD_IA','ONEZONE_IA','INTELLIGENT_TIERING','DEEP_ARCHIVE')",
@@ -115,12 +238,14 @@ var copyCommandFlags = []cli.Flag{
 		Aliases: []string{"c"},
 		Value:   defaultCopyConcurrency,
 		Usage:   "number of concurrent parts transferred between host and remote server",
+		EnvVars: []string{"S5CMD_CONCURRENCY"},
 	},
-	&cli.IntFlag{
+	&cli.StringFlag{
 		Name:    "part-size",
 		Aliases: []string{"p"},
-		Value:   defaultPartSize,
-		Usage:   "size of each part transferred between host and remote server, in MiB",
+		Value:   strconv.Itoa(defaultPartSize),
+		Usage:   "size of each part transferred between host and remote server, in MiB unless a unit is given, e.g. 100M",
+		EnvVars: []string{"S5CMD_PART_SIZE"},
 	},
 	&cli.StringFlag{
 		Name:  "sse",
@@ -134,10 +259,67 @@ var copyCommandFlags = []cli.Flag{
 		Name:  "acl",
 		Usage: "set acl for target: defines granted accesses and their types on different accounts/groups",
 	},
+	&cli.StringFlag{
+		Name:  "content-type",
+		Usage: "set the content type of the destination objects; the one of the source is kept on copies if not specified",
+	},
+	&cli.StringFlag{
+		Name:  "cache-control",
+		Usage: "set the cache control of the destination objects; the one of the source is kept on copies if not specified",
+	},
+	&cli.StringSliceFlag{
+		Name:  "metadata",
+		Usage: "set the user metadata of the destination objects in key=value format, replacing the ones of the source on copies",
+	},
+	&cli.StringSliceFlag{
+		Name:  "tag",
+		Usage: "set the tags of the destination objects in key=value format, replacing the ones of the source on copies",
+	},
+	&cli.StringFlag{
+		Name:  "metadata-directive",
+		Usage: "copy the metadata of the source objects, or replace them to edit the objects in place on copies between S3 objects: (COPY, REPLACE)",
+	},
+	&cli.BoolFlag{
+		Name:  "copy-acl",
+		Usage: "copy the access control lists of the source objects to the destination objects on copies between S3 objects",
+	},
 	&cli.BoolFlag{
 		Name:  "force-glacier-transfer",
 		Usage: "force transfer of GLACIER objects whether they are restored or not",
 	},
+	&cli.BoolFlag{
+		Name:  "preserve-timestamps",
+		Usage: "store modification times of uploaded files in object metadata and restore them on download",
+	},
+	&cli.BoolFlag{
+		Name:  "preserve-permissions",
+		Usage: "store permissions and ownership of uploaded files in object metadata and restore them on download; ownership is only restored when running as root",
+	},
+	&cli.BoolFlag{
+		Name:  "numeric-ids",
+		Usage: "restore ownership with the stored user and group ids instead of mapping the user and group names",
+	},
+	&cli.BoolFlag{
+		Name:  "keep-empty-dirs",
+		Usage: "upload empty directories as zero-byte objects with a trailing slash and create them on download",
+	},
+	&cli.BoolFlag{
+		Name:  "sparse",
+		Usage: "skip writing blocks of zeros to downloaded files and skip reading holes of uploaded files",
+	},
+	&cli.StringFlag{
+		Name:  "range",
+		Usage: "download only the given byte range of the objects, e.g. 0-1023, 1024- or -1024 for the last 1024 bytes",
+	},
+	&cli.BoolFlag{
+		Name:  "fsync",
+		Usage: "flush each downloaded file to durable storage before reporting it as completed",
+	},
+	&cli.StringFlag{
+		Name:  "scheduling",
+		Value: scheduleFIFO,
+		Usage: "order of transfers of listed objects: (fifo, smallest-first, largest-first)",
+	},
 	&cli.StringFlag{
 		Name:  "source-region",
 		Usage: "set the region of source bucket; the region of the source bucket will be automatically discovered if --source-region is not specified",
@@ -146,6 +328,22 @@ var copyCommandFlags = []cli.Flag{
 		Name:  "destination-region",
 		Usage: "set the region of destination bucket: the region of the destination bucket will be automatically discovered if --destination-region is not specified",
 	},
+	&cli.StringFlag{
+		Name:  "source-endpoint-url",
+		Usage: "override S3 host of the source for custom services; --endpoint-url is used if not specified",
+	},
+	&cli.StringFlag{
+		Name:  "destination-endpoint-url",
+		Usage: "override S3 host of the destination for custom services; --endpoint-url is used if not specified",
+	},
+	&cli.StringFlag{
+		Name:  "source-profile",
+		Usage: "use the given shared config profile to access the source bucket",
+	},
+	&cli.StringFlag{
+		Name:  "destination-profile",
+		Usage: "use the given shared config profile to access the destination bucket",
+	},
 }
 
 var copyCommand = &cli.Command{
@@ -164,28 +362,71 @@ var copyCommand = &cli.Command{
 	Action: func(c *cli.Context) (err error) {
 		defer stat.Collect(c.Command.FullName(), &err)()
 
+		// validated in Before
+		partSize, _ := parsePartSize(c.String("part-size"))
+		userMetadata, _ := parseKeyValues("metadata", c.StringSlice("metadata"))
+		tags, _ := parseKeyValues("tag", c.StringSlice("tag"))
+		dsts, _ := copyDestinations(c)
+		byteRange, _ := parseByteRange(c.String("range"))
+
 		return Copy{
 			src:          c.Args().Get(0),
-			dst:          c.Args().Get(1),
+			dsts:         dsts,
 			op:           c.Command.Name,
 			fullCommand:  givenCommand(c),
+			jobFlags:     jobFlags(c),
 			deleteSource: false, // don't delete source
 			// flags
 			noClobber:            c.Bool("no-clobber"),
 			ifSizeDiffer:         c.Bool("if-size-differ"),
 			ifSourceNewer:        c.Bool("if-source-newer"),
+			ifContentDiffer:      c.Bool("if-content-differ"),
+			ifNoneMatch:          c.Bool("if-none-match"),
 			flatten:              c.Bool("flatten"),
+			stripComponents:      c.Int("strip-components"),
+			filesFrom:            c.String("files-from"),
+			inventory:            c.String("inventory"),
+			manifest:             c.String("manifest"),
+			journal:              c.String("journal"),
+			checkpoint:           commandCheckpointFrom(c.Context),
+			onSuccess:            c.String("on-success"),
+			onFailure:            c.String("on-failure"),
+			exec:                 c.String("exec"),
 			followSymlinks:       !c.Bool("no-follow-symlinks"),
 			storageClass:         storage.StorageClass(c.String("storage-class")),
 			concurrency:          c.Int("concurrency"),
-			partSize:             c.Int64("part-size") * megabytes,
+			partSize:             partSize,
 			encryptionMethod:     c.String("sse"),
 			encryptionKeyID:      c.String("sse-kms-key-id"),
 			acl:                  c.String("acl"),
+			contentType:          c.String("content-type"),
+			cacheControl:         c.String("cache-control"),
+			metadataDirective:    c.String("metadata-directive"),
+			userMetadata:         userMetadata,
+			tags:                 tags,
+			copyACL:              c.Bool("copy-acl"),
 			forceGlacierTransfer: c.Bool("force-glacier-transfer"),
+			preserveTimestamps:   c.Bool("preserve-timestamps"),
+			preservePermissions:  c.Bool("preserve-permissions"),
+			preserveSymlinks:     c.Bool("preserve-symlinks"),
+			keepEmptyDirs:        c.Bool("keep-empty-dirs"),
+			numericIDs:           c.Bool("numeric-ids"),
+			sparse:               c.Bool("sparse"),
+			byteRange:            byteRange,
+			fsync:                c.Bool("fsync"),
+			scheduling:           c.String("scheduling"),
+			jobTimeout:           c.Duration("job-timeout"),
+			retryFailed:          c.Int("retry-failed"),
+			retryFailedDelay:     c.Duration("retry-failed-delay"),
 			// region settings
 			srcRegion: c.String("source-region"),
 			dstRegion: c.String("destination-region"),
+			// endpoint settings
+			srcEndpoint: c.String("source-endpoint-url"),
+			dstEndpoint: c.String("destination-endpoint-url"),
+			// credential settings
+			srcProfile: c.String("source-profile"),
+			dstProfile: c.String("destination-profile"),
 
 			storageOpts: NewStorageOpts(c),
 		}.Run(c.Context)
@@ -195,9 +436,10 @@ var copyCommand = &cli.Command{
 // Copy holds copy operation flags and states.
 type Copy struct {
 	src         string
-	dst         string
+	dsts        []string
 	op          string
 	fullCommand string
+	jobFlags    []string
 
 	deleteSource bool
 
@@ -205,22 +447,78 @@ type Copy struct {
 	noClobber            bool
 	ifSizeDiffer         bool
 	ifSourceNewer        bool
+	ifContentDiffer      bool
+	ifNoneMatch          bool
 	flatten              bool
+	stripComponents      int
+	filesFrom            string
+	inventory            string
+	manifest             string
+	journal              string
+	onSuccess            string
+	onFailure            string
+	exec                 string
 	followSymlinks       bool
 	storageClass         storage.StorageClass
 	encryptionMethod     string
 	encryptionKeyID      string
 	acl                  string
+	contentType          string
+	cacheControl         string
+	metadataDirective    string
+	userMetadata         map[string]string
+	tags                 map[string]string
+	copyACL              bool
 	forceGlacierTransfer bool
+	preserveTimestamps   bool
+	preservePermissions  bool
+	preserveSymlinks     bool
+	keepEmptyDirs        bool
+	numericIDs           bool
+	sparse               bool
+	byteRange            string
+	fsync                bool
+	scheduling           string
+	jobTimeout           time.Duration
+	retryFailed          int
+	retryFailedDelay     time.Duration
+
+	// confirm is set if the sources deleted by mv are confirmed by the user.
+	confirm bool
 
 	// region settings
 	srcRegion string
 	dstRegion string
 
+	// endpoint settings
+	srcEndpoint string
+	dstEndpoint string
+
+	// credential settings
+	srcProfile string
+	dstProfile string
+
 	// s3 options
 	concurrency int
 	partSize    int64
 	storageOpts storage.Options
+
+	// transfers records the transferred objects if --manifest flag is
+	// given. srcObject is the listed object of a task, reported along with
+	// the transfer.
+	transfers *transferManifest
+	srcObject *storage.Object
+
+	// completed records the completed transfers if --journal flag is given.
+	completed *transferJournal
+
+	// checkpoint records the completed transfers if the command is run with
+	// --resume flag of run command.
+	checkpoint *commandCheckpoint
+
+	// failed records the transfers failed with transient errors to retry them
+	// if --retry-failed flag is given.
+	failed *failedJobs
 }
 
 const fdlimitWarning = `
@@ -231,33 +529,77 @@ increase the open file limit or try to decrease the number of workers with
 
 // Run starts copying given source objects to destination.
 func (c Copy) Run(ctx context.Context) error {
-	srcurl, err := url.New(c.src)
+	srcurl, err := newSourceURL(c.src)
 	if err != nil {
 		printError(c.fullCommand, c.op, err)
 		return err
 	}
 
-	dsturl, err := url.New(c.dst)
-	if err != nil {
-		printError(c.fullCommand, c.op, err)
-		return err
+	dsturls := make([]*url.URL, 0, len(c.dsts))
+	for _, dst := range c.dsts {
+		dsturl, err := url.New(dst)
+		if err != nil {
+			printError(c.fullCommand, c.op, err)
+			return err
+		}
+		dsturls = append(dsturls, dsturl)
 	}
 
-	// override source region if set
-	if c.srcRegion != "" {
-		c.storageOpts.SetRegion(c.srcRegion)
-	}
-	client, err := storage.NewClient(ctx, srcurl, c.storageOpts)
+	client, err := storage.NewClient(ctx, srcurl, c.srcStorageOpts())
 	if err != nil {
 		printError(c.fullCommand, c.op, err)
 		return err
 	}
 
-	objch, err := expandSource(ctx, client, c.followSymlinks, srcurl)
+	objch, err := c.expandSource(ctx, client, srcurl)
 	if err != nil {
 		printError(c.fullCommand, c.op, err)
 		return err
 	}
+	objch = scheduleObjects(objch, c.scheduling)
+
+	// the sources of mv are deleted, so the matches of the wildcards are
+	// confirmed like the ones of rm.
+	if c.confirm {
+		objch, err = confirmObjects(ctx, promptOutput, promptInput, c.fullCommand, objch)
+		if err != nil {
+			printError(c.fullCommand, c.op, err)
+			return err
+		}
+	}
+
+	// the manifest and the journal are kept open until the failed transfers
+	// are retried, which might be after the command returns in run mode.
+	var closers []io.Closer
+	defer func() {
+		for _, closer := range closers {
+			closer.Close()
+		}
+	}()
+
+	if c.manifest != "" {
+		c.transfers, err = newTransferManifest(c.manifest)
+		if err != nil {
+			printError(c.fullCommand, c.op, err)
+			return err
+		}
+		closers = append(closers, c.transfers)
+	}
+
+	if c.journal != "" {
+		c.completed, err = openTransferJournal(c.journal)
+		if err != nil {
+			printError(c.fullCommand, c.op, err)
+			return err
+		}
+		closers = append(closers, c.completed)
+	}
+
+	// a source is completed once it is transferred to all of the
+	// destinations, so the transfers of a fan-out are not checkpointed.
+	if len(dsturls) > 1 {
+		c.checkpoint = nil
+	}
 
 	waiter := parallel.NewWaiter()
 
@@ -266,28 +608,82 @@ func (c Copy) Run(ctx context.Context) error {
 		errDoneCh = make(chan bool)
 	)
 
-	go func() {
-		defer close(errDoneCh)
-		for err := range waiter.Err() {
-			if strings.Contains(err.Error(), "too many open files") {
-				fmt.Println(strings.TrimSpace(fdlimitWarning))
-				fmt.Printf("ERROR %v\n", err)
+	handleError := func(err error) {
+		if strings.Contains(err.Error(), "too many open files") {
+			fmt.Println(strings.TrimSpace(fdlimitWarning))
+			fmt.Printf("ERROR %v\n", err)
 
-				os.Exit(1)
-			}
+			os.Exit(1)
+		}
+		printError(c.fullCommand, c.op, err)
+		merror = multierror.Append(merror, err)
+
+		if err := recordFailedJobErrors(c.op, c.jobFlags, err); err != nil {
 			printError(c.fullCommand, c.op, err)
 			merror = multierror.Append(merror, err)
 		}
+
+		if c.onFailure != "" && !errorpkg.IsCancelation(err) {
+			// the errors of a fan-out are reported for each destination.
+			errs := []error{err}
+			if merr, ok := err.(*multierror.Error); ok {
+				errs = merr.Errors
+			}
+			for _, err := range errs {
+				if err := runHook(ctx, c.onFailure, failureJob(c.op, err)); err != nil {
+					printError(c.fullCommand, c.op, err)
+					merror = multierror.Append(merror, err)
+				}
+			}
+		}
+	}
+
+	go func() {
+		defer close(errDoneCh)
+		for err := range waiter.Err() {
+			handleError(err)
+		}
 	}()
 
-	isBatch := srcurl.HasGlob()
-	if !isBatch && !srcurl.IsRemote() {
+	if c.retryFailed > 0 {
+		c.failed = newFailedJobs(handleError)
+	}
+
+	isBatch := srcurl.HasGlob() || c.filesFrom != ""
+	if !isBatch && !srcurl.IsRemote() && !srcurl.IsHTTP() {
 		obj, _ := client.Stat(ctx, srcurl)
 		isBatch = obj != nil && obj.Type.IsDir()
 	}
 
+	// the destination objects of a batch are resolved with a listing.
+	for _, dsturl := range dsturls {
+		if isBatch && dsturl.IsRemote() && c.hasOverrideConditions() {
+			c.prefetchDestination(ctx, dsturl)
+		}
+	}
+
 	for object := range objch {
-		if object.Type.IsDir() || errorpkg.IsCancelation(object.Err) {
+		if errorpkg.IsCancelation(object.Err) {
+			continue
+		}
+
+		// objects without enough path components to strip are skipped, like
+		// tar does.
+		if object.Err == nil && isBatch && !c.flatten {
+			if _, ok := stripPathComponents(object.URL.Relative(), c.stripComponents); !ok {
+				continue
+			}
+		}
+
+		if object.Type.IsDir() {
+			if c.keepEmptyDirs && !c.flatten && isEmptyDir(object) {
+				c := c
+				c.srcObject = object
+				for _, dsturl := range dsturls {
+					task := c.prepareEmptyDirTask(ctx, object, dsturl, isBatch)
+					c.runTask(task, waiter)
+				}
+			}
 			continue
 		}
 
@@ -303,28 +699,161 @@ func (c Copy) Run(ctx context.Context) error {
 		}
 
 		srcurl := object.URL
-		var task parallel.Task
+		if c.isCompleted(srcurl) {
+			var dsturl *url.URL
+			if len(dsturls) > 0 {
+				dsturl = dsturls[0]
+			}
+			printDebug(c.op, srcurl, dsturl, errorpkg.ErrObjectInJournal)
+			continue
+		}
+
+		c := c
+		c.srcObject = object
 
-		switch {
-		case srcurl.Type == dsturl.Type: // local->local or remote->remote
-			task = c.prepareCopyTask(ctx, srcurl, dsturl, isBatch)
-		case srcurl.IsRemote(): // remote->local
-			task = c.prepareDownloadTask(ctx, srcurl, dsturl, isBatch)
-		case dsturl.IsRemote(): // local->remote
-			task = c.prepareUploadTask(ctx, srcurl, dsturl, isBatch)
-		default:
-			panic("unexpected src-dst pair")
+		if c.exec != "" {
+			task := c.prepareExecTask(ctx, srcurl)
+			c.runTask(task, waiter)
+			continue
 		}
 
-		parallel.Run(task, waiter)
+		// a local file is read once for all of the destinations.
+		if len(dsturls) > 1 && !srcurl.IsRemote() && !srcurl.IsHTTP() {
+			task := c.prepareFanoutUploadTask(ctx, srcurl, dsturls, isBatch)
+			c.runTask(task, waiter)
+			continue
+		}
+
+		for _, dsturl := range dsturls {
+			var task parallel.Task
+
+			switch {
+			case srcurl.IsHTTP(): // http->remote
+				task = c.prepareHTTPUploadTask(ctx, srcurl, dsturl)
+			case srcurl.Type == dsturl.Type: // local->local or remote->remote
+				task = c.prepareCopyTask(ctx, srcurl, dsturl, isBatch)
+			case srcurl.IsRemote(): // remote->local
+				task = c.prepareDownloadTask(ctx, srcurl, dsturl, isBatch)
+			case dsturl.IsRemote(): // local->remote
+				task = c.prepareUploadTask(ctx, srcurl, dsturl, isBatch)
+			default:
+				panic("unexpected src-dst pair")
+			}
+
+			c.runTask(task, waiter)
+		}
 	}
 
 	waiter.Wait()
 	<-errDoneCh
 
+	if c.failed != nil && c.failed.len() > 0 {
+		// a run retries the failed transfers of its commands once all of
+		// them are completed.
+		if d := deferredRetryFrom(ctx); d != nil {
+			d.failed, d.closers = c.failed, closers
+			closers = nil
+			return merror
+		}
+		retryFailedJobs(ctx, []*failedJobs{c.failed}, c.retryFailed, c.retryFailedDelay)
+	}
+
 	return merror
 }
 
+// runTask runs the task of a transfer. The task is recorded to be retried if
+// it fails with a transient error and --retry-failed flag is given.
+func (c Copy) runTask(task parallel.Task, waiter *parallel.Waiter) {
+	if c.failed != nil {
+		task = c.failed.wrap(task)
+	}
+	parallel.Run(task, waiter)
+}
+
+// isCompleted reports whether the transfer of the source is completed by a
+// previous run, as recorded to the journal or to the checkpoint of the run.
+func (c Copy) isCompleted(srcurl *url.URL) bool {
+	if c.completed != nil && c.completed.has(srcurl.String()) {
+		return true
+	}
+	return c.checkpoint != nil && c.checkpoint.has(srcurl.String())
+}
+
+// report logs a completed transfer, records it to the manifest, the journal
+// and the checkpoint, and runs the --on-success hook.
+func (c Copy) report(ctx context.Context, msg log.InfoMessage) error {
+	log.Info(msg)
+	if obj, ok := msg.Object.(*storage.Object); ok && obj != nil {
+		stat.AddBytes(c.op, obj.Size, transferDirection(msg.Source, msg.Destination))
+	}
+	if c.storageOpts.DryRun {
+		var size int64
+		if c.srcObject != nil && !c.srcObject.Type.IsDir() {
+			size = c.srcObject.Size
+		}
+		addDryRun(c.op, size)
+	}
+	if c.transfers != nil {
+		if err := c.transfers.add(msg, c.srcObject); err != nil {
+			return err
+		}
+	}
+	if c.completed != nil {
+		if err := c.completed.add(msg.Source.String()); err != nil {
+			return err
+		}
+	}
+	if c.checkpoint != nil {
+		if err := c.checkpoint.add(msg.Source.String()); err != nil {
+			return err
+		}
+	}
+	if c.onSuccess != "" {
+		return runHook(ctx, c.onSuccess, successJob(msg))
+	}
+	return nil
+}
+
+// transferDirection returns the direction of a transfer between given urls.
+func transferDirection(src, dst *url.URL) stat.Direction {
+	switch {
+	case src.IsRemote() && (dst == nil || !dst.IsRemote()):
+		return stat.Download
+	case dst.IsRemote() && !src.IsRemote():
+		return stat.Upload
+	default:
+		return stat.Copy
+	}
+}
+
+// expandSource returns the objects to copy, which are either read from the
+// file given with --files-from flag, read from an inventory report or found
+// by walking the source.
+func (c Copy) expandSource(ctx context.Context, client storage.Storage, srcurl *url.URL) (<-chan *storage.Object, error) {
+	if c.inventory != "" {
+		return expandInventory(ctx, client, c.inventory, srcurl)
+	}
+
+	if c.filesFrom == "" {
+		return expandSource(ctx, client, c.followSymlinks, srcurl)
+	}
+
+	f, err := openFilesFrom(c.filesFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *storage.Object)
+	go func() {
+		defer close(ch)
+		defer f.Close()
+		for object := range expandFilesFrom(ctx, f, srcurl) {
+			ch <- object
+		}
+	}()
+	return ch, nil
+}
+
 func (c Copy) prepareCopyTask(
 	ctx context.Context,
 	srcurl *url.URL,
@@ -332,8 +861,10 @@ func (c Copy) prepareCopyTask(
 	isBatch bool,
 ) func() error {
 	return func() error {
-		dsturl = prepareRemoteDestination(srcurl, dsturl, c.flatten, isBatch)
-		err := c.doCopy(ctx, srcurl, dsturl)
+		dsturl = prepareRemoteDestination(srcurl, dsturl, c.flatten, c.stripComponents, isBatch)
+		err := c.withJobTimeout(ctx, func(ctx context.Context) error {
+			return c.doCopy(ctx, srcurl, dsturl)
+		})
 		if err != nil {
 			return &errorpkg.Error{
 				Op:  c.op,
@@ -353,12 +884,37 @@ func (c Copy) prepareDownloadTask(
 	isBatch bool,
 ) func() error {
 	return func() error {
-		dsturl, err := prepareLocalDestination(ctx, srcurl, dsturl, c.flatten, isBatch, c.storageOpts)
+		dsturl, err := prepareLocalDestination(ctx, srcurl, dsturl, c.flatten, c.stripComponents, isBatch, c.dstStorageOpts())
 		if err != nil {
 			return err
 		}
 
-		err = c.doDownload(ctx, srcurl, dsturl)
+		err = c.withJobTimeout(ctx, func(ctx context.Context) error {
+			return c.doDownload(ctx, srcurl, dsturl)
+		})
+		if err != nil {
+			return &errorpkg.Error{
+				Op:  c.op,
+				Src: srcurl,
+				Dst: dsturl,
+				Err: err,
+			}
+		}
+		return nil
+	}
+}
+
+func (c Copy) prepareUploadTask(
+	ctx context.Context,
+	srcurl *url.URL,
+	dsturl *url.URL,
+	isBatch bool,
+) func() error {
+	return func() error {
+		dsturl = prepareRemoteDestination(srcurl, dsturl, c.flatten, c.stripComponents, isBatch)
+		err := c.withJobTimeout(ctx, func(ctx context.Context) error {
+			return c.doUpload(ctx, srcurl, dsturl)
+		})
 		if err != nil {
 			return &errorpkg.Error{
 				Op:  c.op,
@@ -369,41 +925,403 @@ func (c Copy) prepareDownloadTask(
 		}
 		return nil
 	}
-}
+}
+
+func (c Copy) prepareHTTPUploadTask(
+	ctx context.Context,
+	srcurl *url.URL,
+	dsturl *url.URL,
+) func() error {
+	return func() error {
+		dsturl = prepareRemoteDestination(srcurl, dsturl, c.flatten, 0, false)
+		err := c.withJobTimeout(ctx, func(ctx context.Context) error {
+			return c.doHTTPUpload(ctx, srcurl, dsturl)
+		})
+		if err != nil {
+			return &errorpkg.Error{
+				Op:  c.op,
+				Src: srcurl,
+				Dst: dsturl,
+				Err: err,
+			}
+		}
+		return nil
+	}
+}
+
+// withJobTimeout runs the transfer with the timeout of --job-timeout flag. A
+// timed out transfer is canceled and run again, as many times as the
+// retries of the requests.
+func (c Copy) withJobTimeout(ctx context.Context, fn func(context.Context) error) error {
+	if c.jobTimeout <= 0 {
+		return fn(ctx)
+	}
+
+	for attempt := 0; ; attempt++ {
+		jobCtx, cancel := context.WithTimeout(ctx, c.jobTimeout)
+		err := fn(jobCtx)
+		// a job completed right at the deadline is not timed out.
+		timedOut := err != nil && ctx.Err() == nil && jobCtx.Err() == context.DeadlineExceeded
+		cancel()
+
+		if !timedOut {
+			return err
+		}
+		if attempt >= c.storageOpts.MaxRetries {
+			return fmt.Errorf("job timed out after %v: %w", c.jobTimeout, context.DeadlineExceeded)
+		}
+	}
+}
+
+func (c Copy) prepareEmptyDirTask(
+	ctx context.Context,
+	srcObj *storage.Object,
+	dsturl *url.URL,
+	isBatch bool,
+) func() error {
+	return func() error {
+		srcurl := srcObj.URL
+
+		var err error
+		if dsturl.IsRemote() {
+			dsturl = prepareRemoteDestination(srcurl, dsturl, false, c.stripComponents, isBatch).Clone()
+			dsturl.Path += "/"
+		} else {
+			dsturl, err = prepareLocalDestination(ctx, srcurl, dsturl, false, c.stripComponents, isBatch, c.dstStorageOpts())
+			if err != nil {
+				return err
+			}
+		}
+
+		err = c.doEmptyDir(ctx, srcObj, dsturl)
+		if err != nil {
+			return &errorpkg.Error{
+				Op:  c.op,
+				Src: srcurl,
+				Dst: dsturl,
+				Err: err,
+			}
+		}
+		return nil
+	}
+}
+
+// isEmptyDir reports whether the directory object should be created on the
+// destination. Local storage only lists empty directories. Remote
+// directories are zero-byte marker objects, which are distinguished from the
+// common prefixes by their modification time.
+func isEmptyDir(object *storage.Object) bool {
+	return object.ModTime != nil
+}
+
+// doEmptyDir creates an empty directory on the destination. It is a
+// zero-byte object with a trailing slash on remote storage.
+func (c Copy) doEmptyDir(ctx context.Context, srcObj *storage.Object, dsturl *url.URL) error {
+	srcurl := srcObj.URL
+
+	if dsturl.IsRemote() {
+		dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+		if err != nil {
+			return err
+		}
+
+		metadata := storage.NewMetadata().
+			SetStorageClass(string(c.storageClass)).
+			SetSSE(c.encryptionMethod).
+			SetSSEKeyID(c.encryptionKeyID).
+			SetACL(c.acl)
+
+		err = dstClient.Put(ctx, strings.NewReader(""), dsturl, metadata, c.concurrency, c.partSize)
+		if err != nil {
+			return err
+		}
+	} else {
+		dstClient := storage.NewLocalClient(c.dstStorageOpts())
+		if err := dstClient.MkdirAll(dsturl.Absolute()); err != nil {
+			return err
+		}
+	}
+
+	if c.deleteSource {
+		srcClient, err := storage.NewClient(ctx, srcurl, c.srcStorageOpts())
+		if err != nil {
+			return err
+		}
+		if err := srcClient.Delete(ctx, srcurl); err != nil {
+			return err
+		}
+	}
+
+	msg := log.InfoMessage{
+		Operation:   c.op,
+		Source:      srcurl,
+		Destination: dsturl,
+		Object: &storage.Object{
+			Type: srcObj.Type,
+		},
+	}
+	return c.report(ctx, msg)
+}
+
+// doDownload is used to fetch a remote object and save as a local object.
+func (c Copy) doDownload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) error {
+	srcClient, err := storage.NewRemoteClient(ctx, srcurl, c.srcStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	dstClient := storage.NewLocalClient(c.dstStorageOpts())
+
+	err = c.shouldOverride(ctx, srcurl, dsturl)
+	if err != nil {
+		// FIXME(ig): rename
+		if errorpkg.IsWarning(err) {
+			printDebug(c.op, srcurl, dsturl, err)
+			return nil
+		}
+		return err
+	}
+
+	// attributes of the file are stored in the object metadata.
+	var srcObj *storage.Object
+	if c.preserveTimestamps || c.preservePermissions || c.preserveSymlinks {
+		srcObj, err = srcClient.Stat(ctx, srcurl)
+		if err != nil {
+			return err
+		}
+	}
+
+	var symlinkTarget string
+	if c.preserveSymlinks {
+		symlinkTarget = srcObj.UserMetadata[storage.MetadataSymlinkTarget]
+	}
+
+	var size int64
+	if symlinkTarget != "" {
+		err = dstClient.Symlink(symlinkTarget, dsturl.Absolute())
+	} else {
+		size, err = c.downloadFile(ctx, srcClient, dstClient, srcurl, dsturl, srcObj)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.deleteSource {
+		_ = srcClient.Delete(ctx, srcurl)
+	}
+
+	msg := log.InfoMessage{
+		Operation:   c.op,
+		Source:      srcurl,
+		Destination: dsturl,
+		Object: &storage.Object{
+			Size: size,
+		},
+	}
+	return c.report(ctx, msg)
+}
+
+// downloadFile downloads the object to a file and restores the attributes of
+// the file from the given source object, if requested.
+func (c Copy) downloadFile(
+	ctx context.Context,
+	srcClient *storage.S3,
+	dstClient *storage.Filesystem,
+	srcurl, dsturl *url.URL,
+	srcObj *storage.Object,
+) (int64, error) {
+	file, err := dstClient.Create(dsturl.Absolute())
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	// blocks of zeros are not written, so the file is not preallocated and
+	// the trailing hole is created by truncating the file to its size.
+	var writer io.WriterAt = file
+	if c.sparse {
+		writer = storage.NewSparseWriter(file)
+	}
+
+	var size int64
+	if c.byteRange != "" {
+		size, err = srcClient.GetRange(ctx, srcurl, writer, c.byteRange)
+	} else {
+		size, err = srcClient.Get(ctx, srcurl, writer, c.concurrency, c.partSize)
+	}
+	if err == nil && c.sparse {
+		err = dstClient.Truncate(file, size)
+	}
+	if err != nil {
+		_ = dstClient.Delete(ctx, dsturl)
+		return 0, err
+	}
+
+	if c.preserveTimestamps || c.preservePermissions {
+		if err := c.restoreAttributes(dstClient, srcObj, dsturl); err != nil {
+			return 0, err
+		}
+	}
+
+	if c.fsync {
+		if err := dstClient.Sync(file); err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// restoreAttributes restores the modification time, permissions and
+// ownership of the downloaded file, which are stored in the object metadata
+// on upload. The last modification time of the object is used if the object
+// has no modification time metadata.
+func (c Copy) restoreAttributes(dstClient *storage.Filesystem, obj *storage.Object, dsturl *url.URL) error {
+	if c.preservePermissions {
+		perm, err := storage.PermissionsFromMetadata(obj.UserMetadata)
+		if err != nil {
+			return err
+		}
+		if perm != nil {
+			if err := dstClient.SetPermissions(dsturl.Absolute(), perm, c.numericIDs); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.preserveTimestamps {
+		mtime := *obj.ModTime
+		if value, ok := obj.UserMetadata[storage.MetadataModTime]; ok {
+			var err error
+			mtime, err = storage.ParseModTime(value)
+			if err != nil {
+				return err
+			}
+		}
+		return dstClient.Chtimes(dsturl.Absolute(), mtime, mtime)
+	}
+	return nil
+}
+
+func (c Copy) doUpload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) error {
+	srcClient := storage.NewLocalClient(c.srcStorageOpts())
+
+	if c.preserveSymlinks {
+		target, err := srcClient.Readlink(srcurl.Absolute())
+		if err != nil {
+			return err
+		}
+		if target != "" {
+			return c.doUploadSymlink(ctx, srcClient, srcurl, dsturl, target)
+		}
+	}
+
+	file, err := srcClient.Open(srcurl.Absolute())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = c.shouldOverride(ctx, srcurl, dsturl)
+	if err != nil {
+		if errorpkg.IsWarning(err) {
+			printDebug(c.op, srcurl, dsturl, err)
+			return nil
+		}
+		return err
+	}
+
+	dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	metadata, err := c.uploadMetadata(ctx, srcClient, srcurl, file)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = file
+	if c.sparse {
+		reader, err = storage.NewSparseReader(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = dstClient.Put(ctx, reader, dsturl, metadata, c.concurrency, c.partSize)
+	if err != nil {
+		return c.conditionalWriteError(err, srcurl, dsturl)
+	}
+
+	obj, _ := srcClient.Stat(ctx, srcurl)
+	size := obj.Size
+
+	if c.deleteSource {
+		// close the file before deleting
+		file.Close()
+		if err := srcClient.Delete(ctx, srcurl); err != nil {
+			return err
+		}
+	}
+
+	msg := log.InfoMessage{
+		Operation:   c.op,
+		Source:      srcurl,
+		Destination: dsturl,
+		Object: &storage.Object{
+			Size:         size,
+			StorageClass: c.storageClass,
+		},
+	}
+	return c.report(ctx, msg)
+}
+
+// uploadMetadata returns the metadata of the upload of the local file.
+func (c Copy) uploadMetadata(
+	ctx context.Context,
+	srcClient *storage.Filesystem,
+	srcurl *url.URL,
+	file *os.File,
+) (storage.Metadata, error) {
+	metadata := storage.NewMetadata().
+		SetContentType(guessContentType(file)).
+		SetStorageClass(string(c.storageClass)).
+		SetSSE(c.encryptionMethod).
+		SetSSEKeyID(c.encryptionKeyID).
+		SetACL(c.acl).
+		SetIfNoneMatch(c.writeCondition())
+	c.setAttributes(metadata)
+
+	if c.preserveTimestamps {
+		obj, err := srcClient.Stat(ctx, srcurl)
+		if err != nil {
+			return nil, err
+		}
+		metadata.SetUserDefined(storage.MetadataModTime, storage.FormatModTime(*obj.ModTime))
+	}
 
-func (c Copy) prepareUploadTask(
-	ctx context.Context,
-	srcurl *url.URL,
-	dsturl *url.URL,
-	isBatch bool,
-) func() error {
-	return func() error {
-		dsturl = prepareRemoteDestination(srcurl, dsturl, c.flatten, isBatch)
-		err := c.doUpload(ctx, srcurl, dsturl)
+	if c.preservePermissions {
+		perm, err := srcClient.Permissions(srcurl.Absolute())
 		if err != nil {
-			return &errorpkg.Error{
-				Op:  c.op,
-				Src: srcurl,
-				Dst: dsturl,
-				Err: err,
-			}
+			return nil, err
 		}
-		return nil
+		perm.SetMetadata(metadata)
 	}
+	return metadata, nil
 }
 
-// doDownload is used to fetch a remote object and save as a local object.
-func (c Copy) doDownload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) error {
-	srcClient, err := storage.NewRemoteClient(ctx, srcurl, c.storageOpts)
-	if err != nil {
-		return err
-	}
-
-	dstClient := storage.NewLocalClient(c.storageOpts)
-
-	err = c.shouldOverride(ctx, srcurl, dsturl)
+// doUploadSymlink uploads the symbolic link as an object whose content and
+// metadata are the link target. The content is only informational for the
+// tools without metadata support, the link is recreated from the metadata on
+// download.
+func (c Copy) doUploadSymlink(
+	ctx context.Context,
+	srcClient *storage.Filesystem,
+	srcurl, dsturl *url.URL,
+	target string,
+) error {
+	err := c.shouldOverride(ctx, srcurl, dsturl)
 	if err != nil {
-		// FIXME(ig): rename
 		if errorpkg.IsWarning(err) {
 			printDebug(c.op, srcurl, dsturl, err)
 			return nil
@@ -411,20 +1329,28 @@ func (c Copy) doDownload(ctx context.Context, srcurl *url.URL, dsturl *url.URL)
 		return err
 	}
 
-	file, err := dstClient.Create(dsturl.Absolute())
+	dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	size, err := srcClient.Get(ctx, srcurl, file, c.concurrency, c.partSize)
+	metadata := storage.NewMetadata().
+		SetStorageClass(string(c.storageClass)).
+		SetSSE(c.encryptionMethod).
+		SetSSEKeyID(c.encryptionKeyID).
+		SetACL(c.acl).
+		SetIfNoneMatch(c.writeCondition()).
+		SetUserDefined(storage.MetadataSymlinkTarget, target)
+
+	err = dstClient.Put(ctx, strings.NewReader(target), dsturl, metadata, c.concurrency, c.partSize)
 	if err != nil {
-		_ = dstClient.Delete(ctx, dsturl)
-		return err
+		return c.conditionalWriteError(err, srcurl, dsturl)
 	}
 
 	if c.deleteSource {
-		_ = srcClient.Delete(ctx, srcurl)
+		if err := srcClient.Delete(ctx, srcurl); err != nil {
+			return err
+		}
 	}
 
 	msg := log.InfoMessage{
@@ -432,24 +1358,17 @@ func (c Copy) doDownload(ctx context.Context, srcurl *url.URL, dsturl *url.URL)
 		Source:      srcurl,
 		Destination: dsturl,
 		Object: &storage.Object{
-			Size: size,
+			Size:         int64(len(target)),
+			StorageClass: c.storageClass,
 		},
 	}
-	log.Info(msg)
-
-	return nil
+	return c.report(ctx, msg)
 }
 
-func (c Copy) doUpload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) error {
-	srcClient := storage.NewLocalClient(c.storageOpts)
-
-	file, err := srcClient.Open(srcurl.Absolute())
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	err = c.shouldOverride(ctx, srcurl, dsturl)
+// doHTTPUpload streams an object served over HTTP(S) to a remote
+// destination.
+func (c Copy) doHTTPUpload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) error {
+	err := c.shouldOverride(ctx, srcurl, dsturl)
 	if err != nil {
 		if errorpkg.IsWarning(err) {
 			printDebug(c.op, srcurl, dsturl, err)
@@ -458,36 +1377,42 @@ func (c Copy) doUpload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) er
 		return err
 	}
 
-	// override destination region if set
-	if c.dstRegion != "" {
-		c.storageOpts.SetRegion(c.dstRegion)
-	}
-	dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.storageOpts)
-	if err != nil {
-		return err
-	}
-
-	metadata := storage.NewMetadata().
-		SetContentType(guessContentType(file)).
-		SetStorageClass(string(c.storageClass)).
-		SetSSE(c.encryptionMethod).
-		SetSSEKeyID(c.encryptionKeyID).
-		SetACL(c.acl)
-
-	err = dstClient.Put(ctx, file, dsturl, metadata, c.concurrency, c.partSize)
-	if err != nil {
-		return err
-	}
+	var size int64
+	if !c.storageOpts.DryRun {
+		srcClient, err := storage.NewHTTPStorage(c.srcStorageOpts())
+		if err != nil {
+			return err
+		}
 
-	obj, _ := srcClient.Stat(ctx, srcurl)
-	size := obj.Size
+		dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+		if err != nil {
+			return err
+		}
 
-	if c.deleteSource {
-		// close the file before deleting
-		file.Close()
-		if err := srcClient.Delete(ctx, srcurl); err != nil {
+		rc, contentLength, err := srcClient.Read(ctx, srcurl)
+		if err != nil {
 			return err
 		}
+		defer rc.Close()
+
+		metadata := storage.NewMetadata().
+			SetContentType(mime.TypeByExtension(path.Ext(srcurl.Path))).
+			SetStorageClass(string(c.storageClass)).
+			SetSSE(c.encryptionMethod).
+			SetSSEKeyID(c.encryptionKeyID).
+			SetACL(c.acl).
+			SetIfNoneMatch(c.writeCondition())
+		c.setAttributes(metadata)
+
+		partSize := partSizeFor(contentLength, c.partSize)
+		err = dstClient.Put(ctx, rc, dsturl, metadata, c.concurrency, partSize)
+		if err != nil {
+			return c.conditionalWriteError(err, srcurl, dsturl)
+		}
+
+		if contentLength > 0 {
+			size = contentLength
+		}
 	}
 
 	msg := log.InfoMessage{
@@ -499,17 +1424,22 @@ func (c Copy) doUpload(ctx context.Context, srcurl *url.URL, dsturl *url.URL) er
 			StorageClass: c.storageClass,
 		},
 	}
-	log.Info(msg)
+	return c.report(ctx, msg)
+}
 
-	return nil
+// partSizeFor returns a part size large enough to upload an object of the
+// given size within the maximum number of parts of a multipart upload. Sizes
+// of streamed objects are not known by the uploader, so the part size can't
+// be adjusted by it.
+func partSizeFor(size, partSize int64) int64 {
+	if size <= 0 || size/partSize < maxUploadParts {
+		return partSize
+	}
+	return size/(maxUploadParts-1) + 1
 }
 
 func (c Copy) doCopy(ctx context.Context, srcurl, dsturl *url.URL) error {
-	// override destination region if set
-	if c.dstRegion != "" {
-		c.storageOpts.SetRegion(c.dstRegion)
-	}
-	dstClient, err := storage.NewClient(ctx, dsturl, c.storageOpts)
+	dstClient, err := storage.NewClient(ctx, dsturl, c.dstStorageOpts())
 	if err != nil {
 		return err
 	}
@@ -529,13 +1459,36 @@ func (c Copy) doCopy(ctx context.Context, srcurl, dsturl *url.URL) error {
 		return err
 	}
 
-	err = dstClient.Copy(ctx, srcurl, dsturl, metadata)
+	// server side copy requires both objects to reside on the same service and
+	// the destination credentials to be authorized to read the source object.
+	// Stream the object through the client if source and destination are on
+	// different endpoints or accessed with different credentials.
+	streamed := c.srcStorageOpts().Endpoint != c.dstStorageOpts().Endpoint || c.srcProfile != c.dstProfile
+
+	if err := c.copyAttributes(ctx, srcurl, metadata, streamed); err != nil {
+		return err
+	}
+
+	if streamed {
+		err = c.doStreamCopy(ctx, srcurl, dsturl, metadata)
+	} else {
+		err = dstClient.Copy(ctx, srcurl, dsturl, metadata)
+	}
 	if err != nil {
 		return err
 	}
 
-	if c.deleteSource {
-		srcClient, err := storage.NewClient(ctx, srcurl, c.storageOpts)
+	if c.copyACL && !c.storageOpts.DryRun {
+		if err := c.doCopyACL(ctx, srcurl, dsturl); err != nil {
+			return err
+		}
+	}
+
+	// an object copied onto itself to edit its attributes in place is the
+	// destination as well, so it is not deleted.
+	inPlace := !streamed && srcurl.Bucket == dsturl.Bucket && srcurl.Path == dsturl.Path
+	if c.deleteSource && !inPlace {
+		srcClient, err := storage.NewClient(ctx, srcurl, c.srcStorageOpts())
 		if err != nil {
 			return err
 		}
@@ -553,9 +1506,79 @@ func (c Copy) doCopy(ctx context.Context, srcurl, dsturl *url.URL) error {
 			StorageClass: c.storageClass,
 		},
 	}
-	log.Info(msg)
+	return c.report(ctx, msg)
+}
 
-	return nil
+// doStreamCopy copies a remote object to a remote destination by reading the
+// source and uploading it to the destination, using the endpoint and
+// credentials of each side respectively.
+func (c Copy) doStreamCopy(ctx context.Context, srcurl, dsturl *url.URL, metadata storage.Metadata) error {
+	if c.storageOpts.DryRun {
+		return nil
+	}
+
+	srcClient, err := storage.NewRemoteClient(ctx, srcurl, c.srcStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	// the size of the source is known, but not by the uploader of the
+	// stream.
+	var size int64
+	if c.srcObject != nil {
+		size = c.srcObject.Size
+	} else if obj, err := srcClient.Stat(ctx, srcurl); err == nil {
+		size = obj.Size
+	}
+
+	rc, err := srcClient.Read(ctx, srcurl)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return dstClient.Put(ctx, rc, dsturl, metadata, c.concurrency, partSizeFor(size, c.partSize))
+}
+
+// srcStorageOpts returns the storage options used to access the source,
+// overriding the global options with the source specific flags.
+func (c Copy) srcStorageOpts() storage.Options {
+	opts := c.storageOpts
+	opts.PreserveSymlinks = c.preserveSymlinks
+	opts.KeepEmptyDirs = c.keepEmptyDirs
+	if c.srcEndpoint != "" {
+		opts.Endpoint = c.srcEndpoint
+	}
+	if c.srcRegion != "" {
+		opts.SetRegion(c.srcRegion)
+	}
+	if c.srcProfile != "" {
+		opts.SetProfile(c.srcProfile)
+	}
+	return opts
+}
+
+// dstStorageOpts returns the storage options used to access the destination,
+// overriding the global options with the destination specific flags.
+func (c Copy) dstStorageOpts() storage.Options {
+	opts := c.storageOpts
+	// the destination objects are retrieved once to check the conditions.
+	opts.CacheStats = c.hasOverrideConditions()
+	if c.dstEndpoint != "" {
+		opts.Endpoint = c.dstEndpoint
+	}
+	if c.dstRegion != "" {
+		opts.SetRegion(c.dstRegion)
+	}
+	if c.dstProfile != "" {
+		opts.SetProfile(c.dstProfile)
+	}
+	return opts
 }
 
 // shouldOverride function checks if the destination should be overridden if
@@ -565,11 +1588,11 @@ func (c Copy) doCopy(ctx context.Context, srcurl, dsturl *url.URL) error {
 // differs.
 func (c Copy) shouldOverride(ctx context.Context, srcurl *url.URL, dsturl *url.URL) error {
 	// if not asked to override, ignore.
-	if !c.noClobber && !c.ifSizeDiffer && !c.ifSourceNewer {
+	if !c.hasOverrideConditions() {
 		return nil
 	}
 
-	srcClient, err := storage.NewClient(ctx, srcurl, c.storageOpts)
+	srcClient, err := storage.NewClient(ctx, srcurl, c.srcStorageOpts())
 	if err != nil {
 		return err
 	}
@@ -579,7 +1602,7 @@ func (c Copy) shouldOverride(ctx context.Context, srcurl *url.URL, dsturl *url.U
 		return err
 	}
 
-	dstClient, err := storage.NewClient(ctx, dsturl, c.storageOpts)
+	dstClient, err := storage.NewClient(ctx, dsturl, c.dstStorageOpts())
 	if err != nil {
 		return err
 	}
@@ -607,6 +1630,19 @@ func (c Copy) shouldOverride(ctx context.Context, srcurl *url.URL, dsturl *url.U
 		}
 	}
 
+	if c.ifContentDiffer {
+		same, err := sameContent(srcObj, dstObj, c.partSize)
+		if err != nil {
+			return err
+		}
+
+		if same {
+			stickyErr = errorpkg.ErrObjectContentsMatch
+		} else {
+			stickyErr = nil
+		}
+	}
+
 	if c.ifSourceNewer {
 		srcMod, dstMod := srcObj.ModTime, dstObj.ModTime
 
@@ -620,17 +1656,62 @@ func (c Copy) shouldOverride(ctx context.Context, srcurl *url.URL, dsturl *url.U
 	return stickyErr
 }
 
+// hasOverrideConditions reports whether the destination is overridden only if
+// the conditions of the flags are met.
+func (c Copy) hasOverrideConditions() bool {
+	return c.noClobber || c.ifSizeDiffer || c.ifSourceNewer || c.ifContentDiffer
+}
+
+// prefetchDestination lists the objects under the destination prefix, so that
+// the conditions are checked against the listing instead of a HEAD request for
+// each destination object. The objects are retrieved with HEAD requests if
+// the listing fails.
+func (c Copy) prefetchDestination(ctx context.Context, dsturl *url.URL) {
+	client, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+	if err != nil {
+		return
+	}
+	if err := client.PrefetchStats(ctx, dsturl); err != nil {
+		log.Debug(log.DebugMessage{
+			Command:   c.fullCommand,
+			Operation: c.op,
+			Err:       cleanupError(err),
+		})
+	}
+}
+
+// writeCondition returns the condition of the uploads. With --if-none-match
+// flag, objects are only written if they don't exist, so the concurrent
+// uploads of the same key don't overwrite each other.
+func (c Copy) writeCondition() string {
+	if c.ifNoneMatch {
+		return "*"
+	}
+	return ""
+}
+
+// conditionalWriteError skips the upload if its condition failed, i.e. the
+// object is written by someone else in the meantime.
+func (c Copy) conditionalWriteError(err error, srcurl, dsturl *url.URL) error {
+	if c.ifNoneMatch && storage.IsPreconditionFailed(err) {
+		printDebug(c.op, srcurl, dsturl, errorpkg.ErrObjectExists)
+		return nil
+	}
+	return err
+}
+
 // prepareRemoteDestination will return a new destination URL for
 // remote->remote and local->remote copy operations.
 func prepareRemoteDestination(
 	srcurl *url.URL,
 	dsturl *url.URL,
 	flatten bool,
+	stripComponents int,
 	isBatch bool,
 ) *url.URL {
 	objname := srcurl.Base()
 	if isBatch && !flatten {
-		objname = srcurl.Relative()
+		objname, _ = stripPathComponents(srcurl.Relative(), stripComponents)
 	}
 
 	if dsturl.IsPrefix() || dsturl.IsBucket() {
@@ -646,12 +1727,13 @@ func prepareLocalDestination(
 	srcurl *url.URL,
 	dsturl *url.URL,
 	flatten bool,
+	stripComponents int,
 	isBatch bool,
 	storageOpts storage.Options,
 ) (*url.URL, error) {
 	objname := srcurl.Base()
 	if isBatch && !flatten {
-		objname = srcurl.Relative()
+		objname, _ = stripPathComponents(srcurl.Relative(), stripComponents)
 	}
 
 	client := storage.NewLocalClient(storageOpts)
@@ -693,6 +1775,21 @@ func prepareLocalDestination(
 	return dsturl, nil
 }
 
+// stripPathComponents removes the given number of leading components from the
+// relative path of an object. It returns false if the path doesn't have more
+// components than the given number.
+func stripPathComponents(name string, n int) (string, bool) {
+	if n <= 0 {
+		return name, true
+	}
+
+	components := strings.Split(strings.Trim(filepath.ToSlash(name), "/"), "/")
+	if len(components) <= n {
+		return "", false
+	}
+	return path.Join(components[n:]...), true
+}
+
 // getObject checks if the object from given url exists. If no object is
 // found, error and returning object would be nil.
 func getObject(ctx context.Context, url *url.URL, client storage.Storage) (*storage.Object, error) {
@@ -705,15 +1802,53 @@ func getObject(ctx context.Context, url *url.URL, client storage.Storage) (*stor
 }
 
 func validateCopyCommand(c *cli.Context) error {
-	if c.Args().Len() != 2 {
+	if c.Args().Len() < 1 {
+		return fmt.Errorf("expected source and destination arguments")
+	}
+
+	dsts, err := copyDestinations(c)
+	if err != nil {
+		return err
+	}
+
+	if c.String("exec") != "" {
+		return validateExecFlag(c, dsts)
+	}
+
+	switch {
+	case len(dsts) == 0:
 		return fmt.Errorf("expected source and destination arguments")
+	case len(dsts) == 1:
+	case c.Command.Name == "mv":
+		return fmt.Errorf("objects can not be moved to multiple destinations")
+	case c.String("journal") != "":
+		return fmt.Errorf("--journal flag can not be used with multiple destinations")
+	}
+
+	if c.String("range") != "" {
+		if _, err := parseByteRange(c.String("range")); err != nil {
+			return err
+		}
+		if c.Command.Name == "mv" {
+			return fmt.Errorf("--range flag can not be used with mv command")
+		}
+	}
+
+	for _, dst := range dsts {
+		if err := validateCopyDestination(c, dst); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// validateCopyDestination validates the copy of the source to one of the
+// destinations.
+func validateCopyDestination(c *cli.Context, dst string) error {
 	ctx := c.Context
 	src := c.Args().Get(0)
-	dst := c.Args().Get(1)
 
-	srcurl, err := url.New(src)
+	srcurl, err := newSourceURL(src)
 	if err != nil {
 		return err
 	}
@@ -728,18 +1863,66 @@ func validateCopyCommand(c *cli.Context) error {
 		return fmt.Errorf("target %q can not contain glob characters", dst)
 	}
 
+	filesFrom := c.String("files-from")
+	if filesFrom != "" {
+		if srcurl.HasGlob() {
+			return fmt.Errorf("source argument can not contain wildcard character with --files-from flag")
+		}
+		if srcurl.IsHTTP() {
+			return fmt.Errorf("--files-from flag can not be used with http sources")
+		}
+	}
+
+	if err := validateInventoryFlag(c, srcurl); err != nil {
+		return err
+	}
+
 	// we don't operate on S3 prefixes for copy and delete operations.
-	if srcurl.IsBucket() || srcurl.IsPrefix() {
+	if (srcurl.IsBucket() || srcurl.IsPrefix()) && filesFrom == "" {
 		return fmt.Errorf("source argument must contain wildcard character")
 	}
 
 	// 'cp dir/* s3://bucket/prefix': expect a trailing slash to avoid any
 	// surprises.
-	if srcurl.HasGlob() && dsturl.IsRemote() && !dsturl.IsPrefix() && !dsturl.IsBucket() {
+	if (srcurl.HasGlob() || filesFrom != "") && dsturl.IsRemote() && !dsturl.IsPrefix() && !dsturl.IsBucket() {
 		return fmt.Errorf("target %q must be a bucket or a prefix", dsturl)
 	}
 
+	if err := validateSymlinkFlags(c); err != nil {
+		return err
+	}
+
+	if c.Int("strip-components") < 0 {
+		return fmt.Errorf("strip-components can not be a negative number")
+	}
+
+	if err := validateMultipartFlags(c); err != nil {
+		return err
+	}
+
+	if err := validateScheduling(c.String("scheduling")); err != nil {
+		return err
+	}
+
+	if c.Bool("flatten") && c.Int("strip-components") > 0 {
+		return fmt.Errorf("--flatten and --strip-components flags can not be used together")
+	}
+
+	if err := validateAttributeFlags(c, srcurl, dsturl); err != nil {
+		return err
+	}
+
+	if c.String("range") != "" && (!srcurl.IsRemote() || dsturl.IsRemote()) {
+		return fmt.Errorf("--range flag can only be used for downloads")
+	}
+
+	if c.Bool("if-none-match") && (srcurl.IsRemote() || !dsturl.IsRemote()) {
+		return fmt.Errorf("--if-none-match flag can only be used for uploads")
+	}
+
 	switch {
+	case srcurl.IsHTTP():
+		return validateHTTPCopy(c.Command.Name, srcurl, dsturl)
 	case srcurl.Type == dsturl.Type:
 		return validateCopy(srcurl, dsturl)
 	case dsturl.IsRemote():
@@ -749,6 +1932,86 @@ func validateCopyCommand(c *cli.Context) error {
 	}
 }
 
+// validateMultipartFlags validates --concurrency and --part-size flags.
+func validateMultipartFlags(c *cli.Context) error {
+	if c.Int("concurrency") < 1 {
+		return fmt.Errorf("concurrency must be a positive number")
+	}
+
+	partSize, err := parsePartSize(c.String("part-size"))
+	if err != nil {
+		return err
+	}
+	if partSize < 1 {
+		return fmt.Errorf("part size must be a positive number")
+	}
+	return nil
+}
+
+// parsePartSize parses the value of --part-size flag. Sizes without a unit
+// are in MiB.
+func parsePartSize(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n * megabytes, nil
+	}
+	return strutil.ParseBytes(s)
+}
+
+// parseByteRange parses the value of --range flag, which is a byte range in
+// the format of the HTTP Range header without the unit, and returns the value
+// of the header. The end of the range is inclusive.
+func parseByteRange(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	invalid := fmt.Errorf("invalid --range %q, expected start-end, start- or -suffix", s)
+
+	i := strings.Index(s, "-")
+	if i < 0 {
+		return "", invalid
+	}
+	start, end := s[:i], s[i+1:]
+
+	parse := func(v string) (int64, bool) {
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil && n >= 0
+	}
+
+	switch {
+	case start == "" && end == "":
+		return "", invalid
+	case start == "":
+		if n, ok := parse(end); !ok || n == 0 {
+			return "", invalid
+		}
+	case end == "":
+		if _, ok := parse(start); !ok {
+			return "", invalid
+		}
+	default:
+		first, ok1 := parse(start)
+		last, ok2 := parse(end)
+		if !ok1 || !ok2 || first > last {
+			return "", invalid
+		}
+	}
+	return "bytes=" + s, nil
+}
+
+func validateSymlinkFlags(c *cli.Context) error {
+	var set []string
+	for _, flag := range []string{"follow-symlinks", "no-follow-symlinks", "preserve-symlinks"} {
+		if c.Bool(flag) {
+			set = append(set, "--"+flag)
+		}
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("%v flags can not be used together", strings.Join(set, " and "))
+	}
+	return nil
+}
+
 func validateCopy(srcurl, dsturl *url.URL) error {
 	if srcurl.IsRemote() || dsturl.IsRemote() {
 		return nil
@@ -758,6 +2021,24 @@ func validateCopy(srcurl, dsturl *url.URL) error {
 	return fmt.Errorf("local->local copy operations are not permitted")
 }
 
+func validateHTTPCopy(op string, srcurl, dsturl *url.URL) error {
+	if op == "mv" {
+		return fmt.Errorf("http sources can not be moved")
+	}
+
+	if !dsturl.IsRemote() {
+		return fmt.Errorf("http sources can only be copied to a remote destination")
+	}
+
+	// 'cp https://example.com/ s3://bucket/': object name can't be derived
+	// from the source.
+	if base := srcurl.Base(); (base == "." || base == "/") && (dsturl.IsBucket() || dsturl.IsPrefix()) {
+		return fmt.Errorf("target %q must be an object for source %q", dsturl, srcurl)
+	}
+
+	return nil
+}
+
 func validateUpload(ctx context.Context, srcurl, dsturl *url.URL, storageOpts storage.Options) error {
 	srcclient := storage.NewLocalClient(storageOpts)
 
@@ -796,6 +2077,15 @@ func guessContentType(file *os.File) string {
 	return contentType
 }
 
+// newSourceURL creates a URL for the source argument of copy operations,
+// which can also be an HTTP(S) address.
+func newSourceURL(s string) (*url.URL, error) {
+	if url.HasHTTPScheme(s) {
+		return url.NewHTTP(s)
+	}
+	return url.New(s)
+}
+
 func givenCommand(c *cli.Context) string {
 	cmd := c.Command.FullName()
 	if c.Args().Len() > 0 {
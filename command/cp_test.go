@@ -1,12 +1,17 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/peak/s5cmd/storage"
 )
 
 func TestGuessContentType(t *testing.T) {
@@ -62,3 +67,149 @@ func TestGuessContentType(t *testing.T) {
 		os.Remove(f.Name())
 	}
 }
+
+func TestPartSizeFor(t *testing.T) {
+	t.Parallel()
+
+	const partSize = defaultPartSize * megabytes
+
+	testcases := []struct {
+		name     string
+		size     int64
+		expected int64
+	}{
+		{name: "unknown_size", size: -1, expected: partSize},
+		{name: "small_object", size: 1024, expected: partSize},
+		{name: "fits_max_parts", size: partSize * (maxUploadParts - 1), expected: partSize},
+		{name: "exceeds_max_parts", size: partSize * maxUploadParts * 2, expected: partSize*maxUploadParts*2/(maxUploadParts-1) + 1},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := partSizeFor(tc.size, partSize)
+			assert.Equal(t, tc.expected, got)
+			if tc.size > 0 {
+				assert.True(t, (tc.size+got-1)/got <= maxUploadParts)
+			}
+		})
+	}
+}
+
+func TestParsePartSize(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{name: "mebibytes_without_unit", input: "50", expected: 50 * megabytes},
+		{name: "with_unit", input: "100M", expected: 100 * megabytes},
+		{name: "kibibytes", input: "5120K", expected: 5 * megabytes},
+		{name: "gibibytes", input: "1G", expected: 1024 * megabytes},
+		{name: "invalid", input: "big", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parsePartSize(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestParseByteRange(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{name: "empty", input: "", expected: ""},
+		{name: "start_end", input: "0-1048575", expected: "bytes=0-1048575"},
+		{name: "single_byte", input: "5-5", expected: "bytes=5-5"},
+		{name: "start", input: "1024-", expected: "bytes=1024-"},
+		{name: "suffix", input: "-1024", expected: "bytes=-1024"},
+		{name: "without_dash", input: "1024", wantErr: true},
+		{name: "only_dash", input: "-", wantErr: true},
+		{name: "reversed", input: "10-5", wantErr: true},
+		{name: "zero_suffix", input: "-0", wantErr: true},
+		{name: "not_a_number", input: "a-b", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseByteRange(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestStripPathComponents(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name         string
+		n            int
+		expected     string
+		expectedSkip bool
+	}{
+		{name: "a/b/c.txt", n: 0, expected: "a/b/c.txt"},
+		{name: "a/b/c.txt", n: 1, expected: "b/c.txt"},
+		{name: "a/b/c.txt", n: 2, expected: "c.txt"},
+		{name: "a/b/c.txt", n: 3, expectedSkip: true},
+		{name: "c.txt", n: 1, expectedSkip: true},
+		{name: "a/b/", n: 1, expected: "b"},
+	}
+
+	for _, tc := range testcases {
+		got, ok := stripPathComponents(tc.name, tc.n)
+		assert.Equal(t, !tc.expectedSkip, ok, "%v: %v", tc.name, tc.n)
+		assert.Equal(t, tc.expected, got, "%v: %v", tc.name, tc.n)
+	}
+}
+
+func TestCopyWithJobTimeout(t *testing.T) {
+	t.Parallel()
+
+	c := Copy{
+		jobTimeout:  10 * time.Millisecond,
+		storageOpts: storage.Options{MaxRetries: 2},
+	}
+
+	// a job which succeeds after its deadline is not run again.
+	var attempts int
+	err := c.withJobTimeout(context.Background(), func(ctx context.Context) error {
+		attempts++
+		<-ctx.Done()
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// a timed out job is run again as many times as the retries.
+	attempts = 0
+	err = c.withJobTimeout(context.Background(), func(ctx context.Context) error {
+		attempts++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 3, attempts)
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/parallel"
+)
+
+// deadline stops running new jobs once the duration of --deadline flag is
+// passed. Running jobs are completed, so the transfers are not left partial.
+var deadline struct {
+	timer    *time.Timer
+	duration time.Duration
+	exceeded int32
+}
+
+// startDeadline starts the timer of the deadline.
+func startDeadline(d time.Duration) {
+	deadline.duration = d
+	deadline.timer = time.AfterFunc(d, func() {
+		atomic.StoreInt32(&deadline.exceeded, 1)
+		log.Error(log.ErrorMessage{
+			Err: fmt.Sprintf("deadline (%v) exceeded, no more jobs will be run", d),
+		})
+		parallel.Stop()
+	})
+}
+
+func deadlineExceeded() bool {
+	return atomic.LoadInt32(&deadline.exceeded) == 1
+}
+
+// skippedJobs returns the number of jobs which are not run since the run is
+// stopped.
+func skippedJobs() int64 {
+	return int64(parallel.SkippedTaskCount()) + atomic.LoadInt64(&skippedCommands)
+}
+
+// stopDeadline stops the timer of the deadline and reports the number of jobs
+// which are not run if it is exceeded. It must be called after the jobs are
+// completed and before the logger is closed.
+func stopDeadline() {
+	if deadline.timer == nil {
+		return
+	}
+	deadline.timer.Stop()
+
+	if !deadlineExceeded() {
+		return
+	}
+	log.Error(log.ErrorMessage{
+		Err: fmt.Sprintf("deadline (%v) exceeded, %v jobs are not run", deadline.duration, skippedJobs()),
+	})
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/peak/s5cmd/strutil"
+)
+
+// dryRun aggregates the objects which would be processed by the operations
+// in dry-run mode, so the scale of a run can be checked before it is run.
+var dryRun = struct {
+	sync.Mutex
+	counts map[string]*dryRunCount
+}{counts: map[string]*dryRunCount{}}
+
+type dryRunCount struct {
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// addDryRun records an object of given size which would be processed by the
+// operation.
+func addDryRun(op string, size int64) {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+
+	count, ok := dryRun.counts[op]
+	if !ok {
+		count = &dryRunCount{}
+		dryRun.counts[op] = count
+	}
+	count.Objects++
+	count.Bytes += size
+}
+
+// DryRunSummary is the summary of the objects which would be processed by
+// each operation in dry-run mode.
+type DryRunSummary map[string]dryRunCount
+
+// dryRunSummary returns the summary of the objects recorded so far.
+func dryRunSummary() DryRunSummary {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+
+	summary := DryRunSummary{}
+	for op, count := range dryRun.counts {
+		summary[op] = *count
+	}
+	return summary
+}
+
+// String is the string representation of DryRunSummary, e.g. "would copy
+// 12,345 objects, 1.2T; would delete 87 objects, 8.5M".
+func (s DryRunSummary) String() string {
+	ops := make([]string, 0, len(s))
+	for op := range s {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	parts := make([]string, 0, len(ops))
+	for _, op := range ops {
+		count := s[op]
+
+		objects := "objects"
+		if count.Objects == 1 {
+			objects = "object"
+		}
+		part := fmt.Sprintf("would %v %v %v", dryRunVerb(op), formatCount(count.Objects), objects)
+		// sizes of the objects are not known if they are not listed.
+		if count.Bytes > 0 {
+			part += ", " + strutil.HumanizeBytes(count.Bytes)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// JSON is the JSON representation of DryRunSummary.
+func (s DryRunSummary) JSON() string {
+	return strutil.JSON(struct {
+		DryRun map[string]dryRunCount `json:"dry_run"`
+	}{DryRun: s})
+}
+
+// dryRunVerb returns the verb of the operation used in the summary.
+func dryRunVerb(op string) string {
+	switch op {
+	case "cp":
+		return "copy"
+	case "mv":
+		return "move"
+	case "rm":
+		return "delete"
+	default:
+		return op
+	}
+}
+
+// formatCount formats the number with thousands separators.
+func formatCount(n int64) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	s := strconv.FormatInt(n, 10)
+
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
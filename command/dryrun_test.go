@@ -0,0 +1,38 @@
+package command
+
+import "testing"
+
+func TestDryRunSummaryString(t *testing.T) {
+	t.Parallel()
+
+	summary := DryRunSummary{
+		"cp": {Objects: 12345, Bytes: 3 << 40},
+		"rm": {Objects: 1},
+	}
+
+	expected := "would copy 12,345 objects, 3.0T; would delete 1 object"
+	if got := summary.String(); got != expected {
+		t.Errorf("String() = %q, expected %q", got, expected)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		n        int64
+		expected string
+	}{
+		{n: 0, expected: "0"},
+		{n: 999, expected: "999"},
+		{n: 1000, expected: "1,000"},
+		{n: 1234567, expected: "1,234,567"},
+		{n: -12345, expected: "-12,345"},
+	}
+
+	for _, tc := range testcases {
+		if got := formatCount(tc.n); got != tc.expected {
+			t.Errorf("formatCount(%v) = %q, expected %q", tc.n, got, tc.expected)
+		}
+	}
+}
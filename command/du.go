@@ -3,6 +3,10 @@ package command
 import (
 	"context"
 	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/urfave/cli/v2"
@@ -30,6 +34,15 @@ Examples:
 
 	2. Show disk usage of all objects that match a wildcard, grouped by storage class
 		 > s5cmd {{.HelpName}} --group s3://bucket/prefix/obj*.gz
+
+	3. Show disk usage of all objects in a bucket, grouped by storage class, as CSV
+		 > s5cmd {{.HelpName}} --group --format csv s3://bucket/*
+
+	4. Show disk usage of all objects in a bucket, grouped by first-level prefixes
+		 > s5cmd {{.HelpName}} --group-by prefix s3://bucket/*
+
+	5. Show disk usage of all objects in a bucket, grouped by second-level prefixes
+		 > s5cmd {{.HelpName}} --depth 2 s3://bucket/*
 `
 
 var sizeCommand = &cli.Command{
@@ -41,13 +54,22 @@ var sizeCommand = &cli.Command{
 		&cli.BoolFlag{
 			Name:    "group",
 			Aliases: []string{"g"},
-			Usage:   "group sizes by storage class",
+			Usage:   "group sizes by storage class, same as --group-by storage-class",
+		},
+		&cli.StringFlag{
+			Name:  "group-by",
+			Usage: "group sizes by given key: (storage-class, prefix)",
+		},
+		&cli.IntFlag{
+			Name:  "depth",
+			Usage: "number of leading directories of prefixes to group sizes by, implies --group-by prefix (default: 1)",
 		},
 		&cli.BoolFlag{
 			Name:    "humanize",
 			Aliases: []string{"H"},
 			Usage:   "human-readable output for object sizes",
 		},
+		newFormatFlag(),
 	},
 	Before: func(c *cli.Context) error {
 		err := validateDUCommand(c)
@@ -64,8 +86,10 @@ var sizeCommand = &cli.Command{
 			op:          c.Command.Name,
 			fullCommand: givenCommand(c),
 			// flags
-			groupByClass: c.Bool("group"),
-			humanize:     c.Bool("humanize"),
+			groupBy:  sizeGroupBy(c),
+			depth:    c.Int("depth"),
+			humanize: c.Bool("humanize"),
+			format:   c.String("format"),
 
 			storageOpts: NewStorageOpts(c),
 		}.Run(c.Context)
@@ -79,8 +103,10 @@ type Size struct {
 	fullCommand string
 
 	// flags
-	groupByClass bool
-	humanize     bool
+	groupBy  string
+	depth    int
+	humanize bool
+	format   string
 
 	storageOpts storage.Options
 }
@@ -98,7 +124,7 @@ func (sz Size) Run(ctx context.Context) error {
 		return err
 	}
 
-	storageTotal := map[string]sizeAndCount{}
+	groupTotal := map[string]sizeAndCount{}
 	total := sizeAndCount{}
 
 	var merror error
@@ -113,46 +139,118 @@ func (sz Size) Run(ctx context.Context) error {
 			printError(sz.fullCommand, sz.op, err)
 			continue
 		}
-		storageClass := string(object.StorageClass)
-		s := storageTotal[storageClass]
+		key := sz.groupKey(object)
+		s := groupTotal[key]
 		s.addObject(object)
-		storageTotal[storageClass] = s
+		groupTotal[key] = s
 
 		total.addObject(object)
 	}
 
-	if !sz.groupByClass {
-		msg := SizeMessage{
-			Source:        srcurl.String(),
-			Count:         total.count,
-			Size:          total.size,
-			showHumanized: sz.humanize,
+	if sz.groupBy == "" {
+		msg := ReportMessage{
+			format: sz.format,
+			reports: []Report{
+				SizeMessage{
+					Source:        srcurl.String(),
+					Count:         total.count,
+					Size:          total.size,
+					showHumanized: sz.humanize,
+				},
+			},
 		}
-		log.Info(msg)
+		log.Summary(msg)
 		return nil
 	}
 
-	for k, v := range storageTotal {
+	keys := make([]string, 0, len(groupTotal))
+	for k := range groupTotal {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var reports []Report
+	for _, k := range keys {
+		v := groupTotal[k]
 		msg := SizeMessage{
 			Source:        srcurl.String(),
-			StorageClass:  k,
 			Count:         v.count,
 			Size:          v.size,
 			showHumanized: sz.humanize,
+			showPrefix:    sz.groupBy == groupByPrefix,
+		}
+		if sz.groupBy == groupByPrefix {
+			msg.Prefix = k
+		} else {
+			msg.StorageClass = k
 		}
-		log.Info(msg)
+		reports = append(reports, msg)
+	}
+
+	if len(reports) > 0 {
+		log.Summary(ReportMessage{format: sz.format, reports: reports})
 	}
 	return merror
 }
 
+const (
+	groupByStorageClass = "storage-class"
+	groupByPrefix       = "prefix"
+)
+
+// sizeGroupBy returns the key to group sizes by.
+func sizeGroupBy(c *cli.Context) string {
+	switch {
+	case c.String("group-by") != "":
+		return c.String("group-by")
+	case c.IsSet("depth"):
+		return groupByPrefix
+	case c.Bool("group"):
+		return groupByStorageClass
+	}
+	return ""
+}
+
+// groupKey returns the group of the object.
+func (sz Size) groupKey(object *storage.Object) string {
+	switch sz.groupBy {
+	case groupByStorageClass:
+		return string(object.StorageClass)
+	case groupByPrefix:
+		depth := sz.depth
+		if depth == 0 {
+			depth = 1
+		}
+		return leadingDirs(object.URL.Relative(), depth)
+	}
+	return ""
+}
+
+// leadingDirs returns at most n leading directories of the given path, with
+// a trailing slash. It returns an empty string for objects at the top level.
+func leadingDirs(name string, n int) string {
+	dir := path.Dir(filepath.ToSlash(name))
+	if dir == "." || dir == "/" {
+		return ""
+	}
+
+	parts := strings.Split(dir, "/")
+	if len(parts) > n {
+		parts = parts[:n]
+	}
+	return strings.Join(parts, "/") + "/"
+}
+
 // SizeMessage is the structure for logging disk usage.
 type SizeMessage struct {
 	Source       string `json:"source"`
 	StorageClass string `json:"storage_class,omitempty"`
+	Prefix       string `json:"prefix,omitempty"`
 	Count        int64  `json:"count"`
 	Size         int64  `json:"size"`
 
 	showHumanized bool
+	showPrefix    bool
 }
 
 // humanize is a helper method to humanize bytes.
@@ -169,6 +267,9 @@ func (s SizeMessage) String() string {
 	if s.StorageClass != "" {
 		storageCls = fmt.Sprintf(" [%s]", s.StorageClass)
 	}
+	if s.Prefix != "" {
+		storageCls = fmt.Sprintf(" [%s]", s.Prefix)
+	}
 	return fmt.Sprintf(
 		"%s bytes in %d objects: %s%s",
 		s.humanize(),
@@ -183,6 +284,28 @@ func (s SizeMessage) JSON() string {
 	return strutil.JSON(s)
 }
 
+// Header returns the column names of SizeMessage.
+func (s SizeMessage) Header() []string {
+	if s.showPrefix {
+		return []string{"Source", "Prefix", "Count", "Size"}
+	}
+	return []string{"Source", "StorageClass", "Count", "Size"}
+}
+
+// Row returns the column values of SizeMessage.
+func (s SizeMessage) Row() []string {
+	group := s.StorageClass
+	if s.showPrefix {
+		group = s.Prefix
+	}
+	return []string{
+		s.Source,
+		group,
+		fmt.Sprintf("%d", s.Count),
+		s.humanize(),
+	}
+}
+
 type sizeAndCount struct {
 	size  int64
 	count int64
@@ -197,5 +320,20 @@ func validateDUCommand(c *cli.Context) error {
 	if c.Args().Len() != 1 {
 		return fmt.Errorf("expected only 1 argument")
 	}
-	return nil
+
+	switch groupBy := c.String("group-by"); groupBy {
+	case "", groupByStorageClass, groupByPrefix:
+	default:
+		return fmt.Errorf("unknown group key %q", groupBy)
+	}
+
+	if c.IsSet("depth") {
+		if c.Int("depth") < 1 {
+			return fmt.Errorf("depth must be a positive number")
+		}
+		if c.String("group-by") == groupByStorageClass || c.Bool("group") {
+			return fmt.Errorf("--depth flag can only be used with prefix grouping")
+		}
+	}
+	return validateFormatFlag(c)
 }
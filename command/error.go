@@ -3,11 +3,15 @@ package command
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-multierror"
 
 	errorpkg "github.com/peak/s5cmd/error"
 	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/storage"
 	"github.com/peak/s5cmd/storage/url"
 )
 
@@ -27,16 +31,19 @@ func printError(command, op string, err error) {
 		return
 	}
 
+	recordAuthError(err)
+
 	// check if we have our own error type
 	{
 		cerr, ok := err.(*errorpkg.Error)
 		if ok {
 			msg := log.ErrorMessage{
 				Err:       cleanupError(cerr.Err),
+				Code:      storage.ErrorCode(cerr.Err),
 				Command:   cerr.FullCommand(),
 				Operation: cerr.Op,
 			}
-			log.Error(msg)
+			logError(msg)
 			return
 		}
 	}
@@ -50,20 +57,22 @@ func printError(command, op string, err error) {
 				if ok {
 					msg := log.ErrorMessage{
 						Err:       cleanupError(customErr.Err),
+						Code:      storage.ErrorCode(customErr.Err),
 						Command:   customErr.FullCommand(),
 						Operation: customErr.Op,
 					}
-					log.Error(msg)
+					logError(msg)
 					continue
 				}
 
 				msg := log.ErrorMessage{
 					Err:       cleanupError(err),
+					Code:      storage.ErrorCode(err),
 					Command:   command,
 					Operation: op,
 				}
 
-				log.Error(msg)
+				logError(msg)
 			}
 			return
 		}
@@ -72,10 +81,79 @@ func printError(command, op string, err error) {
 	// we don't know the exact error type. log the error as is.
 	msg := log.ErrorMessage{
 		Err:       cleanupError(err),
+		Code:      storage.ErrorCode(err),
 		Command:   command,
 		Operation: op,
 	}
+	logError(msg)
+}
+
+// logError logs the error message and records it as a failure of the run.
+func logError(msg log.ErrorMessage) {
 	log.Error(msg)
+	stat.AddFailure(msg.Command, msg.Operation, msg.Err)
+
+	n := atomic.AddInt64(&errorCount, 1)
+
+	if atomic.LoadInt32(&exitOnError) == 1 {
+		// cancelation errors of the other jobs are not printed.
+		if n == 1 {
+			log.Error(log.ErrorMessage{
+				Err: "exiting on error, running jobs are canceled",
+			})
+		}
+		cancelRun()
+		return
+	}
+
+	if n == atomic.LoadInt64(&maxErrors) {
+		log.Error(log.ErrorMessage{
+			Err: fmt.Sprintf("max errors (%v) reached, no more jobs will be run", n),
+		})
+		parallel.Stop()
+	}
+}
+
+var (
+	// maxErrors is the number of failures which stops the run, if it is not
+	// 0. errorCount is the number of failures so far.
+	maxErrors, errorCount int64
+
+	// exitOnError is set if the run is canceled at the first failure.
+	exitOnError int32
+
+	// cancelRun cancels the context of the run.
+	cancelRun = func() {}
+)
+
+func setMaxErrors(n int) {
+	atomic.StoreInt64(&maxErrors, int64(n))
+}
+
+func setExitOnError(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&exitOnError, 1)
+	}
+}
+
+// errStopped returns an error if the run is stopped before all of its jobs
+// are run, since a job or too many jobs have failed, or the deadline is
+// exceeded.
+func errStopped() error {
+	n := atomic.LoadInt64(&errorCount)
+	if atomic.LoadInt32(&exitOnError) == 1 && n > 0 {
+		return fmt.Errorf("exited on error")
+	}
+
+	max := atomic.LoadInt64(&maxErrors)
+	if max > 0 && n >= max {
+		return fmt.Errorf("too many errors")
+	}
+
+	if deadlineExceeded() {
+		return fmt.Errorf("deadline exceeded")
+	}
+	return nil
 }
 
 // cleanupError converts multiline messages into
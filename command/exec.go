@@ -0,0 +1,146 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+// prepareExecTask prepares streaming a remote object into the command given
+// with --exec flag.
+func (c Copy) prepareExecTask(ctx context.Context, srcurl *url.URL) func() error {
+	return func() error {
+		err := c.withJobTimeout(ctx, func(ctx context.Context) error {
+			return c.doExec(ctx, srcurl)
+		})
+		if err != nil {
+			return &errorpkg.Error{
+				Op:  c.op,
+				Src: srcurl,
+				Err: err,
+			}
+		}
+		return nil
+	}
+}
+
+// doExec streams the content of a remote object into the standard input of
+// the command. The object is not written to the disk, and the transfer fails
+// if the command fails.
+func (c Copy) doExec(ctx context.Context, srcurl *url.URL) error {
+	msg := log.InfoMessage{
+		Operation: c.op,
+		Source:    srcurl,
+	}
+
+	if c.storageOpts.DryRun {
+		return c.report(ctx, msg)
+	}
+
+	srcClient, err := storage.NewRemoteClient(ctx, srcurl, c.srcStorageOpts())
+	if err != nil {
+		return err
+	}
+
+	var rc io.ReadCloser
+	if c.byteRange != "" {
+		rc, err = srcClient.ReadRange(ctx, srcurl, c.byteRange)
+	} else {
+		rc, err = srcClient.Read(ctx, srcurl)
+	}
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	job := hookJob{
+		operation: c.op,
+		command:   msg.String(),
+		source:    srcurl,
+		status:    hookStatusSuccess,
+	}
+	if c.srcObject != nil {
+		job.size = c.srcObject.Size
+	}
+
+	size, err := runExec(ctx, c.exec, rc, job)
+	if err != nil {
+		return err
+	}
+
+	msg.Object = &storage.Object{Size: size}
+	return c.report(ctx, msg)
+}
+
+// runExec runs the command with the shell and writes the content read from r
+// to its standard input. The output of the command is written to the
+// standard output and error of s5cmd. It returns the number of bytes read
+// by the command.
+func runExec(ctx context.Context, command string, r io.Reader, job hookJob) (int64, error) {
+	stdin := &countingReader{r: r}
+
+	cmd := shellCommand(ctx, command)
+	cmd.Env = append(os.Environ(), job.environ()...)
+	cmd.Stdin = stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdin.n, fmt.Errorf("command %q failed: %v", command, err)
+	}
+	return stdin.n, nil
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// validateExecFlag validates the copy of the source into the command given
+// with --exec flag.
+func validateExecFlag(c *cli.Context, dsts []string) error {
+	if c.Command.Name == "mv" {
+		return fmt.Errorf("--exec flag can not be used with mv command")
+	}
+	if len(dsts) > 0 {
+		return fmt.Errorf("destination can not be given with --exec flag")
+	}
+	if c.String("manifest") != "" {
+		return fmt.Errorf("--exec and --manifest flags can not be used together")
+	}
+
+	srcurl, err := newSourceURL(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	if !srcurl.IsRemote() {
+		return fmt.Errorf("--exec flag can only be used with remote sources")
+	}
+	if (srcurl.IsBucket() || srcurl.IsPrefix()) && c.String("files-from") == "" {
+		return fmt.Errorf("source argument must contain wildcard character")
+	}
+
+	if err := validateInventoryFlag(c, srcurl); err != nil {
+		return err
+	}
+	if err := validateScheduling(c.String("scheduling")); err != nil {
+		return err
+	}
+	_, err = parseByteRange(c.String("range"))
+	return err
+}
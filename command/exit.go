@@ -0,0 +1,135 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/storage"
+)
+
+// exit codes of the app.
+const (
+	// ExitSuccess is the exit code of the runs without a failure.
+	ExitSuccess = 0
+
+	// ExitFailure is the exit code of the runs with failed jobs.
+	ExitFailure = 1
+
+	// ExitUsage is the exit code of the invalid flags or arguments.
+	ExitUsage = 2
+
+	// ExitAuth is the exit code of the runs with jobs failed to authenticate
+	// or to be authorized.
+	ExitAuth = 3
+
+	// ExitCommandNotFound is the exit code of the unknown commands.
+	ExitCommandNotFound = 127
+
+	// ExitInterrupted is the exit code of the runs interrupted by a signal.
+	ExitInterrupted = 130
+)
+
+var (
+	// errInterrupted is the error of the runs whose context is canceled by
+	// the caller of Main, i.e. a signal is received.
+	errInterrupted = errors.New("interrupted")
+
+	// authFailed is set if a job failed to authenticate or to be authorized.
+	authFailed int32
+
+	// runsUntilInterrupted is set if the command runs until it is
+	// interrupted, so an interrupt is not a failure.
+	runsUntilInterrupted int32
+)
+
+// jobError is an error returned from the action of a command, i.e. its jobs
+// are run. Errors returned before the action are caused by invalid flags or
+// arguments.
+type jobError struct {
+	err error
+}
+
+func (e *jobError) Error() string { return e.err.Error() }
+
+func (e *jobError) Unwrap() error { return e.err }
+
+// ExitCode returns the exit code of the error returned from Main.
+func ExitCode(err error) int {
+	var jerr *jobError
+	switch {
+	case err == nil:
+		return ExitSuccess
+	case err == errInterrupted:
+		return ExitInterrupted
+	case atomic.LoadInt32(&authFailed) == 1:
+		return ExitAuth
+	case errors.As(err, &jerr):
+		return ExitFailure
+	default:
+		return ExitUsage
+	}
+}
+
+func setRunsUntilInterrupted() {
+	atomic.StoreInt32(&runsUntilInterrupted, 1)
+}
+
+// exitError converts the error returned from the app to the one of its exit
+// code. ctx is the context given to Main.
+func exitError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		if err == nil && atomic.LoadInt32(&runsUntilInterrupted) == 1 {
+			return nil
+		}
+		return errInterrupted
+	}
+	if err != nil {
+		return err
+	}
+	if err := errStopped(); err != nil {
+		return &jobError{err: err}
+	}
+	return nil
+}
+
+// markJobErrors wraps the errors returned from the actions of the commands,
+// so they are distinguished from the errors of invalid flags and arguments.
+func markJobErrors(commands []*cli.Command) {
+	for _, cmd := range commands {
+		action := cmd.Action
+		if action == nil {
+			continue
+		}
+		cmd.Action = func(c *cli.Context) error {
+			err := action(c)
+			if err == nil {
+				return nil
+			}
+
+			var jerr *jobError
+			if errors.As(err, &jerr) {
+				return err
+			}
+			return &jobError{err: err}
+		}
+	}
+}
+
+// recordAuthError records whether the error is an authentication or an
+// authorization failure.
+func recordAuthError(err error) {
+	if merr, ok := err.(*multierror.Error); ok {
+		for _, err := range merr.Errors {
+			recordAuthError(err)
+		}
+		return
+	}
+
+	if storage.IsAuthError(err) {
+		atomic.StoreInt32(&authFailed, 1)
+	}
+}
@@ -0,0 +1,41 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+
+	errorpkg "github.com/peak/s5cmd/error"
+)
+
+func TestExitCode(t *testing.T) {
+	ctx := context.Background()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	jobErr := &jobError{err: fmt.Errorf("no object found")}
+
+	assert.Equal(t, ExitSuccess, ExitCode(exitError(ctx, nil)))
+	assert.Equal(t, ExitFailure, ExitCode(exitError(ctx, jobErr)))
+	assert.Equal(t, ExitUsage, ExitCode(exitError(ctx, fmt.Errorf("expected only 1 argument"))))
+	assert.Equal(t, ExitInterrupted, ExitCode(exitError(canceled, nil)))
+	assert.Equal(t, ExitInterrupted, ExitCode(exitError(canceled, jobErr)))
+
+	defer atomic.StoreInt32(&authFailed, 0)
+
+	recordAuthError(fmt.Errorf("no object found"))
+	assert.Equal(t, ExitFailure, ExitCode(exitError(ctx, jobErr)))
+
+	var merr error
+	merr = multierror.Append(merr, &errorpkg.Error{
+		Op:  "cp",
+		Err: awserr.New("AccessDenied", "Access Denied", nil),
+	})
+	recordAuthError(merr)
+	assert.Equal(t, ExitAuth, ExitCode(exitError(ctx, &jobError{err: merr})))
+}
@@ -2,8 +2,15 @@ package command
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
 	"sync"
 
+	"github.com/urfave/cli/v2"
+
 	"github.com/peak/s5cmd/storage"
 	"github.com/peak/s5cmd/storage/url"
 )
@@ -21,7 +28,7 @@ func expandSource(
 	// if the source is local, we send a Stat call to know if  we have
 	// directory or file to walk. For remote storage, we don't want to send
 	// Stat since it doesn't have any folder semantics.
-	if !srcurl.HasGlob() && !srcurl.IsRemote() {
+	if !srcurl.HasGlob() && !srcurl.IsRemote() && !srcurl.IsHTTP() {
 		obj, err := client.Stat(ctx, srcurl)
 		if err != nil {
 			return nil, err
@@ -89,3 +96,91 @@ func expandSources(
 
 	return ch
 }
+
+// openFilesFrom opens the file given with --files-from flag. "-" is the
+// standard input.
+func openFilesFrom(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// expandFilesFrom is a non-blocking source iterator which creates objects
+// from the keys or paths read from r, one per line. Keys are relative to the
+// base url and they are not listed, so the objects don't have any metadata.
+func expandFilesFrom(ctx context.Context, r io.Reader, base *url.URL) <-chan *storage.Object {
+	ch := make(chan *storage.Object)
+
+	basepath := base.String()
+	if !strings.HasSuffix(basepath, "/") {
+		basepath += "/"
+	}
+
+	go func() {
+		defer close(ch)
+
+		scanner := NewScanner(ctx, r)
+		for line := range scanner.Scan() {
+			line = strings.TrimSuffix(line, "\r")
+			if line == "" {
+				continue
+			}
+
+			srcurl, err := url.New(basepath + strings.TrimPrefix(line, "/"))
+			if err != nil {
+				ch <- &storage.Object{Err: fmt.Errorf("%q: %v", line, err)}
+				continue
+			}
+			srcurl.SetRelative(basepath)
+
+			ch <- &storage.Object{URL: srcurl}
+		}
+
+		if err := scanner.Err(); err != nil {
+			ch <- &storage.Object{Err: err}
+		}
+	}()
+
+	return ch
+}
+
+// expandInventory returns the objects matching srcurl in the S3 Inventory
+// report with the given manifest url.
+func expandInventory(ctx context.Context, client storage.Storage, manifest string, srcurl *url.URL) (<-chan *storage.Object, error) {
+	manifesturl, err := url.New(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	s3, ok := client.(*storage.S3)
+	if !ok {
+		return nil, fmt.Errorf("inventory can only be used with remote sources")
+	}
+	return s3.ListInventory(ctx, manifesturl, srcurl), nil
+}
+
+// validateInventoryFlag validates the source for --inventory flag.
+func validateInventoryFlag(c *cli.Context, srcurl *url.URL) error {
+	manifest := c.String("inventory")
+	if manifest == "" {
+		return nil
+	}
+
+	if c.String("files-from") != "" {
+		return fmt.Errorf("--inventory and --files-from flags can not be used together")
+	}
+
+	manifesturl, err := url.New(manifest)
+	if err != nil {
+		return err
+	}
+	if !manifesturl.IsRemote() || manifesturl.IsBucket() || manifesturl.IsPrefix() || manifesturl.HasGlob() {
+		return fmt.Errorf("inventory manifest must be a remote object")
+	}
+
+	if !srcurl.IsRemote() || !srcurl.HasGlob() {
+		return fmt.Errorf("source argument must be a remote url with wildcard character with --inventory flag")
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -328,6 +329,74 @@ func TestExpandSource_Do_Not_Follow_Symlinks(t *testing.T) {
 	assert.Equal(t, []string{workdirJoin}, expected)
 }
 
+func TestExpandSource_Follow_Symlinks_Skips_Loops(t *testing.T) {
+	folderLayout := []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("f1.txt", ""),
+			fs.WithDir("b"),
+		),
+		fs.WithSymlink("a/b/parent_link", "a"),
+	}
+
+	workdir := fs.NewDir(t, "expandsourcetest", folderLayout...)
+	defer workdir.Remove()
+
+	ctx := context.Background()
+	workdirUrl, _ := url.New(workdir.Path())
+
+	//follow symbolic links
+	ch, _ := expandSource(ctx, storage.NewLocalClient(storage.Options{}), true, workdirUrl)
+	var (
+		expected []string
+		errs     []error
+	)
+	for obj := range ch {
+		if obj.Err != nil {
+			errs = append(errs, obj.Err)
+			continue
+		}
+		expected = append(expected, obj.URL.Absolute())
+	}
+	workdirJoin := filepath.ToSlash(workdir.Join("a/f1.txt"))
+	assert.Equal(t, []string{workdirJoin}, expected)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Error(), "symbolic link loop is skipped")
+}
+
+func TestExpandSource_Preserve_Symlinks(t *testing.T) {
+	folderLayout := []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("f1.txt", ""),
+			fs.WithDir("b",
+				fs.WithFile("f2.txt", "")),
+		),
+		fs.WithSymlink("file_link", "a/f1.txt"),
+		fs.WithSymlink("dir_link", "a/b"),
+	}
+
+	workdir := fs.NewDir(t, "expandsourcetest", folderLayout...)
+	defer workdir.Remove()
+
+	ctx := context.Background()
+	workdirUrl, _ := url.New(workdir.Path())
+
+	//preserve symbolic links
+	client := storage.NewLocalClient(storage.Options{PreserveSymlinks: true})
+	ch, _ := expandSource(ctx, client, true, workdirUrl)
+	symlinks := map[string]bool{}
+	for obj := range ch {
+		symlinks[obj.URL.Absolute()] = obj.Type.IsSymlink()
+	}
+	assert.Equal(t, map[string]bool{
+		filepath.ToSlash(workdir.Join("a/f1.txt")):   false,
+		filepath.ToSlash(workdir.Join("a/b/f2.txt")): false,
+		filepath.ToSlash(workdir.Join("dir_link")):   true,
+		filepath.ToSlash(workdir.Join("file_link")):  true,
+	}, symlinks)
+}
+
 func keys(urls map[string][]*storage.Object) []string {
 	var urlKeys []string
 	for key := range urls {
@@ -346,3 +415,57 @@ func generateObjects(objects []*storage.Object) <-chan *storage.Object {
 	}()
 	return ch
 }
+
+func TestExpandFilesFrom(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		base         string
+		input        string
+		wantObjects  []string
+		wantRelative []string
+	}{
+		{
+			name:         "remote_prefix",
+			base:         "s3://bucket/prefix/",
+			input:        "a.txt\n\nb/c.txt\r\n",
+			wantObjects:  []string{"s3://bucket/prefix/a.txt", "s3://bucket/prefix/b/c.txt"},
+			wantRelative: []string{"a.txt", "b/c.txt"},
+		},
+		{
+			name:         "bucket",
+			base:         "s3://bucket",
+			input:        "/a.txt\n",
+			wantObjects:  []string{"s3://bucket/a.txt"},
+			wantRelative: []string{"a.txt"},
+		},
+		{
+			name:         "local_directory",
+			base:         "dir",
+			input:        "a/b.txt\n",
+			wantObjects:  []string{filepath.Join("dir", "a", "b.txt")},
+			wantRelative: []string{filepath.Join("a", "b.txt")},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			base, err := url.New(tc.base)
+			assert.NoError(t, err)
+
+			var objects, relatives []string
+			for obj := range expandFilesFrom(context.Background(), strings.NewReader(tc.input), base) {
+				assert.NoError(t, obj.Err)
+				objects = append(objects, obj.URL.String())
+				relatives = append(relatives, obj.URL.Relative())
+			}
+
+			assert.Equal(t, tc.wantObjects, objects)
+			assert.Equal(t, tc.wantRelative, relatives)
+		})
+	}
+}
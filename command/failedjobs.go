@@ -0,0 +1,129 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/kballard/go-shellquote"
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+)
+
+// failedJobsFile records each failed job to the file of --failed-jobs-file
+// flag as a command line, so the failed jobs can be run again with
+// "s5cmd -f". It is safe for concurrent use.
+var failedJobsFile struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// replayExcludedFlags are the flags which are not given to the command lines
+// of the failed jobs. The sources and the destinations of the jobs are
+// already expanded, and a manifest of the run is not overwritten by a rerun.
+var replayExcludedFlags = map[string]bool{
+	"files-from":        true,
+	"destinations-file": true,
+	"inventory":         true,
+	"flatten":           true,
+	"strip-components":  true,
+	"keep-empty-dirs":   true,
+	"manifest":          true,
+}
+
+// startFailedJobsFile creates the file of the failed jobs. The file of a
+// previous run is truncated.
+func startFailedJobsFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	failedJobsFile.f = f
+	return nil
+}
+
+// stopFailedJobsFile closes the file of the failed jobs.
+func stopFailedJobsFile() error {
+	failedJobsFile.mu.Lock()
+	defer failedJobsFile.mu.Unlock()
+
+	if failedJobsFile.f == nil {
+		return nil
+	}
+	err := failedJobsFile.f.Close()
+	failedJobsFile.f = nil
+	return err
+}
+
+// recordFailedJob writes the command line of a failed job. Each line is
+// written with a single write call, so the recorded jobs are kept if the
+// process is interrupted. The arguments are quoted, so the "#" characters of
+// the keys are not taken as the comments of the lines by "s5cmd -f".
+func recordFailedJob(args ...string) error {
+	failedJobsFile.mu.Lock()
+	defer failedJobsFile.mu.Unlock()
+
+	if failedJobsFile.f == nil {
+		return nil
+	}
+	_, err := failedJobsFile.f.WriteString(shellquote.Join(args...) + "\n")
+	return err
+}
+
+// recordFailedJobErrors records the jobs of the given error, and of each of
+// the errors of a fan-out. The errors which don't describe their source are
+// not recorded.
+func recordFailedJobErrors(op string, flags []string, err error) error {
+	errs := []error{err}
+	if merr, ok := err.(*multierror.Error); ok {
+		errs = merr.Errors
+	}
+
+	var merror error
+	for _, err := range errs {
+		cerr, ok := err.(*errorpkg.Error)
+		if !ok || cerr.Src == nil {
+			continue
+		}
+
+		args := append([]string{op}, flags...)
+		args = append(args, cerr.Src.String())
+		if cerr.Dst != nil {
+			args = append(args, cerr.Dst.String())
+		}
+		if err := recordFailedJob(args...); err != nil {
+			merror = multierror.Append(merror, err)
+		}
+	}
+	return merror
+}
+
+// jobFlags returns the flags given to the command, in the form of command
+// line arguments, to rerun the jobs of the command.
+func jobFlags(c *cli.Context) []string {
+	var args []string
+	for _, flag := range c.Command.Flags {
+		name := flag.Names()[0]
+		if replayExcludedFlags[name] || !c.IsSet(name) {
+			continue
+		}
+
+		switch flag.(type) {
+		case *cli.BoolFlag:
+			if c.Bool(name) {
+				args = append(args, "--"+name)
+			} else {
+				args = append(args, "--"+name+"=false")
+			}
+		case *cli.StringSliceFlag:
+			for _, value := range c.StringSlice(name) {
+				args = append(args, "--"+name, value)
+			}
+		default:
+			args = append(args, "--"+name, fmt.Sprint(c.Value(name)))
+		}
+	}
+	return args
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli/v2"
+)
+
+func TestJobFlags(t *testing.T) {
+	testcases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name: "no flags",
+			args: []string{"src", "dst"},
+		},
+		{
+			name:     "flags of the jobs are kept in order of the command",
+			args:     []string{"--tag", "a=1", "-n", "--storage-class", "STANDARD_IA", "--tag", "b=2", "src", "dst"},
+			expected: []string{"--no-clobber", "--storage-class", "STANDARD_IA", "--tag", "a=1", "--tag", "b=2"},
+		},
+		{
+			name:     "bool flags set to false",
+			args:     []string{"--follow-symlinks=false", "src", "dst"},
+			expected: []string{"--follow-symlinks=false"},
+		},
+		{
+			name:     "flags of the expansion are excluded",
+			args:     []string{"--flatten", "--strip-components", "1", "--files-from", "keys.txt", "--concurrency", "10", "src", "dst"},
+			expected: []string{"--concurrency", "10"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			app := &cli.App{
+				Commands: []*cli.Command{
+					{
+						Name:  "cp",
+						Flags: copyCommandFlags,
+						Action: func(c *cli.Context) error {
+							got = jobFlags(c)
+							return nil
+						},
+					},
+				},
+			}
+
+			err := app.Run(append([]string{"s5cmd", "cp"}, tc.args...))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+// copyDestinations returns the destinations of a copy, which are the
+// arguments after the source and the lines of the file given with
+// --destinations-file flag.
+func copyDestinations(c *cli.Context) ([]string, error) {
+	dsts := c.Args().Tail()
+
+	name := c.String("destinations-file")
+	if name == "" {
+		return dsts, nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		dsts = append(dsts, line)
+	}
+	return dsts, scanner.Err()
+}
+
+// prepareFanoutUploadTask prepares the upload of a local file to multiple
+// remote destinations.
+func (c Copy) prepareFanoutUploadTask(
+	ctx context.Context,
+	srcurl *url.URL,
+	dsturls []*url.URL,
+	isBatch bool,
+) func() error {
+	return func() error {
+		prepared := make([]*url.URL, 0, len(dsturls))
+		for _, dsturl := range dsturls {
+			prepared = append(prepared, prepareRemoteDestination(srcurl, dsturl, c.flatten, c.stripComponents, isBatch))
+		}
+		return c.withJobTimeout(ctx, func(ctx context.Context) error {
+			return c.doFanoutUpload(ctx, srcurl, prepared)
+		})
+	}
+}
+
+// doFanoutUpload uploads a local file to multiple remote destinations. The
+// file is read once and its content is streamed to the uploads of all of the
+// destinations. The errors of the destinations are returned together.
+func (c Copy) doFanoutUpload(ctx context.Context, srcurl *url.URL, dsturls []*url.URL) error {
+	srcClient := storage.NewLocalClient(c.srcStorageOpts())
+
+	var merr error
+	fail := func(dsturl *url.URL, err error) {
+		merr = multierror.Append(merr, &errorpkg.Error{
+			Op:  c.op,
+			Src: srcurl,
+			Dst: dsturl,
+			Err: err,
+		})
+	}
+
+	// symbolic links are uploaded with their targets, they are not read.
+	if c.preserveSymlinks {
+		target, err := srcClient.Readlink(srcurl.Absolute())
+		if err != nil {
+			return err
+		}
+		if target != "" {
+			for _, dsturl := range dsturls {
+				if err := c.doUploadSymlink(ctx, srcClient, srcurl, dsturl, target); err != nil {
+					fail(dsturl, err)
+				}
+			}
+			return merr
+		}
+	}
+
+	file, err := srcClient.Open(srcurl.Absolute())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var targets []*url.URL
+	for _, dsturl := range dsturls {
+		err := c.shouldOverride(ctx, srcurl, dsturl)
+		if err != nil {
+			if errorpkg.IsWarning(err) {
+				printDebug(c.op, srcurl, dsturl, err)
+			} else {
+				fail(dsturl, err)
+			}
+			continue
+		}
+		targets = append(targets, dsturl)
+	}
+	if len(targets) == 0 {
+		return merr
+	}
+
+	metadata, err := c.uploadMetadata(ctx, srcClient, srcurl, file)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader = file
+	if c.sparse {
+		reader, err = storage.NewSparseReader(file)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		readers  = fanoutReaders(reader, len(targets))
+		errs     = make([]error, len(targets))
+		uploaded = make([]bool, len(targets))
+		wg       sync.WaitGroup
+	)
+	for i, dsturl := range targets {
+		wg.Add(1)
+		go func(i int, dsturl *url.URL) {
+			defer wg.Done()
+			uploaded[i], errs[i] = c.uploadFanoutReader(ctx, readers[i], srcurl, dsturl, metadata)
+		}(i, dsturl)
+	}
+	wg.Wait()
+
+	var size int64
+	if obj, err := srcClient.Stat(ctx, srcurl); err == nil {
+		size = obj.Size
+	}
+
+	for i, dsturl := range targets {
+		if errs[i] != nil {
+			fail(dsturl, errs[i])
+			continue
+		}
+		if !uploaded[i] {
+			continue
+		}
+
+		msg := log.InfoMessage{
+			Operation:   c.op,
+			Source:      srcurl,
+			Destination: dsturl,
+			Object: &storage.Object{
+				Size:         size,
+				StorageClass: c.storageClass,
+			},
+		}
+		if err := c.report(ctx, msg); err != nil {
+			fail(dsturl, err)
+		}
+	}
+	return merr
+}
+
+// uploadFanoutReader uploads the content read from the reader of a fan-out
+// to the destination. The reader is closed once the upload is finished, so
+// the other uploads of the fan-out are not blocked by it.
+func (c Copy) uploadFanoutReader(
+	ctx context.Context,
+	reader *io.PipeReader,
+	srcurl, dsturl *url.URL,
+	metadata storage.Metadata,
+) (bool, error) {
+	defer reader.Close()
+
+	dstClient, err := storage.NewRemoteClient(ctx, dsturl, c.dstStorageOpts())
+	if err != nil {
+		return false, err
+	}
+
+	err = dstClient.Put(ctx, reader, dsturl, metadata, c.concurrency, c.partSize)
+	if err != nil {
+		return false, c.conditionalWriteError(err, srcurl, dsturl)
+	}
+	return true, nil
+}
+
+// fanoutReaders returns n readers of the content of r. r is read once and
+// each chunk is written to all of the readers. A reader which is closed
+// doesn't receive the rest of the content, so a failed upload doesn't block
+// the others.
+func fanoutReaders(r io.Reader, n int) []*io.PipeReader {
+	readers := make([]*io.PipeReader, n)
+	writers := make([]*io.PipeWriter, n)
+	for i := range readers {
+		readers[i], writers[i] = io.Pipe()
+	}
+
+	go func() {
+		buf := make([]byte, 32*1024)
+		active := writers
+		for len(active) > 0 {
+			nr, rerr := r.Read(buf)
+			if nr > 0 {
+				alive := active[:0]
+				for _, w := range active {
+					if _, err := w.Write(buf[:nr]); err == nil {
+						alive = append(alive, w)
+					}
+				}
+				active = alive
+			}
+			if rerr != nil {
+				if rerr == io.EOF {
+					rerr = nil
+				}
+				for _, w := range active {
+					w.CloseWithError(rerr)
+				}
+				return
+			}
+		}
+	}()
+	return readers
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFanoutReaders(t *testing.T) {
+	t.Parallel()
+
+	content := strings.Repeat("s5cmd", 50*1024)
+	readers := fanoutReaders(strings.NewReader(content), 3)
+
+	// a closed reader doesn't block the others.
+	readers[0].Close()
+
+	var (
+		wg   sync.WaitGroup
+		got  = make([]string, len(readers))
+		errs = make([]error, len(readers))
+	)
+	for i := 1; i < len(readers); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b, err := ioutil.ReadAll(readers[i])
+			got[i], errs[i] = string(b), err
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i < len(readers); i++ {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, content, got[i])
+	}
+}
@@ -0,0 +1,424 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/kballard/go-shellquote"
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
+)
+
+var findHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] argument
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Find all objects under a prefix
+		 > s5cmd {{.HelpName}} s3://bucket/prefix/
+
+	2. Find parquet files larger than 100 megabytes
+		 > s5cmd {{.HelpName}} --name "*.parquet" --size +100M s3://bucket/prefix/
+
+	3. Find objects modified in the last 7 days
+		 > s5cmd {{.HelpName}} --mtime -7d s3://bucket/prefix/
+
+	4. Delete objects older than 30 days
+		 > s5cmd {{.HelpName}} --mtime +30d --delete s3://bucket/logs/
+
+	5. Copy gzip files smaller than 1 kilobyte to another bucket
+		 > s5cmd {{.HelpName}} --name "*.gz" --size -1K --exec "cp {} s3://target-bucket/" s3://bucket/prefix/
+`
+
+var findCommand = &cli.Command{
+	Name:               "find",
+	HelpName:           "find",
+	Usage:              "find objects matching given predicates",
+	CustomHelpTemplate: findHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "match base name of objects with given wildcard pattern",
+		},
+		&cli.StringFlag{
+			Name:  "size",
+			Usage: "match objects larger (+N) or smaller (-N) than, or exactly N bytes. K, M, G and T suffixes are supported",
+		},
+		&cli.StringFlag{
+			Name:  "mtime",
+			Usage: "match objects modified before (+D) or within (-D) given duration, such as 7d or 12h",
+		},
+		&cli.BoolFlag{
+			Name:  "print",
+			Usage: "print matching objects, default if no other action is given",
+		},
+		&cli.BoolFlag{
+			Name:  "delete",
+			Usage: "delete matching objects",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "do not ask for a confirmation of the objects deleted with --delete flag",
+		},
+		&cli.StringFlag{
+			Name:  "exec",
+			Usage: "run given command for each matching object, {} is replaced with the object url",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateFindCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		// predicates are validated before the action.
+		filter, _ := newFindFilter(c.String("name"), c.String("size"), c.String("mtime"), time.Now())
+
+		return Find{
+			src:         c.Args().First(),
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+			filter:      filter,
+			// flags
+			print:   c.Bool("print") || (!c.Bool("delete") && c.String("exec") == ""),
+			delete:  c.Bool("delete"),
+			confirm: c.Bool("delete") && shouldConfirm(c),
+			command: c.String("exec"),
+
+			cliCtx:      c,
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Find holds find operation flags and states.
+type Find struct {
+	src         string
+	op          string
+	fullCommand string
+	filter      findFilter
+
+	// flags
+	print   bool
+	delete  bool
+	confirm bool
+	command string
+
+	// cliCtx is used to run the commands given with --exec.
+	cliCtx      *cli.Context
+	storageOpts storage.Options
+}
+
+// Run finds objects matching the predicates and runs the actions on them.
+func (f Find) Run(ctx context.Context) error {
+	srcurl, err := findURL(f.src)
+	if err != nil {
+		printError(f.fullCommand, f.op, err)
+		return err
+	}
+
+	client, err := storage.NewClient(ctx, srcurl, f.storageOpts)
+	if err != nil {
+		printError(f.fullCommand, f.op, err)
+		return err
+	}
+
+	var (
+		merror  error
+		listErr error
+		objch   = make(chan *storage.Object)
+	)
+
+	go func() {
+		defer close(objch)
+
+		for object := range client.List(ctx, srcurl, false) {
+			if object.Type.IsDir() || errorpkg.IsCancelation(object.Err) {
+				continue
+			}
+
+			if err := object.Err; err != nil {
+				// no matching objects is not an error for find.
+				if err == storage.ErrNoObjectFound {
+					continue
+				}
+				listErr = multierror.Append(listErr, err)
+				printError(f.fullCommand, f.op, err)
+				continue
+			}
+
+			if !f.filter.match(object) {
+				continue
+			}
+
+			if f.print {
+				log.Summary(FindMessage{Object: object})
+			}
+			objch <- object
+		}
+	}()
+
+	switch {
+	case f.delete:
+		var delch <-chan *storage.Object = objch
+		if f.confirm {
+			delch, err = confirmObjects(ctx, promptOutput, promptInput, f.fullCommand, objch)
+			if err != nil {
+				printError(f.fullCommand, f.op, err)
+				return err
+			}
+		}
+		if err := f.deleteObjects(ctx, client, delch); err != nil {
+			merror = multierror.Append(merror, err)
+		}
+	case f.command != "":
+		if err := runCommands(f.cliCtx, f.commands(objch), runOptions{}); err != nil {
+			merror = multierror.Append(merror, err)
+		}
+	default:
+		for range objch {
+		}
+	}
+
+	// all actions consume the objects until the listing is completed.
+	if listErr != nil {
+		merror = multierror.Append(merror, listErr)
+	}
+	return merror
+}
+
+// deleteObjects deletes the given objects in batches.
+func (f Find) deleteObjects(ctx context.Context, client storage.Storage, objch <-chan *storage.Object) error {
+	urlch := make(chan *url.URL)
+	go func() {
+		defer close(urlch)
+		for object := range objch {
+			urlch <- object.URL
+		}
+	}()
+
+	var merror error
+	for obj := range client.MultiDelete(ctx, urlch) {
+		if err := obj.Err; err != nil {
+			if errorpkg.IsCancelation(obj.Err) {
+				continue
+			}
+
+			merror = multierror.Append(merror, obj.Err)
+			printError(f.fullCommand, f.op, obj.Err)
+			continue
+		}
+
+		msg := log.InfoMessage{
+			Operation: "rm",
+			Source:    obj.URL,
+		}
+		log.Info(msg)
+	}
+	return merror
+}
+
+// commands generates a command line for each object from the --exec
+// template.
+func (f Find) commands(objch <-chan *storage.Object) <-chan string {
+	linech := make(chan string)
+	go func() {
+		defer close(linech)
+		for object := range objch {
+			quoted := shellquote.Join(object.URL.Absolute())
+			linech <- strings.Replace(f.command, "{}", quoted, -1)
+		}
+	}()
+	return linech
+}
+
+// findURL returns the url to list given source recursively. Sources without
+// wildcards are treated as key prefixes.
+func findURL(src string) (*url.URL, error) {
+	srcurl, err := url.New(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if !srcurl.IsRemote() || srcurl.HasGlob() {
+		return srcurl, nil
+	}
+
+	if srcurl.IsBucket() {
+		return url.New(strings.TrimSuffix(src, "/") + "/*")
+	}
+	return url.New(src + "*")
+}
+
+// findFilter holds the predicates of find command. Zero values match all
+// objects.
+type findFilter struct {
+	name string
+
+	// size is matched by comparing object size with sizeRef, sizeCmp is
+	// the expected result of the comparison.
+	hasSize bool
+	sizeRef int64
+	sizeCmp int
+
+	// objects are matched if they are modified before the reference time,
+	// or after the reference time if newer is set.
+	hasMtime bool
+	mtimeRef time.Time
+	newer    bool
+}
+
+func newFindFilter(name, size, mtime string, now time.Time) (findFilter, error) {
+	filter := findFilter{name: name}
+
+	if name != "" {
+		if _, err := path.Match(name, ""); err != nil {
+			return filter, fmt.Errorf("invalid name pattern %q", name)
+		}
+	}
+
+	if size != "" {
+		cmp, value := splitSign(size)
+		n, err := strutil.ParseBytes(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.hasSize = true
+		filter.sizeRef = n
+		filter.sizeCmp = cmp
+	}
+
+	if mtime != "" {
+		cmp, value := splitSign(mtime)
+		if cmp == 0 {
+			return filter, fmt.Errorf("mtime %q must start with + or -", mtime)
+		}
+		d, err := parseDays(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid mtime %q", mtime)
+		}
+		filter.hasMtime = true
+		filter.mtimeRef = now.Add(-d)
+		filter.newer = cmp < 0
+	}
+
+	return filter, nil
+}
+
+// splitSign splits the leading + or - of the value. The returned comparison
+// is 1 for +, -1 for - and 0 if there is no sign.
+func splitSign(s string) (int, string) {
+	switch {
+	case strings.HasPrefix(s, "+"):
+		return 1, s[1:]
+	case strings.HasPrefix(s, "-"):
+		return -1, s[1:]
+	default:
+		return 0, s
+	}
+}
+
+// parseDays parses a duration which may have a "d" suffix for days.
+func parseDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func (f findFilter) match(object *storage.Object) bool {
+	if f.name != "" {
+		if ok, _ := path.Match(f.name, object.URL.Base()); !ok {
+			return false
+		}
+	}
+
+	if f.hasSize {
+		var cmp int
+		switch {
+		case object.Size > f.sizeRef:
+			cmp = 1
+		case object.Size < f.sizeRef:
+			cmp = -1
+		}
+		if cmp != f.sizeCmp {
+			return false
+		}
+	}
+
+	if f.hasMtime {
+		if object.ModTime == nil {
+			return false
+		}
+		if f.newer != object.ModTime.After(f.mtimeRef) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FindMessage is a structure for logging find results.
+type FindMessage struct {
+	Object *storage.Object `json:"object"`
+}
+
+// String returns the string representation of FindMessage.
+func (m FindMessage) String() string {
+	return m.Object.URL.String()
+}
+
+// JSON returns the JSON representation of FindMessage.
+func (m FindMessage) JSON() string {
+	return strutil.JSON(m.Object)
+}
+
+func validateFindCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only 1 argument")
+	}
+
+	if _, err := findURL(c.Args().First()); err != nil {
+		return err
+	}
+
+	if c.Bool("delete") && c.String("exec") != "" {
+		return fmt.Errorf("--delete and --exec flags can not be used together")
+	}
+
+	if command := c.String("exec"); command != "" && !strings.Contains(command, "{}") {
+		return fmt.Errorf("--exec command must contain {} placeholder")
+	}
+
+	_, err := newFindFilter(c.String("name"), c.String("size"), c.String("mtime"), time.Now())
+	return err
+}
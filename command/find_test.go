@@ -0,0 +1,88 @@
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func TestFindFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 3, 18, 12, 0, 0, 0, time.UTC)
+	twoDaysAgo := now.Add(-48 * time.Hour)
+
+	u, err := url.New("s3://bucket/prefix/data.parquet")
+	assert.NoError(t, err)
+
+	object := &storage.Object{
+		URL:     u,
+		Size:    2 << 20,
+		ModTime: &twoDaysAgo,
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		size     string
+		mtime    string
+		expected bool
+	}{
+		{name: "no_predicates", expected: true},
+		{name: "name_matches", pattern: "*.parquet", expected: true},
+		{name: "name_does_not_match", pattern: "*.gz", expected: false},
+		{name: "name_is_matched_against_base", pattern: "prefix/*", expected: false},
+		{name: "larger_than", size: "+1M", expected: true},
+		{name: "not_larger_than", size: "+2M", expected: false},
+		{name: "smaller_than", size: "-3M", expected: true},
+		{name: "not_smaller_than", size: "-1M", expected: false},
+		{name: "exact_size", size: "2M", expected: true},
+		{name: "modified_before", mtime: "+1d", expected: true},
+		{name: "not_modified_before", mtime: "+3d", expected: false},
+		{name: "modified_within", mtime: "-72h", expected: true},
+		{name: "not_modified_within", mtime: "-1d", expected: false},
+		{name: "all_predicates", pattern: "*.parquet", size: "+1M", mtime: "+1d", expected: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := newFindFilter(tc.pattern, tc.size, tc.mtime, now)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, filter.match(object))
+		})
+	}
+}
+
+func TestFindFilterErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		pattern        string
+		size           string
+		mtime          string
+		expectedErrStr string
+	}{
+		{name: "invalid_pattern", pattern: "[", expectedErrStr: `invalid name pattern "["`},
+		{name: "invalid_size", size: "+10X", expectedErrStr: `invalid byte size "10X"`},
+		{name: "mtime_without_sign", mtime: "7d", expectedErrStr: `mtime "7d" must start with + or -`},
+		{name: "invalid_mtime", mtime: "-7w", expectedErrStr: `invalid mtime "-7w"`},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newFindFilter(tc.pattern, tc.size, tc.mtime, time.Now())
+			assert.EqualError(t, err, tc.expectedErrStr)
+		})
+	}
+}
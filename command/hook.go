@@ -0,0 +1,112 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+const (
+	hookStatusSuccess = "success"
+	hookStatusFailure = "failure"
+)
+
+// hookJob describes the job a hook command is run for.
+type hookJob struct {
+	operation   string
+	command     string
+	source      *url.URL
+	destination *url.URL
+	size        int64
+	status      string
+	err         error
+}
+
+// environ returns the environment variables describing the job.
+func (j hookJob) environ() []string {
+	env := []string{
+		"S5CMD_OPERATION=" + j.operation,
+		"S5CMD_COMMAND=" + j.command,
+		"S5CMD_SOURCE=" + urlString(j.source),
+		"S5CMD_DESTINATION=" + urlString(j.destination),
+		fmt.Sprintf("S5CMD_SIZE=%d", j.size),
+		"S5CMD_STATUS=" + j.status,
+	}
+	if j.err != nil {
+		env = append(env, "S5CMD_ERROR="+cleanupError(j.err))
+		if code := storage.ErrorCode(j.err); code != "" {
+			env = append(env, "S5CMD_ERROR_CODE="+code)
+		}
+	}
+	return env
+}
+
+// successJob returns the job of a completed transfer.
+func successJob(msg log.InfoMessage) hookJob {
+	job := hookJob{
+		operation:   msg.Operation,
+		command:     msg.String(),
+		source:      msg.Source,
+		destination: msg.Destination,
+		status:      hookStatusSuccess,
+	}
+	if obj, ok := msg.Object.(*storage.Object); ok && obj != nil {
+		job.size = obj.Size
+	}
+	return job
+}
+
+// failureJob returns the job of a failed transfer. The source and the
+// destination are only known for the errors of the jobs.
+func failureJob(op string, err error) hookJob {
+	job := hookJob{
+		operation: op,
+		command:   op,
+		status:    hookStatusFailure,
+		err:       err,
+	}
+	if cerr, ok := err.(*errorpkg.Error); ok {
+		job.command = cerr.FullCommand()
+		job.source = cerr.Src
+		job.destination = cerr.Dst
+		job.err = cerr.Err
+	}
+	return job
+}
+
+// runHook runs the hook command with the shell. The output of the command
+// is written to the standard output and error of s5cmd.
+func runHook(ctx context.Context, command string, job hookJob) error {
+	cmd := shellCommand(ctx, command)
+	cmd.Env = append(os.Environ(), job.environ()...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q failed: %v", command, err)
+	}
+	return nil
+}
+
+// shellCommand returns the command to run the given command line with the
+// shell of the platform.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+func urlString(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// transferJournal records the sources of completed transfers, one per line,
+// so a rerun of the same command can skip them. It is safe for concurrent
+// use.
+type transferJournal struct {
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]struct{}
+}
+
+// openTransferJournal reads the completed transfers of previous runs from
+// the journal file and opens it for appending. The file is created if it
+// does not exist.
+func openTransferJournal(path string) (*transferJournal, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	done, partial, err := readTransferJournal(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	// terminate the partial line, so it is not joined with the next entry.
+	if partial {
+		if _, err := f.WriteString("\n"); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &transferJournal{f: f, done: done}, nil
+}
+
+// readTransferJournal reads the entries of a journal. A trailing line
+// without a newline is the result of an interrupted write, so it is ignored
+// and reported as partial.
+func readTransferJournal(r io.Reader) (map[string]struct{}, bool, error) {
+	done := map[string]struct{}{}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			return done, line != "", nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if line != "" {
+			done[line] = struct{}{}
+		}
+	}
+}
+
+// has reports whether the transfer of given source is completed.
+func (j *transferJournal) has(src string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	_, ok := j.done[src]
+	return ok
+}
+
+// add records a completed transfer. Each entry is written with a single
+// write call, so completed entries are kept if the process is interrupted.
+func (j *transferJournal) add(src string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, err := j.f.WriteString(src + "\n"); err != nil {
+		return err
+	}
+	j.done[src] = struct{}{}
+	return nil
+}
+
+// Close closes the journal file.
+func (j *transferJournal) Close() error {
+	return j.f.Close()
+}
@@ -3,6 +3,8 @@ package command
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/urfave/cli/v2"
@@ -39,6 +41,18 @@ Examples:
 
 	5. List all objects in a public bucket
 		 > s5cmd --no-sign-request {{.HelpName}} s3://bucket/*
+
+	6. List all objects in a bucket with human-readable sizes and a summary line
+		 > s5cmd {{.HelpName}} -H --summarize s3://bucket/*
+
+	7. List all objects in a bucket, largest objects first
+		 > s5cmd {{.HelpName}} --sort size --reverse s3://bucket/*
+
+	8. List all versions of objects in a versioned bucket
+		 > s5cmd {{.HelpName}} --versions s3://bucket/*
+
+	9. List first 10 objects of a prefix
+		 > s5cmd {{.HelpName}} --limit 10 s3://bucket/prefix/*
 `
 
 var listCommand = &cli.Command{
@@ -59,9 +73,29 @@ var listCommand = &cli.Command{
 		},
 		&cli.BoolFlag{
 			Name:    "storage-class",
-			Aliases: []string{"s"},
+			Aliases: []string{"s", "show-storage-class"},
 			Usage:   "display full name of the object class",
 		},
+		&cli.BoolFlag{
+			Name:  "versions",
+			Usage: "list all versions of objects and show their version ids",
+		},
+		&cli.BoolFlag{
+			Name:  "summarize",
+			Usage: "display total number of objects and total size at the end",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "stop listing after given number of items, in listing order",
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Usage: "sort objects by given key before printing, one of: name, size, mtime. all objects are kept in memory until the listing is completed",
+		},
+		&cli.BoolFlag{
+			Name:  "reverse",
+			Usage: "reverse the order of sorting",
+		},
 	},
 	Before: func(c *cli.Context) error {
 		err := validateLSCommand(c)
@@ -88,6 +122,11 @@ var listCommand = &cli.Command{
 			showEtag:         c.Bool("etag"),
 			humanize:         c.Bool("humanize"),
 			showStorageClass: c.Bool("storage-class"),
+			showVersions:     c.Bool("versions"),
+			summarize:        c.Bool("summarize"),
+			limit:            c.Int("limit"),
+			sortBy:           c.String("sort"),
+			reverse:          c.Bool("reverse"),
 
 			storageOpts: NewStorageOpts(c),
 		}.Run(c.Context)
@@ -104,6 +143,11 @@ type List struct {
 	showEtag         bool
 	humanize         bool
 	showStorageClass bool
+	showVersions     bool
+	summarize        bool
+	limit            int
+	sortBy           string
+	reverse          bool
 
 	storageOpts storage.Options
 }
@@ -123,7 +167,7 @@ func ListBuckets(ctx context.Context, storageOpts storage.Options) error {
 	}
 
 	for _, bucket := range buckets {
-		log.Info(bucket)
+		log.Summary(bucket)
 	}
 
 	return nil
@@ -143,32 +187,145 @@ func (l List) Run(ctx context.Context) error {
 		return err
 	}
 
-	var merror error
+	// listing is canceled when the limit is reached, so that the remaining
+	// pages are not requested.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objch, err := l.list(ctx, client, srcurl)
+	if err != nil {
+		printError(l.fullCommand, l.op, err)
+		return err
+	}
+
+	var (
+		merror  error
+		total   sizeAndCount
+		objects []*storage.Object
+		count   int
+	)
 
-	for object := range client.List(ctx, srcurl, false) {
+	for object := range objch {
 		if errorpkg.IsCancelation(object.Err) {
 			continue
 		}
 
+		// drain the channel until the listing goroutine observes the
+		// cancelation.
+		if l.limit > 0 && count >= l.limit {
+			continue
+		}
+
 		if err := object.Err; err != nil {
 			merror = multierror.Append(merror, err)
 			printError(l.fullCommand, l.op, err)
 			continue
 		}
 
-		msg := ListMessage{
-			Object:           object,
-			showEtag:         l.showEtag,
-			showHumanized:    l.humanize,
-			showStorageClass: l.showStorageClass,
+		if !object.Type.IsDir() {
+			total.addObject(object)
+		}
+
+		count++
+		if l.limit > 0 && count >= l.limit {
+			cancel()
+		}
+
+		// sorting requires the whole listing, objects are printed after
+		// the listing is completed.
+		if l.sortBy != "" {
+			objects = append(objects, object)
+			continue
+		}
+
+		l.print(object)
+	}
+
+	if l.sortBy != "" {
+		sortObjects(objects, l.sortBy, l.reverse)
+		for _, object := range objects {
+			l.print(object)
 		}
+	}
 
-		log.Info(msg)
+	if l.summarize {
+		log.Summary(ListSummaryMessage{
+			Count:         total.count,
+			Size:          total.size,
+			showHumanized: l.humanize,
+		})
 	}
 
 	return merror
 }
 
+// list lists the objects, or all versions of the objects if versions are
+// requested.
+func (l List) list(ctx context.Context, client storage.Storage, srcurl *url.URL) (<-chan *storage.Object, error) {
+	if !l.showVersions {
+		return client.List(ctx, srcurl, false), nil
+	}
+
+	s3, ok := client.(*storage.S3)
+	if !ok {
+		return nil, fmt.Errorf("versions can only be listed for remote objects")
+	}
+	return s3.ListVersions(ctx, srcurl), nil
+}
+
+func (l List) print(object *storage.Object) {
+	msg := ListMessage{
+		Object:           object,
+		showEtag:         l.showEtag,
+		showHumanized:    l.humanize,
+		showStorageClass: l.showStorageClass,
+		showVersion:      l.showVersions,
+	}
+
+	log.Summary(msg)
+}
+
+const (
+	sortByName  = "name"
+	sortBySize  = "size"
+	sortByMtime = "mtime"
+)
+
+// sortObjects sorts objects by the given key. Objects with equal keys are
+// ordered by their names, so that the output is deterministic.
+func sortObjects(objects []*storage.Object, key string, reverse bool) {
+	less := func(a, b *storage.Object) bool {
+		switch key {
+		case sortBySize:
+			if a.Size != b.Size {
+				return a.Size < b.Size
+			}
+		case sortByMtime:
+			at, bt := modTime(a), modTime(b)
+			if !at.Equal(bt) {
+				return at.Before(bt)
+			}
+		}
+		return a.URL.Relative() < b.URL.Relative()
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		if reverse {
+			return less(objects[j], objects[i])
+		}
+		return less(objects[i], objects[j])
+	})
+}
+
+// modTime returns the modification time of the object, or the zero time for
+// directories.
+func modTime(object *storage.Object) time.Time {
+	if object.ModTime == nil {
+		return time.Time{}
+	}
+	return *object.ModTime
+}
+
 // ListMessage is a structure for logging ls results.
 type ListMessage struct {
 	Object *storage.Object `json:"object"`
@@ -176,6 +333,7 @@ type ListMessage struct {
 	showEtag         bool
 	showHumanized    bool
 	showStorageClass bool
+	showVersion      bool
 }
 
 // humanize is a helper function to humanize bytes.
@@ -202,7 +360,7 @@ func (l ListMessage) String() string {
 		listFormat = "%19s %2s %-38s %12s %s"
 	}
 
-	if l.Object.Type.IsDir() {
+	if l.Object.Type.IsDir() && l.Object.VersionID == "" {
 		s := fmt.Sprintf(
 			listFormat,
 			"",
@@ -211,6 +369,9 @@ func (l ListMessage) String() string {
 			"DIR",
 			l.Object.URL.Relative(),
 		)
+		if l.showVersion {
+			s = fmt.Sprintf("%-32s %s", "", s)
+		}
 		return s
 	}
 
@@ -227,6 +388,9 @@ func (l ListMessage) String() string {
 		l.humanize(),
 		l.Object.URL.Relative(),
 	)
+	if l.showVersion {
+		s = fmt.Sprintf("%-32s %s", l.Object.VersionID, s)
+	}
 	return s
 }
 
@@ -235,9 +399,44 @@ func (l ListMessage) JSON() string {
 	return strutil.JSON(l.Object)
 }
 
+// ListSummaryMessage is a structure for logging the summary of ls results.
+type ListSummaryMessage struct {
+	Count int64 `json:"count"`
+	Size  int64 `json:"size"`
+
+	showHumanized bool
+}
+
+// String returns the string representation of ListSummaryMessage.
+func (s ListSummaryMessage) String() string {
+	if s.showHumanized {
+		return fmt.Sprintf("%d objects, %s total", s.Count, strutil.HumanizeBytes(s.Size))
+	}
+	return fmt.Sprintf("%d objects, %d bytes total", s.Count, s.Size)
+}
+
+// JSON returns the JSON representation of ListSummaryMessage.
+func (s ListSummaryMessage) JSON() string {
+	return strutil.JSON(s)
+}
+
 func validateLSCommand(c *cli.Context) error {
 	if c.Args().Len() > 1 {
 		return fmt.Errorf("expected only 1 argument")
 	}
+
+	switch sortBy := c.String("sort"); sortBy {
+	case "", sortByName, sortBySize, sortByMtime:
+	default:
+		return fmt.Errorf("unknown sort key %q", sortBy)
+	}
+
+	if c.Int("limit") < 0 {
+		return fmt.Errorf("limit can not be a negative number")
+	}
+
+	if c.Bool("reverse") && c.String("sort") == "" {
+		return fmt.Errorf("--reverse flag can only be used with --sort flag")
+	}
 	return nil
 }
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/storage"
+)
+
+// transferManifest records the successfully transferred objects to a CSV
+// file. It is safe for concurrent use.
+type transferManifest struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *csv.Writer
+}
+
+// newTransferManifest creates the manifest file and writes the header.
+func newTransferManifest(path string) (*transferManifest, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &transferManifest{f: f, w: csv.NewWriter(f)}
+	if err := m.write("source", "destination", "size", "etag", "timestamp"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// add records a completed transfer. The etag is taken from the listed source
+// object, if any.
+func (m *transferManifest) add(msg log.InfoMessage, src *storage.Object) error {
+	var (
+		size int64
+		etag string
+	)
+	if obj, ok := msg.Object.(*storage.Object); ok && obj != nil {
+		size = obj.Size
+	}
+	if src != nil {
+		etag = src.Etag
+	}
+
+	return m.write(
+		msg.Source.String(),
+		msg.Destination.String(),
+		strconv.FormatInt(size, 10),
+		etag,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+}
+
+// write writes a row and flushes it, so the manifest is up to date even if
+// the process is interrupted.
+func (m *transferManifest) write(record ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.w.Write(record); err != nil {
+		return err
+	}
+	m.w.Flush()
+	return m.w.Error()
+}
+
+// Close closes the manifest file.
+func (m *transferManifest) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.w.Flush()
+	if err := m.w.Error(); err != nil {
+		m.f.Close()
+		return err
+	}
+	return m.f.Close()
+}
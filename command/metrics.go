@@ -0,0 +1,46 @@
+package command
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+)
+
+// metricsServer serves the statistics of the run in Prometheus format at
+// /metrics, if --metrics-addr flag is given.
+var metricsServer *http.Server
+
+// startMetricsServer starts serving the metrics on given address. The
+// listener is created before returning, so address errors are reported to
+// the caller.
+func startMetricsServer(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", serveMetrics)
+
+	metricsServer = &http.Server{Handler: mux}
+	go metricsServer.Serve(listener)
+	return nil
+}
+
+// stopMetricsServer stops serving the metrics.
+func stopMetricsServer() {
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+}
+
+func serveMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	stat.WritePrometheus(w)
+	stat.WritePrometheusGauge(w, "s5cmd_workers", "Number of workers.", int64(parallel.WorkerCount()))
+	stat.WritePrometheusGauge(w, "s5cmd_workers_busy", "Number of workers running a task.", int64(parallel.BusyWorkerCount()))
+	stat.WritePrometheusGauge(w, "s5cmd_tasks_waiting", "Number of tasks waiting for a worker.", int64(parallel.WaitingTaskCount()))
+}
@@ -31,13 +31,23 @@ Examples:
 
 	5. Move a directory to S3 bucket recursively
 		 > s5cmd {{.HelpName}} dir/ s3://bucket/
+
+	6. Move all S3 objects to a directory without asking for a confirmation on a terminal
+		 > s5cmd {{.HelpName}} --force s3://bucket/* target-directory/
 `
 
+// moveCommandFlags are the flags of copy command, and the flag to skip the
+// confirmation of the deleted sources.
+var moveCommandFlags = append(copyCommandFlags[:len(copyCommandFlags):len(copyCommandFlags)], &cli.BoolFlag{
+	Name:  "force",
+	Usage: "do not ask for a confirmation of the sources matched by the wildcards, which are deleted once moved",
+})
+
 var moveCommand = &cli.Command{
 	Name:               "mv",
 	HelpName:           "mv",
 	Usage:              "move/rename objects",
-	Flags:              copyCommandFlags, // move and copy commands share the same flags
+	Flags:              moveCommandFlags,
 	CustomHelpTemplate: moveHelpTemplate,
 	Before: func(c *cli.Context) error {
 		return copyCommand.Before(c)
@@ -45,22 +55,68 @@ var moveCommand = &cli.Command{
 	Action: func(c *cli.Context) (err error) {
 		defer stat.Collect(c.Command.FullName(), &err)()
 
+		// validated in Before
+		partSize, _ := parsePartSize(c.String("part-size"))
+		userMetadata, _ := parseKeyValues("metadata", c.StringSlice("metadata"))
+		tags, _ := parseKeyValues("tag", c.StringSlice("tag"))
+		dsts, _ := copyDestinations(c)
+
 		copyCommand := Copy{
 			src:          c.Args().Get(0),
-			dst:          c.Args().Get(1),
+			dsts:         dsts,
 			op:           c.Command.Name,
 			fullCommand:  givenCommand(c),
+			jobFlags:     jobFlags(c),
 			deleteSource: true, // delete source
+			confirm:      shouldConfirmDelete(c),
 			// flags
-			noClobber:        c.Bool("no-clobber"),
-			ifSizeDiffer:     c.Bool("if-size-differ"),
-			ifSourceNewer:    c.Bool("if-source-newer"),
-			flatten:          c.Bool("flatten"),
-			followSymlinks:   !c.Bool("no-follow-symlinks"),
-			storageClass:     storage.StorageClass(c.String("storage-class")),
-			encryptionMethod: c.String("sse"),
-			encryptionKeyID:  c.String("sse-kms-key-id"),
-			acl:              c.String("acl"),
+			noClobber:           c.Bool("no-clobber"),
+			ifSizeDiffer:        c.Bool("if-size-differ"),
+			ifSourceNewer:       c.Bool("if-source-newer"),
+			ifContentDiffer:     c.Bool("if-content-differ"),
+			ifNoneMatch:         c.Bool("if-none-match"),
+			flatten:             c.Bool("flatten"),
+			stripComponents:     c.Int("strip-components"),
+			filesFrom:           c.String("files-from"),
+			inventory:           c.String("inventory"),
+			manifest:            c.String("manifest"),
+			journal:             c.String("journal"),
+			checkpoint:          commandCheckpointFrom(c.Context),
+			onSuccess:           c.String("on-success"),
+			onFailure:           c.String("on-failure"),
+			followSymlinks:      !c.Bool("no-follow-symlinks"),
+			storageClass:        storage.StorageClass(c.String("storage-class")),
+			concurrency:         c.Int("concurrency"),
+			partSize:            partSize,
+			encryptionMethod:    c.String("sse"),
+			encryptionKeyID:     c.String("sse-kms-key-id"),
+			acl:                 c.String("acl"),
+			contentType:         c.String("content-type"),
+			cacheControl:        c.String("cache-control"),
+			metadataDirective:   c.String("metadata-directive"),
+			userMetadata:        userMetadata,
+			tags:                tags,
+			copyACL:             c.Bool("copy-acl"),
+			preserveTimestamps:  c.Bool("preserve-timestamps"),
+			preservePermissions: c.Bool("preserve-permissions"),
+			preserveSymlinks:    c.Bool("preserve-symlinks"),
+			keepEmptyDirs:       c.Bool("keep-empty-dirs"),
+			numericIDs:          c.Bool("numeric-ids"),
+			sparse:              c.Bool("sparse"),
+			fsync:               c.Bool("fsync"),
+			scheduling:          c.String("scheduling"),
+			jobTimeout:          c.Duration("job-timeout"),
+			retryFailed:         c.Int("retry-failed"),
+			retryFailedDelay:    c.Duration("retry-failed-delay"),
+			// region settings
+			srcRegion: c.String("source-region"),
+			dstRegion: c.String("destination-region"),
+			// endpoint settings
+			srcEndpoint: c.String("source-endpoint-url"),
+			dstEndpoint: c.String("destination-endpoint-url"),
+			// credential settings
+			srcProfile: c.String("source-profile"),
+			dstProfile: c.String("destination-profile"),
 
 			storageOpts: NewStorageOpts(c),
 		}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+)
+
+// pprofServer serves the runtime profiling data at /debug/pprof/, if
+// --pprof-addr flag is given.
+var pprofServer *http.Server
+
+// startPprofServer starts serving the profiling data on given address.
+func startPprofServer(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	pprofServer = &http.Server{Handler: mux}
+	go pprofServer.Serve(listener)
+	return nil
+}
+
+// stopPprofServer stops serving the profiling data.
+func stopPprofServer() {
+	if pprofServer != nil {
+		pprofServer.Close()
+	}
+}
@@ -0,0 +1,54 @@
+package command
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// cpuProfile is the file the CPU profile is written to, if --cpu-profile
+// flag is given.
+var cpuProfile *os.File
+
+// startCPUProfile starts writing the CPU profile to given file.
+func startCPUProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	cpuProfile = f
+	return nil
+}
+
+// stopCPUProfile stops writing the CPU profile and closes its file.
+func stopCPUProfile() error {
+	if cpuProfile == nil {
+		return nil
+	}
+
+	pprof.StopCPUProfile()
+	err := cpuProfile.Close()
+	cpuProfile = nil
+	return err
+}
+
+// writeMemProfile writes the heap profile to given file. Garbage collection
+// is run first, so the profile is up to date.
+func writeMemProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/strutil"
+)
+
+// progress prints the progress of the run periodically, if --progress flag
+// is given.
+var progress struct {
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// startProgress starts printing the progress with given interval.
+func startProgress(interval time.Duration) {
+	progress.stopCh = make(chan struct{})
+	progress.doneCh = make(chan struct{})
+
+	go func() {
+		defer close(progress.doneCh)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		start := time.Now()
+		last, lastBytes := start, int64(0)
+		for {
+			select {
+			case <-progress.stopCh:
+				return
+			case now := <-ticker.C:
+				bytes := stat.TransferredBytes()
+
+				var throughput int64
+				if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+					throughput = int64(float64(bytes-lastBytes) / elapsed)
+				}
+
+				log.Progress(ProgressMessage{
+					Elapsed:    now.Sub(start).Round(time.Second).String(),
+					Completed:  parallel.CompletedTaskCount(),
+					Remaining:  parallel.BusyWorkerCount() + parallel.WaitingTaskCount(),
+					Bytes:      bytes,
+					Throughput: throughput,
+				})
+				last, lastBytes = now, bytes
+			}
+		}
+	}()
+}
+
+// stopProgress stops printing the progress. It must be called before the
+// logger is closed.
+func stopProgress() {
+	if progress.stopCh == nil {
+		return
+	}
+	close(progress.stopCh)
+	<-progress.doneCh
+}
+
+// ProgressMessage is a structure for logging the progress of a run.
+// Remaining is the number of started jobs which are not completed yet.
+type ProgressMessage struct {
+	Elapsed    string `json:"elapsed"`
+	Completed  int    `json:"completed"`
+	Remaining  int    `json:"remaining"`
+	Bytes      int64  `json:"bytes"`
+	Throughput int64  `json:"bytes_per_second"`
+}
+
+// String returns the string representation of ProgressMessage.
+func (m ProgressMessage) String() string {
+	return fmt.Sprintf(
+		"progress %v: %d completed, %d remaining, %v transferred, %v/s",
+		m.Elapsed,
+		m.Completed,
+		m.Remaining,
+		strutil.HumanizeBytes(m.Bytes),
+		strutil.HumanizeBytes(m.Throughput),
+	)
+}
+
+// JSON returns the JSON representation of ProgressMessage.
+func (m ProgressMessage) JSON() string {
+	return strutil.JSON(m)
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/log"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// newFormatFlag creates the flag to choose the output format of the reports
+// of a command.
+func newFormatFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:  "format",
+		Usage: "output format of the report: (table, json, csv)",
+	}
+}
+
+func validateFormatFlag(c *cli.Context) error {
+	switch format := c.String("format"); format {
+	case "", formatTable, formatJSON, formatCSV:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// Report is the interface implemented by the results of the commands which
+// print their reports with ReportMessage. A Report can be rendered in every
+// supported output format.
+type Report interface {
+	log.Message
+
+	// Header returns the column names of the report.
+	Header() []string
+
+	// Row returns the column values of the report, in the same order with
+	// the header.
+	Row() []string
+}
+
+// ReportMessage is a structure for logging a list of reports in the given
+// format. The reports are rendered once all of them are collected, since the
+// columns of a table are aligned, so the listings of ls, tree and find are
+// printed as they are streamed instead.
+type ReportMessage struct {
+	format  string
+	reports []Report
+}
+
+// String returns the representation of reports in the requested format. If
+// no format is requested, string representations of reports are printed
+// line by line.
+func (r ReportMessage) String() string {
+	switch r.format {
+	case formatTable:
+		return r.table()
+	case formatCSV:
+		return r.csv()
+	case formatJSON:
+		return r.JSON()
+	}
+
+	lines := make([]string, 0, len(r.reports))
+	for _, report := range r.reports {
+		lines = append(lines, report.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON returns the JSON representation of reports, one line per report.
+func (r ReportMessage) JSON() string {
+	lines := make([]string, 0, len(r.reports))
+	for _, report := range r.reports {
+		lines = append(lines, report.JSON())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r ReportMessage) table() string {
+	if len(r.reports) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, strings.Join(r.reports[0].Header(), "\t"))
+	for _, report := range r.reports {
+		fmt.Fprintln(w, strings.Join(report.Row(), "\t"))
+	}
+
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (r ReportMessage) csv() string {
+	if len(r.reports) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	_ = w.Write(r.reports[0].Header())
+	for _, report := range r.reports {
+		_ = w.Write(report.Row())
+	}
+
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/strutil"
+)
+
+// resizer resizes the workers of the run on growSignal and shrinkSignal.
+var resizer struct {
+	signalCh chan os.Signal
+	doneCh   chan struct{}
+}
+
+// startResizer starts listening for the resize signals, if they are
+// supported by the platform.
+func startResizer() {
+	if growSignal == nil {
+		return
+	}
+
+	resizer.signalCh = make(chan os.Signal, 1)
+	resizer.doneCh = make(chan struct{})
+	signal.Notify(resizer.signalCh, growSignal, shrinkSignal)
+
+	go func() {
+		defer close(resizer.doneCh)
+		for sig := range resizer.signalCh {
+			workers := parallel.WorkerCount()
+			if sig == growSignal {
+				workers *= 2
+			} else {
+				workers /= 2
+			}
+			resizeWorkers(workers, "signal")
+		}
+	}()
+}
+
+// stopResizer stops listening for the resize signals. It must be called
+// before the logger is closed.
+func stopResizer() {
+	if resizer.signalCh == nil {
+		return
+	}
+	signal.Stop(resizer.signalCh)
+	close(resizer.signalCh)
+	<-resizer.doneCh
+}
+
+// resizeWorkers resizes the workers of the run and logs the new number of
+// workers with the reason.
+func resizeWorkers(workers int, reason string) {
+	from := parallel.WorkerCount()
+	to := parallel.Resize(workers)
+	log.Progress(ResizeMessage{From: from, To: to, Reason: reason})
+}
+
+// ResizeMessage is a structure for logging a change of the number of workers.
+type ResizeMessage struct {
+	From   int    `json:"from"`
+	To     int    `json:"to"`
+	Reason string `json:"reason"`
+}
+
+// String returns the string representation of ResizeMessage.
+func (m ResizeMessage) String() string {
+	return fmt.Sprintf("workers resized from %d to %d (%v)", m.From, m.To, m.Reason)
+}
+
+// JSON returns the JSON representation of ResizeMessage.
+func (m ResizeMessage) JSON() string {
+	return strutil.JSON(m)
+}
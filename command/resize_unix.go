@@ -0,0 +1,14 @@
+// +build !windows
+
+package command
+
+import (
+	"os"
+	"syscall"
+)
+
+// growSignal doubles and shrinkSignal halves the number of workers.
+var (
+	growSignal   os.Signal = syscall.SIGUSR1
+	shrinkSignal os.Signal = syscall.SIGUSR2
+)
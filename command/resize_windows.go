@@ -0,0 +1,12 @@
+// +build windows
+
+package command
+
+import "os"
+
+// workers can not be resized with signals on Windows, since there are no
+// user defined signals.
+var (
+	growSignal   os.Signal
+	shrinkSignal os.Signal
+)
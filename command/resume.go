@@ -0,0 +1,134 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// progressMarkerInterval is the interval of updating the progress marker of
+// a command file.
+const progressMarkerInterval = time.Second
+
+// progressMarkerPath returns the path of the progress marker of the command
+// file.
+func progressMarkerPath(file string) string {
+	return file + ".progress"
+}
+
+// lineTracker tracks the lines of a command file whose commands are
+// completed. Lines are numbered from 0 as in the error messages. It is safe
+// for concurrent use.
+type lineTracker struct {
+	mu      sync.Mutex
+	next    int
+	pending map[int]int
+}
+
+func newLineTracker(start int) *lineTracker {
+	return &lineTracker{
+		next:    start,
+		pending: map[int]int{},
+	}
+}
+
+// add records that a command of the line is started.
+func (t *lineTracker) add(line int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[line]++
+}
+
+// done records that a command of the line is completed.
+func (t *lineTracker) done(line int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[line]--
+	if t.pending[line] <= 0 {
+		delete(t.pending, line)
+	}
+}
+
+// read records that all of the commands of the line are started.
+func (t *lineTracker) read(line int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if line >= t.next {
+		t.next = line + 1
+	}
+}
+
+// resumeLine returns the first line which is not completed. The run can be
+// resumed from this line without running a completed line again.
+func (t *lineTracker) resumeLine() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	line := t.next
+	for pending := range t.pending {
+		if pending < line {
+			line = pending
+		}
+	}
+	return line
+}
+
+// writeProgressMarkers writes the resume line of the tracker to the marker
+// file periodically until the context is done. The returned function stops
+// writing and removes the marker if all of the lines are completed, or
+// writes the final resume line otherwise.
+func writeProgressMarkers(ctx context.Context, path string, t *lineTracker) func(completed bool) {
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+
+	written := -1
+	write := func() {
+		line := t.resumeLine()
+		if line == written {
+			return
+		}
+		if err := writeProgressMarker(path, line); err == nil {
+			written = line
+		}
+	}
+
+	go func() {
+		defer close(doneCh)
+
+		ticker := time.NewTicker(progressMarkerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				write()
+			}
+		}
+	}()
+
+	return func(completed bool) {
+		close(stopCh)
+		<-doneCh
+
+		if completed && ctx.Err() == nil {
+			os.Remove(path)
+			return
+		}
+		write()
+	}
+}
+
+// writeProgressMarker replaces the marker with the line atomically, so an
+// interrupted write doesn't leave a corrupt marker.
+func writeProgressMarker(path string, line int) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.Itoa(line)+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineTrackerResumeLine(t *testing.T) {
+	t.Parallel()
+
+	tracker := newLineTracker(2)
+	assert.Equal(t, 2, tracker.resumeLine())
+
+	tracker.add(2)
+	tracker.read(2)
+	tracker.add(3)
+	tracker.add(3)
+	tracker.read(3)
+	tracker.add(4)
+	assert.Equal(t, 2, tracker.resumeLine())
+
+	// later lines are completed before the first one.
+	tracker.done(3)
+	tracker.done(4)
+	assert.Equal(t, 2, tracker.resumeLine())
+
+	tracker.done(2)
+	assert.Equal(t, 3, tracker.resumeLine())
+
+	tracker.done(3)
+	assert.Equal(t, 4, tracker.resumeLine())
+
+	tracker.read(4)
+	assert.Equal(t, 5, tracker.resumeLine())
+}
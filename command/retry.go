@@ -0,0 +1,271 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/strutil"
+)
+
+// failedJob is a job failed with a transient error.
+type failedJob struct {
+	task parallel.Task
+	err  error
+}
+
+// failedJobs collects the jobs of a command failed with transient errors, so
+// they are run again once the other jobs are completed.
+type failedJobs struct {
+	mu   sync.Mutex
+	jobs []failedJob
+
+	// report is given the errors of the jobs which fail with other errors
+	// when they are retried, or which still fail after the last retry.
+	report func(error)
+
+	// failures is the number of errors given to report.
+	failures int
+}
+
+// newFailedJobs creates the failed jobs of a command whose errors are given
+// to report.
+func newFailedJobs(report func(error)) *failedJobs {
+	return &failedJobs{report: report}
+}
+
+// wrap returns the task which records the failure of the given task if it is
+// transient, instead of returning its error. The retries of the returned
+// task are recorded the same way.
+func (f *failedJobs) wrap(task parallel.Task) parallel.Task {
+	var wrapped parallel.Task
+	wrapped = func() error {
+		err := task()
+		if err == nil || !isTransientJobError(err) {
+			return err
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.jobs = append(f.jobs, failedJob{task: wrapped, err: err})
+		return nil
+	}
+	return wrapped
+}
+
+// take returns the recorded jobs and clears them.
+func (f *failedJobs) take() []failedJob {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	jobs := f.jobs
+	f.jobs = nil
+	return jobs
+}
+
+// len returns the number of the recorded jobs.
+func (f *failedJobs) len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.jobs)
+}
+
+// fail reports the error of a job which is not retried anymore.
+func (f *failedJobs) fail(err error) {
+	f.failures++
+	f.report(err)
+}
+
+// retryError is the error of a retried job, with the failed jobs of its
+// command to report it.
+type retryError struct {
+	failed *failedJobs
+	err    error
+}
+
+func (e *retryError) Error() string { return e.err.Error() }
+
+// retryFailedJobs runs the recorded jobs of the commands again up to the
+// given number of times, waiting for the delay before each retry. The delay is
+// doubled for each of the following retries. The jobs of all of the commands
+// are retried together, so the delay is waited for once for all of them. The
+// errors of the jobs which still fail after the last retry, or which fail
+// with other errors, are reported to their commands.
+func retryFailedJobs(ctx context.Context, commands []*failedJobs, retries int, delay time.Duration) {
+	for attempt := 1; attempt <= retries && !parallel.Stopped(); attempt++ {
+		var (
+			jobs   []failedJob
+			owners []*failedJobs
+		)
+		for _, failed := range commands {
+			for _, job := range failed.take() {
+				jobs = append(jobs, job)
+				owners = append(owners, failed)
+			}
+		}
+		if len(jobs) == 0 {
+			return
+		}
+
+		log.Info(RetryMessage{
+			Jobs:    len(jobs),
+			Delay:   delay.String(),
+			Attempt: attempt,
+			Retries: retries,
+		})
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			for i, job := range jobs {
+				owners[i].fail(job.err)
+			}
+			return
+		}
+		delay *= 2
+
+		waiter := parallel.NewWaiter()
+		errDoneCh := make(chan bool)
+		go func() {
+			defer close(errDoneCh)
+			for err := range waiter.Err() {
+				rerr := err.(*retryError)
+				rerr.failed.fail(rerr.err)
+			}
+		}()
+
+		for i, job := range jobs {
+			task, failed := job.task, owners[i]
+			parallel.Run(func() error {
+				if err := task(); err != nil {
+					return &retryError{failed: failed, err: err}
+				}
+				return nil
+			}, waiter)
+		}
+		waiter.Wait()
+		<-errDoneCh
+	}
+
+	for _, failed := range commands {
+		for _, job := range failed.take() {
+			failed.fail(job.err)
+		}
+	}
+}
+
+// deferredRetry is the failed jobs of a command of a run, which are retried
+// by the run once the other commands are completed. The resources of the
+// command, such as its manifest, are kept open until then.
+type deferredRetry struct {
+	failed  *failedJobs
+	closers []io.Closer
+
+	// done is called once the jobs are retried. ok is set if all of them
+	// have succeeded.
+	done func(ok bool)
+}
+
+type deferredRetryKey struct{}
+
+// withDeferredRetry returns the context of a command whose failed jobs are
+// handed over to the given retry, instead of being retried by the command.
+func withDeferredRetry(ctx context.Context, d *deferredRetry) context.Context {
+	return context.WithValue(ctx, deferredRetryKey{}, d)
+}
+
+// deferredRetryFrom returns the retry of the failed jobs of the command, or
+// nil if the command retries its failed jobs itself.
+func deferredRetryFrom(ctx context.Context) *deferredRetry {
+	if ctx == nil {
+		return nil
+	}
+	d, _ := ctx.Value(deferredRetryKey{}).(*deferredRetry)
+	return d
+}
+
+// retryQueue collects the failed jobs of the commands of a run, so they are
+// retried once the running commands are completed. A command doesn't hold a
+// worker of the run while waiting for the delay of its retries. It is safe
+// for concurrent use.
+type retryQueue struct {
+	mu      sync.Mutex
+	pending []*deferredRetry
+
+	retries int
+	delay   time.Duration
+}
+
+// add queues the failed jobs of a command.
+func (q *retryQueue) add(d *deferredRetry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, d)
+}
+
+// retry retries the queued jobs, then closes the resources of their commands.
+func (q *retryQueue) retry(ctx context.Context) {
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = nil
+	q.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	commands := make([]*failedJobs, 0, len(pending))
+	for _, d := range pending {
+		commands = append(commands, d.failed)
+	}
+	retryFailedJobs(ctx, commands, q.retries, q.delay)
+
+	for _, d := range pending {
+		for _, closer := range d.closers {
+			closer.Close()
+		}
+		if d.done != nil {
+			d.done(d.failed.failures == 0)
+		}
+	}
+}
+
+// isTransientJobError reports whether the job might succeed if it is run
+// again later. A fan-out is run again only if all of the errors of its
+// destinations are transient.
+func isTransientJobError(err error) bool {
+	if merr, ok := err.(*multierror.Error); ok {
+		for _, err := range merr.Errors {
+			if !isTransientJobError(err) {
+				return false
+			}
+		}
+		return len(merr.Errors) > 0
+	}
+	return storage.IsTransientError(err)
+}
+
+// RetryMessage is a structure for logging the retries of the failed jobs.
+type RetryMessage struct {
+	Jobs    int    `json:"jobs"`
+	Delay   string `json:"delay"`
+	Attempt int    `json:"attempt"`
+	Retries int    `json:"retries"`
+}
+
+// String returns the string representation of RetryMessage.
+func (m RetryMessage) String() string {
+	return fmt.Sprintf("retrying %d failed jobs in %s (retry: %d/%d)", m.Jobs, m.Delay, m.Attempt, m.Retries)
+}
+
+// JSON returns the JSON representation of RetryMessage.
+func (m RetryMessage) JSON() string {
+	return strutil.JSON(m)
+}
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/parallel"
+)
+
+func TestFailedJobsRetry(t *testing.T) {
+	log.Init("error", false)
+	if parallel.WorkerCount() == 0 {
+		parallel.Init(4)
+	}
+
+	var (
+		mu       sync.Mutex
+		attempts = map[string]int{}
+		reported []string
+	)
+
+	// the job succeeds after failing with transient errors the given times.
+	job := func(name string, failures int, err error) parallel.Task {
+		return func() error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts[name]++
+			if attempts[name] <= failures {
+				return err
+			}
+			return nil
+		}
+	}
+	slowDown := awserr.New("SlowDown", "", nil)
+
+	failed := newFailedJobs(func(err error) {
+		reported = append(reported, err.Error())
+	})
+	waiter := parallel.NewWaiter()
+	errDoneCh := make(chan bool)
+	go func() {
+		defer close(errDoneCh)
+		for err := range waiter.Err() {
+			reported = append(reported, err.Error())
+		}
+	}()
+
+	for _, task := range []parallel.Task{
+		job("succeeded", 0, nil),
+		job("recovered", 2, slowDown),
+		job("exhausted", 10, slowDown),
+		job("permanent", 10, fmt.Errorf("access denied")),
+		job("fanout", 1, multierror.Append(nil, slowDown, slowDown)),
+	} {
+		parallel.Run(failed.wrap(task), waiter)
+	}
+	waiter.Wait()
+	<-errDoneCh
+
+	// the permanent failure is reported without being retried.
+	assert.Equal(t, []string{"access denied"}, reported)
+
+	retryFailedJobs(context.Background(), []*failedJobs{failed}, 3, time.Millisecond)
+
+	assert.Equal(t, map[string]int{
+		"succeeded": 1,
+		"recovered": 3,
+		"exhausted": 4,
+		"permanent": 1,
+		"fanout":    2,
+	}, attempts)
+	assert.Equal(t, []string{"access denied", slowDown.Error()}, reported)
+}
+
+func TestFailedJobsRetryCanceled(t *testing.T) {
+	log.Init("error", false)
+
+	var reported []error
+	failed := newFailedJobs(func(err error) {
+		reported = append(reported, err)
+	})
+	failed.jobs = []failedJob{{task: func() error { return nil }, err: fmt.Errorf("slow down")}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the jobs are not run again once the context is canceled.
+	retryFailedJobs(ctx, []*failedJobs{failed}, 3, time.Hour)
+	assert.Len(t, reported, 1)
+}
+
+func TestRetryQueue(t *testing.T) {
+	log.Init("error", false)
+	if parallel.WorkerCount() == 0 {
+		parallel.Init(4)
+	}
+
+	// the jobs of both of the commands are retried once, the job of the
+	// second command still fails.
+	var reported []string
+	command := func(name string, err error) *deferredRetry {
+		failed := newFailedJobs(func(err error) {
+			reported = append(reported, name+": "+err.Error())
+		})
+		failed.jobs = []failedJob{{task: func() error { return err }, err: err}}
+		return &deferredRetry{failed: failed}
+	}
+
+	var done []bool
+	closer := &testCloser{}
+
+	first := command("first", nil)
+	first.closers = []io.Closer{closer}
+	first.done = func(ok bool) { done = append(done, ok) }
+
+	second := command("second", fmt.Errorf("access denied"))
+	second.done = func(ok bool) { done = append(done, ok) }
+
+	queue := &retryQueue{retries: 1, delay: time.Millisecond}
+	queue.add(first)
+	queue.add(second)
+	queue.retry(context.Background())
+
+	assert.Equal(t, []string{"second: access denied"}, reported)
+	assert.Equal(t, []bool{true, false}, done)
+	assert.True(t, closer.closed)
+
+	// the queue is emptied once its jobs are retried.
+	queue.retry(context.Background())
+	assert.Equal(t, []bool{true, false}, done)
+}
+
+type testCloser struct {
+	closed bool
+}
+
+func (c *testCloser) Close() error {
+	c.closed = true
+	return nil
+}
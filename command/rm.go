@@ -14,6 +14,10 @@ import (
 	"github.com/peak/s5cmd/storage/url"
 )
 
+// defaultDeleteBatchSize is the max number of objects which can be deleted
+// with a single request.
+const defaultDeleteBatchSize = 1000
+
 var deleteHelpTemplate = `Name:
 	{{.HelpName}} - {{.Usage}}
 
@@ -35,6 +39,18 @@ Examples:
 
 	4. Delete all matching objects and a specific object
 		 > s5cmd {{.HelpName}} s3://bucketname/prefix/* s3://bucketname/object1.gz
+
+	5. Delete objects whose keys are listed in "keys.txt" file, relative to a prefix
+		 > s5cmd {{.HelpName}} --files-from keys.txt s3://bucketname/prefix/
+
+	6. Delete all objects with a prefix, reading the keys from an S3 Inventory report
+		 > s5cmd {{.HelpName}} --inventory s3://inventory-bucket/bucketname/config/2020-03-18T00-00Z/manifest.json s3://bucketname/prefix/*
+
+	7. Delete all objects with a prefix in smaller batches, with 64 concurrent requests
+		 > s5cmd --numworkers 64 {{.HelpName}} --batch-size 500 s3://bucketname/prefix/*
+
+	8. Delete all objects with a prefix without asking for a confirmation on a terminal
+		 > s5cmd {{.HelpName}} --force s3://bucketname/prefix/*
 `
 
 var deleteCommand = &cli.Command{
@@ -42,6 +58,25 @@ var deleteCommand = &cli.Command{
 	HelpName:           "rm",
 	Usage:              "remove objects",
 	CustomHelpTemplate: deleteHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "files-from",
+			Usage: "read keys relative to source, one per line, from given file instead of listing the source. use - for standard input",
+		},
+		&cli.StringFlag{
+			Name:  "inventory",
+			Usage: "read keys of source bucket from the S3 Inventory report with given manifest.json url instead of listing the bucket",
+		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "do not ask for a confirmation of the objects matched by the wildcards",
+		},
+		&cli.IntFlag{
+			Name:  "batch-size",
+			Value: defaultDeleteBatchSize,
+			Usage: "number of objects deleted with a single request, at most 1000",
+		},
+	},
 	Before: func(c *cli.Context) error {
 		err := validateRMCommand(c)
 		if err != nil {
@@ -51,11 +86,24 @@ var deleteCommand = &cli.Command{
 	},
 	Action: func(c *cli.Context) (err error) {
 		defer stat.Collect(c.Command.FullName(), &err)()
+
+		storageOpts := NewStorageOpts(c)
+		storageOpts.DeleteBatchSize = c.Int("batch-size")
+		// batches are deleted by as many workers as given with --numworkers
+		// flag, or with the default concurrency of the deletes.
+		if c.IsSet("numworkers") {
+			storageOpts.DeleteConcurrency = c.Int("numworkers")
+		}
+
 		return Delete{
 			src:         c.Args().Slice(),
 			op:          c.Command.Name,
 			fullCommand: givenCommand(c),
-			storageOpts: NewStorageOpts(c),
+			jobFlags:    jobFlags(c),
+			filesFrom:   c.String("files-from"),
+			inventory:   c.String("inventory"),
+			confirm:     shouldConfirmDelete(c),
+			storageOpts: storageOpts,
 		}.Run(c.Context)
 	},
 }
@@ -65,6 +113,12 @@ type Delete struct {
 	src         []string
 	op          string
 	fullCommand string
+	jobFlags    []string
+
+	// flags
+	filesFrom string
+	inventory string
+	confirm   bool
 
 	// storage options
 	storageOpts storage.Options
@@ -85,7 +139,34 @@ func (d Delete) Run(ctx context.Context) error {
 		return err
 	}
 
-	objChan := expandSources(ctx, client, false, srcurls...)
+	var objChan <-chan *storage.Object
+	switch {
+	case d.inventory != "":
+		objChan, err = expandInventory(ctx, client, d.inventory, srcurl)
+		if err != nil {
+			printError(d.fullCommand, d.op, err)
+			return err
+		}
+	case d.filesFrom != "":
+		f, err := openFilesFrom(d.filesFrom)
+		if err != nil {
+			printError(d.fullCommand, d.op, err)
+			return err
+		}
+		defer f.Close()
+
+		objChan = expandFilesFrom(ctx, f, srcurl)
+	default:
+		objChan = expandSources(ctx, client, false, srcurls...)
+	}
+
+	if d.confirm {
+		objChan, err = confirmObjects(ctx, promptOutput, promptInput, d.fullCommand, objChan)
+		if err != nil {
+			printError(d.fullCommand, d.op, err)
+			return err
+		}
+	}
 
 	// do object->url transformation
 	urlch := make(chan *url.URL)
@@ -101,6 +182,9 @@ func (d Delete) Run(ctx context.Context) error {
 				printError(d.fullCommand, d.op, err)
 				continue
 			}
+			if d.storageOpts.DryRun {
+				addDryRun(d.op, object.Size)
+			}
 			urlch <- object.URL
 		}
 	}()
@@ -116,6 +200,14 @@ func (d Delete) Run(ctx context.Context) error {
 
 			merror = multierror.Append(merror, obj.Err)
 			printError(d.fullCommand, d.op, obj.Err)
+
+			if obj.URL != nil {
+				err := recordFailedJobErrors(d.op, d.jobFlags, &errorpkg.Error{Op: d.op, Src: obj.URL, Err: obj.Err})
+				if err != nil {
+					merror = multierror.Append(merror, err)
+					printError(d.fullCommand, d.op, err)
+				}
+			}
 			continue
 		}
 
@@ -129,6 +221,21 @@ func (d Delete) Run(ctx context.Context) error {
 	return merror
 }
 
+// shouldConfirmDelete reports whether the objects matched by the wildcards
+// are confirmed by the user before they are deleted.
+func shouldConfirmDelete(c *cli.Context) bool {
+	if !shouldConfirm(c) {
+		return false
+	}
+
+	for _, arg := range c.Args().Slice() {
+		if srcurl, err := url.New(arg); err == nil && srcurl.HasGlob() {
+			return true
+		}
+	}
+	return false
+}
+
 // newSources creates object URL list from given sources.
 func newURLs(sources ...string) ([]*url.URL, error) {
 	var urls []*url.URL
@@ -152,6 +259,27 @@ func validateRMCommand(c *cli.Context) error {
 		return err
 	}
 
+	if n := c.Int("batch-size"); c.IsSet("batch-size") && (n < 1 || n > defaultDeleteBatchSize) {
+		return fmt.Errorf("batch size must be between 1 and %v", defaultDeleteBatchSize)
+	}
+
+	if c.String("inventory") != "" {
+		if len(srcurls) > 1 {
+			return fmt.Errorf("expected only 1 source with --inventory flag")
+		}
+		return validateInventoryFlag(c, srcurls[0])
+	}
+
+	if c.String("files-from") != "" {
+		if len(srcurls) > 1 {
+			return fmt.Errorf("expected only 1 source with --files-from flag")
+		}
+		if srcurls[0].HasGlob() {
+			return fmt.Errorf("source argument can not contain wildcard character with --files-from flag")
+		}
+		return nil
+	}
+
 	var (
 		firstBucket         string
 		hasRemote, hasLocal bool
@@ -3,15 +3,20 @@ package command
 import (
 	"bufio"
 	"context"
+	"crypto/sha256"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/kballard/go-shellquote"
 	"github.com/urfave/cli/v2"
 
+	"github.com/peak/s5cmd/log"
 	"github.com/peak/s5cmd/parallel"
 )
 
@@ -19,7 +24,7 @@ var runHelpTemplate = `Name:
 	{{.HelpName}} - {{.Usage}}
 
 Usage:
-	{{.HelpName}} [file]
+	{{.HelpName}} [options] [file]
 
 Options:
 	{{range .VisibleFlags}}{{.}}
@@ -30,6 +35,30 @@ Examples:
 
 	2. Read commands from standard input and execute in parallel.
 		 > cat commands.txt | s5cmd {{.HelpName}}
+
+	3. Run the commands as they are generated by another process
+		 > generate_jobs | s5cmd -f -
+
+	4. Upload the files, then write the marker once all of the uploads are completed
+		 > printf 'cp dir/ s3://bucket/prefix/\n!wait\ncp _SUCCESS s3://bucket/prefix/\n' | s5cmd {{.HelpName}}
+
+	5. Run the commands of a template file, where ${DATE} and ${BUCKET} are replaced
+		 > BUCKET=logs s5cmd {{.HelpName}} --var DATE=2020-03-18 commands.txt
+
+	6. Run the commands of "daily.txt" file, which includes the commands of other files with "!include common.txt" lines
+		 > s5cmd {{.HelpName}} daily.txt
+
+	7. Resume an interrupted run of "commands.txt" file from the line written to its progress marker
+		 > s5cmd {{.HelpName}} --start-line $(cat commands.txt.progress) commands.txt
+
+	8. Upload a file to a fallback bucket if its upload fails
+		 > echo 'cp file.tar s3://bucket/ || cp file.tar s3://fallback-bucket/' | s5cmd {{.HelpName}}
+
+	9. Run the commands of "commands.txt" file, or continue the run recorded to "commands.checkpoint" file after a crash
+		 > s5cmd {{.HelpName}} --resume commands.checkpoint commands.txt
+
+	10. Run the commands of "keys.txt" file without replacing the ${...} in their keys
+		 > s5cmd {{.HelpName}} --no-vars keys.txt
 `
 
 var runCommand = &cli.Command{
@@ -37,6 +66,29 @@ var runCommand = &cli.Command{
 	HelpName:           "run",
 	Usage:              "run commands in batch",
 	CustomHelpTemplate: runHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "var",
+			Usage: "set a variable in key=value format to replace ${key} in the commands, can be given multiple times",
+		},
+		&cli.BoolFlag{
+			Name:  "no-vars",
+			Usage: "do not replace ${key} variables with the values of --var flag or the environment, run the commands as they are",
+		},
+		&cli.IntFlag{
+			Name:  "start-line",
+			Usage: "skip the lines before given line, numbered from 0 as in the error messages, to resume a run",
+		},
+		&cli.IntFlag{
+			Name:  "dedupe-window",
+			Value: defaultDedupeWindow,
+			Usage: "number of the last commands remembered to skip the identical ones until the next !wait line, a command repeated after them is run again",
+		},
+		&cli.StringFlag{
+			Name:  "resume",
+			Usage: "record completed commands and transfers to given checkpoint file and skip the ones recorded by previous runs",
+		},
+	},
 	Before: func(c *cli.Context) error {
 		err := validateRunCommand(c)
 		if err != nil {
@@ -45,9 +97,14 @@ var runCommand = &cli.Command{
 		return err
 	},
 	Action: func(c *cli.Context) error {
+		file := c.Args().First()
+		if file == stdinFile {
+			file = ""
+		}
+
 		reader := os.Stdin
-		if c.Args().Len() == 1 {
-			f, err := os.Open(c.Args().First())
+		if file != "" {
+			f, err := os.Open(file)
 			if err != nil {
 				return err
 			}
@@ -56,81 +113,537 @@ var runCommand = &cli.Command{
 			reader = f
 		}
 
-		pm := parallel.New(c.Int("numworkers"))
-		defer pm.Close()
+		// validated in Before. Variables are replaced with the given ones
+		// or the environment variables, unless --no-vars flag is given.
+		var vars map[string]string
+		if !c.Bool("no-vars") {
+			vars, _ = parseVariables(c.StringSlice("var"))
+		}
+
+		opts := runOptions{
+			file:         file,
+			vars:         vars,
+			startLine:    c.Int("start-line"),
+			checkpoint:   c.String("resume"),
+			dedupeWindow: c.Int("dedupe-window"),
+		}
+
+		scanner := NewScanner(c.Context, reader)
+		if err := runCommands(c, scanner.Scan(), opts); err != nil {
+			return err
+		}
 
-		waiter := parallel.NewWaiter()
+		return scanner.Err()
+	},
+}
+
+// stdinFile is the file name to read the commands from standard input.
+const stdinFile = "-"
+
+// runFile runs the commands of the file as run command does. It is used by
+// --file flag to run the commands without the run command.
+func runFile(c *cli.Context, file string) error {
+	cmd := c.App.Command("run")
+
+	flagset := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	if err := flagset.Parse([]string{cmd.Name, file}); err != nil {
+		return err
+	}
+	return cmd.Run(cli.NewContext(c.App, flagset, c))
+}
 
-		var errDoneCh = make(chan bool)
-		go func() {
-			defer close(errDoneCh)
-			for range waiter.Err() {
-				// app.ExitErrHandler is called after each command.Run
-				// invocation. Ignore the errors returned from parallel.Run,
-				// just drain the channel for synchronization.
+// directives of the command files.
+const (
+	// waitDirective waits for the previous commands to complete before
+	// running the next ones.
+	waitDirective = "!wait"
+
+	// includeDirective runs the commands of another file in place.
+	includeDirective = "!include"
+)
+
+// errRunStopped is the error of the runs whose remaining lines are not run,
+// since too many jobs have failed or the deadline is exceeded.
+var errRunStopped = fmt.Errorf("run is stopped")
+
+// skippedCommands is the number of commands which are not run since the run
+// is stopped.
+var skippedCommands int64
+
+// runOptions are the options of running the lines of a command file.
+type runOptions struct {
+	// file is the command file. Empty file means the lines are not read from
+	// a file, and the included files are relative to the current directory.
+	file string
+
+	// vars are the variables replaced in the lines, in addition to the
+	// environment variables. Variables are not replaced if vars is nil.
+	vars map[string]string
+
+	// startLine is the first line of the file to run.
+	startLine int
+
+	// checkpoint is the checkpoint file of the completed commands and
+	// transfers. The commands are not checkpointed if it is empty.
+	checkpoint string
+
+	// dedupeWindow is the number of the last commands remembered to skip
+	// the identical ones. defaultDedupeWindow is used if it is zero.
+	dedupeWindow int
+}
+
+// runCommands executes the given command lines in parallel and waits for
+// them to complete. The first line not completed yet is kept in a progress
+// marker next to the command file, so an interrupted run can be resumed with
+// --start-line flag. The marker is removed once all of the lines are
+// completed.
+func runCommands(c *cli.Context, lines <-chan string, opts runOptions) error {
+	// commands run concurrently can not ask for a confirmation.
+	disablePrompts()
+
+	var checkpoint *runCheckpoint
+	if opts.checkpoint != "" {
+		var err error
+		checkpoint, err = openRunCheckpoint(opts.checkpoint)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+			return err
+		}
+		defer func() {
+			if err := checkpoint.Close(); err != nil {
+				printError(givenCommand(c), c.Command.Name, err)
 			}
 		}()
+	}
 
-		scanner := NewScanner(c.Context, reader)
-		lineno := -1
-		for line := range scanner.Scan() {
-			lineno++
+	pm := parallel.New(c.Int("numworkers"))
+	defer pm.Close()
+
+	r := &commandRunner{
+		c:          c,
+		pm:         pm,
+		vars:       opts.vars,
+		startLine:  opts.startLine,
+		tracker:    newLineTracker(opts.startLine),
+		checkpoint: checkpoint,
+		seen:       newDedupeWindow(opts.dedupeWindow),
+	}
+	if retries := c.Int("retry-failed"); retries > 0 {
+		r.retries = &retryQueue{retries: retries, delay: c.Duration("retry-failed-delay")}
+	}
+	r.waiter, r.errDoneCh = newRunWaiter()
 
-			// support inline comments
-			line = strings.Split(line, " #")[0]
+	var includes []string
+	if opts.file != "" {
+		if abspath, err := filepath.Abs(opts.file); err == nil {
+			includes = append(includes, abspath)
+		}
+	}
 
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
+	stopMarkers := func(bool) {}
+	if opts.file != "" {
+		stopMarkers = writeProgressMarkers(c.Context, progressMarkerPath(opts.file), r.tracker)
+	}
 
-			if strings.HasPrefix(line, "#") {
-				continue
-			}
+	err := r.run(opts.file, lines, includes)
 
-			fields, err := shellquote.Split(line)
-			if err != nil {
-				return err
-			}
+	r.wait()
 
-			if len(fields) == 0 {
-				continue
+	stopMarkers(err == nil)
+
+	return err
+}
+
+// commandRunner runs the commands of a file and the files it includes with
+// the shared workers.
+type commandRunner struct {
+	c    *cli.Context
+	pm   *parallel.Manager
+	vars map[string]string
+
+	waiter    *parallel.Waiter
+	errDoneCh <-chan bool
+
+	// seen is the line numbers of the commands run since the last wait
+	// directive. Identical commands are run once, since running them
+	// concurrently is redundant and they might race for the same
+	// destination.
+	seen *dedupeWindow
+
+	// the lines of the command file are tracked, not of the included ones.
+	// The commands of the included files belong to the line including them.
+	startLine int
+	tracker   *lineTracker
+	depth     int
+	line      int
+
+	// checkpoint records the completed commands and transfers if --resume
+	// flag is given.
+	checkpoint *runCheckpoint
+
+	// retries is the failed jobs of the commands to retry once the running
+	// commands are completed, if --retry-failed flag is given.
+	retries *retryQueue
+}
+
+// wait waits for the running commands to complete, then retries their failed
+// jobs.
+func (r *commandRunner) wait() {
+	r.waiter.Wait()
+	<-r.errDoneCh
+
+	if r.retries != nil {
+		r.retries.retry(r.c.Context)
+	}
+}
+
+// run runs the commands of the lines of the file. includes are the absolute
+// paths of the files being run, for detecting the include cycles.
+func (r *commandRunner) run(file string, lines <-chan string, includes []string) error {
+	c := r.c
+
+	lineno := -1
+	for line := range lines {
+		lineno++
+
+		// new jobs are not run once too many jobs have failed or the deadline
+		// is exceeded.
+		if parallel.Stopped() {
+			// the lines of the standard input might never end.
+			if file != "" {
+				skipCommands(line, lines)
 			}
+			return errRunStopped
+		}
 
-			if fields[0] == "run" {
-				err := fmt.Errorf("%q command (line: %v) is not permitted in run-mode", "run", lineno)
-				printError(givenCommand(c), c.Command.Name, err)
+		if r.depth == 0 {
+			if lineno < r.startLine {
 				continue
 			}
+			// commands of the previous lines are started.
+			r.tracker.read(lineno - 1)
+			r.line = lineno
+		}
+
+		// support inline comments
+		line = stripComment(line)
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields, err := shellquote.Split(line)
+		if err != nil {
+			return err
+		}
 
-			fn := func() error {
-				subcmd := fields[0]
+		if len(fields) == 0 {
+			continue
+		}
 
-				cmd := app.Command(subcmd)
-				if cmd == nil {
-					err := fmt.Errorf("%q command (line: %v) not found", subcmd, lineno)
+		if strings.HasPrefix(fields[0], "!") {
+			switch fields[0] {
+			case waitDirective:
+				r.wait()
+				r.waiter, r.errDoneCh = newRunWaiter()
+				r.seen.reset()
+			case includeDirective:
+				err := expandFields(fields[1:], r.vars)
+				if err == nil {
+					err = r.include(file, fields[1:], includes)
+				}
+				if err == errRunStopped {
+					return err
+				}
+				if err != nil {
+					err := fmt.Errorf("%v (line: %v)", err, lineno)
 					printError(givenCommand(c), c.Command.Name, err)
-					return nil
 				}
+			default:
+				err := fmt.Errorf("%q directive (line: %v) not found", fields[0], lineno)
+				printError(givenCommand(c), c.Command.Name, err)
+			}
+			continue
+		}
 
-				flagset := flag.NewFlagSet(subcmd, flag.ExitOnError)
-				if err := flagset.Parse(fields); err != nil {
-					printError(givenCommand(c), c.Command.Name, err)
-					return nil
+		chain, err := parseChain(line)
+		if err == nil {
+			err = chain.expandVariables(r.vars)
+		}
+		if err != nil {
+			err := fmt.Errorf("%v (line: %v)", err, lineno)
+			printError(givenCommand(c), c.Command.Name, err)
+			continue
+		}
+
+		if err := validateChain(chain, lineno); err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+			continue
+		}
+
+		command := chain.String()
+		if first, ok := r.seen.add(command, lineno); !ok {
+			reportSkippedCommand(command, fmt.Sprintf("duplicate of the command (line: %v)", first))
+			continue
+		}
+
+		tracked := r.line
+		if r.checkpoint != nil && r.checkpoint.commandDone(tracked, command) {
+			reportSkippedCommand(command, fmt.Sprintf("command (line: %v) is completed by a previous run", tracked))
+			continue
+		}
+		r.tracker.add(tracked)
+
+		fn := func() error {
+			// the failed jobs of a command are retried by the run, so the
+			// worker is not held during the delays of the retries. The
+			// commands of a chain retry their failed jobs themselves, since
+			// the next command depends on their results.
+			var retry *deferredRetry
+			if r.retries != nil && chain.op == "" {
+				retry = &deferredRetry{}
+			}
+
+			err := r.runChain(tracked, chain, retry)
+
+			// the line is completed once its failed jobs are retried.
+			done := func(ok bool) {
+				defer r.tracker.done(tracked)
+
+				if ok && r.checkpoint != nil {
+					if err := r.checkpoint.addCommand(tracked, command); err != nil {
+						printError(givenCommand(c), c.Command.Name, err)
+					}
 				}
+			}
 
-				ctx := cli.NewContext(app, flagset, c)
-				return cmd.Run(ctx)
+			if retry != nil && retry.failed != nil {
+				retry.done = func(ok bool) { done(ok && err == nil) }
+				r.retries.add(retry)
+				return err
 			}
 
-			pm.Run(fn, waiter)
+			done(err == nil)
+			return err
 		}
 
-		waiter.Wait()
-		<-errDoneCh
+		r.pm.Run(fn, r.waiter)
+	}
 
-		return scanner.Err()
-	},
+	if r.depth == 0 {
+		r.tracker.read(lineno)
+	}
+	return nil
+}
+
+// validateChain validates the commands of the chain before any of them is
+// run.
+func validateChain(chain *commandChain, lineno int) error {
+	for _, fields := range chain.commands() {
+		if strings.HasPrefix(fields[0], "!") {
+			return fmt.Errorf("%q directive (line: %v) can not be chained", fields[0], lineno)
+		}
+		if fields[0] == "run" {
+			return fmt.Errorf("%q command (line: %v) is not permitted in run-mode", "run", lineno)
+		}
+		if app.Command(fields[0]) == nil {
+			return fmt.Errorf("%q command (line: %v) not found", fields[0], lineno)
+		}
+	}
+	return nil
+}
+
+// runChain runs the commands of the chain in order. A command is skipped if
+// the result of the commands before it doesn't satisfy its operator. The
+// failed jobs of a single command are handed over to retry, if it is given.
+func (r *commandRunner) runChain(line int, chain *commandChain, retry *deferredRetry) error {
+	if chain.op == "" {
+		return r.runCommand(line, chain.fields, retry)
+	}
+
+	err := r.runChain(line, chain.left, nil)
+	// the rest of the chain is not run once the run is stopped, regardless
+	// of the operator.
+	if err == errRunStopped || (chain.op == chainAnd) != (err == nil) {
+		return err
+	}
+	return r.runChain(line, chain.right, nil)
+}
+
+// runCommand runs a command of a chain. The transfers of the command are
+// checkpointed with the line if --resume flag is given. The failed jobs of the
+// command are handed over to retry, if it is given.
+func (r *commandRunner) runCommand(line int, fields []string, retry *deferredRetry) error {
+	// the run might be stopped while waiting for a worker.
+	if parallel.Stopped() {
+		atomic.AddInt64(&skippedCommands, 1)
+		return errRunStopped
+	}
+
+	subcmd := fields[0]
+	cmd := app.Command(subcmd)
+
+	flagset := flag.NewFlagSet(subcmd, flag.ExitOnError)
+	if err := flagset.Parse(fields); err != nil {
+		printError(givenCommand(r.c), r.c.Command.Name, err)
+		return err
+	}
+
+	ctx := cli.NewContext(app, flagset, r.c)
+	if r.checkpoint != nil {
+		ctx.Context = withCommandCheckpoint(ctx.Context, r.checkpoint.command(line, shellquote.Join(fields...)))
+	}
+	if retry != nil {
+		ctx.Context = withDeferredRetry(ctx.Context, retry)
+	}
+	return cmd.Run(ctx)
+}
+
+// defaultDedupeWindow is the number of the last commands remembered to skip
+// the identical ones, unless it is given with --dedupe-window flag.
+const defaultDedupeWindow = 100000
+
+// dedupeWindow remembers the line numbers of the last commands of a run, up
+// to its size, to find the identical commands. The commands are kept as
+// their hashes, so the memory of a window doesn't depend on the length of
+// the commands.
+type dedupeWindow struct {
+	size  int
+	lines map[[sha256.Size]byte]int
+
+	// order is the ring of the hashes in the order they are added, to
+	// forget the oldest one once the window is full.
+	order [][sha256.Size]byte
+	next  int
+}
+
+// newDedupeWindow creates a window of given size. defaultDedupeWindow is used
+// if size is zero.
+func newDedupeWindow(size int) *dedupeWindow {
+	if size <= 0 {
+		size = defaultDedupeWindow
+	}
+	return &dedupeWindow{
+		size:  size,
+		lines: map[[sha256.Size]byte]int{},
+	}
+}
+
+// add remembers the command of the line. If an identical command is in the
+// window, it is not added and the line of that command is returned.
+func (w *dedupeWindow) add(command string, line int) (int, bool) {
+	key := sha256.Sum256([]byte(command))
+	if first, ok := w.lines[key]; ok {
+		return first, false
+	}
+
+	if len(w.order) < w.size {
+		w.order = append(w.order, key)
+	} else {
+		delete(w.lines, w.order[w.next])
+		w.order[w.next] = key
+		w.next = (w.next + 1) % w.size
+	}
+	w.lines[key] = line
+	return line, true
+}
+
+// reset forgets all of the commands.
+func (w *dedupeWindow) reset() {
+	w.lines = map[[sha256.Size]byte]int{}
+	w.order = nil
+	w.next = 0
+}
+
+// reportSkippedCommand prints the command which is not run, and counts it as
+// skipped.
+func reportSkippedCommand(command, reason string) {
+	atomic.AddInt64(&skippedCommands, 1)
+	log.Info(log.SkipMessage{Command: command, Reason: reason})
+}
+
+// skipCommands counts the commands of the line and the remaining lines as
+// skipped. Empty lines, comments and directives are not counted.
+func skipCommands(line string, lines <-chan string) {
+	isCommand := func(line string) bool {
+		line = strings.TrimSpace(stripComment(line))
+		return line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "!")
+	}
+
+	var n int64
+	if isCommand(line) {
+		n++
+	}
+	for line := range lines {
+		if isCommand(line) {
+			n++
+		}
+	}
+	atomic.AddInt64(&skippedCommands, n)
+}
+
+// include runs the commands of the file given to include directive. Its path
+// is relative to the directory of the including file.
+func (r *commandRunner) include(file string, args []string, includes []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%q directive expects only 1 file", includeDirective)
+	}
+
+	path := args[0]
+	if !filepath.IsAbs(path) && file != "" {
+		path = filepath.Join(filepath.Dir(file), path)
+	}
+
+	abspath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	for _, included := range includes {
+		if included == abspath {
+			return fmt.Errorf("%q is already included", args[0])
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r.depth++
+	defer func() { r.depth-- }()
+
+	scanner := NewScanner(r.c.Context, f)
+	if err := r.run(path, scanner.Scan(), append(includes[:len(includes):len(includes)], abspath)); err != nil {
+		// drain the lines to stop the scanner.
+		for range scanner.Scan() {
+		}
+		return err
+	}
+	return scanner.Err()
+}
+
+// newRunWaiter creates a waiter for the commands and drains its errors. The
+// returned channel is closed once the waiter is done.
+func newRunWaiter() (*parallel.Waiter, <-chan bool) {
+	waiter := parallel.NewWaiter()
+
+	errDoneCh := make(chan bool)
+	go func() {
+		defer close(errDoneCh)
+		for range waiter.Err() {
+			// app.ExitErrHandler is called after each command.Run
+			// invocation. Ignore the errors returned from parallel.Run,
+			// just drain the channel for synchronization.
+		}
+	}()
+	return waiter, errDoneCh
 }
 
 // Scanner is a cancelable scanner.
@@ -186,9 +699,87 @@ func (s *Scanner) Err() error {
 	return s.Scanner.Err()
 }
 
+// variableRegex matches the variables in ${name} format.
+var variableRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandFields replaces the variables in each of the fields. The fields of a
+// line are expanded after it is split, so the values with spaces, quotes or
+// operators are not split again. Variables are not replaced if vars is nil.
+func expandFields(fields []string, vars map[string]string) error {
+	if vars == nil {
+		return nil
+	}
+	for i, field := range fields {
+		expanded, err := expandVariables(field, vars)
+		if err != nil {
+			return err
+		}
+		fields[i] = expanded
+	}
+	return nil
+}
+
+// parseVariables parses the variables given in key=value format.
+func parseVariables(values []string) (map[string]string, error) {
+	vars := make(map[string]string, len(values))
+	for _, value := range values {
+		i := strings.Index(value, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("variable %q must be in key=value format", value)
+		}
+
+		name := value[:i]
+		if !variableRegex.MatchString("${" + name + "}") {
+			return nil, fmt.Errorf("invalid variable name %q", name)
+		}
+		vars[name] = value[i+1:]
+	}
+	return vars, nil
+}
+
+// expandVariables replaces the variables in the line with the given ones,
+// or the environment variables if they are not given. Undefined variables
+// are not replaced with empty strings, since a command such as
+// "rm s3://bucket/${DATE}/*" would remove a lot more than intended.
+func expandVariables(line string, vars map[string]string) (string, error) {
+	var undefined string
+	expanded := variableRegex.ReplaceAllStringFunc(line, func(match string) string {
+		name := variableRegex.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		if undefined == "" {
+			undefined = name
+		}
+		return match
+	})
+
+	if undefined != "" {
+		return "", fmt.Errorf("variable %q is not defined", undefined)
+	}
+	return expanded, nil
+}
+
 func validateRunCommand(c *cli.Context) error {
 	if c.Args().Len() > 1 {
 		return fmt.Errorf("expected only 1 file")
 	}
-	return nil
+
+	if c.Int("start-line") < 0 {
+		return fmt.Errorf("start line can not be a negative number")
+	}
+
+	if c.Int("dedupe-window") < 0 {
+		return fmt.Errorf("dedupe window can not be a negative number")
+	}
+
+	if c.Bool("no-vars") && c.IsSet("var") {
+		return fmt.Errorf("--var and --no-vars flags can not be used together")
+	}
+
+	_, err := parseVariables(c.StringSlice("var"))
+	return err
 }
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandVariables(t *testing.T) {
+	os.Setenv("S5CMD_TEST_BUCKET", "bucket")
+	defer os.Unsetenv("S5CMD_TEST_BUCKET")
+
+	vars := map[string]string{
+		"DATE":              "2020-03-18",
+		"S5CMD_TEST_BUCKET": "overridden",
+	}
+
+	tests := []struct {
+		name     string
+		line     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "no_variables", line: "ls s3://bucket/", expected: "ls s3://bucket/"},
+		{name: "given_variable", line: "ls s3://bucket/${DATE}/", expected: "ls s3://bucket/2020-03-18/"},
+		{name: "given_variable_overrides_environment", line: "ls s3://${S5CMD_TEST_BUCKET}/", expected: "ls s3://overridden/"},
+		{name: "dollar_without_braces", line: "ls s3://bucket/$DATE", expected: "ls s3://bucket/$DATE"},
+		{name: "undefined_variable", line: "rm s3://bucket/${S5CMD_TEST_UNDEFINED}/*", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := expandVariables(tc.line, vars)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	got, err := expandVariables("ls s3://${S5CMD_TEST_BUCKET}/", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, "ls s3://bucket/", got)
+}
+
+func TestParseVariables(t *testing.T) {
+	t.Parallel()
+
+	vars, err := parseVariables([]string{"DATE=2020-03-18", "QUERY=a=b", "EMPTY="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DATE": "2020-03-18", "QUERY": "a=b", "EMPTY": ""}, vars)
+
+	_, err = parseVariables([]string{"DATE"})
+	assert.Error(t, err)
+
+	_, err = parseVariables([]string{"1DATE=2020"})
+	assert.Error(t, err)
+}
+
+func TestDedupeWindow(t *testing.T) {
+	t.Parallel()
+
+	w := newDedupeWindow(2)
+
+	_, ok := w.add("ls a", 0)
+	assert.True(t, ok)
+	_, ok = w.add("ls b", 1)
+	assert.True(t, ok)
+
+	first, ok := w.add("ls a", 2)
+	assert.False(t, ok)
+	assert.Equal(t, 0, first)
+
+	// the oldest command is forgotten once the window is full.
+	_, ok = w.add("ls c", 3)
+	assert.True(t, ok)
+	_, ok = w.add("ls a", 4)
+	assert.True(t, ok)
+
+	first, ok = w.add("ls c", 5)
+	assert.False(t, ok)
+	assert.Equal(t, 3, first)
+
+	// all of the commands are forgotten once the window is reset.
+	w.reset()
+	_, ok = w.add("ls c", 6)
+	assert.True(t, ok)
+}
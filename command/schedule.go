@@ -0,0 +1,110 @@
+package command
+
+import (
+	"container/heap"
+	"fmt"
+
+	"github.com/peak/s5cmd/storage"
+)
+
+// scheduling policies of the listed objects.
+const (
+	scheduleFIFO          = "fifo"
+	scheduleSmallestFirst = "smallest-first"
+	scheduleLargestFirst  = "largest-first"
+)
+
+func validateScheduling(policy string) error {
+	switch policy {
+	case scheduleFIFO, scheduleSmallestFirst, scheduleLargestFirst:
+		return nil
+	default:
+		return fmt.Errorf("unknown scheduling policy %q, expected one of %v, %v or %v",
+			policy, scheduleFIFO, scheduleSmallestFirst, scheduleLargestFirst)
+	}
+}
+
+// scheduleObjects reorders the objects by their sizes according to the
+// policy. Objects are buffered as they are listed, and the smallest or the
+// largest one of the buffered objects is sent whenever the receiver is ready.
+// Errors and directories are sent before the files to be handled early.
+func scheduleObjects(objch <-chan *storage.Object, policy string) <-chan *storage.Object {
+	if policy == scheduleFIFO {
+		return objch
+	}
+
+	out := make(chan *storage.Object)
+	go func() {
+		defer close(out)
+
+		queue := &objectQueue{largestFirst: policy == scheduleLargestFirst}
+		var seq int64
+		for objch != nil || queue.Len() > 0 {
+			var (
+				sendCh chan<- *storage.Object
+				next   *storage.Object
+			)
+			if queue.Len() > 0 {
+				sendCh, next = out, queue.items[0].object
+			}
+
+			select {
+			case object, ok := <-objch:
+				if !ok {
+					objch = nil
+					continue
+				}
+				heap.Push(queue, queuedObject{object: object, seq: seq})
+				seq++
+			case sendCh <- next:
+				heap.Pop(queue)
+			}
+		}
+	}()
+	return out
+}
+
+type queuedObject struct {
+	object *storage.Object
+	seq    int64
+}
+
+// urgent reports whether the object is sent regardless of its size.
+func (q queuedObject) urgent() bool {
+	return q.object.Err != nil || q.object.Type.IsDir()
+}
+
+// objectQueue is a priority queue of objects ordered by their sizes. Objects
+// with the same size are kept in the listing order.
+type objectQueue struct {
+	items        []queuedObject
+	largestFirst bool
+}
+
+func (q objectQueue) Len() int { return len(q.items) }
+
+func (q objectQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if a.urgent() != b.urgent() {
+		return a.urgent()
+	}
+	if a.object.Size != b.object.Size && !a.urgent() {
+		if q.largestFirst {
+			return a.object.Size > b.object.Size
+		}
+		return a.object.Size < b.object.Size
+	}
+	return a.seq < b.seq
+}
+
+func (q objectQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *objectQueue) Push(x interface{}) { q.items = append(q.items, x.(queuedObject)) }
+
+func (q *objectQueue) Pop() interface{} {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items[n-1] = queuedObject{}
+	q.items = q.items[:n-1]
+	return item
+}
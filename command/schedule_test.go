@@ -0,0 +1,90 @@
+package command
+
+import (
+	"container/heap"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func scheduleTestObjects() []*storage.Object {
+	object := func(key string, size int64) *storage.Object {
+		return &storage.Object{URL: &url.URL{Path: key}, Size: size}
+	}
+
+	return []*storage.Object{
+		object("b", 20),
+		object("a", 10),
+		object("c", 30),
+		object("d", 10),
+		{Err: errors.New("error")},
+	}
+}
+
+func TestObjectQueue(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name         string
+		largestFirst bool
+		expected     []string
+	}{
+		{name: "smallest_first", expected: []string{"", "a", "d", "b", "c"}},
+		{name: "largest_first", largestFirst: true, expected: []string{"", "c", "b", "a", "d"}},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			queue := &objectQueue{largestFirst: tc.largestFirst}
+			for i, object := range scheduleTestObjects() {
+				heap.Push(queue, queuedObject{object: object, seq: int64(i)})
+			}
+
+			var got []string
+			for queue.Len() > 0 {
+				item := heap.Pop(queue).(queuedObject)
+				if item.object.URL == nil {
+					got = append(got, "")
+					continue
+				}
+				got = append(got, item.object.URL.Path)
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestScheduleObjects(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range []string{scheduleFIFO, scheduleSmallestFirst, scheduleLargestFirst} {
+		objects := scheduleTestObjects()
+
+		objch := make(chan *storage.Object, len(objects))
+		for _, object := range objects {
+			objch <- object
+		}
+		close(objch)
+
+		var got []*storage.Object
+		for object := range scheduleObjects(objch, policy) {
+			got = append(got, object)
+		}
+
+		// all objects are sent regardless of the order.
+		assert.Len(t, got, len(objects), policy)
+		sort.Slice(got, func(i, j int) bool { return got[i].Size < got[j].Size })
+		assert.Equal(t, int64(30), got[len(got)-1].Size, policy)
+	}
+
+	assert.NoError(t, validateScheduling(scheduleLargestFirst))
+	assert.Error(t, validateScheduling("random"))
+}
@@ -0,0 +1,392 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
+)
+
+const (
+	seedContentRandom  = "random"
+	seedContentPattern = "pattern"
+
+	// seedPattern is repeated to generate patterned object content.
+	seedPattern = "0123456789abcdefghijklmnopqrstuvwxyz\n"
+)
+
+var seedHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] destination
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Generate 100 objects of 1KiB each under a prefix
+		 > s5cmd {{.HelpName}} s3://bucket/prefix/
+
+	2. Generate 10000 objects of 4MiB each
+		 > s5cmd {{.HelpName}} --count 10000 --size 4M s3://bucket/prefix/
+
+	3. Generate objects with sizes distributed between 1KiB and 1MiB
+		 > s5cmd {{.HelpName}} --count 1000 --size 1K..1M s3://bucket/prefix/
+
+	4. Distribute objects evenly into 16 sub-prefixes
+		 > s5cmd {{.HelpName}} --count 1000 --dirs 16 s3://bucket/prefix/
+
+	5. Generate compressible, patterned content instead of random bytes
+		 > s5cmd {{.HelpName}} --content pattern s3://bucket/prefix/
+
+	6. Generate the same dataset again using the same random seed
+		 > s5cmd {{.HelpName}} --count 1000 --size 1K..1M --random-seed 42 s3://bucket/prefix/
+
+	7. Generate a million objects with sizes between 4KiB and 1MiB
+		 > s5cmd {{.HelpName}} --count 1M --size 4K..1M s3://bucket/prefix/
+`
+
+var seedCommand = &cli.Command{
+	Name:               "seed",
+	HelpName:           "seed",
+	Aliases:            []string{"gen"},
+	Usage:              "generate objects for testing",
+	CustomHelpTemplate: seedHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "count",
+			Aliases: []string{"n"},
+			Value:   "100",
+			Usage:   "number of objects to generate, K, M and G suffixes are supported",
+		},
+		&cli.StringFlag{
+			Name:  "size",
+			Value: "1K",
+			Usage: "size of each object, or a MIN..MAX range to distribute sizes uniformly",
+		},
+		&cli.IntFlag{
+			Name:  "dirs",
+			Usage: "number of sub-prefixes to distribute objects into",
+		},
+		&cli.StringFlag{
+			Name:  "content",
+			Value: seedContentRandom,
+			Usage: "content of the objects: (random, pattern)",
+		},
+		&cli.Int64Flag{
+			Name:  "random-seed",
+			Usage: "seed for generating object sizes and random content",
+		},
+		&cli.IntFlag{
+			Name:    "concurrency",
+			Aliases: []string{"c"},
+			Value:   defaultCopyConcurrency,
+			Usage:   "number of concurrent parts transferred between host and remote server",
+		},
+		&cli.StringFlag{
+			Name:    "part-size",
+			Aliases: []string{"p"},
+			Value:   strconv.Itoa(defaultPartSize),
+			Usage:   "size of each part transferred between host and remote server, in MiB unless a unit is given, e.g. 100M",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateSeedCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		// validated in Before
+		minSize, maxSize, _ := parseSizeRange(c.String("size"))
+		count, _ := strutil.ParseCount(c.String("count"))
+		partSize, _ := parsePartSize(c.String("part-size"))
+
+		return Seed{
+			dst:         c.Args().First(),
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+
+			count:       int(count),
+			minSize:     minSize,
+			maxSize:     maxSize,
+			dirs:        c.Int("dirs"),
+			content:     c.String("content"),
+			randomSeed:  c.Int64("random-seed"),
+			concurrency: c.Int("concurrency"),
+			partSize:    partSize,
+
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Seed holds seed operation flags and states.
+type Seed struct {
+	dst         string
+	op          string
+	fullCommand string
+
+	count      int
+	minSize    int64
+	maxSize    int64
+	dirs       int
+	content    string
+	randomSeed int64
+
+	// s3 options
+	concurrency int
+	partSize    int64
+	storageOpts storage.Options
+}
+
+// Run generates objects under the given destination.
+func (s Seed) Run(ctx context.Context) error {
+	dsturl, err := url.New(s.dst)
+	if err != nil {
+		printError(s.fullCommand, s.op, err)
+		return err
+	}
+
+	client, err := storage.NewRemoteClient(ctx, dsturl, s.storageOpts)
+	if err != nil {
+		printError(s.fullCommand, s.op, err)
+		return err
+	}
+
+	waiter := parallel.NewWaiter()
+
+	var (
+		merror    error
+		errDoneCh = make(chan bool)
+	)
+
+	go func() {
+		defer close(errDoneCh)
+		for err := range waiter.Err() {
+			printError(s.fullCommand, s.op, err)
+			merror = multierror.Append(merror, err)
+		}
+	}()
+
+	for i := 0; i < s.count; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		objurl := dsturl.Join(s.key(i))
+		task := s.prepareTask(ctx, client, objurl, i)
+		parallel.Run(task, waiter)
+	}
+
+	waiter.Wait()
+	<-errDoneCh
+
+	return merror
+}
+
+func (s Seed) prepareTask(ctx context.Context, client *storage.S3, objurl *url.URL, index int) func() error {
+	return func() error {
+		reader := s.newReader(index)
+
+		err := client.Put(ctx, reader, objurl, storage.NewMetadata(), s.concurrency, s.partSize)
+		if err != nil {
+			return &errorpkg.Error{
+				Op:  s.op,
+				Src: objurl,
+				Err: err,
+			}
+		}
+
+		msg := log.InfoMessage{
+			Operation: s.op,
+			Source:    objurl,
+			Object: &storage.Object{
+				Size: reader.size,
+			},
+		}
+		log.Info(msg)
+		return nil
+	}
+}
+
+// key returns the key of the object with the given index, relative to the
+// destination.
+func (s Seed) key(index int) string {
+	width := len(strconv.Itoa(s.count - 1))
+	name := fmt.Sprintf("object%0*d", width, index)
+	if s.dirs <= 1 {
+		return name
+	}
+
+	dirWidth := len(strconv.Itoa(s.dirs - 1))
+	return fmt.Sprintf("dir%0*d/%v", dirWidth, index%s.dirs, name)
+}
+
+// newReader returns the reader generating the content of the object with the
+// given index. Both the size and the content of an object only depend on the
+// random seed and the index of the object.
+func (s Seed) newReader(index int) *seedReader {
+	seed := splitmix64(uint64(s.randomSeed) ^ uint64(index)<<32)
+
+	size := s.minSize
+	if s.maxSize > s.minSize {
+		size += int64(seed % uint64(s.maxSize-s.minSize+1))
+	}
+
+	return &seedReader{
+		size:    size,
+		content: s.content,
+		seed:    splitmix64(seed),
+	}
+}
+
+// seedReader generates object content on the fly. It implements io.Seeker, so
+// objects smaller than a part are uploaded without buffering.
+type seedReader struct {
+	size    int64
+	offset  int64
+	content string
+	seed    uint64
+}
+
+// Read implements io.Reader.
+func (r *seedReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+
+	if remaining := r.size - r.offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	for i := range p {
+		p[i] = r.byteAt(r.offset + int64(i))
+	}
+	r.offset += int64(len(p))
+	return len(p), nil
+}
+
+// Seek implements io.Seeker.
+func (r *seedReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %v", whence)
+	}
+
+	if abs < 0 {
+		return 0, fmt.Errorf("negative position %v", abs)
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func (r *seedReader) byteAt(offset int64) byte {
+	if r.content == seedContentPattern {
+		return seedPattern[offset%int64(len(seedPattern))]
+	}
+	block := splitmix64(r.seed + uint64(offset/8))
+	return byte(block >> (8 * uint64(offset%8)))
+}
+
+// splitmix64 is a fast, deterministic hash function used as a pseudo-random
+// number generator.
+func splitmix64(x uint64) uint64 {
+	z := x + 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// parseSizeRange parses a size, such as "4K", or a size range, such as
+// "1K..1M".
+func parseSizeRange(s string) (int64, int64, error) {
+	parts := strings.SplitN(s, "..", 2)
+
+	min, err := strutil.ParseBytes(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return min, min, nil
+	}
+
+	max, err := strutil.ParseBytes(parts[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	if min > max {
+		return 0, 0, fmt.Errorf("invalid size range %q: minimum is greater than maximum", s)
+	}
+	return min, max, nil
+}
+
+func validateSeedCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only 1 argument")
+	}
+
+	dsturl, err := url.New(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	if !dsturl.IsRemote() || !(dsturl.IsBucket() || dsturl.IsPrefix()) {
+		return fmt.Errorf("destination must be a bucket or a prefix")
+	}
+
+	if dsturl.HasGlob() {
+		return fmt.Errorf("target %q can not contain glob characters", dsturl)
+	}
+
+	count, err := strutil.ParseCount(c.String("count"))
+	if err != nil {
+		return err
+	}
+	if count < 1 {
+		return fmt.Errorf("count must be a positive number")
+	}
+
+	if c.Int("dirs") < 0 {
+		return fmt.Errorf("dirs can not be a negative number")
+	}
+
+	if _, _, err := parseSizeRange(c.String("size")); err != nil {
+		return err
+	}
+
+	if err := validateMultipartFlags(c); err != nil {
+		return err
+	}
+
+	switch content := c.String("content"); content {
+	case seedContentRandom, seedContentPattern:
+	default:
+		return fmt.Errorf("unknown content type %q", content)
+	}
+
+	return nil
+}
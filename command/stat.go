@@ -0,0 +1,232 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
+)
+
+var statHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] argument
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Print metadata of an object
+		 > s5cmd {{.HelpName}} s3://bucket/prefix/object.gz
+
+	2. Print metadata of an object as JSON
+		 > s5cmd --json {{.HelpName}} s3://bucket/prefix/object.gz
+
+	3. Print metadata of an object as CSV
+		 > s5cmd {{.HelpName}} --format csv s3://bucket/prefix/object.gz
+`
+
+var statCommand = &cli.Command{
+	Name:               "stat",
+	HelpName:           "stat",
+	Aliases:            []string{"head"},
+	Usage:              "print metadata of an object",
+	CustomHelpTemplate: statHelpTemplate,
+	Flags: []cli.Flag{
+		newFormatFlag(),
+	},
+	Before: func(c *cli.Context) error {
+		err := validateStatCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		return Stat{
+			src:         c.Args().First(),
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+			// flags
+			format: c.String("format"),
+
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Stat holds stat operation flags and states.
+type Stat struct {
+	src         string
+	op          string
+	fullCommand string
+
+	// flags
+	format string
+
+	storageOpts storage.Options
+}
+
+// Run prints the metadata and tags of the object.
+func (s Stat) Run(ctx context.Context) error {
+	srcurl, err := url.New(s.src)
+	if err != nil {
+		printError(s.fullCommand, s.op, err)
+		return err
+	}
+
+	client, err := storage.NewRemoteClient(ctx, srcurl, s.storageOpts)
+	if err != nil {
+		printError(s.fullCommand, s.op, err)
+		return err
+	}
+
+	object, err := client.Stat(ctx, srcurl)
+	if err != nil {
+		printError(s.fullCommand, s.op, err)
+		return err
+	}
+
+	object.Tags, err = client.Tags(ctx, srcurl)
+	if err != nil {
+		printError(s.fullCommand, s.op, err)
+		return err
+	}
+
+	log.Summary(ReportMessage{
+		format:  s.format,
+		reports: []Report{StatMessage{Object: object}},
+	})
+	return nil
+}
+
+// StatMessage is a structure for logging stat results.
+type StatMessage struct {
+	Object *storage.Object `json:"object"`
+}
+
+// String returns the string representation of StatMessage, one field per
+// line.
+func (m StatMessage) String() string {
+	obj := m.Object
+
+	fields := [][2]string{
+		{"Name", obj.URL.String()},
+		{"ContentType", obj.ContentType},
+		{"ContentLength", fmt.Sprintf("%d", obj.Size)},
+		{"LastModified", obj.ModTime.Format(dateFormat)},
+		{"ETag", obj.Etag},
+		{"StorageClass", m.storageClass()},
+	}
+	if obj.CacheControl != "" {
+		fields = append(fields, [2]string{"CacheControl", obj.CacheControl})
+	}
+	if obj.SSE != "" {
+		fields = append(fields, [2]string{"SSE", obj.SSE})
+	}
+	if obj.SSEKeyID != "" {
+		fields = append(fields, [2]string{"SSEKMSKeyID", obj.SSEKeyID})
+	}
+	for _, kv := range sortedPairs(obj.UserMetadata) {
+		fields = append(fields, [2]string{"Metadata", kv})
+	}
+	for _, kv := range sortedPairs(obj.Tags) {
+		fields = append(fields, [2]string{"Tag", kv})
+	}
+
+	lines := make([]string, 0, len(fields))
+	for _, f := range fields {
+		lines = append(lines, fmt.Sprintf("%-14s %s", f[0]+":", f[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON returns the JSON representation of StatMessage.
+func (m StatMessage) JSON() string {
+	return strutil.JSON(m.Object)
+}
+
+// Header returns the column names of StatMessage.
+func (m StatMessage) Header() []string {
+	return []string{
+		"Name",
+		"ContentType",
+		"ContentLength",
+		"LastModified",
+		"ETag",
+		"StorageClass",
+		"CacheControl",
+		"SSE",
+		"SSEKMSKeyID",
+		"Metadata",
+		"Tags",
+	}
+}
+
+// Row returns the column values of StatMessage. Metadata and tags are given
+// as comma separated key=value pairs.
+func (m StatMessage) Row() []string {
+	obj := m.Object
+	return []string{
+		obj.URL.String(),
+		obj.ContentType,
+		fmt.Sprintf("%d", obj.Size),
+		obj.ModTime.Format(dateFormat),
+		obj.Etag,
+		m.storageClass(),
+		obj.CacheControl,
+		obj.SSE,
+		obj.SSEKeyID,
+		strings.Join(sortedPairs(obj.UserMetadata), ","),
+		strings.Join(sortedPairs(obj.Tags), ","),
+	}
+}
+
+func (m StatMessage) storageClass() string {
+	if m.Object.StorageClass == "" {
+		return "STANDARD"
+	}
+	return string(m.Object.StorageClass)
+}
+
+// sortedPairs returns key=value pairs of the map sorted by keys.
+func sortedPairs(m map[string]string) []string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+func validateStatCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only 1 argument")
+	}
+
+	srcurl, err := url.New(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	if !srcurl.IsRemote() || srcurl.IsBucket() || srcurl.IsPrefix() {
+		return fmt.Errorf("source must be a remote object")
+	}
+
+	if srcurl.HasGlob() {
+		return fmt.Errorf("source can not contain wildcards")
+	}
+
+	return validateFormatFlag(c)
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	"github.com/peak/s5cmd/log/stat"
+)
+
+// runStart is the start time of the run, used to calculate its duration.
+var runStart time.Time
+
+// RunSummary is the summary of a run, which is sent to the --notify-url and
+// written to the --stat-json file.
+type RunSummary struct {
+	Command    string         `json:"command"`
+	Total      int64          `json:"total"`
+	Success    int64          `json:"success"`
+	Error      int64          `json:"error"`
+	Skipped    int64          `json:"skipped"`
+	Bytes      int64          `json:"bytes"`
+	Uploaded   int64          `json:"uploaded_bytes"`
+	Downloaded int64          `json:"downloaded_bytes"`
+	Duration   float64        `json:"duration_seconds"`
+	Operations stat.Stats     `json:"operations"`
+	Latencies  stat.Latencies `json:"latencies"`
+	Failures   []stat.Failure `json:"failures"`
+}
+
+// newRunSummary aggregates the statistics collected so far.
+func newRunSummary(command string, duration time.Duration) RunSummary {
+	stats := stat.Statistics()
+	if stats == nil {
+		stats = stat.Stats{}
+	}
+
+	summary := RunSummary{
+		Command:    command,
+		Duration:   duration.Seconds(),
+		Operations: stats,
+		Latencies:  stat.LatencyStatistics(),
+		Failures:   stat.Failures(),
+		Skipped:    skippedJobs(),
+	}
+
+	for _, s := range stats {
+		summary.Total += s.Success + s.Error
+		summary.Success += s.Success
+		summary.Error += s.Error
+		summary.Bytes += s.Bytes
+		summary.Uploaded += s.Uploaded
+		summary.Downloaded += s.Downloaded
+	}
+	return summary
+}
+
+// writeRunSummary writes the summary as JSON to given file.
+func writeRunSummary(path string, summary RunSummary) error {
+	body, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(body, '\n'), 0644)
+}
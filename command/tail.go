@@ -0,0 +1,260 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
+)
+
+const (
+	defaultTailLines = 10
+
+	// tailChunkSize is the size of the first range fetched from the end of
+	// the object in line mode. The size is doubled for each of the following
+	// ranges until enough lines are read.
+	tailChunkSize = 64 * 1024
+)
+
+var tailHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] source
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Print the last 10 lines of a remote object to stdout
+		 > s5cmd {{.HelpName}} s3://bucket/logs/huge.log
+
+	2. Print the last 100 lines of a remote object to stdout
+		 > s5cmd {{.HelpName}} -n 100 s3://bucket/logs/huge.log
+
+	3. Print the last megabyte of a remote object to stdout
+		 > s5cmd {{.HelpName}} -c 1M s3://bucket/logs/huge.log
+`
+
+var tailCommand = &cli.Command{
+	Name:               "tail",
+	HelpName:           "tail",
+	Usage:              "print the end of remote object content",
+	CustomHelpTemplate: tailHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "bytes",
+			Aliases: []string{"c"},
+			Usage:   "print the last given bytes of the object, e.g. 4096, 64K or 1M",
+		},
+		&cli.IntFlag{
+			Name:    "lines",
+			Aliases: []string{"n"},
+			Value:   defaultTailLines,
+			Usage:   "print the last given lines of the object",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateTailCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		src, err := url.New(c.Args().Get(0))
+		op := c.Command.Name
+		fullCommand := givenCommand(c)
+		if err != nil {
+			printError(fullCommand, op, err)
+			return err
+		}
+
+		var size int64
+		if c.IsSet("bytes") {
+			size, _ = strutil.ParseBytes(c.String("bytes"))
+		}
+
+		return Tail{
+			src:         src,
+			op:          op,
+			fullCommand: fullCommand,
+			// flags
+			bytes: size,
+			lines: c.Int("lines"),
+
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Tail holds tail operation flags and states.
+type Tail struct {
+	src         *url.URL
+	op          string
+	fullCommand string
+
+	// flags
+	bytes int64
+	lines int
+
+	storageOpts storage.Options
+}
+
+// Run prints the end of the content of given source to standard output. Only
+// the trailing range of the object is fetched.
+func (c Tail) Run(ctx context.Context) error {
+	client, err := storage.NewRemoteClient(ctx, c.src, c.storageOpts)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	obj, err := client.Stat(ctx, c.src)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	var content []byte
+	if c.bytes > 0 {
+		start := obj.Size - c.bytes
+		if start < 0 {
+			start = 0
+		}
+		content, err = tailRange(ctx, client, c.src, start, obj.Size)
+	} else {
+		content, err = c.tailLines(ctx, client, obj.Size)
+	}
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+
+	_, err = os.Stdout.Write(content)
+	if err != nil {
+		printError(c.fullCommand, c.op, err)
+		return err
+	}
+	return nil
+}
+
+// tailLines fetches the ranges from the end of the object until the given
+// number of lines are read, and returns the content of the lines.
+func (c Tail) tailLines(ctx context.Context, client *storage.S3, size int64) ([]byte, error) {
+	var (
+		content []byte
+		end     = size
+		chunk   = int64(tailChunkSize)
+	)
+	for end > 0 {
+		start := end - chunk
+		if start < 0 {
+			start = 0
+		}
+
+		data, err := tailRange(ctx, client, c.src, start, end)
+		if err != nil {
+			return nil, err
+		}
+		content = append(data, content...)
+
+		if offset, ok := lastLines(content, c.lines); ok {
+			return content[offset:], nil
+		}
+		end = start
+		chunk *= 2
+	}
+	return content, nil
+}
+
+// tailRange returns the content of the object between start and end, end
+// being exclusive.
+func tailRange(ctx context.Context, client *storage.S3, src *url.URL, start, end int64) ([]byte, error) {
+	if start >= end {
+		return nil, nil
+	}
+
+	rc, err := client.ReadRange(ctx, src, fmt.Sprintf("bytes=%d-%d", start, end-1))
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return ioutil.ReadAll(io.LimitReader(rc, end-start))
+}
+
+// lastLines returns the offset of the last n lines of the content, and
+// whether the content has that many lines. The newline at the end of the
+// content terminates the last line, it doesn't start a new one.
+func lastLines(content []byte, n int) (int, bool) {
+	if n <= 0 {
+		return len(content), true
+	}
+
+	end := len(content)
+	if end > 0 && content[end-1] == '\n' {
+		end--
+	}
+	for ; n > 0; n-- {
+		i := bytes.LastIndexByte(content[:end], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		end = i
+	}
+	return end + 1, true
+}
+
+func validateTailCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only one argument")
+	}
+
+	src, err := url.New(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	if !src.IsRemote() {
+		return fmt.Errorf("source must be a remote object")
+	}
+
+	if src.IsBucket() || src.IsPrefix() {
+		return fmt.Errorf("remote source must be an object")
+	}
+
+	if src.HasGlob() {
+		return fmt.Errorf("remote source %q can not contain glob characters", src)
+	}
+
+	if c.IsSet("bytes") && c.IsSet("lines") {
+		return fmt.Errorf("--bytes and --lines flags can not be used together")
+	}
+
+	if c.IsSet("bytes") {
+		size, err := strutil.ParseBytes(c.String("bytes"))
+		if err != nil {
+			return fmt.Errorf("invalid --bytes %q: %v", c.String("bytes"), err)
+		}
+		if size <= 0 {
+			return fmt.Errorf("--bytes must be a positive number")
+		}
+	}
+
+	if c.Int("lines") < 0 {
+		return fmt.Errorf("--lines can not be negative")
+	}
+	return nil
+}
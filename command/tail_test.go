@@ -0,0 +1,38 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLastLines(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		content  string
+		n        int
+		expected string
+		ok       bool
+	}{
+		{name: "trailing_newline", content: "a\nb\nc\n", n: 2, expected: "b\nc\n", ok: true},
+		{name: "no_trailing_newline", content: "a\nb\nc", n: 2, expected: "b\nc", ok: true},
+		{name: "exact_number_of_lines", content: "\na\nb\n", n: 2, expected: "a\nb\n", ok: true},
+		{name: "empty_lines", content: "a\n\n\n", n: 2, expected: "\n\n", ok: true},
+		{name: "zero_lines", content: "a\nb\n", n: 0, expected: "", ok: true},
+		{name: "not_enough_lines", content: "a\nb\n", n: 2, ok: false},
+		{name: "empty", content: "", n: 1, ok: false},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			offset, ok := lastLines([]byte(tc.content), tc.n)
+			assert.Equal(t, tc.ok, ok)
+			if ok {
+				assert.Equal(t, tc.expected, tc.content[offset:])
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+	"github.com/peak/s5cmd/strutil"
+)
+
+var treeHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] argument
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Print the directory hierarchy of a bucket
+		 > s5cmd {{.HelpName}} s3://bucket
+
+	2. Print the directories under a prefix up to 2 levels deep, with human-readable sizes
+		 > s5cmd {{.HelpName}} --depth 2 -H s3://bucket/prefix/
+`
+
+var treeCommand = &cli.Command{
+	Name:               "tree",
+	HelpName:           "tree",
+	Usage:              "print directory hierarchy of a prefix",
+	CustomHelpTemplate: treeHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:    "depth",
+			Aliases: []string{"L"},
+			Usage:   "maximum depth of printed directories, 0 is unlimited. sizes of deeper directories are included in their ancestors",
+		},
+		&cli.BoolFlag{
+			Name:    "humanize",
+			Aliases: []string{"H"},
+			Usage:   "human-readable output for object sizes",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateTreeCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		return Tree{
+			src:         c.Args().First(),
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+			// flags
+			depth:    c.Int("depth"),
+			humanize: c.Bool("humanize"),
+
+			storageOpts: NewStorageOpts(c),
+		}.Run(c.Context)
+	},
+}
+
+// Tree holds tree operation flags and states.
+type Tree struct {
+	src         string
+	op          string
+	fullCommand string
+
+	// flags
+	depth    int
+	humanize bool
+
+	storageOpts storage.Options
+}
+
+// treeNode is a directory in the hierarchy. Object counts and sizes include
+// all objects in the subtree.
+type treeNode struct {
+	url      *url.URL
+	total    sizeAndCount
+	children []*treeNode
+}
+
+// Run prints the directory hierarchy of given prefix.
+func (t Tree) Run(ctx context.Context) error {
+	srcurl, err := treeURL(t.src)
+	if err != nil {
+		printError(t.fullCommand, t.op, err)
+		return err
+	}
+
+	client, err := storage.NewClient(ctx, srcurl, t.storageOpts)
+	if err != nil {
+		printError(t.fullCommand, t.op, err)
+		return err
+	}
+
+	root := &treeNode{url: srcurl}
+	merror := t.walk(ctx, client, root, 0)
+
+	t.print(root, "", "", 0)
+
+	return merror
+}
+
+// walk lists the directory with a delimiter and descends into its
+// subdirectories. Directories at the maximum depth are listed without a
+// delimiter, as their subdirectories are not printed.
+func (t Tree) walk(ctx context.Context, client storage.Storage, node *treeNode, depth int) error {
+	if t.depth > 0 && depth >= t.depth {
+		return t.count(ctx, client, node)
+	}
+
+	var merror error
+	for object := range client.List(ctx, node.url, false) {
+		if errorpkg.IsCancelation(object.Err) {
+			continue
+		}
+
+		if err := object.Err; err != nil {
+			if err == storage.ErrNoObjectFound {
+				continue
+			}
+			merror = multierror.Append(merror, err)
+			printError(t.fullCommand, t.op, err)
+			continue
+		}
+
+		if !object.Type.IsDir() {
+			node.total.addObject(object)
+			continue
+		}
+
+		// directory marker of the listed prefix itself.
+		if object.URL.Path == node.url.Path {
+			continue
+		}
+
+		childurl, err := url.New(object.URL.String())
+		if err != nil {
+			merror = multierror.Append(merror, err)
+			continue
+		}
+
+		child := &treeNode{url: childurl}
+		if err := t.walk(ctx, client, child, depth+1); err != nil {
+			merror = multierror.Append(merror, err)
+		}
+		node.children = append(node.children, child)
+		node.total.size += child.total.size
+		node.total.count += child.total.count
+	}
+	return merror
+}
+
+// count calculates the total size of all objects under the directory.
+func (t Tree) count(ctx context.Context, client storage.Storage, node *treeNode) error {
+	allurl, err := url.New(node.url.String() + "*")
+	if err != nil {
+		return err
+	}
+
+	var merror error
+	for object := range client.List(ctx, allurl, false) {
+		if object.Type.IsDir() || errorpkg.IsCancelation(object.Err) {
+			continue
+		}
+
+		if err := object.Err; err != nil {
+			if err == storage.ErrNoObjectFound {
+				continue
+			}
+			merror = multierror.Append(merror, err)
+			printError(t.fullCommand, t.op, err)
+			continue
+		}
+		node.total.addObject(object)
+	}
+	return merror
+}
+
+// print prints the node and its children. Each line is prefixed with the
+// branches of its ancestors.
+func (t Tree) print(node *treeNode, prefix, branch string, depth int) {
+	name := node.url.String()
+	if depth > 0 {
+		name = node.url.Base() + "/"
+	}
+
+	log.Summary(TreeMessage{
+		Prefix:        node.url.String(),
+		Depth:         depth,
+		Count:         node.total.count,
+		Size:          node.total.size,
+		line:          prefix + branch + name,
+		showHumanized: t.humanize,
+	})
+
+	if depth > 0 {
+		if branch == "└── " {
+			prefix += "    "
+		} else {
+			prefix += "│   "
+		}
+	}
+
+	for i, child := range node.children {
+		branch := "├── "
+		if i == len(node.children)-1 {
+			branch = "└── "
+		}
+		t.print(child, prefix, branch, depth+1)
+	}
+}
+
+// treeURL returns the url to list the directories of given prefix.
+func treeURL(src string) (*url.URL, error) {
+	srcurl, err := url.New(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if !srcurl.IsRemote() {
+		return nil, fmt.Errorf("source must be a remote prefix")
+	}
+
+	if srcurl.HasGlob() {
+		return nil, fmt.Errorf("source can not contain wildcards")
+	}
+
+	if srcurl.IsBucket() || srcurl.IsPrefix() {
+		return srcurl, nil
+	}
+	return url.New(src + "/")
+}
+
+// TreeMessage is a structure for logging a directory of tree results.
+type TreeMessage struct {
+	Prefix string `json:"prefix"`
+	Depth  int    `json:"depth"`
+	Count  int64  `json:"count"`
+	Size   int64  `json:"size"`
+
+	line          string
+	showHumanized bool
+}
+
+// String returns the string representation of TreeMessage.
+func (m TreeMessage) String() string {
+	size := fmt.Sprintf("%d bytes", m.Size)
+	if m.showHumanized {
+		size = strutil.HumanizeBytes(m.Size)
+	}
+	return fmt.Sprintf("%s (%d objects, %s)", m.line, m.Count, size)
+}
+
+// JSON returns the JSON representation of TreeMessage.
+func (m TreeMessage) JSON() string {
+	return strutil.JSON(m)
+}
+
+func validateTreeCommand(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		return fmt.Errorf("expected only 1 argument")
+	}
+
+	if c.Int("depth") < 0 {
+		return fmt.Errorf("depth can not be a negative number")
+	}
+
+	_, err := treeURL(c.Args().First())
+	return err
+}
@@ -0,0 +1,322 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	errorpkg "github.com/peak/s5cmd/error"
+	"github.com/peak/s5cmd/log/stat"
+	"github.com/peak/s5cmd/parallel"
+	"github.com/peak/s5cmd/storage"
+	"github.com/peak/s5cmd/storage/url"
+)
+
+var watchHelpTemplate = `Name:
+	{{.HelpName}} - {{.Usage}}
+
+Usage:
+	{{.HelpName}} [options] source destination
+
+Options:
+	{{range .VisibleFlags}}{{.}}
+	{{end}}
+Examples:
+	1. Upload new and modified files of a directory until interrupted
+		 > s5cmd {{.HelpName}} logs/ s3://bucket/logs/
+
+	2. Upload files which are not modified for 10 seconds, checking the directory every 5 seconds
+		 > s5cmd {{.HelpName}} --interval 5s --debounce 10s logs/ s3://bucket/logs/
+
+	3. Upload files except temporary ones
+		 > s5cmd {{.HelpName}} --exclude "*.tmp" --exclude ".git/*" artifacts/ s3://bucket/artifacts/
+`
+
+var watchCommand = &cli.Command{
+	Name:               "watch",
+	HelpName:           "watch",
+	Usage:              "upload new and modified files of a directory continuously",
+	CustomHelpTemplate: watchHelpTemplate,
+	Flags: []cli.Flag{
+		&cli.DurationFlag{
+			Name:  "interval",
+			Value: time.Second,
+			Usage: "check the directory for changes with given interval",
+		},
+		&cli.DurationFlag{
+			Name:  "debounce",
+			Value: 2 * time.Second,
+			Usage: "upload files only if they are not modified for given duration",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "do not upload files whose relative paths or names match given wildcard pattern, can be given multiple times",
+		},
+		&cli.StringFlag{
+			Name:  "storage-class",
+			Usage: "set storage class for target ('STANDARD','REDUCED_REDUNDANCY','GLACIER','STANDARD_IA','ONEZONE_IA','INTELLIGENT_TIERING','DEEP_ARCHIVE')",
+		},
+	},
+	Before: func(c *cli.Context) error {
+		err := validateWatchCommand(c)
+		if err != nil {
+			printError(givenCommand(c), c.Command.Name, err)
+		}
+		return err
+	},
+	Action: func(c *cli.Context) (err error) {
+		defer stat.Collect(c.Command.FullName(), &err)()
+
+		// watch runs until it is interrupted.
+		setRunsUntilInterrupted()
+
+		return Watch{
+			src:         c.Args().Get(0),
+			dst:         c.Args().Get(1),
+			op:          c.Command.Name,
+			fullCommand: givenCommand(c),
+			// flags
+			interval: c.Duration("interval"),
+			debounce: c.Duration("debounce"),
+			exclude:  c.StringSlice("exclude"),
+
+			copy: Copy{
+				op:           c.Command.Name,
+				fullCommand:  givenCommand(c),
+				storageClass: storage.StorageClass(c.String("storage-class")),
+				concurrency:  defaultCopyConcurrency,
+				partSize:     defaultPartSize * megabytes,
+				storageOpts:  NewStorageOpts(c),
+			},
+		}.Run(c.Context)
+	},
+}
+
+// Watch holds watch operation flags and states.
+type Watch struct {
+	src         string
+	dst         string
+	op          string
+	fullCommand string
+
+	// flags
+	interval time.Duration
+	debounce time.Duration
+	exclude  []string
+
+	// copy uploads the changed files.
+	copy Copy
+}
+
+// fileState is the state of a file when it is last seen.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// watchState holds the states of uploaded files between scans.
+type watchState struct {
+	mu       sync.Mutex
+	uploaded map[string]fileState
+}
+
+// Run checks the source directory with given interval and uploads the new
+// and modified files until the context is canceled. Existing files are
+// uploaded on the first check.
+func (w Watch) Run(ctx context.Context) error {
+	srcurl, err := url.New(w.src)
+	if err != nil {
+		printError(w.fullCommand, w.op, err)
+		return err
+	}
+
+	dsturl, err := url.New(w.dst)
+	if err != nil {
+		printError(w.fullCommand, w.op, err)
+		return err
+	}
+
+	client := storage.NewLocalClient(w.copy.srcStorageOpts())
+
+	state := &watchState{
+		uploaded: map[string]fileState{},
+	}
+
+	for {
+		w.scan(ctx, client, srcurl, dsturl, state, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// scan walks the source directory and uploads the changed files. Failed uploads are retried on
+// the next scan.
+func (w Watch) scan(
+	ctx context.Context,
+	client *storage.Filesystem,
+	srcurl *url.URL,
+	dsturl *url.URL,
+	state *watchState,
+	now time.Time,
+) {
+	waiter := parallel.NewWaiter()
+	errDoneCh := make(chan bool)
+
+	go func() {
+		defer close(errDoneCh)
+		for err := range waiter.Err() {
+			printError(w.fullCommand, w.op, err)
+		}
+	}()
+
+	seen := map[string]struct{}{}
+	for object := range client.List(ctx, srcurl, false) {
+		if object.Type.IsDir() || errorpkg.IsCancelation(object.Err) {
+			continue
+		}
+
+		if err := object.Err; err != nil {
+			printError(w.fullCommand, w.op, err)
+			continue
+		}
+
+		rel, err := filepath.Rel(srcurl.Absolute(), object.URL.Absolute())
+		if err != nil {
+			printError(w.fullCommand, w.op, err)
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		if w.isExcluded(rel) {
+			continue
+		}
+
+		key := object.URL.Absolute()
+		seen[key] = struct{}{}
+
+		current := fileState{size: object.Size, modTime: *object.ModTime}
+		if !state.changed(key, current, now, w.debounce) {
+			continue
+		}
+
+		fileurl := object.URL
+		remoteurl := dsturl.Join(rel)
+		parallel.Run(func() error {
+			if err := w.copy.doUpload(ctx, fileurl, remoteurl); err != nil {
+				return &errorpkg.Error{
+					Op:  w.op,
+					Src: fileurl,
+					Dst: remoteurl,
+					Err: err,
+				}
+			}
+			state.done(key, current)
+			return nil
+		}, waiter)
+	}
+
+	waiter.Wait()
+	<-errDoneCh
+
+	state.forget(seen)
+}
+
+// isExcluded reports whether the file with given relative path matches any
+// of the exclude patterns.
+func (w Watch) isExcluded(rel string) bool {
+	for _, pattern := range w.exclude {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// changed reports whether the file should be uploaded. A file is uploaded
+// if its state differs from the uploaded state and it is not modified for
+// the debounce duration, so files being written are not uploaded partially.
+func (s *watchState) changed(key string, current fileState, now time.Time, debounce time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if uploaded, ok := s.uploaded[key]; ok && uploaded == current {
+		return false
+	}
+	return now.Sub(current.modTime) >= debounce
+}
+
+// done marks the file as uploaded with given state.
+func (s *watchState) done(key string, state fileState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.uploaded[key] = state
+}
+
+// forget removes the states of the files which no longer exist.
+func (s *watchState) forget(seen map[string]struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.uploaded {
+		if _, ok := seen[key]; !ok {
+			delete(s.uploaded, key)
+		}
+	}
+}
+
+func validateWatchCommand(c *cli.Context) error {
+	if c.Args().Len() != 2 {
+		return fmt.Errorf("expected source and destination arguments")
+	}
+
+	srcurl, err := url.New(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	dsturl, err := url.New(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	if srcurl.IsRemote() || srcurl.HasGlob() {
+		return fmt.Errorf("source must be a local directory")
+	}
+
+	obj, err := storage.NewLocalClient(storage.Options{}).Stat(c.Context, srcurl)
+	if err != nil || !obj.Type.IsDir() {
+		return fmt.Errorf("source must be a local directory")
+	}
+
+	if !dsturl.IsRemote() || !(dsturl.IsPrefix() || dsturl.IsBucket()) {
+		return fmt.Errorf("target must be a bucket or a prefix")
+	}
+
+	for _, pattern := range c.StringSlice("exclude") {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid exclude pattern %q", pattern)
+		}
+	}
+
+	if c.Duration("interval") <= 0 {
+		return fmt.Errorf("interval must be a positive duration")
+	}
+
+	if c.Duration("debounce") < 0 {
+		return fmt.Errorf("debounce can not be a negative duration")
+	}
+	return nil
+}
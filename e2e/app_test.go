@@ -2,11 +2,19 @@ package e2e
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
 	"gotest.tools/v3/icmd"
 )
 
@@ -23,7 +31,7 @@ func TestAppRetryCount(t *testing.T) {
 			name:             "retry_count_negative",
 			retry:            -1,
 			expectedError:    fmt.Errorf(`ERROR retry count cannot be a negative value`),
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 		},
 		{
 			name:             "retry_count_zero",
@@ -69,6 +77,294 @@ func TestAppRetryCount(t *testing.T) {
 	}
 }
 
+func TestAppRetryFailed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("negative", func(t *testing.T) {
+		t.Parallel()
+
+		_, s5cmd, cleanup := setup(t)
+		defer cleanup()
+
+		cmd := s5cmd("--retry-failed", "-1")
+		result := icmd.RunCmd(cmd)
+
+		result.Assert(t, icmd.Expected{ExitCode: 2})
+		assertLines(t, result.Stderr(), map[int]compareFunc{
+			0: equals("ERROR retry failed cannot be a negative value"),
+		})
+	})
+
+	// only the transient failures are retried, the other failures are
+	// reported without waiting for a retry.
+	t.Run("permanent_failure", func(t *testing.T) {
+		t.Parallel()
+
+		s3client, s5cmd, cleanup := setup(t)
+		defer cleanup()
+
+		createBucket(t, s3client, "bucket")
+		putFile(t, s3client, "bucket", "file.txt", "content")
+
+		cmd := s5cmd("--retry-failed", "3", "--retry-failed-delay", "1h", "cp", "s3://bucket/*.txt", "s3://non-existent-bucket/")
+		result := icmd.RunCmd(cmd, icmd.WithTimeout(time.Minute))
+
+		result.Assert(t, icmd.Expected{ExitCode: 1})
+		assertLines(t, result.Stderr(), map[int]compareFunc{
+			0: contains(`ERROR "cp s3://bucket/file.txt s3://non-existent-bucket/file.txt"`),
+		})
+	})
+
+	// the lines of a run are not blocked by the retries of the other lines.
+	t.Run("run", func(t *testing.T) {
+		t.Parallel()
+
+		s3client, s5cmd, cleanup := setup(t)
+		defer cleanup()
+
+		createBucket(t, s3client, "bucket")
+		putFile(t, s3client, "bucket", "file.txt", "content")
+
+		input := strings.NewReader(
+			strings.Join([]string{
+				"cp s3://bucket/file.txt s3://non-existent-bucket/",
+				"cp s3://bucket/file.txt s3://bucket/copy.txt",
+			}, "\n"),
+		)
+
+		cmd := s5cmd("--retry-failed", "3", "--retry-failed-delay", "1h", "--numworkers", "1", "run")
+		result := icmd.RunCmd(cmd, icmd.WithStdin(input), icmd.WithTimeout(time.Minute))
+
+		result.Assert(t, icmd.Success)
+		assertLines(t, result.Stdout(), map[int]compareFunc{
+			0: equals(`cp s3://bucket/file.txt s3://bucket/copy.txt`),
+		})
+		assertLines(t, result.Stderr(), map[int]compareFunc{
+			0: contains(`ERROR "cp s3://bucket/file.txt s3://non-existent-bucket/file.txt"`),
+		})
+	})
+}
+
+// failed jobs are recorded as command lines which can be run with -f flag
+// once the cause of the failures is fixed.
+func TestAppFailedJobsFile(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	workdir := fs.NewDir(t, t.Name(),
+		fs.WithDir("dir",
+			fs.WithFile("a.txt", "content of a"),
+			fs.WithFile("b.txt", "content of b"),
+		),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("--failed-jobs-file", "failures.txt", "cp", "--flatten", "--storage-class", "STANDARD_IA", "dir/*.txt", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), "failures.txt"))
+	assert.NilError(t, err)
+
+	assertLines(t, string(content), map[int]compareFunc{
+		0: equals("cp --storage-class STANDARD_IA dir/a.txt s3://%v/prefix/a.txt", bucket),
+		1: equals("cp --storage-class STANDARD_IA dir/b.txt s3://%v/prefix/b.txt", bucket),
+	}, sortInput(true))
+
+	createBucket(t, s3client, bucket)
+
+	cmd = s5cmd("-f", "failures.txt")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Success)
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a.txt", "content of a"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/b.txt", "content of b"))
+}
+
+// the recorded lines are quoted, so a key with " #" is not cut as a comment
+// when the failed jobs are run with -f flag.
+func TestAppFailedJobsFileWithHashInKey(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	workdir := fs.NewDir(t, t.Name(),
+		fs.WithDir("dir",
+			fs.WithFile("a #b.txt", "content of a"),
+		),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("--failed-jobs-file", "failures.txt", "cp", "dir/*.txt", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), "failures.txt"))
+	assert.NilError(t, err)
+
+	assertLines(t, string(content), map[int]compareFunc{
+		0: equals("cp 'dir/a #b.txt' 's3://%v/prefix/a #b.txt'", bucket),
+	})
+
+	createBucket(t, s3client, bucket)
+
+	cmd = s5cmd("-f", "failures.txt")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Success)
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a #b.txt", "content of a"))
+}
+
+func TestAppTransportOptions(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{
+			name:  "valid_options",
+			flags: []string{"--max-idle-conns-per-host", "16", "--connect-timeout", "5s", "--read-timeout", "1m", "--keep-alive", "-1s"},
+		},
+		{
+			name:          "negative_max_idle_conns_per_host",
+			flags:         []string{"--max-idle-conns-per-host", "-1"},
+			expectedError: "ERROR max idle connections per host cannot be a negative value",
+		},
+		{
+			name:          "negative_timeout",
+			flags:         []string{"--tls-handshake-timeout", "-1s"},
+			expectedError: "ERROR tls-handshake-timeout cannot be a negative value",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd(tc.flags...)
+			result := icmd.RunCmd(cmd)
+
+			if tc.expectedError == "" {
+				result.Assert(t, icmd.Success)
+				return
+			}
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: equals("%v", tc.expectedError),
+			})
+		})
+	}
+}
+
+func TestAppListOptions(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{
+			name:  "valid_options",
+			flags: []string{"--list-shards", "8", "--max-keys", "100", "--start-after", "logs/2020"},
+		},
+		{
+			name:          "negative_list_shards",
+			flags:         []string{"--list-shards", "-1"},
+			expectedError: "ERROR list shards cannot be a negative value",
+		},
+		{
+			name:          "zero_max_keys",
+			flags:         []string{"--max-keys", "0"},
+			expectedError: "ERROR max keys must be between 1 and 1000",
+		},
+		{
+			name:          "too_many_max_keys",
+			flags:         []string{"--max-keys", "1001"},
+			expectedError: "ERROR max keys must be between 1 and 1000",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd(tc.flags...)
+			result := icmd.RunCmd(cmd)
+
+			if tc.expectedError == "" {
+				result.Assert(t, icmd.Success)
+				return
+			}
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: equals("%v", tc.expectedError),
+			})
+		})
+	}
+}
+
+func TestAppAWSDebug(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	cmd := s5cmd("--aws-debug", "http", "ls", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// requests and responses of the SDK are printed as debug messages.
+	out := result.Stdout()
+	for _, expected := range []string{
+		"DEBUG Request s3/ListObjectsV2 Details:",
+		"DEBUG Response s3/ListObjectsV2 Details:",
+		"<Key>file.txt</Key>",
+	} {
+		assert.Assert(t, strings.Contains(out, expected), out)
+	}
+}
+
+func TestAppAWSDebugWithUnknownMode(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("--aws-debug", "headers")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR unknown aws debug mode "headers", expected one of http, signing or retries`),
+	})
+}
+
 func TestAppDashStat(t *testing.T) {
 	_, s5cmd, cleanup := setup(t)
 	defer cleanup()
@@ -84,6 +380,410 @@ func TestAppDashStat(t *testing.T) {
 	assert.Assert(t, strings.Contains(out, tsv))
 }
 
+func TestAppDashStatWithRequestLatencies(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	cmd := s5cmd("--stat", "ls", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	out := result.Stdout()
+
+	tsv := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t", "Request", "Count", "p50", "p95", "p99")
+	assert.Assert(t, strings.Contains(out, tsv), out)
+	assert.Assert(t, strings.Contains(out, "list\t1\t"), out)
+}
+
+func TestAppDashStatWithTransferredBytes(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "another content")
+
+	cmd := s5cmd("--json", "--stat", "cp", "s3://"+bucket+"/*", "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	out := result.Stdout()
+	assert.Assert(t, strings.Contains(out, `"operation":"cp","success":1,"error":0,"bytes":22,"uploaded_bytes":0,"downloaded_bytes":22,"bytes_per_second":`), out)
+}
+
+func TestAppOnlyShowErrors(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "content")
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp s3://%v/* dir/", bucket),
+		fmt.Sprintf("cp s3://%v/missing.txt dir/", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("--only-show-errors", "--stat", "run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// results of the jobs are not printed, the summary is.
+	out := result.Stdout()
+	assert.Assert(t, !strings.Contains(out, "cp s3://"), out)
+
+	tsv := fmt.Sprintf("%s\t%s\t%s\t%s\t", "Operation", "Total", "Error", "Success")
+	assert.Assert(t, strings.Contains(out, tsv), out)
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: prefix(`ERROR "cp s3://%v/missing.txt dir/missing.txt": NoSuchKey`, bucket),
+	})
+}
+
+// the outputs of the queries are printed, they aren't results of jobs.
+func TestAppOnlyShowErrorsWithQueries(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	cmd := s5cmd("--only-show-errors", "ls", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix(" file.txt"),
+	}, trimMatch(dateRe))
+
+	cmd = s5cmd("--only-show-errors", "ls")
+	result = icmd.RunCmd(cmd)
+
+	// buckets of the other tests are listed as well.
+	result.Assert(t, icmd.Success)
+	assert.Assert(t, strings.Contains(result.Stdout(), "s3://"+bucket+"\n"), result.Stdout())
+
+	cmd = s5cmd("--only-show-errors", "stat", "s3://"+bucket+"/file.txt")
+	result = icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assert.Assert(t, strings.Contains(result.Stdout(), "file.txt"), result.Stdout())
+}
+
+func TestAppStatJSON(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	// the duplicate of the first command is skipped.
+	commands := fmt.Sprintf("cp file.txt s3://%v/file.txt\ncp s3://%v/missing.txt dir/\ncp file.txt s3://%v/file.txt\n", bucket, bucket, bucket)
+	workdir := fs.NewDir(t, bucket,
+		fs.WithFile("file.txt", "content"),
+		fs.WithFile("commands.txt", commands),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("--stat-json", "stats.json", "run", "commands.txt")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), "stats.json"))
+	assert.NilError(t, err)
+
+	// the report is indented, compare it without whitespace.
+	report := strings.Join(strings.Fields(string(content)), "")
+	for _, field := range []string{
+		`"total":2,"success":1,"error":1,"skipped":1,"bytes":7,"uploaded_bytes":7,"downloaded_bytes":0,"duration_seconds":`,
+		`"latencies":[{"operation":"`,
+		fmt.Sprintf(`"failures":[{"command":"cps3://%v/missing.txtdir/missing.txt","operation":"cp","error":"NoSuchKey:`, bucket),
+	} {
+		assert.Assert(t, strings.Contains(report, field), string(content))
+	}
+}
+
+func TestAppLogFileRotation(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	commands := fmt.Sprintf("cp file1.txt s3://%v/\ncp file2.txt s3://%v/\ncp s3://%v/missing.txt dir/\n", bucket, bucket, bucket)
+	workdir := fs.NewDir(t, bucket,
+		fs.WithFile("file1.txt", "content"),
+		fs.WithFile("file2.txt", "content"),
+		fs.WithFile("commands.txt", commands),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("--log-file", "s5cmd.log", "--log-max-size", "1", "--log-max-backups", "1", "run", "commands.txt")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	// the logs are written to the file instead of the standard output.
+	assert.Equal(t, result.Stdout(), "")
+	assert.Equal(t, result.Stderr(), "")
+
+	// each message rotates the log file, and one of the rotated ones is kept.
+	backups, err := filepath.Glob(filepath.Join(workdir.Path(), "s5cmd.log.*"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(backups), 1, backups)
+
+	content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), "s5cmd.log"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(strings.Split(strings.TrimSpace(string(content)), "\n")), 1, string(content))
+}
+
+func TestAppNotifyURL(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	bodyCh := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodyCh <- r.Method + " " + r.Header.Get("Content-Type") + " " + string(body)
+	}))
+	defer server.Close()
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", "content"))
+	defer workdir.Remove()
+
+	cmd := s5cmd("--notify-url", server.URL, "cp", "file.txt", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	body := <-bodyCh
+	assert.Assert(t, strings.HasPrefix(body, "POST application/json {"), body)
+
+	for _, field := range []string{
+		`"total":1`,
+		`"success":1`,
+		`"error":0`,
+		`"bytes":7`,
+		`"duration_seconds":`,
+		`"operations":[{"operation":"cp","success":1,"error":0,"bytes":7,"uploaded_bytes":7,"downloaded_bytes":0,`,
+	} {
+		assert.Assert(t, strings.Contains(body, field), body)
+	}
+}
+
+func TestAppMetricsAddr(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	addr := freeAddr(t)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", "content"))
+	defer workdir.Remove()
+
+	// commands are read from a pipe, so the run continues until it is
+	// closed.
+	stdin, stdinWriter := io.Pipe()
+
+	cmd := s5cmd("--metrics-addr", addr, "run")
+	cmd.Dir = workdir.Path()
+	cmd.Stdin = stdin
+
+	result := icmd.StartCmd(cmd)
+	assert.NilError(t, result.Error)
+
+	_, err := fmt.Fprintf(stdinWriter, "cp file.txt s3://%v/file.txt\n", bucket)
+	assert.NilError(t, err)
+
+	var metrics string
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err != nil {
+			continue
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		metrics = string(body)
+		if strings.Contains(metrics, `s5cmd_operations_total{operation="cp",status="success"} 1`) {
+			break
+		}
+	}
+
+	stdinWriter.Close()
+	icmd.WaitOnCmd(10*time.Second, result).Assert(t, icmd.Success)
+
+	for _, line := range []string{
+		`s5cmd_operations_total{operation="cp",status="success"} 1`,
+		`s5cmd_operations_total{operation="cp",status="error"} 0`,
+		`s5cmd_transferred_bytes_total{operation="cp"} 7`,
+		`s5cmd_s3_requests_total{api="PutObject"} 1`,
+		`# TYPE s5cmd_workers_busy gauge`,
+	} {
+		assert.Assert(t, strings.Contains(metrics, line+"\n"), metrics)
+	}
+}
+
+func TestAppPprofAddr(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	addr := freeAddr(t)
+
+	// commands are read from a pipe, so the run continues until it is
+	// closed.
+	stdin, stdinWriter := io.Pipe()
+
+	cmd := s5cmd("--pprof-addr", addr, "run")
+	cmd.Stdin = stdin
+
+	result := icmd.StartCmd(cmd)
+	assert.NilError(t, result.Error)
+
+	var (
+		body string
+		err  error
+	)
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		var resp *http.Response
+		resp, err = http.Get("http://" + addr + "/debug/pprof/cmdline")
+		if err != nil {
+			continue
+		}
+		content, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		body = string(content)
+		break
+	}
+
+	stdinWriter.Close()
+	icmd.WaitOnCmd(10*time.Second, result).Assert(t, icmd.Success)
+
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(body, "--pprof-addr"), body)
+}
+
+func TestAppProfiles(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	workdir := fs.NewDir(t, t.Name())
+	defer workdir.Remove()
+
+	cmd := s5cmd("--cpu-profile", "cpu.prof", "--mem-profile", "mem.prof", "ls")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	for _, name := range []string{"cpu.prof", "mem.prof"} {
+		content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), name))
+		assert.NilError(t, err)
+		assert.Assert(t, len(content) > 0, "%v is empty", name)
+	}
+}
+
+// freeAddr returns a local address which is not in use.
+func freeAddr(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer listener.Close()
+
+	return listener.Addr().String()
+}
+
+func TestAppProgress(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", "content"))
+	defer workdir.Remove()
+
+	// commands are read from a pipe, so the run continues until it is
+	// closed.
+	stdin, stdinWriter := io.Pipe()
+
+	cmd := s5cmd("--json", "--progress", "50ms", "run")
+	cmd.Dir = workdir.Path()
+	cmd.Stdin = stdin
+
+	result := icmd.StartCmd(cmd)
+	assert.NilError(t, result.Error)
+
+	_, err := fmt.Fprintf(stdinWriter, "cp file.txt s3://%v/file.txt\n", bucket)
+	assert.NilError(t, err)
+
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if ensureS3Object(s3client, bucket, "file.txt", "content") == nil {
+			break
+		}
+	}
+
+	// wait for a progress line after the upload.
+	time.Sleep(200 * time.Millisecond)
+
+	stdinWriter.Close()
+	result = icmd.WaitOnCmd(10*time.Second, result)
+	result.Assert(t, icmd.Success)
+
+	assert.Assert(t, strings.Contains(result.Stderr(), `"completed":1,"remaining":0,"bytes":7,`), result.Stderr())
+	assert.Assert(t, !strings.Contains(result.Stdout(), `"completed"`), result.Stdout())
+}
+
 func TestAppUnknownCommand(t *testing.T) {
 	_, s5cmd, cleanup := setup(t)
 	defer cleanup()
@@ -91,9 +791,139 @@ func TestAppUnknownCommand(t *testing.T) {
 	cmd := s5cmd("unknown-command")
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 127})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`ERROR "unknown-command": command not found`),
 	})
 }
+
+func TestAppConfigFile(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	config := `
+numworkers: 8
+ls:
+  etag: true
+profiles:
+  machine:
+    json: true
+`
+	workdir := fs.NewDir(t, bucket, fs.WithFile("config.yaml", config))
+	defer workdir.Remove()
+
+	cmd := s5cmd("--config", "config.yaml", "ls", "s3://"+bucket)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: match(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\s+[0-9a-f]{32}\s+\d+ file.txt$`),
+	})
+
+	cmd = s5cmd("--config=config.yaml", "--config-profile", "machine", "ls", "s3://"+bucket)
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: prefix(`{"key":"s3://%v/file.txt",`, bucket),
+	})
+}
+
+func TestAppDefaultConfigFile(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	configdir := fs.NewDir(t, bucket, fs.WithDir("s5cmd", fs.WithFile("config.yaml", "json: true\n")))
+	defer configdir.Remove()
+
+	cmd := s5cmd("ls", "s3://"+bucket)
+	cmd.Env = append(cmd.Env, "XDG_CONFIG_HOME="+configdir.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: prefix(`{"key":"s3://%v/file.txt",`, bucket),
+	})
+}
+
+func TestAppConfigFileWithUnknownOption(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("config.yaml", "cp:\n  no-such-flag: true\n"))
+	defer workdir.Remove()
+
+	cmd := s5cmd("--config", "config.yaml", "ls")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR config "config.yaml": command "cp": unknown option "no-such-flag"`),
+	})
+}
+
+func TestAppEnvironmentVariables(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	cmd := s5cmd("ls", "s3://"+bucket)
+	cmd.Env = append(cmd.Env, "S5CMD_JSON=true")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: prefix(`{"key":"s3://%v/file.txt",`, bucket),
+	})
+
+	// flags override the environment.
+	cmd = s5cmd("--json=false", "ls", "s3://"+bucket)
+	cmd.Env = append(cmd.Env, "S5CMD_JSON=true")
+	result = icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix(` file.txt`),
+	})
+
+	cmd = s5cmd("ls")
+	cmd.Env = append(cmd.Env, "S5CMD_RETRY_COUNT=-1")
+	result = icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: contains(`retry count cannot be a negative value`),
+	})
+}
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/icmd"
+)
+
+func TestBench(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("--json", "bench", "--count", "3", "--size", "1K", "--workers", "2", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: prefix(`{"operation":"upload","count":3,"bytes":3072,"duration_ms":`),
+		1: prefix(`{"operation":"download","count":3,"bytes":3072,"duration_ms":`),
+	})
+
+	// objects are deleted after the benchmark.
+	list, err := s3client.ListObjects(&s3.ListObjectsInput{Bucket: aws.String(bucket)})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Contents), 0)
+}
+
+func TestBenchKeepObjects(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("bench", "--count", "2", "--size", "2K", "--keep", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: match(`^upload: 2 objects, 4.0K in \S+ms, \S+/s, p50 \S+ms, p95 \S+ms, p99 \S+ms$`),
+		1: match(`^download: 2 objects, 4.0K in `),
+	})
+
+	for _, key := range []string{"prefix/s5cmd-bench0", "prefix/s5cmd-bench1"} {
+		head, err := s3client.HeadObject(&s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, aws.Int64Value(head.ContentLength), int64(2048))
+	}
+}
+
+func TestBenchToObjectDestination(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("bench", "s3://bucket/key")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "bench s3://bucket/key": destination must be a bucket or a prefix`),
+	})
+}
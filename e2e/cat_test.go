@@ -66,6 +66,29 @@ func TestCatS3Object(t *testing.T) {
 
 }
 
+func TestCatS3ObjectWithRange(t *testing.T) {
+	t.Parallel()
+
+	const (
+		bucket   = "bucket"
+		filename = "file.txt"
+	)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, filename, "0123456789")
+
+	cmd := s5cmd("cat", "--range", "2-5", fmt.Sprintf("s3://%v/%v", bucket, filename))
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("2345"),
+	})
+}
+
 func TestCatS3ObjectFail(t *testing.T) {
 	const (
 		bucket   = "bucket"
@@ -79,6 +102,7 @@ func TestCatS3ObjectFail(t *testing.T) {
 	testcases := []struct {
 		name      string
 		cmd       []string
+		exitCode  int
 		expected  map[int]compareFunc
 		assertOps []assertOp
 	}{
@@ -88,6 +112,7 @@ func TestCatS3ObjectFail(t *testing.T) {
 				"cat",
 				src,
 			},
+			exitCode: 1,
 			expected: map[int]compareFunc{
 				0: contains(`ERROR "cat s3://bucket/prefix/file.txt": NoSuchKey: status code: 404`),
 			},
@@ -99,6 +124,7 @@ func TestCatS3ObjectFail(t *testing.T) {
 				"cat",
 				src,
 			},
+			exitCode: 1,
 			expected: map[int]compareFunc{
 				0: contains(`{"operation":"cat","command":"cat s3://bucket/prefix/file.txt","error":"NoSuchKey: status code: 404,`),
 			},
@@ -113,6 +139,7 @@ func TestCatS3ObjectFail(t *testing.T) {
 				"cat",
 				src + "/*",
 			},
+			exitCode: 2,
 			expected: map[int]compareFunc{
 				0: equals(`{"operation":"cat","command":"cat s3://bucket/prefix/file.txt/*","error":"remote source \"s3://bucket/prefix/file.txt/*\" can not contain glob characters"}`),
 			},
@@ -126,6 +153,7 @@ func TestCatS3ObjectFail(t *testing.T) {
 				"cat",
 				bucketSrc,
 			},
+			exitCode: 2,
 			expected: map[int]compareFunc{
 				0: contains(`ERROR "cat s3://bucket": remote source must be an object`),
 			},
@@ -144,7 +172,7 @@ func TestCatS3ObjectFail(t *testing.T) {
 			cmd := s5cmd(tc.cmd...)
 			result := icmd.RunCmd(cmd)
 
-			result.Assert(t, icmd.Expected{ExitCode: 1})
+			result.Assert(t, icmd.Expected{ExitCode: tc.exitCode})
 			assertLines(t, result.Stderr(), tc.expected, tc.assertOps...)
 		})
 	}
@@ -193,7 +221,7 @@ func TestCatLocalFileFail(t *testing.T) {
 			cmd := s5cmd(tc.cmd...)
 			result := icmd.RunCmd(cmd)
 
-			result.Assert(t, icmd.Expected{ExitCode: 1})
+			result.Assert(t, icmd.Expected{ExitCode: 2})
 
 			assertLines(t, result.Stderr(), tc.expected)
 		})
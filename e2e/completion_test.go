@@ -0,0 +1,143 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/icmd"
+)
+
+func TestCompletionScripts(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		shell    string
+		expected []string
+	}{
+		{
+			shell: "bash",
+			expected: []string{
+				`seed|gen) flags="--count -n --size`,
+				`complete -o filenames -F _s5cmd s5cmd`,
+			},
+		},
+		{
+			shell: "zsh",
+			expected: []string{
+				`#compdef s5cmd`,
+				`'cp:copy objects'`,
+				`'--no-clobber[do not overwrite destination if already exists]'`,
+				`'*:file:_files'`,
+			},
+		},
+		{
+			shell: "fish",
+			expected: []string{
+				`complete -c s5cmd -n '__fish_use_subcommand' -f -a cp -d 'copy objects'`,
+				`complete -c s5cmd -n '__fish_seen_subcommand_from cp' -l no-clobber -s n -d 'do not overwrite destination if already exists'`,
+				`complete -c s5cmd -n '__fish_use_subcommand' -l numworkers -r -d 'number of workers execute operation on each object'`,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.shell, func(t *testing.T) {
+			t.Parallel()
+
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd("completion", tc.shell)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Success)
+
+			for _, expected := range tc.expected {
+				assert.Assert(t, strings.Contains(result.Stdout(), expected), "missing %q", expected)
+			}
+		})
+	}
+}
+
+func TestCompletionWithUnsupportedShell(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("completion", "ksh")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "completion ksh": unsupported shell "ksh", expected one of bash, zsh or fish`),
+	})
+}
+
+func TestCompletionOfRemoteKeys(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "content")
+	putFile(t, s3client, bucket, "filedir/file3.txt", "content")
+	putFile(t, s3client, bucket, "other.txt", "content")
+
+	cachedir := fs.NewDir(t, "completion-cache")
+	defer cachedir.Remove()
+
+	complete := func(line string) *icmd.Result {
+		cmd := s5cmd()
+		endpoint := cmd.Command[2]
+		cmd.Env = append(
+			cmd.Env,
+			"COMP_LINE=s5cmd --endpoint-url "+endpoint+" "+line,
+			"XDG_CACHE_HOME="+cachedir.Path(),
+			"HOME="+cachedir.Path(),
+		)
+		return icmd.RunCmd(cmd)
+	}
+
+	result := complete("ls s3://" + bucket + "/fi")
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`s3://%v/file1.txt`, bucket),
+		1: equals(`s3://%v/file2.txt`, bucket),
+		2: equals(`s3://%v/filedir/`, bucket),
+	}, sortInput(true))
+
+	// listing of the prefix is cached, deleted object is still completed.
+	_, err := s3client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String("file2.txt"),
+	})
+	assert.NilError(t, err)
+
+	result = complete("cp s3://" + bucket + "/file")
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`s3://%v/file1.txt`, bucket),
+		1: equals(`s3://%v/file2.txt`, bucket),
+		2: equals(`s3://%v/filedir/`, bucket),
+	}, sortInput(true))
+
+	result = complete("ls s3://" + bucket + "/filedir/")
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`s3://%v/filedir/file3.txt`, bucket),
+	})
+}
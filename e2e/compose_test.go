@@ -0,0 +1,150 @@
+package e2e
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/icmd"
+)
+
+// compose s3://bucket/parts/* s3://bucket/merged.txt
+func TestComposeS3Objects(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	// bolt backend lists the objects with the sizes of their serialized
+	// values.
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "parts/part-2", "third\n")
+	putFile(t, s3client, bucket, "parts/part-1", "first\n")
+	putFile(t, s3client, bucket, "parts/part-10", "second\n")
+
+	src := fmt.Sprintf("s3://%v/parts/*", bucket)
+	dst := fmt.Sprintf("s3://%v/merged.txt", bucket)
+
+	cmd := s5cmd("compose", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`compose %v %v`, src, dst),
+	})
+
+	// the objects matched by the wildcard are merged in the order of their
+	// keys.
+	assert.Assert(t, ensureS3Object(s3client, bucket, "merged.txt", "first\nsecond\nthird\n"))
+}
+
+// compose s3://bucket/header.csv s3://bucket/rows/* s3://bucket/report.csv
+func TestComposeS3ObjectsInArgumentOrder(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "rows/a.csv", "1,2\n")
+	putFile(t, s3client, bucket, "rows/b.csv", "3,4\n")
+	putFile(t, s3client, bucket, "header.csv", "x,y\n")
+
+	cmd := s5cmd(
+		"compose",
+		"s3://"+bucket+"/header.csv",
+		"s3://"+bucket+"/rows/*",
+		"s3://"+bucket+"/report.csv",
+	)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "report.csv", "x,y\n1,2\n3,4\n"))
+}
+
+// --dry-run compose s3://bucket/parts/* s3://bucket/merged.txt
+func TestComposeS3ObjectsDryRun(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "parts/part-1", "first\n")
+
+	src := fmt.Sprintf("s3://%v/parts/*", bucket)
+	dst := fmt.Sprintf("s3://%v/merged.txt", bucket)
+
+	cmd := s5cmd("--dry-run", "compose", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`compose %v %v`, src, dst),
+		1: equals("would compose 1 object, 6"),
+	})
+
+	err := ensureS3Object(s3client, bucket, "merged.txt", "first\n")
+	assertError(t, err, errS3NoSuchKey)
+}
+
+func TestComposeWithInvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "no_destination",
+			args:          []string{"s3://bucket/parts/*"},
+			expectedError: "expected source and destination arguments",
+		},
+		{
+			name:          "local_source",
+			args:          []string{"parts/*", "s3://bucket/merged"},
+			expectedError: `source "parts/*" must be a remote object`,
+		},
+		{
+			name:          "prefix_destination",
+			args:          []string{"s3://bucket/parts/*", "s3://bucket/merged/"},
+			expectedError: `target "s3://bucket/merged/" must be a remote object`,
+		},
+		{
+			name:          "wildcard_destination",
+			args:          []string{"s3://bucket/parts/*", "s3://bucket/merged*"},
+			expectedError: `target "s3://bucket/merged*" can not contain glob characters`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd(append([]string{"compose"}, tc.args...)...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: contains("%v", tc.expectedError),
+			})
+		})
+	}
+}
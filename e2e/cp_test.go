@@ -23,16 +23,27 @@
 package e2e
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/fs"
 	"gotest.tools/v3/icmd"
+
+	"github.com/peak/s5cmd/storage"
 )
 
 func TestCopySingleS3ObjectToLocal(t *testing.T) {
@@ -160,6 +171,302 @@ func TestCopySingleS3ObjectToLocalJSON(t *testing.T) {
 	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 }
 
+// cp --fsync s3://bucket/object dir/
+func TestCopySingleS3ObjectToLocalWithFsync(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	putFile(t, s3client, bucket, filename, content)
+
+	cmd := s5cmd("cp", "--fsync", "s3://"+bucket+"/"+filename, "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/testfile1.txt dir/testfile1.txt`, bucket),
+	})
+
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithDir("dir", fs.WithFile(filename, content, fs.WithMode(0644))))
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+}
+
+// cp --sparse file s3://bucket/ && cp --sparse s3://bucket/file dir/
+func TestCopySparseFileRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const filename = "disk.img"
+
+	// zeros with some data in between
+	content := make([]byte, 64*1024)
+	copy(content[10*1024:], "this is a file content")
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, string(content)))
+	defer workdir.Remove()
+
+	cmd := s5cmd("cp", "--sparse", filename, "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, string(content)))
+
+	cmd = s5cmd("cp", "--sparse", "s3://"+bucket+"/"+filename, "dir/")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/disk.img dir/disk.img`, bucket),
+	})
+
+	expected := fs.Expected(t,
+		fs.WithFile(filename, string(content), fs.WithMode(0644)),
+		fs.WithDir("dir", fs.WithFile(filename, string(content), fs.WithMode(0644))),
+	)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
+
+// cp --preserve-timestamps file s3://bucket/ && cp --preserve-timestamps s3://bucket/file dir/
+func TestCopyPreserveTimestampsRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content, fs.WithTimestamps(mtime, mtime)))
+	defer workdir.Remove()
+
+	cmd := s5cmd("cp", "--preserve-timestamps", filename, "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	head, err := s3client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(filename),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, aws.StringValue(head.Metadata["Mtime"]), "1577934245.123456789")
+
+	cmd = s5cmd("cp", "--preserve-timestamps", "s3://"+bucket+"/"+filename, "dir/")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	fi, err := os.Stat(workdir.Join("dir", filename))
+	assert.NilError(t, err)
+	assert.Assert(t, fi.ModTime().Equal(mtime), "expected %v, got %v", mtime, fi.ModTime())
+}
+
+// cp --preserve-permissions file s3://bucket/ && cp --preserve-permissions s3://bucket/file dir/
+func TestCopyPreservePermissionsRoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content, fs.WithMode(0640)))
+	defer workdir.Remove()
+
+	cmd := s5cmd("cp", "--preserve-permissions", filename, "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	head, err := s3client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(filename),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, aws.StringValue(head.Metadata["Mode"]), "0640")
+	assert.Equal(t, aws.StringValue(head.Metadata["Uid"]), fmt.Sprint(os.Getuid()))
+	assert.Equal(t, aws.StringValue(head.Metadata["Gid"]), fmt.Sprint(os.Getgid()))
+
+	cmd = s5cmd("cp", "--preserve-permissions", "s3://"+bucket+"/"+filename, "dir/")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	expected := fs.Expected(t,
+		fs.WithFile(filename, content, fs.WithMode(0640)),
+		fs.WithDir("dir", fs.WithFile(filename, content, fs.WithMode(0640))),
+	)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
+
+// cp --preserve-symlinks dir/ s3://bucket/ && cp --preserve-symlinks s3://bucket/* dir/
+func TestCopyPreserveSymlinksRoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require privileges on Windows")
+	}
+
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const content = "this is a file content"
+
+	workdir := fs.NewDir(t, bucket, fs.WithDir("src", fs.WithFile("file.txt", content)))
+	defer workdir.Remove()
+
+	err := os.Symlink("file.txt", workdir.Join("src", "link.txt"))
+	assert.NilError(t, err)
+
+	cmd := s5cmd("cp", "--preserve-symlinks", "src/", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp src/file.txt s3://%v/file.txt`, bucket),
+		1: equals(`cp src/link.txt s3://%v/link.txt`, bucket),
+	}, sortInput(true))
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "link.txt", "file.txt"))
+
+	head, err := s3client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String("link.txt"),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, aws.StringValue(head.Metadata["Symlink-Target"]), "file.txt")
+
+	cmd = s5cmd("cp", "--preserve-symlinks", "s3://"+bucket+"/*", "dst/")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	target, err := os.Readlink(workdir.Join("dst", "link.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, target, "file.txt")
+
+	got, err := ioutil.ReadFile(workdir.Join("dst", "link.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), content)
+}
+
+// cp dir/ s3://bucket/ (dir has a symbolic link to its parent)
+func TestCopyDirWithSymlinkLoopToS3(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require privileges on Windows")
+	}
+
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const content = "this is a file content"
+
+	workdir := fs.NewDir(t, bucket, fs.WithDir("src", fs.WithFile("file.txt", content), fs.WithDir("sub")))
+	defer workdir.Remove()
+
+	err := os.Symlink("..", workdir.Join("src", "sub", "loop"))
+	assert.NilError(t, err)
+
+	cmd := s5cmd("cp", "src/", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp src/file.txt s3://%v/file.txt`, bucket),
+	})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: contains(`symbolic link loop is skipped`),
+	})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file.txt", content))
+}
+
+// --dry-run cp --keep-empty-dirs dir/ s3://bucket/
+func TestCopyDirWithEmptyDirsToS3(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, bucket,
+		fs.WithDir("src",
+			fs.WithFile("file.txt", "this is a file content"),
+			fs.WithDir("empty"),
+			fs.WithDir("nonempty",
+				fs.WithDir("nested_empty"),
+			),
+		),
+	)
+	defer workdir.Remove()
+
+	// uploading zero-byte objects is not supported by the test server.
+	cmd := s5cmd("--dry-run", "cp", "--keep-empty-dirs", "src/", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp src/empty s3://%v/prefix/empty/`, bucket),
+		1: equals(`cp src/file.txt s3://%v/prefix/file.txt`, bucket),
+		2: equals(`cp src/nonempty/nested_empty s3://%v/prefix/nonempty/nested_empty/`, bucket),
+		3: equals("would copy 3 objects, 22"),
+	}, sortInput(true))
+}
+
 // cp s3://bucket/object *
 func TestCopySingleS3ObjectToLocalWithDestinationWildcard(t *testing.T) {
 	t.Parallel()
@@ -181,7 +488,7 @@ func TestCopySingleS3ObjectToLocalWithDestinationWildcard(t *testing.T) {
 	cmd := s5cmd("cp", "s3://"+bucket+"/"+filename, "*")
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	// ignore stdout. we expect error logs from stderr.
 	assertLines(t, result.Stderr(), map[int]compareFunc{
@@ -218,7 +525,7 @@ func TestCopyS3PrefixToLocalMustReturnError(t *testing.T) {
 	cmd := s5cmd("cp", "s3://"+bucket+"/"+prefix, ".")
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	// ignore stdout. we expect error logs from stderr.
 	assertLines(t, result.Stderr(), map[int]compareFunc{
@@ -284,54 +591,69 @@ func TestCopyMultipleFlatS3ObjectsToLocal(t *testing.T) {
 	}
 }
 
-// cp --flatten s3://bucket/*.txt dir/
-func TestCopyMultipleFlatS3ObjectsToLocalWithPartialMatching(t *testing.T) {
+// cp --inventory s3://inventory/manifest.json s3://bucket/prefix/* dir/
+func TestCopyS3ObjectsToLocalWithInventory(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
+	inventoryBucket := "inventory-" + bucket
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
+	createBucket(t, s3client, inventoryBucket)
 
 	filesToContent := map[string]string{
-		"testfile1.txt":             "this is a test file 1",
-		"readme.md":                 "this is a readme file",
-		"filename-with-hypen.gz":    "file has hypen in its name",
-		"dir/another_test_file.txt": "yet another txt file",
+		"prefix/file1.txt":            "this is a test file 1",
+		"prefix/a/file two.gz":        "file with a space in its name",
+		"prefix/not-in-inventory.txt": "objects created after the inventory report are not copied",
+		"other/file.txt":              "yet another txt file. yatf.",
 	}
 
 	for filename, content := range filesToContent {
 		putFile(t, s3client, bucket, filename, content)
 	}
 
-	cmd := s5cmd("cp", "--flatten", "s3://"+bucket+"/*.txt", ".")
+	var data bytes.Buffer
+	gz := gzip.NewWriter(&data)
+	fmt.Fprintf(gz, "%q,%q,%q\n", bucket, "prefix/file1.txt", "21")
+	fmt.Fprintf(gz, "%q,%q,%q\n", bucket, "prefix/a/file+two.gz", "29")
+	fmt.Fprintf(gz, "%q,%q,%q\n", bucket, "other/file.txt", "27")
+	assert.NilError(t, gz.Close())
+
+	putFile(t, s3client, inventoryBucket, "data/inventory.csv.gz", data.String())
+	putFile(t, s3client, inventoryBucket, "manifest.json", fmt.Sprintf(`{
+		"sourceBucket": %q,
+		"destinationBucket": "arn:aws:s3:::%v",
+		"fileFormat": "CSV",
+		"fileSchema": "Bucket, Key, Size",
+		"files": [{"key": "data/inventory.csv.gz"}]
+	}`, bucket, inventoryBucket))
+
+	cmd := s5cmd("cp", "--inventory", "s3://"+inventoryBucket+"/manifest.json", "s3://"+bucket+"/prefix/*", "dir/")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/dir/another_test_file.txt another_test_file.txt`, bucket),
-		1: equals(`cp s3://%v/testfile1.txt testfile1.txt`, bucket),
+		0: equals(`cp s3://%v/prefix/a/file two.gz dir/a/file two.gz`, bucket),
+		1: equals(`cp s3://%v/prefix/file1.txt dir/file1.txt`, bucket),
 	}, sortInput(true))
 
-	// assert local filesystem
-	expectedFiles := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1", fs.WithMode(0644)),
-		fs.WithFile("another_test_file.txt", "yet another txt file", fs.WithMode(0644)),
-	}
-	expected := fs.Expected(t, expectedFiles...)
+	expected := fs.Expected(t,
+		fs.WithDir("dir",
+			fs.WithFile("file1.txt", "this is a test file 1", fs.WithMode(0644)),
+			fs.WithDir("a",
+				fs.WithFile("file two.gz", "file with a space in its name", fs.WithMode(0644)),
+			),
+		),
+	)
 	assert.Assert(t, fs.Equal(cmd.Dir, expected))
-
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
 }
 
-// cp s3://bucket/*/*.txt dir/
-func TestCopyMultipleFlatNestedS3ObjectsToLocalWithPartialMatching(t *testing.T) {
+// cp --files-from keys.txt s3://bucket/prefix/ dir/
+func TestCopyS3ObjectsToLocalWithFilesFrom(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -342,36 +664,41 @@ func TestCopyMultipleFlatNestedS3ObjectsToLocalWithPartialMatching(t *testing.T)
 	createBucket(t, s3client, bucket)
 
 	filesToContent := map[string]string{
-		"testfile1.txt":     "test file 1",
-		"a/readme.md":       "this is a readme file",
-		"a/b/testfile2.txt": "test file 2",
+		"prefix/testfile1.txt": "this is a test file 1",
+		"prefix/a/readme.md":   "this is a readme file",
+		"prefix/a/c/file.gz":   "file in a nested directory",
+		"other/file.txt":       "yet another txt file. yatf.",
 	}
 
 	for filename, content := range filesToContent {
 		putFile(t, s3client, bucket, filename, content)
 	}
 
-	cmd := s5cmd("cp", "--flatten", "s3://"+bucket+"/*/*.txt", ".")
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("cp", "--files-from", "-", "s3://"+bucket+"/prefix/", "dir/")
+	result := icmd.RunCmd(cmd, icmd.WithStdin(strings.NewReader("testfile1.txt\na/c/file.gz\n\n")))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/a/b/testfile2.txt testfile2.txt`, bucket),
+		0: equals(`cp s3://%v/prefix/a/c/file.gz dir/a/c/file.gz`, bucket),
+		1: equals(`cp s3://%v/prefix/testfile1.txt dir/testfile1.txt`, bucket),
 	}, sortInput(true))
 
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile("testfile2.txt", "test file 2", fs.WithMode(0644)))
+	expected := fs.Expected(t,
+		fs.WithDir("dir",
+			fs.WithFile("testfile1.txt", "this is a test file 1", fs.WithMode(0644)),
+			fs.WithDir("a",
+				fs.WithDir("c",
+					fs.WithFile("file.gz", "file in a nested directory", fs.WithMode(0644)),
+				),
+			),
+		),
+	)
 	assert.Assert(t, fs.Equal(cmd.Dir, expected))
-
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
 }
 
-// --json cp --flatten s3://bucket/* .
-func TestCopyMultipleFlatS3ObjectsToLocalJSON(t *testing.T) {
+// cp --files-from files.txt dir/ s3://bucket/prefix/
+func TestCopyLocalFilesToS3WithFilesFrom(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -381,163 +708,68 @@ func TestCopyMultipleFlatS3ObjectsToLocalJSON(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	filesToContent := map[string]string{
-		"testfile1.txt":            "this is a test file 1",
-		"readme.md":                "this is a readme file",
-		"b/filename-with-hypen.gz": "file has hypen in its name",
-		"a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	workdir := fs.NewDir(t, bucket,
+		fs.WithFile("files.txt", "a/file1.txt\nfile2.txt\n"),
+		fs.WithDir("dir",
+			fs.WithFile("file2.txt", "file 2"),
+			fs.WithFile("file3.txt", "file 3"),
+			fs.WithDir("a",
+				fs.WithFile("file1.txt", "file 1"),
+			),
+		),
+	)
+	defer workdir.Remove()
 
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
-
-	cmd := s5cmd("--json", "cp", "--flatten", "s3://"+bucket+"/*", ".")
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("cp", "--files-from", "files.txt", "dir/", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: json(`
-			{
-				"operation": "cp",
-				"success": true,
-				"source": "s3://%v/a/another_test_file.txt",
-				"destination": "another_test_file.txt",
-				"object":{
-					"type": "file",
-					"size": 27
-				}
-			}
-		`, bucket),
-		1: json(`
-			{
-				"operation": "cp",
-				"success": true,
-				"source": "s3://%v/b/filename-with-hypen.gz",
-				"destination": "filename-with-hypen.gz",
-				"object": {
-					"type": "file",
-					"size": 26
-				}
-			}
-		`, bucket),
-		2: json(`
-			{
-				"operation": "cp",
-				"success": true,
-				"source": "s3://%v/readme.md",
-				"destination": "readme.md",
-				"object": {
-					"type": "file",
-					"size": 21
-				}
-			}
-		`, bucket),
-		3: json(`
-			{
-				"operation": "cp",
-				"success": true,
-				"source": "s3://%v/testfile1.txt",
-				"destination": "testfile1.txt",
-				"object": {
-					"type": "file",
-					"size": 21
-				}
-			}
-		`, bucket),
-	}, sortInput(true), jsonCheck(true))
+		0: equals(`cp dir/a/file1.txt s3://%v/prefix/a/file1.txt`, bucket),
+		1: equals(`cp dir/file2.txt s3://%v/prefix/file2.txt`, bucket),
+	}, sortInput(true))
 
-	// assert local filesystem
-	// expect flattened directory structure
-	var expectedFiles = []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-	}
-	expected := fs.Expected(t, expectedFiles...)
-	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/file1.txt", "file 1"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/file2.txt", "file 2"))
 
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	err := ensureS3Object(s3client, bucket, "prefix/file3.txt", "file 3")
+	assertError(t, err, errS3NoSuchKey)
 }
 
-// cp s3://bucket/* dir/ (nested source hierarchy)
-func TestCopyMultipleNestedS3ObjectsToLocal(t *testing.T) {
+// cp --manifest manifest.csv s3://bucket/prefix/* dir/
+func TestCopyS3ObjectsToLocalWithManifest(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
 
-	filesToContent := map[string]string{
-		"testfile1.txt":               "this is a test file 1",
-		"a/readme.md":                 "this is a readme file",
-		"a/b/filename-with-hypen.gz":  "file has hypen in its name",
-		"b/another_test_file.txt":     "yet another txt file. yatf.",
-		"c/d/e/another_test_file.txt": "yet another txt file. yatf.",
-	}
-
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+	putFile(t, s3client, bucket, "prefix/file1.txt", "file 1")
+	putFile(t, s3client, bucket, "prefix/a/file2.txt", "second file")
 
-	cmd := s5cmd("cp", "s3://"+bucket+"/*", ".")
+	cmd := s5cmd("cp", "--manifest", "manifest.csv", "s3://"+bucket+"/prefix/*", "dir/")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/a/b/filename-with-hypen.gz a/b/filename-with-hypen.gz`, bucket),
-		1: equals(`cp s3://%v/a/readme.md a/readme.md`, bucket),
-		2: equals(`cp s3://%v/b/another_test_file.txt b/another_test_file.txt`, bucket),
-		3: equals(`cp s3://%v/c/d/e/another_test_file.txt c/d/e/another_test_file.txt`, bucket),
-		4: equals(`cp s3://%v/testfile1.txt testfile1.txt`, bucket),
-	}, sortInput(true))
-
-	// assert local filesystem
-	var expectedFiles = []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("readme.md", "this is a readme file"),
-			fs.WithDir(
-				"b",
-				fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-			),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"c",
-			fs.WithDir(
-				"d",
-				fs.WithDir(
-					"e",
-					fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-				),
-			),
-		),
-	}
-	expected := fs.Expected(t, expectedFiles...)
-	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+	content, err := ioutil.ReadFile(filepath.Join(cmd.Dir, "manifest.csv"))
+	assert.NilError(t, err)
 
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	// timestamps are not known beforehand, only the leading fields of the
+	// rows are checked.
+	assertLines(t, string(content), map[int]compareFunc{
+		0: prefix("s3://%v/prefix/a/file2.txt,dir/a/file2.txt,11,", bucket),
+		1: prefix("s3://%v/prefix/file1.txt,dir/file1.txt,6,", bucket),
+		2: equals("source,destination,size,etag,timestamp"),
+	}, sortInput(true))
 }
 
-// cp s3://bucket/*/*.ext dir/
-func TestCopyMultipleNestedS3ObjectsToLocalWithPartial(t *testing.T) {
+// cp --journal transfers.journal s3://bucket/prefix/* dir/
+func TestCopyS3ObjectsToLocalWithJournal(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -547,169 +779,96 @@ func TestCopyMultipleNestedS3ObjectsToLocalWithPartial(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	filesToContent := map[string]string{
-		"testfile1.txt":               "this is a test file 1",
-		"a/readme.md":                 "this is a readme file",
-		"a/b/filename-with-hypen.gz":  "file has hypen in its name",
-		"b/another_test_file.txt":     "yet another txt file. yatf.",
-		"c/d/e/another_test_file.txt": "yet another txt file. yatf.",
-	}
+	putFile(t, s3client, bucket, "prefix/file1.txt", "file 1")
+	putFile(t, s3client, bucket, "prefix/file2.txt", "file 2")
+	putFile(t, s3client, bucket, "prefix/file20.txt", "file 20")
 
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+	// the last entry is not completely written by the previous run.
+	journal := fmt.Sprintf("s3://%v/prefix/file1.txt\ns3://%v/prefix/file2", bucket, bucket)
+	workdir := fs.NewDir(t, bucket, fs.WithFile("transfers.journal", journal))
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", "s3://"+bucket+"/*/*.txt", ".")
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("cp", "--journal", "transfers.journal", "s3://"+bucket+"/prefix/*", "dir/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/b/another_test_file.txt b/another_test_file.txt`, bucket),
-		1: equals(`cp s3://%v/c/d/e/another_test_file.txt c/d/e/another_test_file.txt`, bucket),
+		0: equals(`cp s3://%v/prefix/file2.txt dir/file2.txt`, bucket),
+		1: equals(`cp s3://%v/prefix/file20.txt dir/file20.txt`, bucket),
 	}, sortInput(true))
 
-	// assert local filesystem
-	expected := fs.Expected(
-		t,
-		fs.WithDir(
-			"b",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"c",
-			fs.WithDir(
-				"d",
-				fs.WithDir(
-					"e",
-					fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-				),
-			),
+	expected := fs.Expected(t,
+		fs.WithFile("transfers.journal", "", fs.MatchAnyFileContent),
+		fs.WithDir("dir",
+			fs.WithFile("file2.txt", "file 2", fs.WithMode(0644)),
+			fs.WithFile("file20.txt", "file 20", fs.WithMode(0644)),
 		),
 	)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 
-	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+	// rerun skips all objects.
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{})
 }
 
-// cp s3://bucket/* dir/ (dir/ doesn't exist)
-func TestCopyMultipleS3ObjectsToGivenLocalDirectory(t *testing.T) {
+// cp --on-success 'echo ...' s3://bucket/prefix/* dir/
+func TestCopyS3ObjectsToLocalWithSuccessHook(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
 
-	filesToContent := map[string]string{
-		"testfile1.txt":            "this is a test file 1",
-		"readme.md":                "this is a readme file",
-		"b/filename-with-hypen.gz": "file has hypen in its name",
-		"a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
-
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+	putFile(t, s3client, bucket, "prefix/file1.txt", "file 1")
+	putFile(t, s3client, bucket, "prefix/file2.txt", "second file")
 
-	const dst = "given-directory"
-	cmd := s5cmd("cp", "s3://"+bucket+"/*", dst)
+	hook := `echo "$S5CMD_OPERATION $S5CMD_SOURCE $S5CMD_DESTINATION $S5CMD_SIZE $S5CMD_STATUS" >> hook.txt`
+	cmd := s5cmd("cp", "--on-success", hook, "s3://"+bucket+"/prefix/*", "dir/")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/a/another_test_file.txt %v/a/another_test_file.txt`, bucket, dst),
-		1: equals(`cp s3://%v/b/filename-with-hypen.gz %v/b/filename-with-hypen.gz`, bucket, dst),
-		2: equals(`cp s3://%v/readme.md %v/readme.md`, bucket, dst),
-		3: equals(`cp s3://%v/testfile1.txt %v/testfile1.txt`, bucket, dst),
-	}, sortInput(true))
-
-	// assert local filesystem
-	var expectedFiles = []fs.PathOp{
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-	}
-	expected := fs.Expected(t, fs.WithDir(dst, expectedFiles...))
-	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+	content, err := ioutil.ReadFile(filepath.Join(cmd.Dir, "hook.txt"))
+	assert.NilError(t, err)
 
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	assertLines(t, string(content), map[int]compareFunc{
+		0: equals("cp s3://%v/prefix/file1.txt dir/file1.txt 6 success", bucket),
+		1: equals("cp s3://%v/prefix/file2.txt dir/file2.txt 11 success", bucket),
+	}, sortInput(true))
 }
 
-// cp dir/file s3://bucket/
-func TestCopySingleFileToS3(t *testing.T) {
+// cp --on-failure 'echo ...' file s3://non-existent-bucket/file
+func TestCopySingleFileToNonExistentBucketWithFailureHook(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
-
-	s3client, s5cmd, cleanup := setup(t)
+	_, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	const (
-		// make sure that Put reads the file header, not the extension
-		filename = "index.txt"
-		content  = `
-<html lang="en">
-	<head>
-	<meta charset="utf-8">
-	<body>
-		<div id="foo">
-			<div class="bar"></div>
-		</div>
-		<div id="baz">
-			<style data-hey="naber"></style>
-		</div>
-	</body>
-</html>
-`
-		expectedContentType = "text/html; charset=utf-8"
-	)
-
-	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content))
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile("file.txt", "content"))
 	defer workdir.Remove()
 
-	srcpath := workdir.Join(filename)
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
+	hook := `echo "$S5CMD_SOURCE $S5CMD_DESTINATION $S5CMD_STATUS" >> hook.txt`
+	cmd := s5cmd("cp", "--on-failure", hook, "file.txt", "s3://non-existent-bucket/file.txt")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	srcpath = filepath.ToSlash(srcpath)
-	cmd := s5cmd("cp", srcpath, dstpath)
-	result := icmd.RunCmd(cmd)
+	result.Assert(t, icmd.Expected{ExitCode: 1})
 
-	result.Assert(t, icmd.Success)
+	content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), "hook.txt"))
+	assert.NilError(t, err)
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: suffix(`cp %v %v%v`, srcpath, dstpath, filename),
+	assertLines(t, string(content), map[int]compareFunc{
+		0: equals("file.txt s3://non-existent-bucket/file.txt failure"),
 	})
-
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, content))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert S3
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content, ensureContentType(expectedContentType)))
 }
 
-// --json cp dir/file s3://bucket
-func TestCopySingleFileToS3JSON(t *testing.T) {
+// cp --on-success 'echo ...' --on-failure 'echo ...' s3://bucket/*.txt dir/
+func TestCopyMultipleS3ObjectsWithHookCommandAndErrorCode(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -718,49 +877,31 @@ func TestCopySingleFileToS3JSON(t *testing.T) {
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is a test file"
-	)
-
-	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content))
+	workdir := fs.NewDir(t, t.Name())
 	defer workdir.Remove()
 
-	fpath := workdir.Join(filename)
-
-	cmd := s5cmd("--json", "cp", fpath, "s3://"+bucket+"/")
-	result := icmd.RunCmd(cmd)
-
-	jsonText := `
-		{
-			"operation": "cp",
-			"success": true,
-			"source": "%v",
-			"destination": "s3://%v/testfile1.txt",
-			"object": {
-				"type": "file",
-				"size":19
-			}
-		}
-	`
-
+	hook := `echo "$S5CMD_STATUS: $S5CMD_COMMAND${S5CMD_ERROR_CODE:+: $S5CMD_ERROR_CODE}" >> hook.txt`
+	cmd := s5cmd("cp", "--on-success", hook, "--on-failure", hook, "s3://"+bucket+"/*.txt", "dir/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 	result.Assert(t, icmd.Success)
-	fpath = filepath.ToSlash(fpath)
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: json(jsonText, fpath, bucket),
-	}, jsonCheck(true))
 
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, content))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	cmd = s5cmd("cp", "--on-failure", hook, "s3://"+bucket+"/missing.txt", ".")
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Expected{ExitCode: 1})
 
-	// assert S3
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	content, err := ioutil.ReadFile(filepath.Join(workdir.Path(), "hook.txt"))
+	assert.NilError(t, err)
+
+	assertLines(t, string(content), map[int]compareFunc{
+		0: equals("success: cp s3://%v/file.txt dir/file.txt", bucket),
+		1: equals("failure: cp s3://%v/missing.txt missing.txt: NoSuchKey", bucket),
+	})
 }
 
-// cp dir/ s3://bucket/
-func TestCopyDirToS3(t *testing.T) {
+// cp --strip-components 1 s3://bucket/* dir/
+func TestCopyMultipleS3ObjectsToLocalWithStripComponents(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -770,49 +911,43 @@ func TestCopyDirToS3(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("file1.txt", "this is the first test file"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"c",
-			fs.WithFile("file2.txt", "this is the second test file"),
-		),
+	filesToContent := map[string]string{
+		"testfile1.txt":           "this is a test file 1",
+		"a/readme.md":             "this is a readme file",
+		"a/c/file.gz":             "file in a nested directory",
+		"b/another_test_file.txt": "yet another txt file. yatf.",
 	}
 
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
-	defer workdir.Remove()
-	srcpath := workdir.Path()
-	srcpath = filepath.ToSlash(srcpath)
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
 
-	// this command ('s5cmd cp dir/ s3://bucket/') will run in 'walk' mode,
-	// which is different than 'glob' mode.
-	cmd := s5cmd("cp", workdir.Path()+"/", dstpath)
+	cmd := s5cmd("cp", "--strip-components", "1", "s3://"+bucket+"/*", "dir/")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
+	// objects without enough components are skipped.
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/c/file2.txt %vc/file2.txt`, srcpath, dstpath),
-		1: equals(`cp %v/file1.txt %vfile1.txt`, srcpath, dstpath),
-		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+		0: equals(`cp s3://%v/a/c/file.gz dir/c/file.gz`, bucket),
+		1: equals(`cp s3://%v/a/readme.md dir/readme.md`, bucket),
+		2: equals(`cp s3://%v/b/another_test_file.txt dir/another_test_file.txt`, bucket),
 	}, sortInput(true))
 
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert s3
-	assert.Assert(t, ensureS3Object(s3client, bucket, "file1.txt", "this is the first test file"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "readme.md", "this is a readme file"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "c/file2.txt", "this is the second test file"))
+	expected := fs.Expected(t,
+		fs.WithDir("dir",
+			fs.WithFile("readme.md", "this is a readme file", fs.WithMode(0644)),
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf.", fs.WithMode(0644)),
+			fs.WithDir("c",
+				fs.WithFile("file.gz", "file in a nested directory", fs.WithMode(0644)),
+			),
+		),
+	)
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
 }
 
-// cp dir/{file, folderWithBackslash} s3://bucket
-func TestCopyDirBackslashedToS3(t *testing.T) {
-	if runtime.GOOS == "windows" {
-		t.Skip()
-	}
+// cp --strip-components 1 s3://bucket/* s3://bucket/prefix/
+func TestCopyMultipleS3ObjectsToS3WithStripComponents(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -822,82 +957,38 @@ func TestCopyDirBackslashedToS3(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("readme.md", `¯\_(ツ)_/¯`),
-		fs.WithDir(
-			"t\\est",
-			fs.WithFile("filetest.txt", "try reaching me on windows :-)"),
-		),
-	}
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
-	defer workdir.Remove()
-	srcpath := workdir.Path()
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
+	putFile(t, s3client, bucket, "src/2020/01/file.txt", "this is a test file")
 
-	cmd := s5cmd("cp", workdir.Path()+"/", dstpath)
+	cmd := s5cmd("cp", "--strip-components", "2", "s3://"+bucket+"/src/*", "s3://"+bucket+"/dst/")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
-		1: equals(`cp %v/t\est/filetest.txt %vt\est/filetest.txt`, srcpath, dstpath),
-	}, sortInput(true))
-
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert s3
-	assert.Assert(t, ensureS3Object(s3client, bucket, "readme.md", `¯\_(ツ)_/¯`))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "t\\est/filetest.txt", "try reaching me on windows :-)"))
+		0: equals(`cp s3://%v/src/2020/01/file.txt s3://%v/dst/file.txt`, bucket, bucket),
+	})
 
+	assert.Assert(t, ensureS3Object(s3client, bucket, "dst/file.txt", "this is a test file"))
 }
 
-// cp --storage-class=GLACIER file s3://bucket/
-func TestCopySingleFileToS3WithStorageClassGlacier(t *testing.T) {
+func TestCopyFlattenWithStripComponents(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
-
-	s3client, s5cmd, cleanup := setup(t)
+	_, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	const (
-		// make sure that Put reads the file header, not the extension
-		filename             = "index.txt"
-		content              = "content"
-		expectedStorageClass = "GLACIER"
-	)
-
-	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content))
-	defer workdir.Remove()
-
-	srcpath := workdir.Join(filename)
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
-
-	srcpath = filepath.ToSlash(srcpath)
-	cmd := s5cmd("cp", "--storage-class=GLACIER", srcpath, dstpath)
+	cmd := s5cmd("cp", "--flatten", "--strip-components", "1", "s3://bucket/*", "dir/")
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Success)
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: suffix(`cp %v %v%v`, srcpath, dstpath, filename),
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp s3://bucket/* dir/": --flatten and --strip-components flags can not be used together`),
 	})
-
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, content))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert S3
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content, ensureStorageClass(expectedStorageClass)))
 }
 
-// cp --flatten dir/ s3://bucket/
-func TestFlattenCopyDirToS3(t *testing.T) {
+// cp --flatten s3://bucket/*.txt dir/
+func TestCopyMultipleFlatS3ObjectsToLocalWithPartialMatching(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -907,47 +998,43 @@ func TestFlattenCopyDirToS3(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("file1.txt", "this is the first test file"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"c",
-			fs.WithFile("file2.txt", "this is the second test file"),
-		),
+	filesToContent := map[string]string{
+		"testfile1.txt":             "this is a test file 1",
+		"readme.md":                 "this is a readme file",
+		"filename-with-hypen.gz":    "file has hypen in its name",
+		"dir/another_test_file.txt": "yet another txt file",
 	}
 
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
-	defer workdir.Remove()
-	srcpath := workdir.Path()
-	srcpath = filepath.ToSlash(srcpath)
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
 
-	// this command ('s5cmd cp dir/ s3://bucket/') will run in 'walk' mode,
-	// which is different than 'glob' mode.
-	cmd := s5cmd("cp", "--flatten", workdir.Path()+"/", dstpath)
+	cmd := s5cmd("cp", "--flatten", "s3://"+bucket+"/*.txt", ".")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/c/file2.txt %vfile2.txt`, srcpath, dstpath),
-		1: equals(`cp %v/file1.txt %vfile1.txt`, srcpath, dstpath),
-		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+		0: equals(`cp s3://%v/dir/another_test_file.txt another_test_file.txt`, bucket),
+		1: equals(`cp s3://%v/testfile1.txt testfile1.txt`, bucket),
 	}, sortInput(true))
 
 	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	expectedFiles := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1", fs.WithMode(0644)),
+		fs.WithFile("another_test_file.txt", "yet another txt file", fs.WithMode(0644)),
+	}
+	expected := fs.Expected(t, expectedFiles...)
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
 
-	// assert s3
-	assert.Assert(t, ensureS3Object(s3client, bucket, "file1.txt", "this is the first test file"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "readme.md", "this is a readme file"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "file2.txt", "this is the second test file"))
+	// assert s3 objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
 }
 
-// cp dir/* s3://bucket/
-func TestCopyMultipleFilesToS3Bucket(t *testing.T) {
-
+// cp s3://bucket/*/*.txt dir/
+func TestCopyMultipleFlatNestedS3ObjectsToLocalWithPartialMatching(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -957,56 +1044,37 @@ func TestCopyMultipleFilesToS3Bucket(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
+	filesToContent := map[string]string{
+		"testfile1.txt":     "test file 1",
+		"a/readme.md":       "this is a readme file",
+		"a/b/testfile2.txt": "test file 2",
 	}
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
-	srcpath := workdir.Path()
-	srcpath = filepath.ToSlash(srcpath)
-	defer workdir.Remove()
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
 
-	cmd := s5cmd("cp", srcpath+"/*", dstpath)
+	cmd := s5cmd("cp", "--flatten", "s3://"+bucket+"/*/*.txt", ".")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
+
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/a/another_test_file.txt %va/another_test_file.txt`, srcpath, dstpath),
-		1: equals(`cp %v/b/filename-with-hypen.gz %vb/filename-with-hypen.gz`, srcpath, dstpath),
-		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
-		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dstpath),
+		0: equals(`cp s3://%v/a/b/testfile2.txt testfile2.txt`, bucket),
 	}, sortInput(true))
 
 	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	expectedS3Content := map[string]string{
-		"testfile1.txt":            "this is a test file 1",
-		"readme.md":                "this is a readme file",
-		"b/filename-with-hypen.gz": "file has hypen in its name",
-		"a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	expected := fs.Expected(t, fs.WithFile("testfile2.txt", "test file 2", fs.WithMode(0644)))
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
 
-	// assert s3
-	for filename, content := range expectedS3Content {
+	// assert s3 objects
+	for filename, content := range filesToContent {
 		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 	}
 }
 
-// cp --flatten dir/* s3://bucket/
-func TestFlattenCopyMultipleFilesToS3Bucket(t *testing.T) {
-
+// --json cp --flatten s3://bucket/* .
+func TestCopyMultipleFlatS3ObjectsToLocalJSON(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1016,101 +1084,1929 @@ func TestFlattenCopyMultipleFilesToS3Bucket(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
+	filesToContent := map[string]string{
+		"testfile1.txt":            "this is a test file 1",
+		"readme.md":                "this is a readme file",
+		"b/filename-with-hypen.gz": "file has hypen in its name",
+		"a/another_test_file.txt":  "yet another txt file. yatf.",
 	}
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("--json", "cp", "--flatten", "s3://"+bucket+"/*", ".")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "s3://%v/a/another_test_file.txt",
+				"destination": "another_test_file.txt",
+				"object":{
+					"type": "file",
+					"size": 27
+				}
+			}
+		`, bucket),
+		1: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "s3://%v/b/filename-with-hypen.gz",
+				"destination": "filename-with-hypen.gz",
+				"object": {
+					"type": "file",
+					"size": 26
+				}
+			}
+		`, bucket),
+		2: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "s3://%v/readme.md",
+				"destination": "readme.md",
+				"object": {
+					"type": "file",
+					"size": 21
+				}
+			}
+		`, bucket),
+		3: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "s3://%v/testfile1.txt",
+				"destination": "testfile1.txt",
+				"object": {
+					"type": "file",
+					"size": 21
+				}
+			}
+		`, bucket),
+	}, sortInput(true), jsonCheck(true))
+
+	// assert local filesystem
+	// expect flattened directory structure
+	var expectedFiles = []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+	}
+	expected := fs.Expected(t, expectedFiles...)
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+
+	// assert s3 objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+}
+
+// cp s3://bucket/* dir/ (nested source hierarchy)
+func TestCopyMultipleNestedS3ObjectsToLocal(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"testfile1.txt":               "this is a test file 1",
+		"a/readme.md":                 "this is a readme file",
+		"a/b/filename-with-hypen.gz":  "file has hypen in its name",
+		"b/another_test_file.txt":     "yet another txt file. yatf.",
+		"c/d/e/another_test_file.txt": "yet another txt file. yatf.",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("cp", "s3://"+bucket+"/*", ".")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/a/b/filename-with-hypen.gz a/b/filename-with-hypen.gz`, bucket),
+		1: equals(`cp s3://%v/a/readme.md a/readme.md`, bucket),
+		2: equals(`cp s3://%v/b/another_test_file.txt b/another_test_file.txt`, bucket),
+		3: equals(`cp s3://%v/c/d/e/another_test_file.txt c/d/e/another_test_file.txt`, bucket),
+		4: equals(`cp s3://%v/testfile1.txt testfile1.txt`, bucket),
+	}, sortInput(true))
+
+	// assert local filesystem
+	var expectedFiles = []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("readme.md", "this is a readme file"),
+			fs.WithDir(
+				"b",
+				fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+			),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"c",
+			fs.WithDir(
+				"d",
+				fs.WithDir(
+					"e",
+					fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+				),
+			),
+		),
+	}
+	expected := fs.Expected(t, expectedFiles...)
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+
+	// assert s3 objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+}
+
+// cp s3://bucket/*/*.ext dir/
+func TestCopyMultipleNestedS3ObjectsToLocalWithPartial(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"testfile1.txt":               "this is a test file 1",
+		"a/readme.md":                 "this is a readme file",
+		"a/b/filename-with-hypen.gz":  "file has hypen in its name",
+		"b/another_test_file.txt":     "yet another txt file. yatf.",
+		"c/d/e/another_test_file.txt": "yet another txt file. yatf.",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("cp", "s3://"+bucket+"/*/*.txt", ".")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/b/another_test_file.txt b/another_test_file.txt`, bucket),
+		1: equals(`cp s3://%v/c/d/e/another_test_file.txt c/d/e/another_test_file.txt`, bucket),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(
+		t,
+		fs.WithDir(
+			"b",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"c",
+			fs.WithDir(
+				"d",
+				fs.WithDir(
+					"e",
+					fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+				),
+			),
+		),
+	)
+
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+
+	// assert s3 objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+}
+
+// cp s3://bucket/* dir/ (dir/ doesn't exist)
+func TestCopyMultipleS3ObjectsToGivenLocalDirectory(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"testfile1.txt":            "this is a test file 1",
+		"readme.md":                "this is a readme file",
+		"b/filename-with-hypen.gz": "file has hypen in its name",
+		"a/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	const dst = "given-directory"
+	cmd := s5cmd("cp", "s3://"+bucket+"/*", dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/a/another_test_file.txt %v/a/another_test_file.txt`, bucket, dst),
+		1: equals(`cp s3://%v/b/filename-with-hypen.gz %v/b/filename-with-hypen.gz`, bucket, dst),
+		2: equals(`cp s3://%v/readme.md %v/readme.md`, bucket, dst),
+		3: equals(`cp s3://%v/testfile1.txt %v/testfile1.txt`, bucket, dst),
+	}, sortInput(true))
+
+	// assert local filesystem
+	var expectedFiles = []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+	}
+	expected := fs.Expected(t, fs.WithDir(dst, expectedFiles...))
+	assert.Assert(t, fs.Equal(cmd.Dir, expected))
+
+	// assert s3 objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+}
+
+// cp dir/file s3://bucket/
+func TestCopySingleFileToS3(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		// make sure that Put reads the file header, not the extension
+		filename = "index.txt"
+		content  = `
+<html lang="en">
+	<head>
+	<meta charset="utf-8">
+	<body>
+		<div id="foo">
+			<div class="bar"></div>
+		</div>
+		<div id="baz">
+			<style data-hey="naber"></style>
+		</div>
+	</body>
+</html>
+`
+		expectedContentType = "text/html; charset=utf-8"
+	)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content))
+	defer workdir.Remove()
+
+	srcpath := workdir.Join(filename)
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	srcpath = filepath.ToSlash(srcpath)
+	cmd := s5cmd("cp", srcpath, dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix(`cp %v %v%v`, srcpath, dstpath, filename),
+	})
+
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, content))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert S3
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content, ensureContentType(expectedContentType)))
+}
+
+// --json cp dir/file s3://bucket
+func TestCopySingleFileToS3JSON(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a test file"
+	)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content))
+	defer workdir.Remove()
+
+	fpath := workdir.Join(filename)
+
+	cmd := s5cmd("--json", "cp", fpath, "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd)
+
+	jsonText := `
+		{
+			"operation": "cp",
+			"success": true,
+			"source": "%v",
+			"destination": "s3://%v/testfile1.txt",
+			"object": {
+				"type": "file",
+				"size":19
+			}
+		}
+	`
+
+	result.Assert(t, icmd.Success)
+	fpath = filepath.ToSlash(fpath)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: json(jsonText, fpath, bucket),
+	}, jsonCheck(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, content))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert S3
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+}
+
+// cp dir/ s3://bucket/
+func TestCopyDirToS3(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("file1.txt", "this is the first test file"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"c",
+			fs.WithFile("file2.txt", "this is the second test file"),
+		),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	defer workdir.Remove()
+	srcpath := workdir.Path()
+	srcpath = filepath.ToSlash(srcpath)
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	// this command ('s5cmd cp dir/ s3://bucket/') will run in 'walk' mode,
+	// which is different than 'glob' mode.
+	cmd := s5cmd("cp", workdir.Path()+"/", dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/c/file2.txt %vc/file2.txt`, srcpath, dstpath),
+		1: equals(`cp %v/file1.txt %vfile1.txt`, srcpath, dstpath),
+		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert s3
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file1.txt", "this is the first test file"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "readme.md", "this is a readme file"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "c/file2.txt", "this is the second test file"))
+}
+
+// cp dir/{file, folderWithBackslash} s3://bucket
+func TestCopyDirBackslashedToS3(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip()
+	}
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("readme.md", `¯\_(ツ)_/¯`),
+		fs.WithDir(
+			"t\\est",
+			fs.WithFile("filetest.txt", "try reaching me on windows :-)"),
+		),
+	}
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	defer workdir.Remove()
+	srcpath := workdir.Path()
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	cmd := s5cmd("cp", workdir.Path()+"/", dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+		1: equals(`cp %v/t\est/filetest.txt %vt\est/filetest.txt`, srcpath, dstpath),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert s3
+	assert.Assert(t, ensureS3Object(s3client, bucket, "readme.md", `¯\_(ツ)_/¯`))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "t\\est/filetest.txt", "try reaching me on windows :-)"))
+
+}
+
+// cp --storage-class=GLACIER file s3://bucket/
+func TestCopySingleFileToS3WithStorageClassGlacier(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		// make sure that Put reads the file header, not the extension
+		filename             = "index.txt"
+		content              = "content"
+		expectedStorageClass = "GLACIER"
+	)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile(filename, content))
+	defer workdir.Remove()
+
+	srcpath := workdir.Join(filename)
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	srcpath = filepath.ToSlash(srcpath)
+	cmd := s5cmd("cp", "--storage-class=GLACIER", srcpath, dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix(`cp %v %v%v`, srcpath, dstpath, filename),
+	})
+
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, content))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert S3
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content, ensureStorageClass(expectedStorageClass)))
+}
+
+// cp --flatten dir/ s3://bucket/
+func TestFlattenCopyDirToS3(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("file1.txt", "this is the first test file"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"c",
+			fs.WithFile("file2.txt", "this is the second test file"),
+		),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	defer workdir.Remove()
+	srcpath := workdir.Path()
+	srcpath = filepath.ToSlash(srcpath)
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	// this command ('s5cmd cp dir/ s3://bucket/') will run in 'walk' mode,
+	// which is different than 'glob' mode.
+	cmd := s5cmd("cp", "--flatten", workdir.Path()+"/", dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/c/file2.txt %vfile2.txt`, srcpath, dstpath),
+		1: equals(`cp %v/file1.txt %vfile1.txt`, srcpath, dstpath),
+		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert s3
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file1.txt", "this is the first test file"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "readme.md", "this is a readme file"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file2.txt", "this is the second test file"))
+}
+
+// cp dir/* s3://bucket/
+func TestCopyMultipleFilesToS3Bucket(t *testing.T) {
+
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+	srcpath := workdir.Path()
+	srcpath = filepath.ToSlash(srcpath)
+	defer workdir.Remove()
+
+	cmd := s5cmd("cp", srcpath+"/*", dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/a/another_test_file.txt %va/another_test_file.txt`, srcpath, dstpath),
+		1: equals(`cp %v/b/filename-with-hypen.gz %vb/filename-with-hypen.gz`, srcpath, dstpath),
+		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dstpath),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	expectedS3Content := map[string]string{
+		"testfile1.txt":            "this is a test file 1",
+		"readme.md":                "this is a readme file",
+		"b/filename-with-hypen.gz": "file has hypen in its name",
+		"a/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	// assert s3
+	for filename, content := range expectedS3Content {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+}
+
+// cp dir/*.txt s3://bucket/
+func TestCopyMultipleFilesToS3BucketWildcardAcrossDirectories(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, "somedir",
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir("a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+			fs.WithDir("b",
+				fs.WithFile("nested.txt", "nested txt file"),
+				fs.WithFile("nested.md", "nested md file"),
+			),
+		),
+	)
+	defer workdir.Remove()
+
+	srcpath := filepath.ToSlash(workdir.Path())
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	// wildcards match across directories, as the keys of a bucket are
+	// matched.
+	cmd := s5cmd("cp", srcpath+"/*.txt", dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/a/another_test_file.txt %va/another_test_file.txt`, srcpath, dstpath),
+		1: equals(`cp %v/a/b/nested.txt %va/b/nested.txt`, srcpath, dstpath),
+		2: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dstpath),
+	}, sortInput(true))
+
+	// the same wildcard matches the same keys of the bucket.
+	cmd = s5cmd("ls", "s3://"+bucket+"/*.txt")
+	result = icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix(" a/another_test_file.txt"),
+		1: suffix(" a/b/nested.txt"),
+		2: suffix(" testfile1.txt"),
+	})
+
+	err := ensureS3Object(s3client, bucket, "a/b/nested.md", "nested md file")
+	assertError(t, err, errS3NoSuchKey)
+}
+
+// cp dir/*/file.txt s3://bucket/
+func TestCopyMultipleFilesToS3BucketWildcardInDirectory(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, "somedir",
+		fs.WithDir("logs-a", fs.WithFile("file.txt", "a")),
+		fs.WithDir("logs-b", fs.WithFile("file.txt", "b"), fs.WithFile("other.txt", "other")),
+		fs.WithDir("data", fs.WithFile("file.txt", "data")),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("cp", "logs-*/file.txt", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp logs-a/file.txt s3://%v/logs-a/file.txt`, bucket),
+		1: equals(`cp logs-b/file.txt s3://%v/logs-b/file.txt`, bucket),
+	}, sortInput(true))
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "logs-a/file.txt", "a"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "logs-b/file.txt", "b"))
+}
+
+// cp 's3://bucket/logs/**/errors/*.gz' dir/
+func TestCopyS3ObjectsWithDoubleStarWildcard(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for _, key := range []string{
+		"logs/errors/a.gz",
+		"logs/2020/03/errors/b.gz",
+		"logs/2020/03/myerrors/c.gz",
+		"logs/2020/03/info/d.gz",
+	} {
+		putFile(t, s3client, bucket, key, "content")
+	}
+
+	cmd := s5cmd("cp", "s3://"+bucket+"/logs/**/errors/*.gz", "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/logs/2020/03/errors/b.gz dir/2020/03/errors/b.gz`, bucket),
+		1: equals(`cp s3://%v/logs/errors/a.gz dir/errors/a.gz`, bucket),
+	}, sortInput(true))
+}
+
+// cp 's3://bucket/logs/{2019,2020}-*/x/*.gz' dir/
+func TestCopyS3ObjectsWithAlternativesWildcard(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for _, key := range []string{
+		"logs/2019-01/x/a.gz",
+		"logs/2020-02/x/b.gz",
+		"logs/2020-02/y/c.gz",
+		"logs/2021-03/x/d.gz",
+	} {
+		putFile(t, s3client, bucket, key, "content")
+	}
+
+	cmd := s5cmd("cp", "s3://"+bucket+"/logs/{2019,2020}-*/x/*.gz", "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/logs/2019-01/x/a.gz dir/2019-01/x/a.gz`, bucket),
+		1: equals(`cp s3://%v/logs/2020-02/x/b.gz dir/2020-02/x/b.gz`, bucket),
+	}, sortInput(true))
+}
+
+// --list-shards 4 cp 's3://bucket/logs/*.gz' dir/
+func TestCopyS3ObjectsWithShardedListing(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for _, key := range []string{
+		"logs/a.gz",
+		"logs/b.txt",
+		"logs/2019/c.gz",
+		"logs/2020/03/d.gz",
+		"logs/2020/03/e.txt",
+		"other/f.gz",
+	} {
+		putFile(t, s3client, bucket, key, "content")
+	}
+
+	cmd := s5cmd("--list-shards", "4", "cp", "s3://"+bucket+"/logs/*.gz", "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/logs/2019/c.gz dir/2019/c.gz`, bucket),
+		1: equals(`cp s3://%v/logs/2020/03/d.gz dir/2020/03/d.gz`, bucket),
+		2: equals(`cp s3://%v/logs/a.gz dir/a.gz`, bucket),
+	}, sortInput(true))
+}
+
+// cp 'dir/**/errors/*.gz' s3://bucket/
+func TestCopyLocalFilesWithDoubleStarWildcard(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, "somedir",
+		fs.WithDir("logs",
+			fs.WithDir("errors", fs.WithFile("a.gz", "a")),
+			fs.WithDir("2020",
+				fs.WithDir("errors", fs.WithFile("b.gz", "b")),
+				fs.WithDir("myerrors", fs.WithFile("c.gz", "c")),
+			),
+		),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("cp", "logs/**/errors/*.gz", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp logs/2020/errors/b.gz s3://%v/2020/errors/b.gz`, bucket),
+		1: equals(`cp logs/errors/a.gz s3://%v/errors/a.gz`, bucket),
+	}, sortInput(true))
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "errors/a.gz", "a"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "2020/errors/b.gz", "b"))
+}
+
+// cp --flatten dir/* s3://bucket/
+func TestFlattenCopyMultipleFilesToS3Bucket(t *testing.T) {
+
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
 	dstpath := fmt.Sprintf("s3://%v/", bucket)
 	srcpath := workdir.Path()
 	srcpath = filepath.ToSlash(srcpath)
 	defer workdir.Remove()
 
-	cmd := s5cmd("cp", "--flatten", srcpath+"/*", dstpath)
+	cmd := s5cmd("cp", "--flatten", srcpath+"/*", dstpath)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/a/another_test_file.txt %vanother_test_file.txt`, srcpath, dstpath),
+		1: equals(`cp %v/b/filename-with-hypen.gz %vfilename-with-hypen.gz`, srcpath, dstpath),
+		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
+		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dstpath),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	expectedS3Content := map[string]string{
+		"testfile1.txt":          "this is a test file 1",
+		"readme.md":              "this is a readme file",
+		"filename-with-hypen.gz": "file has hypen in its name",
+		"another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	// assert s3
+	for filename, content := range expectedS3Content {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+}
+
+// cp dir/* s3://bucket/prefix (error)
+func TestCopyMultipleFilesToS3WithPrefixWithoutSlash(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	defer workdir.Remove()
+
+	src := fmt.Sprintf("%v/*", workdir.Path())
+	src = filepath.ToSlash(src)
+	dst := fmt.Sprintf("s3://%v/prefix", bucket)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp %v %v": target %q must be a bucket or a prefix`, src, dst, dst),
+	})
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
+
+// cp dir/* s3://bucket/prefix/
+func TestCopyMultipleFilesToS3WithPrefixWithSlash(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	defer workdir.Remove()
+
+	srcpath := workdir.Path()
+
+	srcpath = filepath.ToSlash(srcpath)
+	src := fmt.Sprintf("%v/*", srcpath)
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/a/another_test_file.txt %va/another_test_file.txt`, srcpath, dst),
+		1: equals(`cp %v/b/filename-with-hypen.gz %vb/filename-with-hypen.gz`, srcpath, dst),
+		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dst),
+		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dst),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	expectedS3Content := map[string]string{
+		"prefix/testfile1.txt":            "this is a test file 1",
+		"prefix/readme.md":                "this is a readme file",
+		"prefix/b/filename-with-hypen.gz": "file has hypen in its name",
+		"prefix/a/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	// assert s3
+	for key, content := range expectedS3Content {
+		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
+	}
+}
+
+// cp --flatten dir/* s3://bucket/prefix/
+func TestFlattenCopyMultipleFilesToS3WithPrefixWithSlash(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	defer workdir.Remove()
+
+	srcpath := workdir.Path()
+
+	srcpath = filepath.ToSlash(srcpath)
+	src := fmt.Sprintf("%v/*", srcpath)
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	cmd := s5cmd("cp", "--flatten", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v/a/another_test_file.txt %vanother_test_file.txt`, srcpath, dst),
+		1: equals(`cp %v/b/filename-with-hypen.gz %vfilename-with-hypen.gz`, srcpath, dst),
+		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dst),
+		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dst),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	expectedS3Content := map[string]string{
+		"prefix/testfile1.txt":          "this is a test file 1",
+		"prefix/readme.md":              "this is a readme file",
+		"prefix/filename-with-hypen.gz": "file has hypen in its name",
+		"prefix/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	// assert s3
+	for key, content := range expectedS3Content {
+		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
+	}
+}
+
+// cp dir/ s3://bucket/prefix/
+func TestCopyLocalDirectoryToS3WithPrefixWithSlash(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	defer workdir.Remove()
+
+	src := fmt.Sprintf("%v/", workdir.Path())
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	src = filepath.ToSlash(src)
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %va/another_test_file.txt %va/another_test_file.txt`, src, dst),
+		1: equals(`cp %vb/filename-with-hypen.gz %vb/filename-with-hypen.gz`, src, dst),
+		2: equals(`cp %vreadme.md %vreadme.md`, src, dst),
+		3: equals(`cp %vtestfile1.txt %vtestfile1.txt`, src, dst),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	expectedS3Content := map[string]string{
+		"prefix/testfile1.txt":            "this is a test file 1",
+		"prefix/readme.md":                "this is a readme file",
+		"prefix/b/filename-with-hypen.gz": "file has hypen in its name",
+		"prefix/a/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	// assert s3
+	for key, content := range expectedS3Content {
+		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
+	}
+}
+
+// cp --flatten dir/ s3://bucket/prefix/
+func TestFlattenCopyLocalDirectoryToS3WithPrefixWithSlash(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	folderLayout := []fs.PathOp{
+		fs.WithFile("testfile1.txt", "this is a test file 1"),
+		fs.WithFile("readme.md", "this is a readme file"),
+		fs.WithDir(
+			"a",
+			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithDir(
+				"c",
+				fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
+			),
+		),
+	}
+
+	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	defer workdir.Remove()
+
+	src := fmt.Sprintf("%v/", workdir.Path())
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	src = filepath.ToSlash(src)
+	cmd := s5cmd("cp", "--flatten", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %va/another_test_file.txt %vanother_test_file.txt`, src, dst),
+		1: equals(`cp %vb/c/filename-with-hypen.gz %vfilename-with-hypen.gz`, src, dst),
+		2: equals(`cp %vreadme.md %vreadme.md`, src, dst),
+		3: equals(`cp %vtestfile1.txt %vtestfile1.txt`, src, dst),
+	}, sortInput(true))
+
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	expectedS3Content := map[string]string{
+		"prefix/testfile1.txt":          "this is a test file 1",
+		"prefix/readme.md":              "this is a readme file",
+		"prefix/filename-with-hypen.gz": "file has hypen in its name",
+		"prefix/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	// assert s3
+	for key, content := range expectedS3Content {
+		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
+	}
+}
+
+// cp dir/ s3://bucket/prefix
+func TestCopyLocalDirectoryToS3WithPrefixWithoutSlash(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"testfile1.txt":          "this is a test file 1",
+		"readme.md":              "this is a readme file",
+		"filename-with-hypen.gz": "file has hypen in its name",
+		"another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	var files []fs.PathOp
+	for filename, content := range filesToContent {
+		op := fs.WithFile(filename, content)
+		files = append(files, op)
+	}
+
+	workdir := fs.NewDir(t, "somedir", files...)
+	defer workdir.Remove()
+
+	src := fmt.Sprintf("%v/", workdir.Path())
+	dst := fmt.Sprintf("s3://%v/prefix", bucket)
+
+	src = filepath.ToSlash(src)
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp %v %v": target %q must be a bucket or a prefix`, src, dst, dst),
+	})
+
+	// assert local filesystem
+	expected := fs.Expected(t, files...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
+
+// cp s3://bucket/object s3://bucket/object2
+func TestCopySingleS3ObjectToS3(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename    = "testfile1.txt"
+		dstfilename = "copy_" + filename
+		content     = "this is a file content"
+	)
+
+	putFile(t, s3client, bucket, filename, content)
+
+	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
+	dst := fmt.Sprintf("s3://%v/%v", bucket, dstfilename)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v`, src, dst),
+	})
+
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, bucket, dstfilename, content))
+}
+
+// cp --content-type text/html s3://bucket/object s3://bucket/object2
+func TestCopySingleS3ObjectToS3WithContentType(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	_, err := s3client.PutObject(&s3.PutObjectInput{
+		Body:        strings.NewReader("content"),
+		Bucket:      aws.String(bucket),
+		Key:         aws.String("index.txt"),
+		ContentType: aws.String("text/plain"),
+		Metadata:    map[string]*string{"Owner": aws.String("web")},
+	})
+	assert.NilError(t, err)
+
+	src := fmt.Sprintf("s3://%v/index.txt", bucket)
+	dst := fmt.Sprintf("s3://%v/index.html", bucket)
+
+	cmd := s5cmd("--aws-debug", "http", "cp", "--content-type", "text/html", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// the fake S3 server doesn't keep the content types, so the copy
+	// request is checked instead.
+	out := result.Stdout()
+	for _, expected := range []string{
+		"Content-Type: text/html",
+		"X-Amz-Metadata-Directive: REPLACE",
+	} {
+		assert.Assert(t, strings.Contains(out, expected), out)
+	}
+
+	// the user metadata of the source are kept.
+	head, err := s3client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String("index.html"),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, aws.StringValue(head.Metadata["Owner"]), "web")
+}
+
+// cp --metadata owner=api s3://bucket/object s3://bucket/object2
+func TestCopySingleS3ObjectToS3WithMetadata(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	_, err := s3client.PutObject(&s3.PutObjectInput{
+		Body:        strings.NewReader("content"),
+		Bucket:      aws.String(bucket),
+		Key:         aws.String("data.json"),
+		ContentType: aws.String("application/json"),
+		Metadata:    map[string]*string{"Owner": aws.String("web")},
+	})
+	assert.NilError(t, err)
+
+	src := fmt.Sprintf("s3://%v/data.json", bucket)
+	dst := fmt.Sprintf("s3://%v/copy.json", bucket)
+
+	cmd := s5cmd("--aws-debug", "http", "cp", "--metadata", "owner=api", "--metadata", "version=2", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	out := result.Stdout()
+	assert.Assert(t, strings.Contains(out, "X-Amz-Metadata-Directive: REPLACE"), out)
+
+	// the user metadata of the source are replaced.
+	head, err := s3client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String("copy.json"),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, aws.StringValue(head.Metadata["Owner"]), "api")
+	assert.Equal(t, aws.StringValue(head.Metadata["Version"]), "2")
+}
+
+func TestCopyWithInvalidAttributeFlags(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "metadata_without_value",
+			args:          []string{"--metadata", "owner", "s3://bucket/a", "s3://bucket/b"},
+			expectedError: `invalid --metadata "owner", expected key=value`,
+		},
+		{
+			name:          "tag_without_key",
+			args:          []string{"--tag", "=x", "s3://bucket/a", "s3://bucket/b"},
+			expectedError: `invalid --tag "=x", expected key=value`,
+		},
+		{
+			name:          "copy_acl_on_download",
+			args:          []string{"--copy-acl", "s3://bucket/a", "."},
+			expectedError: "--copy-acl flag can only be used for copies between S3 objects",
+		},
+		{
+			name:          "copy_acl_with_acl",
+			args:          []string{"--copy-acl", "--acl", "public-read", "s3://bucket/a", "s3://bucket/b"},
+			expectedError: "--acl and --copy-acl flags can not be used together",
+		},
+		{
+			name:          "unknown_metadata_directive",
+			args:          []string{"--metadata-directive", "KEEP", "s3://bucket/a", "s3://bucket/b"},
+			expectedError: `unknown metadata directive "KEEP", expected one of COPY or REPLACE`,
+		},
+		{
+			name:          "metadata_directive_on_download",
+			args:          []string{"--metadata-directive", "REPLACE", "s3://bucket/a", "."},
+			expectedError: "--metadata-directive flag can only be used for copies between S3 objects",
+		},
+		{
+			name:          "copy_metadata_directive_with_content_type",
+			args:          []string{"--metadata-directive", "COPY", "--content-type", "text/html", "s3://bucket/a", "s3://bucket/b"},
+			expectedError: "--metadata-directive COPY can not be used with --content-type, --cache-control or --metadata flags",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd(append([]string{"cp"}, tc.args...)...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: contains("%v", tc.expectedError),
+			})
+		})
+	}
+}
+
+// cp --metadata-directive REPLACE --content-type text/html --cache-control max-age=3600 s3://bucket/object s3://bucket/object
+func TestCopySingleS3ObjectToItselfWithMetadataDirective(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const content = "<html></html>"
+	putFile(t, s3client, bucket, "index.html", content)
+
+	src := fmt.Sprintf("s3://%v/index.html", bucket)
+
+	cmd := s5cmd(
+		"--aws-debug", "http",
+		"cp",
+		"--metadata-directive", "REPLACE",
+		"--content-type", "text/html",
+		"--cache-control", "max-age=3600",
+		src, src,
+	)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	out := result.Stdout()
+	for _, expected := range []string{
+		"DEBUG Request s3/CopyObject Details:",
+		"Cache-Control: max-age=3600",
+		"Content-Type: text/html",
+		"X-Amz-Metadata-Directive: REPLACE",
+	} {
+		assert.Assert(t, strings.Contains(out, expected), out)
+	}
+	assert.Assert(t, strings.Contains(out, fmt.Sprintf("cp %v %v", src, src)), out)
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "index.html", content))
+}
+
+// cp --metadata-directive REPLACE --cache-control no-cache s3://bucket/prefix/* s3://bucket/prefix/
+func TestCopyMultipleS3ObjectsToThemselvesWithMetadataDirective(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "site/index.html", "index")
+	putFile(t, s3client, bucket, "site/about/index.html", "about")
+
+	cmd := s5cmd(
+		"cp",
+		"--metadata-directive", "REPLACE",
+		"--cache-control", "no-cache",
+		"s3://"+bucket+"/site/*",
+		"s3://"+bucket+"/site/",
+	)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// each object is copied onto itself.
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/site/about/index.html s3://%v/site/about/index.html`, bucket, bucket),
+		1: equals(`cp s3://%v/site/index.html s3://%v/site/index.html`, bucket, bucket),
+	}, sortInput(true))
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "site/index.html", "index"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "site/about/index.html", "about"))
+}
+
+// mv --metadata-directive REPLACE s3://bucket/object s3://bucket/object
+func TestMoveSingleS3ObjectToItselfKeepsObject(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const content = "content"
+	putFile(t, s3client, bucket, "file.txt", content)
+
+	src := fmt.Sprintf("s3://%v/file.txt", bucket)
+
+	cmd := s5cmd("mv", "--metadata-directive", "REPLACE", src, src)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`mv %v %v`, src, src),
+	})
+
+	// the source is the destination as well, so it is not deleted.
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file.txt", content))
+}
+
+// --json cp s3://bucket/object s3://bucket2/object
+func TestCopySingleS3ObjectToS3JSON(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename    = "testfile1.txt"
+		dstfilename = "copy_" + filename
+		content     = "this is a file content"
+	)
+
+	putFile(t, s3client, bucket, filename, content)
+
+	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
+	dst := fmt.Sprintf("s3://%v/%v", bucket, dstfilename)
+
+	cmd := s5cmd("--json", "cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	jsonText := fmt.Sprintf(`
+		{
+			"operation":"cp",
+			"success":true,
+			"source":"%v",
+			"destination":"%v",
+			"object": {
+				"key": "%v",
+				"type":"file"
+			}
+		}
+	`, src, dst, dst)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: json(jsonText),
+	}, jsonCheck(true))
+
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, bucket, dstfilename, content))
+}
+
+// cp s3://bucket/object s3://bucket2/
+func TestCopySingleS3ObjectIntoAnotherBucket(t *testing.T) {
+	t.Parallel()
+
+	srcbucket := s3BucketFromTestName(t)
+	dstbucket := "copy-" + s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, srcbucket)
+	createBucket(t, s3client, dstbucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	putFile(t, s3client, srcbucket, filename, content)
+
+	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
+	dst := fmt.Sprintf("s3://%v/", dstbucket)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v%v`, src, dst, filename),
+	})
+
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
+
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
+}
+
+// cp --flatten s3://bucket/object s3://bucket2/
+func TestFlattenCopySingleS3ObjectIntoAnotherBucket(t *testing.T) {
+	t.Parallel()
+
+	srcbucket := s3BucketFromTestName(t)
+	dstbucket := "copy-" + s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, srcbucket)
+	createBucket(t, s3client, dstbucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	putFile(t, s3client, srcbucket, filename, content)
+
+	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
+	dst := fmt.Sprintf("s3://%v/", dstbucket)
+
+	cmd := s5cmd("cp", "--flatten", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v%v`, src, dst, filename),
+	})
+
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
+
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
+}
+
+// cp s3://bucket/object s3://bucket2/object
+func TestCopySingleS3ObjectIntoAnotherBucketWithObjName(t *testing.T) {
+	t.Parallel()
+
+	const (
+		srcbucket = "bucket"
+		dstbucket = "dstbucket"
+	)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, srcbucket)
+	createBucket(t, s3client, dstbucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	putFile(t, s3client, srcbucket, filename, content)
+
+	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
+	dst := fmt.Sprintf("s3://%v/%v", dstbucket, filename)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v`, src, dst),
+	})
+
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
+
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
+}
+
+// cp s3://bucket/object s3://bucket2/prefix/
+func TestCopySingleS3ObjectIntoAnotherBucketWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	const bucket = "bucket"
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const (
+		filename = "testfile1.txt"
+		content  = "this is a file content"
+	)
+
+	putFile(t, s3client, bucket, filename, content)
+
+	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
+	dst := fmt.Sprintf("s3://%v/prefix/%v", bucket, filename)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v`, src, dst),
+	})
+
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/"+filename, content))
+}
+
+// cp s3://bucket/* s3://dstbucket/
+func TestCopyAllObjectsIntoAnotherBucketIncludingSpecialCharacter(t *testing.T) {
+	t.Parallel()
+
+	const (
+		srcbucket = "bucket"
+		dstbucket = "dstbucket"
+	)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, srcbucket)
+	createBucket(t, s3client, dstbucket)
+
+	filesToContent := map[string]string{
+		"sub&@$/test+1.txt":           "this is a test file 1",
+		"sub:,?/test; =2.txt":         "this is a test file 2",
+		"test&@$:,?;= 3.txt":          "this is a test file 3",
+		"sub/this-is-normal-file.txt": "this is a normal file",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, srcbucket, filename, content)
+	}
+
+	src := fmt.Sprintf("s3://%v/*", srcbucket)
+	dst := fmt.Sprintf("s3://%v/", dstbucket)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/sub&@$/test+1.txt s3://%v/sub&@$/test+1.txt`, srcbucket, dstbucket),
+		1: equals(`cp s3://%v/sub/this-is-normal-file.txt s3://%v/sub/this-is-normal-file.txt`, srcbucket, dstbucket),
+		2: equals(`cp s3://%v/sub:,?/test; =2.txt s3://%v/sub:,?/test; =2.txt`, srcbucket, dstbucket),
+		3: equals(`cp s3://%v/test&@$:,?;= 3.txt s3://%v/test&@$:,?;= 3.txt`, srcbucket, dstbucket),
+	}, sortInput(true))
+}
+
+// cp s3://bucket/* s3://bucket/prefix/
+func TestCopyMultipleS3ObjectsToS3WithPrefix(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"testfile1.txt":            "this is a test file 1",
+		"readme.md":                "this is a readme file",
+		"b/filename-with-hypen.gz": "file has hypen in its name",
+		"a/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	src := fmt.Sprintf("s3://%v/*", bucket)
+	dst := fmt.Sprintf("s3://%v/dst/", bucket)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/a/another_test_file.txt %va/another_test_file.txt`, bucket, dst),
+		1: equals(`cp s3://%v/b/filename-with-hypen.gz %vb/filename-with-hypen.gz`, bucket, dst),
+		2: equals(`cp s3://%v/readme.md %vreadme.md`, bucket, dst),
+		3: equals(`cp s3://%v/testfile1.txt %vtestfile1.txt`, bucket, dst),
+	}, sortInput(true))
+
+	// assert s3 source objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+
+	// assert s3 destination objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, "dst/"+filename, content))
+	}
+}
+
+// cp --flatten s3://bucket/* s3://bucket/prefix/
+func TestFlattenCopyMultipleS3ObjectsToS3WithPrefix(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"testfile1.txt":            "this is a test file 1",
+		"readme.md":                "this is a readme file",
+		"b/filename-with-hypen.gz": "file has hypen in its name",
+		"a/another_test_file.txt":  "yet another txt file. yatf.",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	src := fmt.Sprintf("s3://%v/*", bucket)
+	dst := fmt.Sprintf("s3://%v/dst/", bucket)
+
+	cmd := s5cmd("cp", "--flatten", src, dst)
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/a/another_test_file.txt %vanother_test_file.txt`, srcpath, dstpath),
-		1: equals(`cp %v/b/filename-with-hypen.gz %vfilename-with-hypen.gz`, srcpath, dstpath),
-		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dstpath),
-		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dstpath),
+		0: equals(`cp s3://%v/a/another_test_file.txt %vanother_test_file.txt`, bucket, dst),
+		1: equals(`cp s3://%v/b/filename-with-hypen.gz %vfilename-with-hypen.gz`, bucket, dst),
+		2: equals(`cp s3://%v/readme.md %vreadme.md`, bucket, dst),
+		3: equals(`cp s3://%v/testfile1.txt %vtestfile1.txt`, bucket, dst),
 	}, sortInput(true))
 
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	// assert s3 source objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
 
-	expectedS3Content := map[string]string{
-		"testfile1.txt":          "this is a test file 1",
-		"readme.md":              "this is a readme file",
-		"filename-with-hypen.gz": "file has hypen in its name",
-		"another_test_file.txt":  "yet another txt file. yatf.",
+	dstContent := map[string]string{
+		"dst/testfile1.txt":          "this is a test file 1",
+		"dst/readme.md":              "this is a readme file",
+		"dst/filename-with-hypen.gz": "file has hypen in its name",
+		"dst/another_test_file.txt":  "yet another txt file. yatf.",
 	}
 
-	// assert s3
-	for filename, content := range expectedS3Content {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	// assert s3 destination objects
+	for key, content := range dstContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
 	}
 }
 
-// cp dir/* s3://bucket/prefix (error)
-func TestCopyMultipleFilesToS3WithPrefixWithoutSlash(t *testing.T) {
+// cp s3://bucket/* s3://bucket/prefix
+func TestCopyMultipleS3ObjectsToS3WithPrefixWithoutSlash(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
+	const bucket = "bucket"
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
+	filesToContent := map[string]string{
+		"testfile1.txt":            "this is a test file 1",
+		"readme.md":                "this is a readme file",
+		"b/filename-with-hypen.gz": "file has hypen in its name",
+		"a/another_test_file.txt":  "yet another txt file. yatf.",
 	}
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
-	defer workdir.Remove()
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
 
-	src := fmt.Sprintf("%v/*", workdir.Path())
-	src = filepath.ToSlash(src)
-	dst := fmt.Sprintf("s3://%v/prefix", bucket)
+	src := fmt.Sprintf("s3://%v/*", bucket)
+	dst := fmt.Sprintf("s3://%v/dst", bucket)
 
 	cmd := s5cmd("cp", src, dst)
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`ERROR "cp %v %v": target %q must be a bucket or a prefix`, src, dst, dst),
 	})
 
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	// assert s3 source objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
+
 }
 
-// cp dir/* s3://bucket/prefix/
-func TestCopyMultipleFilesToS3WithPrefixWithSlash(t *testing.T) {
+// --json cp s3://bucket/* s3://bucket/prefix/
+func TestCopyMultipleS3ObjectsToS3JSON(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1120,59 +3016,63 @@ func TestCopyMultipleFilesToS3WithPrefixWithSlash(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
+	filesToContent := map[string]string{
+		"testfile1.txt": "this is a test file 1",
+		"readme.md":     "this is a readme file",
 	}
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
-	defer workdir.Remove()
-
-	srcpath := workdir.Path()
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
 
-	srcpath = filepath.ToSlash(srcpath)
-	src := fmt.Sprintf("%v/*", srcpath)
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+	src := fmt.Sprintf("s3://%v/*", bucket)
+	dst := fmt.Sprintf("s3://%v/dst/", bucket)
 
-	cmd := s5cmd("cp", src, dst)
+	cmd := s5cmd("--json", "cp", src, dst)
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/a/another_test_file.txt %va/another_test_file.txt`, srcpath, dst),
-		1: equals(`cp %v/b/filename-with-hypen.gz %vb/filename-with-hypen.gz`, srcpath, dst),
-		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dst),
-		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dst),
-	}, sortInput(true))
-
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+		0: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "s3://%v/readme.md",
+				"destination": "s3://%v/dst/readme.md",
+				"object": {
+					"key": "s3://%v/dst/readme.md",
+					"type": "file"
+				}
+			}
+		`, bucket, bucket, bucket),
+		1: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "s3://%v/testfile1.txt",
+				"destination": "s3://%v/dst/testfile1.txt",
+				"object": {
+					"key": "s3://%v/dst/testfile1.txt",
+					"type": "file"
+				}
+			}
+		`, bucket, bucket, bucket),
+	}, sortInput(true), jsonCheck(true))
 
-	expectedS3Content := map[string]string{
-		"prefix/testfile1.txt":            "this is a test file 1",
-		"prefix/readme.md":                "this is a readme file",
-		"prefix/b/filename-with-hypen.gz": "file has hypen in its name",
-		"prefix/a/another_test_file.txt":  "yet another txt file. yatf.",
+	// assert s3 source objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 	}
 
-	// assert s3
-	for key, content := range expectedS3Content {
-		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
+	// assert s3 destination objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, "dst/"+filename, content))
 	}
 }
 
-// cp --flatten dir/* s3://bucket/prefix/
-func TestFlattenCopyMultipleFilesToS3WithPrefixWithSlash(t *testing.T) {
+// cp -u -s s3://bucket/prefix/* s3://bucket/prefix2/
+func TestCopyMultipleS3ObjectsToS3_Issue70(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1182,59 +3082,111 @@ func TestFlattenCopyMultipleFilesToS3WithPrefixWithSlash(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
+	filesToContent := map[string]string{
+		"config/.local/folder1/file1.txt": "this is a test file 1",
+		"config/.local/folder2/file2.txt": "this is a test file 2",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	src := fmt.Sprintf("s3://%v/config/.local/*", bucket)
+	dst := fmt.Sprintf("s3://%v/.local/", bucket)
+
+	cmd := s5cmd("cp", "-u", "-s", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/config/.local/folder1/file1.txt %vfolder1/file1.txt`, bucket, dst),
+		1: equals(`cp s3://%v/config/.local/folder2/file2.txt %vfolder2/file2.txt`, bucket, dst),
+	}, sortInput(true))
+
+	// assert s3 source objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 	}
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
-	defer workdir.Remove()
+	// assert s3 destination objects
+	assert.Assert(t, ensureS3Object(s3client, bucket, ".local/folder1/file1.txt", "this is a test file 1"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, ".local/folder2/file2.txt", "this is a test file 2"))
+}
+
+// cp s3://bucket/object dir/ (dirobject exists)
+func TestCopyS3ObjectToLocalWithTheSameFilename(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	const (
+		filename        = "testfile1.txt"
+		content         = "this is the content"
+		expectedContent = content + "\n"
+	)
+
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
+	defer workdir.Remove()
+
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file
+	putFile(t, s3client, bucket, filename, expectedContent)
+
+	cmd := s5cmd("cp", "s3://"+bucket+"/"+filename, ".")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("cp s3://%v/%v %v", bucket, filename, filename),
+	})
+
+	expected := fs.Expected(t, fs.WithFile(filename, expectedContent))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
+
+// -log=debug cp -n s3://bucket/object .
+func TestCopyS3ToLocalWithSameFilenameWithNoClobber(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
 
-	srcpath := workdir.Path()
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
 
-	srcpath = filepath.ToSlash(srcpath)
-	src := fmt.Sprintf("%v/*", srcpath)
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+	const (
+		filename = "testfile1.txt"
+		content  = "this is the content"
+	)
 
-	cmd := s5cmd("cp", "--flatten", src, dst)
-	result := icmd.RunCmd(cmd)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
+	defer workdir.Remove()
+
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file
+	putFile(t, s3client, bucket, filename, content+"\n")
+
+	cmd := s5cmd("--log=debug", "cp", "-n", "s3://"+bucket+"/"+filename, ".")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/a/another_test_file.txt %vanother_test_file.txt`, srcpath, dst),
-		1: equals(`cp %v/b/filename-with-hypen.gz %vfilename-with-hypen.gz`, srcpath, dst),
-		2: equals(`cp %v/readme.md %vreadme.md`, srcpath, dst),
-		3: equals(`cp %v/testfile1.txt %vtestfile1.txt`, srcpath, dst),
-	}, sortInput(true))
-
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+		0: equals(`DEBUG "cp s3://%v/%v %v": object already exists`, bucket, filename, filename),
+	})
 
-	expectedS3Content := map[string]string{
-		"prefix/testfile1.txt":          "this is a test file 1",
-		"prefix/readme.md":              "this is a readme file",
-		"prefix/filename-with-hypen.gz": "file has hypen in its name",
-		"prefix/another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
-	// assert s3
-	for key, content := range expectedS3Content {
-		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
-	}
+	expected := fs.Expected(t, fs.WithFile(filename, content))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 }
 
-// cp dir/ s3://bucket/prefix/
-func TestCopyLocalDirectoryToS3WithPrefixWithSlash(t *testing.T) {
+// cp -n -s s3://bucket/object dir/
+func TestCopyS3ToLocalWithSameFilenameOverrideIfSizeDiffers(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1242,59 +3194,36 @@ func TestCopyLocalDirectoryToS3WithPrefixWithSlash(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-		),
-	}
+	const (
+		filename        = "testfile1.txt"
+		content         = "this is the content"
+		expectedContent = content + "\n"
+	)
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
 	defer workdir.Remove()
 
-	src := fmt.Sprintf("%v/", workdir.Path())
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file
+	putFile(t, s3client, bucket, filename, expectedContent)
 
-	src = filepath.ToSlash(src)
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("cp", "-n", "-s", "s3://"+bucket+"/"+filename, ".")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
+	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
+	// size differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %va/another_test_file.txt %va/another_test_file.txt`, src, dst),
-		1: equals(`cp %vb/filename-with-hypen.gz %vb/filename-with-hypen.gz`, src, dst),
-		2: equals(`cp %vreadme.md %vreadme.md`, src, dst),
-		3: equals(`cp %vtestfile1.txt %vtestfile1.txt`, src, dst),
-	}, sortInput(true))
+		0: equals(`cp s3://%v/%v %v`, bucket, filename, filename),
+	})
 
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
+	expected := fs.Expected(t, fs.WithFile(filename, expectedContent))
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	expectedS3Content := map[string]string{
-		"prefix/testfile1.txt":            "this is a test file 1",
-		"prefix/readme.md":                "this is a readme file",
-		"prefix/b/filename-with-hypen.gz": "file has hypen in its name",
-		"prefix/a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
-
-	// assert s3
-	for key, content := range expectedS3Content {
-		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
-	}
 }
 
-// cp --flatten dir/ s3://bucket/prefix/
-func TestFlattenCopyLocalDirectoryToS3WithPrefixWithSlash(t *testing.T) {
+// cp -n -u s3://bucket/object dir/ (source is newer)
+func TestCopyS3ToLocalWithSameFilenameOverrideIfSourceIsNewer(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1302,62 +3231,42 @@ func TestFlattenCopyLocalDirectoryToS3WithPrefixWithSlash(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	folderLayout := []fs.PathOp{
-		fs.WithFile("testfile1.txt", "this is a test file 1"),
-		fs.WithFile("readme.md", "this is a readme file"),
-		fs.WithDir(
-			"a",
-			fs.WithFile("another_test_file.txt", "yet another txt file. yatf."),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithDir(
-				"c",
-				fs.WithFile("filename-with-hypen.gz", "file has hypen in its name"),
-			),
-		),
-	}
+	const (
+		filename        = "testfile1.txt"
+		content         = "this is the content"
+		expectedContent = content + "\n"
+	)
 
-	workdir := fs.NewDir(t, "somedir", folderLayout...)
+	now := time.Now().UTC()
+	timestamp := fs.WithTimestamps(
+		now.Add(-time.Minute), // access time
+		now.Add(-time.Minute), // mod time
+	)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content, timestamp))
 	defer workdir.Remove()
 
-	src := fmt.Sprintf("%v/", workdir.Path())
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file. also uploaded file is newer than
+	// the file on local fs.
+	putFile(t, s3client, bucket, filename, expectedContent)
 
-	src = filepath.ToSlash(src)
-	cmd := s5cmd("cp", "--flatten", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("cp", "-n", "-u", "s3://"+bucket+"/"+filename, ".")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
+	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
+	// size differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %va/another_test_file.txt %vanother_test_file.txt`, src, dst),
-		1: equals(`cp %vb/c/filename-with-hypen.gz %vfilename-with-hypen.gz`, src, dst),
-		2: equals(`cp %vreadme.md %vreadme.md`, src, dst),
-		3: equals(`cp %vtestfile1.txt %vtestfile1.txt`, src, dst),
-	}, sortInput(true))
+		0: equals(`cp s3://%v/%v %v`, bucket, filename, filename),
+	})
 
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
+	expected := fs.Expected(t, fs.WithFile(filename, expectedContent))
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	expectedS3Content := map[string]string{
-		"prefix/testfile1.txt":          "this is a test file 1",
-		"prefix/readme.md":              "this is a readme file",
-		"prefix/filename-with-hypen.gz": "file has hypen in its name",
-		"prefix/another_test_file.txt":  "yet another txt file. yatf.",
-	}
-
-	// assert s3
-	for key, content := range expectedS3Content {
-		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
-	}
 }
 
-// cp dir/ s3://bucket/prefix
-func TestCopyLocalDirectoryToS3WithPrefixWithoutSlash(t *testing.T) {
+// cp -n -u s3://bucket/object dir/ (source is older)
+func TestCopyS3ToLocalWithSameFilenameDontOverrideIfS3ObjectIsOlder(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1365,44 +3274,44 @@ func TestCopyLocalDirectoryToS3WithPrefixWithoutSlash(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	filesToContent := map[string]string{
-		"testfile1.txt":          "this is a test file 1",
-		"readme.md":              "this is a readme file",
-		"filename-with-hypen.gz": "file has hypen in its name",
-		"another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	const (
+		filename = "testfile1.txt"
+		content  = "this is the content"
+	)
 
-	var files []fs.PathOp
-	for filename, content := range filesToContent {
-		op := fs.WithFile(filename, content)
-		files = append(files, op)
-	}
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file.
+	putFile(t, s3client, bucket, filename, content+"\n")
 
-	workdir := fs.NewDir(t, "somedir", files...)
+	// file on the fs is newer than the file on s3. expect an 'dont override'
+	// behaviour.
+	now := time.Now().UTC()
+	timestamp := fs.WithTimestamps(
+		now.Add(time.Minute), // access time
+		now.Add(time.Minute), // mod time
+	)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content, timestamp))
 	defer workdir.Remove()
 
-	src := fmt.Sprintf("%v/", workdir.Path())
-	dst := fmt.Sprintf("s3://%v/prefix", bucket)
-
-	src = filepath.ToSlash(src)
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("--log=debug", "cp", "-n", "-u", "s3://"+bucket+"/"+filename, ".")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
+	// size differs.
+	result.Assert(t, icmd.Success)
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{
-		0: equals(`ERROR "cp %v %v": target %q must be a bucket or a prefix`, src, dst, dst),
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`DEBUG "cp s3://%v/%v %v": object is newer or same age`, bucket, filename, filename),
 	})
 
-	// assert local filesystem
-	expected := fs.Expected(t, files...)
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
+
+	expected := fs.Expected(t, fs.WithFile(filename, content))
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 }
 
-// cp s3://bucket/object s3://bucket/object2
-func TestCopySingleS3ObjectToS3(t *testing.T) {
+// cp -u -s s3://bucket/prefix/* dir/
+func TestCopyS3ToLocal_Issue70(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1412,35 +3321,54 @@ func TestCopySingleS3ObjectToS3(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	const (
-		filename    = "testfile1.txt"
-		dstfilename = "copy_" + filename
-		content     = "this is a file content"
-	)
+	filesToContent := map[string]string{
+		"config/.local/folder1/file1.txt": "this is a test file 1",
+		"config/.local/folder2/file2.txt": "this is a test file 2",
+	}
 
-	putFile(t, s3client, bucket, filename, content)
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
 
-	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
-	dst := fmt.Sprintf("s3://%v/%v", bucket, dstfilename)
+	workdir := fs.NewDir(t, t.Name())
+	defer workdir.Remove()
+
+	srcpath := fmt.Sprintf("s3://%v/config/.local/*", bucket)
+	dstpath := filepath.Join(workdir.Path(), ".local")
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	dstpath = filepath.ToSlash(dstpath)
+	cmd := s5cmd("cp", "-u", "-s", srcpath, dstpath)
+
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v`, src, dst),
-	})
+		0: equals(`cp s3://%v/config/.local/folder1/file1.txt %v/folder1/file1.txt`, bucket, dstpath),
+		1: equals(`cp s3://%v/config/.local/folder2/file2.txt %v/folder2/file2.txt`, bucket, dstpath),
+	}, sortInput(true))
 
-	// assert s3 source object
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	// assert local filesystem
+	expectedFiles := []fs.PathOp{
+		fs.WithDir(
+			".local",
+			fs.WithMode(0755),
+			fs.WithDir("folder1", fs.WithMode(0755), fs.WithFile("file1.txt", "this is a test file 1")),
+			fs.WithDir("folder2", fs.WithMode(0755), fs.WithFile("file2.txt", "this is a test file 2")),
+		),
+	}
 
-	// assert s3 destination object
-	assert.Assert(t, ensureS3Object(s3client, bucket, dstfilename, content))
+	expectedResult := fs.Expected(t, expectedFiles...)
+	assert.Assert(t, fs.Equal(workdir.Path(), expectedResult))
+
+	// assert s3 objects
+	for filename, content := range filesToContent {
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	}
 }
 
-// --json cp s3://bucket/object s3://bucket2/object
-func TestCopySingleS3ObjectToS3JSON(t *testing.T) {
+// cp file s3://bucket (bucket/file exists)
+func TestCopyLocalFileToS3WithTheSameFilename(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1448,247 +3376,240 @@ func TestCopySingleS3ObjectToS3JSON(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
 	const (
-		filename    = "testfile1.txt"
-		dstfilename = "copy_" + filename
-		content     = "this is a file content"
+		filename   = "testfile1.txt"
+		content    = "this is the content"
+		newContent = content + "\n"
 	)
 
+	createBucket(t, s3client, bucket)
 	putFile(t, s3client, bucket, filename, content)
 
-	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
-	dst := fmt.Sprintf("s3://%v/%v", bucket, dstfilename)
+	// the file to be uploaded is modified
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, newContent))
+	defer workdir.Remove()
 
-	cmd := s5cmd("--json", "cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	dst := "s3://" + bucket
+	cmd := s5cmd("cp", filename, dst)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
-	jsonText := fmt.Sprintf(`
-		{
-			"operation":"cp",
-			"success":true,
-			"source":"%v",
-			"destination":"%v",
-			"object": {
-				"key": "%v",
-				"type":"file"
-			}
-		}
-	`, src, dst, dst)
-
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: json(jsonText),
-	}, jsonCheck(true))
+		0: equals(`cp %v %v/%v`, filename, dst, filename),
+	})
 
-	// assert s3 source object
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, newContent))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 
-	// assert s3 destination object
-	assert.Assert(t, ensureS3Object(s3client, bucket, dstfilename, content))
+	// expect s3 object to be updated with new content
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, newContent))
 }
 
-// cp s3://bucket/object s3://bucket2/
-func TestCopySingleS3ObjectIntoAnotherBucket(t *testing.T) {
+// -log=debug cp -n file s3://bucket (bucket/file exists)
+func TestCopyLocalFileToS3WithSameFilenameWithNoClobber(t *testing.T) {
 	t.Parallel()
 
-	srcbucket := s3BucketFromTestName(t)
-	dstbucket := "copy-" + s3BucketFromTestName(t)
+	bucket := s3BucketFromTestName(t)
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, srcbucket)
-	createBucket(t, s3client, dstbucket)
-
 	const (
-		filename = "testfile1.txt"
-		content  = "this is a file content"
+		filename   = "testfile1.txt"
+		content    = "this is the content"
+		newContent = content + "\n"
 	)
 
-	putFile(t, s3client, srcbucket, filename, content)
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, filename, content)
 
-	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
-	dst := fmt.Sprintf("s3://%v/", dstbucket)
+	// the file to be uploaded is modified
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, newContent))
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("--log=debug", "cp", "-n", filename, "s3://"+bucket)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v%v`, src, dst, filename),
+		0: equals(`DEBUG "cp %v s3://%v/%v": object already exists`, filename, bucket, filename),
 	})
 
-	// assert s3 source object
-	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
-	// assert s3 destination object
-	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, newContent))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// expect s3 object is not overridden
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 }
 
-// cp --flatten s3://bucket/object s3://bucket2/
-func TestFlattenCopySingleS3ObjectIntoAnotherBucket(t *testing.T) {
+// cp -n 'dir/*' s3://bucket/prefix/
+func TestCopyMultipleLocalFilesToS3WithNoClobberListsDestination(t *testing.T) {
 	t.Parallel()
 
-	srcbucket := s3BucketFromTestName(t)
-	dstbucket := "copy-" + s3BucketFromTestName(t)
+	bucket := s3BucketFromTestName(t)
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, srcbucket)
-	createBucket(t, s3client, dstbucket)
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "prefix/a.txt", "content")
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is a file content"
+	workdir := fs.NewDir(t, t.Name(),
+		fs.WithFile("a.txt", "new content"),
+		fs.WithFile("b.txt", "content"),
+		fs.WithFile("c.txt", "content"),
 	)
+	defer workdir.Remove()
 
-	putFile(t, s3client, srcbucket, filename, content)
-
-	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
-	dst := fmt.Sprintf("s3://%v/", dstbucket)
-
-	cmd := s5cmd("cp", "--flatten", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("--aws-debug", "http", "cp", "-n", "*.txt", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v%v`, src, dst, filename),
-	})
-
-	// assert s3 source object
-	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
+	// the destination prefix is listed instead of a HEAD request for each
+	// of the destination objects.
+	out := result.Stdout()
+	assert.Equal(t, strings.Count(out, "DEBUG Request s3/ListObjectsV2 Details:"), 1, out)
+	assert.Equal(t, strings.Count(out, "DEBUG Request s3/HeadObject Details:"), 0, out)
+	for _, expected := range []string{
+		`DEBUG "cp a.txt s3://` + bucket + `/prefix/a.txt": object already exists`,
+		`cp b.txt s3://` + bucket + `/prefix/b.txt`,
+		`cp c.txt s3://` + bucket + `/prefix/c.txt`,
+	} {
+		assert.Assert(t, strings.Contains(out, expected), out)
+	}
 
-	// assert s3 destination object
-	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a.txt", "content"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/b.txt", "content"))
 }
 
-// cp s3://bucket/object s3://bucket2/object
-func TestCopySingleS3ObjectIntoAnotherBucketWithObjName(t *testing.T) {
+// cp -n file s3://bucket
+func TestCopyLocalFileToS3WithNoClobber(t *testing.T) {
 	t.Parallel()
 
-	const (
-		srcbucket = "bucket"
-		dstbucket = "dstbucket"
-	)
+	bucket := s3BucketFromTestName(t)
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, srcbucket)
-	createBucket(t, s3client, dstbucket)
-
 	const (
-		filename = "testfile1.txt"
-		content  = "this is a file content"
+		filename   = "testfile1.txt"
+		content    = "this is the content"
+		newContent = content + "\n"
 	)
 
-	putFile(t, s3client, srcbucket, filename, content)
+	createBucket(t, s3client, bucket)
 
-	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
-	dst := fmt.Sprintf("s3://%v/%v", dstbucket, filename)
+	// the file to be uploaded is modified
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, newContent))
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	dst := "s3://" + bucket
+	cmd := s5cmd("cp", "-n", filename, dst)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v`, src, dst),
+		0: equals(`cp %v %v/%v`, filename, dst, filename),
 	})
 
-	// assert s3 source object
-	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
-	// assert s3 destination object
-	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, newContent))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// expect s3 object is not overridden
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, newContent))
 }
 
-// cp s3://bucket/object s3://bucket2/prefix/
-func TestCopySingleS3ObjectIntoAnotherBucketWithPrefix(t *testing.T) {
+// cp -n -s file s3://bucket (bucket/file exists)
+func TestCopyLocalFileToS3WithSameFilenameOverrideIfSizeDiffers(t *testing.T) {
 	t.Parallel()
 
-	const bucket = "bucket"
+	bucket := s3BucketFromTestName(t)
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
 	const (
-		filename = "testfile1.txt"
-		content  = "this is a file content"
+		filename        = "testfile1.txt"
+		content         = "this is the content"
+		expectedContent = content + "\n"
 	)
 
-	putFile(t, s3client, bucket, filename, content)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, expectedContent))
+	defer workdir.Remove()
 
-	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
-	dst := fmt.Sprintf("s3://%v/prefix/%v", bucket, filename)
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file
+	putFile(t, s3client, bucket, filename, content)
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	dst := "s3://" + bucket
+	cmd := s5cmd("cp", "-n", "-s", filename, dst)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
+	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
+	// size differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v`, src, dst),
+		0: equals(`cp %v %v/%v`, filename, dst, filename),
 	})
 
-	// assert s3 source object
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
-	// assert s3 destination object
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/"+filename, content))
+	assert.NilError(t, ensureS3Object(s3client, bucket, filename, expectedContent))
 }
 
-// cp s3://bucket/* s3://dstbucket/
-func TestCopyAllObjectsIntoAnotherBucketIncludingSpecialCharacter(t *testing.T) {
+// cp --if-content-differ dir/* s3://bucket (bucket has the same and changed files)
+func TestCopyLocalDirToS3OverrideIfContentDiffers(t *testing.T) {
 	t.Parallel()
 
-	const (
-		srcbucket = "bucket"
-		dstbucket = "dstbucket"
-	)
+	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
+	// the destination is listed, and the listings of the bolt backend don't
+	// have the ETags of the contents.
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
 	defer cleanup()
 
-	createBucket(t, s3client, srcbucket)
-	createBucket(t, s3client, dstbucket)
-
-	filesToContent := map[string]string{
-		"sub&@$/test+1.txt":           "this is a test file 1",
-		"sub:,?/test; =2.txt":         "this is a test file 2",
-		"test&@$:,?;= 3.txt":          "this is a test file 3",
-		"sub/this-is-normal-file.txt": "this is a normal file",
-	}
-
-	for filename, content := range filesToContent {
-		putFile(t, s3client, srcbucket, filename, content)
-	}
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "same.txt", "this is the content")
+	putFile(t, s3client, bucket, "changed.txt", "this is the content")
 
-	src := fmt.Sprintf("s3://%v/*", srcbucket)
-	dst := fmt.Sprintf("s3://%v/", dstbucket)
+	// the changed file has the same size.
+	workdir := fs.NewDir(t, t.Name(),
+		fs.WithFile("same.txt", "this is the content"),
+		fs.WithFile("changed.txt", "this is new content"),
+	)
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	dst := "s3://" + bucket
+	cmd := s5cmd("--log=debug", "cp", "--if-content-differ", "*.txt", dst+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
+
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/sub&@$/test+1.txt s3://%v/sub&@$/test+1.txt`, srcbucket, dstbucket),
-		1: equals(`cp s3://%v/sub/this-is-normal-file.txt s3://%v/sub/this-is-normal-file.txt`, srcbucket, dstbucket),
-		2: equals(`cp s3://%v/sub:,?/test; =2.txt s3://%v/sub:,?/test; =2.txt`, srcbucket, dstbucket),
-		3: equals(`cp s3://%v/test&@$:,?;= 3.txt s3://%v/test&@$:,?;= 3.txt`, srcbucket, dstbucket),
+		0: equals(`DEBUG "cp same.txt %v/same.txt": object content matches`, dst),
+		1: equals(`cp changed.txt %v/changed.txt`, dst),
 	}, sortInput(true))
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "same.txt", "this is the content"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "changed.txt", "this is new content"))
 }
 
-// cp s3://bucket/* s3://bucket/prefix/
-func TestCopyMultipleS3ObjectsToS3WithPrefix(t *testing.T) {
+// cp --if-none-match dir/* s3://bucket (bucket has one of the files)
+func TestCopyLocalDirToS3WithIfNoneMatch(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1697,46 +3618,50 @@ func TestCopyMultipleS3ObjectsToS3WithPrefix(t *testing.T) {
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "existing.txt", "this is the content")
 
-	filesToContent := map[string]string{
-		"testfile1.txt":            "this is a test file 1",
-		"readme.md":                "this is a readme file",
-		"b/filename-with-hypen.gz": "file has hypen in its name",
-		"a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
-
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
-
-	src := fmt.Sprintf("s3://%v/*", bucket)
-	dst := fmt.Sprintf("s3://%v/dst/", bucket)
+	workdir := fs.NewDir(t, t.Name(),
+		fs.WithFile("existing.txt", "this is new content"),
+		fs.WithFile("new.txt", "this is new content"),
+	)
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	dst := "s3://" + bucket
+	cmd := s5cmd("--log=debug", "cp", "--if-none-match", "*.txt", dst+"/")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/a/another_test_file.txt %va/another_test_file.txt`, bucket, dst),
-		1: equals(`cp s3://%v/b/filename-with-hypen.gz %vb/filename-with-hypen.gz`, bucket, dst),
-		2: equals(`cp s3://%v/readme.md %vreadme.md`, bucket, dst),
-		3: equals(`cp s3://%v/testfile1.txt %vtestfile1.txt`, bucket, dst),
+		0: equals(`DEBUG "cp existing.txt %v/existing.txt": object already exists`, dst),
+		1: equals(`cp new.txt %v/new.txt`, dst),
 	}, sortInput(true))
 
-	// assert s3 source objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
-	// assert s3 destination objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, "dst/"+filename, content))
-	}
+	// the existing object is not overwritten.
+	assert.Assert(t, ensureS3Object(s3client, bucket, "existing.txt", "this is the content"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "new.txt", "this is new content"))
 }
 
-// cp --flatten s3://bucket/* s3://bucket/prefix/
-func TestFlattenCopyMultipleS3ObjectsToS3WithPrefix(t *testing.T) {
+func TestCopyS3ToLocalWithIfNoneMatch(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("cp", "--if-none-match", "s3://bucket/file.txt", "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp s3://bucket/file.txt dir/": --if-none-match flag can only be used for uploads`),
+	})
+}
+
+// cp -n -u file s3://bucket (bucket/file exists, source is newer)
+func TestCopyLocalFileToS3WithSameFilenameOverrideIfSourceIsNewer(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1744,161 +3669,130 @@ func TestFlattenCopyMultipleS3ObjectsToS3WithPrefix(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	filesToContent := map[string]string{
-		"testfile1.txt":            "this is a test file 1",
-		"readme.md":                "this is a readme file",
-		"b/filename-with-hypen.gz": "file has hypen in its name",
-		"a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	const (
+		filename        = "testfile1.txt"
+		content         = "this is the content"
+		expectedContent = content + "\n"
+	)
 
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file. also uploaded file is newer than
+	// the file on local fs.
+	putFile(t, s3client, bucket, filename, content)
 
-	src := fmt.Sprintf("s3://%v/*", bucket)
-	dst := fmt.Sprintf("s3://%v/dst/", bucket)
+	now := time.Now().UTC()
+	timestamp := fs.WithTimestamps(
+		now.Add(time.Minute), // access time
+		now.Add(time.Minute), // mod time
+	)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, expectedContent, timestamp))
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", "--flatten", src, dst)
-	result := icmd.RunCmd(cmd)
+	dst := "s3://" + bucket
+	cmd := s5cmd("cp", "-n", "-u", filename, dst)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
+	// '-n' prevents overriding the file, but '-u' overrides '-n' if the file
+	// modtime differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/a/another_test_file.txt %vanother_test_file.txt`, bucket, dst),
-		1: equals(`cp s3://%v/b/filename-with-hypen.gz %vfilename-with-hypen.gz`, bucket, dst),
-		2: equals(`cp s3://%v/readme.md %vreadme.md`, bucket, dst),
-		3: equals(`cp s3://%v/testfile1.txt %vtestfile1.txt`, bucket, dst),
-	}, sortInput(true))
-
-	// assert s3 source objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+		0: equals(`cp %v %v/%v`, filename, dst, filename),
+	})
 
-	dstContent := map[string]string{
-		"dst/testfile1.txt":          "this is a test file 1",
-		"dst/readme.md":              "this is a readme file",
-		"dst/filename-with-hypen.gz": "file has hypen in its name",
-		"dst/another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
-	// assert s3 destination objects
-	for key, content := range dstContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, key, content))
-	}
+	assert.NilError(t, ensureS3Object(s3client, bucket, filename, expectedContent))
 }
 
-// cp s3://bucket/* s3://bucket/prefix
-func TestCopyMultipleS3ObjectsToS3WithPrefixWithoutSlash(t *testing.T) {
+// cp -n -u file s3://bucket (bucket/file exists, source is older)
+func TestCopyLocalFileToS3WithSameFilenameDontOverrideIfS3ObjectIsOlder(t *testing.T) {
 	t.Parallel()
 
-	const bucket = "bucket"
+	bucket := s3BucketFromTestName(t)
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	createBucket(t, s3client, bucket)
-
-	filesToContent := map[string]string{
-		"testfile1.txt":            "this is a test file 1",
-		"readme.md":                "this is a readme file",
-		"b/filename-with-hypen.gz": "file has hypen in its name",
-		"a/another_test_file.txt":  "yet another txt file. yatf.",
-	}
+	const (
+		filename        = "testfile1.txt"
+		content         = "this is the content"
+		expectedContent = content + "\n"
+	)
 
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+	createBucket(t, s3client, bucket)
+	// upload a modified version of the file. also uploaded file is newer than
+	// the file on local fs.
+	putFile(t, s3client, bucket, filename, content)
 
-	src := fmt.Sprintf("s3://%v/*", bucket)
-	dst := fmt.Sprintf("s3://%v/dst", bucket)
+	now := time.Now().UTC()
+	timestamp := fs.WithTimestamps(
+		now.Add(-time.Minute), // access time
+		now.Add(-time.Minute), // mod time
+	)
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, expectedContent, timestamp))
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("--log=debug", "cp", "-n", "-u", filename, "s3://"+bucket)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	// '-n' prevents overriding the file, but '-u' overrides '-n' if the file
+	// modtime differs.
+	result.Assert(t, icmd.Success)
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{
-		0: equals(`ERROR "cp %v %v": target %q must be a bucket or a prefix`, src, dst, dst),
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`DEBUG "cp %v s3://%v/%v": object is newer or same age`, filename, bucket, filename),
 	})
 
-	// assert s3 source objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
 
+	assert.NilError(t, ensureS3Object(s3client, bucket, filename, content))
 }
 
-// --json cp s3://bucket/* s3://bucket/prefix/
-func TestCopyMultipleS3ObjectsToS3JSON(t *testing.T) {
+// cp file s3://bucket/
+func TestCopyLocalFileToS3WithFilePermissions(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
-	defer cleanup()
+	const (
+		filename = "testfile1.txt"
+		content  = "this is the content"
+	)
 
-	createBucket(t, s3client, bucket)
+	fileModes := []os.FileMode{0400, 0440, 0444, 0600, 0640, 0644, 0700, 0750, 0755}
 
-	filesToContent := map[string]string{
-		"testfile1.txt": "this is a test file 1",
-		"readme.md":     "this is a readme file",
-	}
+	for _, fileMode := range fileModes {
+		s3client, s5cmd, cleanup := setup(t)
+		defer cleanup()
 
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+		createBucket(t, s3client, bucket)
 
-	src := fmt.Sprintf("s3://%v/*", bucket)
-	dst := fmt.Sprintf("s3://%v/dst/", bucket)
+		workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content, fs.WithMode(fileMode)))
+		defer workdir.Remove()
 
-	cmd := s5cmd("--json", "cp", src, dst)
-	result := icmd.RunCmd(cmd)
+		dstpath := fmt.Sprintf("s3://%v/%v", bucket, filename)
 
-	result.Assert(t, icmd.Success)
+		cmd := s5cmd("cp", filename, dstpath)
+		result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: json(`
-			{
-				"operation": "cp",
-				"success": true,
-				"source": "s3://%v/readme.md",
-				"destination": "s3://%v/dst/readme.md",
-				"object": {
-					"key": "s3://%v/dst/readme.md",
-					"type": "file"
-				}
-			}
-		`, bucket, bucket, bucket),
-		1: json(`
-			{
-				"operation": "cp",
-				"success": true,
-				"source": "s3://%v/testfile1.txt",
-				"destination": "s3://%v/dst/testfile1.txt",
-				"object": {
-					"key": "s3://%v/dst/testfile1.txt",
-					"type": "file"
-				}
-			}
-		`, bucket, bucket, bucket),
-	}, sortInput(true), jsonCheck(true))
+		result.Assert(t, icmd.Success)
 
-	// assert s3 source objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+		assertLines(t, result.Stdout(), map[int]compareFunc{
+			0: equals(`cp %v %v`, filename, dstpath),
+		})
 
-	// assert s3 destination objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, "dst/"+filename, content))
+		// assert local filesystem
+		expected := fs.Expected(t, fs.WithFile(filename, content, fs.WithMode(fileMode)))
+		assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+		// assert s3 object
+		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 	}
 }
 
-// cp -u -s s3://bucket/prefix/* s3://bucket/prefix2/
-func TestCopyMultipleS3ObjectsToS3_Issue70(t *testing.T) {
+// cp file s3://bucket/object
+func TestCopyLocalFileToS3WithCustomName(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1906,42 +3800,37 @@ func TestCopyMultipleS3ObjectsToS3_Issue70(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
+	const (
+		filename = "testfile1.txt"
+		content  = "this is the content"
+	)
+
 	createBucket(t, s3client, bucket)
 
-	filesToContent := map[string]string{
-		"config/.local/folder1/file1.txt": "this is a test file 1",
-		"config/.local/folder2/file2.txt": "this is a test file 2",
-	}
-
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
+	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
+	defer workdir.Remove()
 
-	src := fmt.Sprintf("s3://%v/config/.local/*", bucket)
-	dst := fmt.Sprintf("s3://%v/.local/", bucket)
+	dstpath := fmt.Sprintf("s3://%v/%v", bucket, filename)
 
-	cmd := s5cmd("cp", "-u", "-s", src, dst)
-	result := icmd.RunCmd(cmd)
+	cmd := s5cmd("cp", filename, dstpath)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/config/.local/folder1/file1.txt %vfolder1/file1.txt`, bucket, dst),
-		1: equals(`cp s3://%v/config/.local/folder2/file2.txt %vfolder2/file2.txt`, bucket, dst),
-	}, sortInput(true))
+		0: equals(`cp %v %v`, filename, dstpath),
+	})
 
-	// assert s3 source objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, content))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 
-	// assert s3 destination objects
-	assert.Assert(t, ensureS3Object(s3client, bucket, ".local/folder1/file1.txt", "this is a test file 1"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, ".local/folder2/file2.txt", "this is a test file 2"))
+	// assert s3 object
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 }
 
-// cp s3://bucket/object dir/ (dirobject exists)
-func TestCopyS3ObjectToLocalWithTheSameFilename(t *testing.T) {
+// cp file s3://bucket/prefix/
+func TestCopyLocalFileToS3WithPrefix(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1950,33 +3839,36 @@ func TestCopyS3ObjectToLocalWithTheSameFilename(t *testing.T) {
 	defer cleanup()
 
 	const (
-		filename        = "testfile1.txt"
-		content         = "this is the content"
-		expectedContent = content + "\n"
+		filename = "testfile1.txt"
+		content  = "this is the content"
 	)
 
+	createBucket(t, s3client, bucket)
+
 	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
 	defer workdir.Remove()
 
-	createBucket(t, s3client, bucket)
-	// upload a modified version of the file
-	putFile(t, s3client, bucket, filename, expectedContent)
+	dstpath := fmt.Sprintf("s3://%v/s5cmdtest/", bucket)
 
-	cmd := s5cmd("cp", "s3://"+bucket+"/"+filename, ".")
+	cmd := s5cmd("cp", filename, dstpath)
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals("cp s3://%v/%v %v", bucket, filename, filename),
+		0: equals(`cp %v %v%v`, filename, dstpath, filename),
 	})
 
-	expected := fs.Expected(t, fs.WithFile(filename, expectedContent))
+	// assert local filesystem
+	expected := fs.Expected(t, fs.WithFile(filename, content))
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert s3 object
+	assert.Assert(t, ensureS3Object(s3client, bucket, fmt.Sprintf("s5cmdtest/%v", filename), content))
 }
 
-// -log=debug cp -n s3://bucket/object .
-func TestCopyS3ToLocalWithSameFilenameWithNoClobber(t *testing.T) {
+// cp file s3://bucket
+func TestMultipleLocalFileToS3Bucket(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -1989,110 +3881,219 @@ func TestCopyS3ToLocalWithSameFilenameWithNoClobber(t *testing.T) {
 		content  = "this is the content"
 	)
 
+	createBucket(t, s3client, bucket)
+
 	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
 	defer workdir.Remove()
 
-	createBucket(t, s3client, bucket)
-	// upload a modified version of the file
-	putFile(t, s3client, bucket, filename, content+"\n")
+	dstpath := fmt.Sprintf("s3://%v", bucket)
 
-	cmd := s5cmd("--log=debug", "cp", "-n", "s3://"+bucket+"/"+filename, ".")
+	cmd := s5cmd("cp", filename, dstpath)
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`DEBUG "cp s3://%v/%v %v": object already exists`, bucket, filename, filename),
+		0: equals(`cp %v %v/%v`, filename, dstpath, filename),
 	})
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
-
+	// assert local filesystem
 	expected := fs.Expected(t, fs.WithFile(filename, content))
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert s3 object
+	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 }
 
-// cp -n -s s3://bucket/object dir/
-func TestCopyS3ToLocalWithSameFilenameOverrideIfSizeDiffers(t *testing.T) {
+// cp * s3://bucket/prefix/
+func TestCopyMultipleLocalNestedFilesToS3(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
-
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename        = "testfile1.txt"
-		content         = "this is the content"
-		expectedContent = content + "\n"
-	)
+	const bucket = "bucket"
+	createBucket(t, s3client, bucket)
 
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
+	// nested folder layout
+	//
+	// ├─a
+	// │ ├─readme.md
+	// │ └─file1.txt
+	// └─b
+	//   └─c
+	//     └─file2.txt
+	//
+	// after `s5cmd cp * s3://bucket/prefix/`, expect:
+	//
+	// prefix
+	//  ├─a
+	//  │ ├─readme.md
+	//  │ └─file1.txt
+	//  └─b
+	//    └─c
+	//      └─file2.txt
+
+	folderLayout := []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("file1.txt", "file1"),
+			fs.WithFile("readme.md", "readme"),
+		),
+		fs.WithDir(
+			"b",
+			fs.WithDir(
+				"c",
+				fs.WithFile("file2.txt", "file2"),
+			),
+		),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
 	defer workdir.Remove()
 
-	createBucket(t, s3client, bucket)
-	// upload a modified version of the file
-	putFile(t, s3client, bucket, filename, expectedContent)
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
 
-	cmd := s5cmd("cp", "-n", "-s", "s3://"+bucket+"/"+filename, ".")
+	cmd := s5cmd("cp", "*", dst)
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
-	// size differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/%v %v`, bucket, filename, filename),
-	})
+		0: equals("cp a/file1.txt %va/file1.txt", dst),
+		1: equals("cp a/readme.md %va/readme.md", dst),
+		2: equals("cp b/c/file2.txt %vb/c/file2.txt", dst),
+	}, sortInput(true))
 
-	expected := fs.Expected(t, fs.WithFile(filename, expectedContent))
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+
+	// assert s3 objects
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/readme.md", "readme"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/file1.txt", "file1"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/b/c/file2.txt", "file2"))
 }
 
-// cp -n -u s3://bucket/object dir/ (source is newer)
-func TestCopyS3ToLocalWithSameFilenameOverrideIfSourceIsNewer(t *testing.T) {
+// cp --no-follow-symlinks my_link s3://bucket/prefix/
+func TestCopyLinkToASingleFileWithFollowSymlinkDisabled(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	const bucket = "bucket"
+	createBucket(t, s3client, bucket)
+
+	fileContent := "CAFEBABE"
+	folderLayout := []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("f1.txt", fileContent),
+		),
+		fs.WithDir("b"),
+		fs.WithSymlink("b/my_link", "a/f1.txt"),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	defer workdir.Remove()
+
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	cmd := s5cmd("cp", "--no-follow-symlinks", "b/my_link", dst)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{})
+}
+
+// cp * s3://bucket/prefix/
+func TestCopyWithFollowSymlink(t *testing.T) {
+	t.Parallel()
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename        = "testfile1.txt"
-		content         = "this is the content"
-		expectedContent = content + "\n"
-	)
+	const bucket = "bucket"
+	createBucket(t, s3client, bucket)
 
-	now := time.Now().UTC()
-	timestamp := fs.WithTimestamps(
-		now.Add(-time.Minute), // access time
-		now.Add(-time.Minute), // mod time
-	)
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content, timestamp))
+	fileContent := "CAFEBABE"
+	folderLayout := []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("f1.txt", fileContent),
+		),
+		fs.WithDir("b"),
+		fs.WithDir("c"),
+		fs.WithSymlink("b/link1", "a/f1.txt"),
+		fs.WithSymlink("c/link2", "b/link1"),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
 	defer workdir.Remove()
 
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	cmd := s5cmd("cp", "*", dst)
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("cp a/f1.txt %va/f1.txt", dst),
+		1: equals("cp b/link1 %vb/link1", dst),
+		2: equals("cp c/link2 %vc/link2", dst),
+	}, sortInput(true))
+
+	// assert s3 objects
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/f1.txt", fileContent))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/b/link1", fileContent))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/c/link2", fileContent))
+}
+
+// cp --no-follow-symlinks * s3://bucket/prefix/
+func TestCopyWithNoFollowSymlink(t *testing.T) {
+	t.Parallel()
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	const bucket = "bucket"
 	createBucket(t, s3client, bucket)
-	// upload a modified version of the file. also uploaded file is newer than
-	// the file on local fs.
-	putFile(t, s3client, bucket, filename, expectedContent)
 
-	cmd := s5cmd("cp", "-n", "-u", "s3://"+bucket+"/"+filename, ".")
+	fileContent := "CAFEBABE"
+	folderLayout := []fs.PathOp{
+		fs.WithDir(
+			"a",
+			fs.WithFile("f1.txt", fileContent),
+		),
+		fs.WithDir("b"),
+		fs.WithDir("c"),
+		fs.WithSymlink("b/link1", "a/f1.txt"),
+		fs.WithSymlink("c/link2", "b/link1"),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	defer workdir.Remove()
+
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	cmd := s5cmd("cp", "--no-follow-symlinks", "*", dst)
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
-	// size differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/%v %v`, bucket, filename, filename),
-	})
+		0: equals("cp a/f1.txt %va/f1.txt", dst),
+	}, sortInput(true))
 
-	expected := fs.Expected(t, fs.WithFile(filename, expectedContent))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	// assert s3 objects
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/f1.txt", fileContent))
 }
 
-// cp -n -u s3://bucket/object dir/ (source is older)
-func TestCopyS3ToLocalWithSameFilenameDontOverrideIfS3ObjectIsOlder(t *testing.T) {
+// --dry-run cp dir/ s3://bucket/
+func TestCopyDirToS3DryRun(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -2100,44 +4101,47 @@ func TestCopyS3ToLocalWithSameFilenameDontOverrideIfS3ObjectIsOlder(t *testing.T
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is the content"
-	)
-
 	createBucket(t, s3client, bucket)
-	// upload a modified version of the file.
-	putFile(t, s3client, bucket, filename, content+"\n")
 
-	// file on the fs is newer than the file on s3. expect an 'dont override'
-	// behaviour.
-	now := time.Now().UTC()
-	timestamp := fs.WithTimestamps(
-		now.Add(time.Minute), // access time
-		now.Add(time.Minute), // mod time
-	)
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content, timestamp))
+	folderLayout := []fs.PathOp{
+		fs.WithFile("file1.txt", "content"),
+		fs.WithDir(
+			"c",
+			fs.WithFile("file2.txt", "content"),
+		),
+	}
+
+	workdir := fs.NewDir(t, t.Name(), folderLayout...)
 	defer workdir.Remove()
 
-	cmd := s5cmd("--log=debug", "cp", "-n", "-u", "s3://"+bucket+"/"+filename, ".")
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	srcpath := filepath.ToSlash(workdir.Path())
+	dstpath := fmt.Sprintf("s3://%v/", bucket)
+
+	cmd := s5cmd("--dry-run", "cp", workdir.Path()+"/", dstpath)
+	result := icmd.RunCmd(cmd)
 
-	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
-	// size differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`DEBUG "cp s3://%v/%v %v": object is newer or same age`, bucket, filename, filename),
-	})
+		0: equals(`cp %v/c/file2.txt %vc/file2.txt`, srcpath, dstpath),
+		1: equals(`cp %v/file1.txt %vfile1.txt`, srcpath, dstpath),
+		2: equals("would copy 2 objects, 14"),
+	}, sortInput(true))
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
+	// assert no change in s3
+	objs := []string{"c/file2.txt", "file1.txt"}
+	for _, obj := range objs {
+		err := ensureS3Object(s3client, bucket, obj, "content")
+		assertError(t, err, errS3NoSuchKey)
+	}
 
-	expected := fs.Expected(t, fs.WithFile(filename, content))
+	// assert local filesystem
+	expected := fs.Expected(t, folderLayout...)
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 }
 
-// cp -u -s s3://bucket/prefix/* dir/
-func TestCopyS3ToLocal_Issue70(t *testing.T) {
+// --dry-run cp s3://bucket/* dir/
+func TestCopyS3ToDirDryRun(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -2147,135 +4151,135 @@ func TestCopyS3ToLocal_Issue70(t *testing.T) {
 
 	createBucket(t, s3client, bucket)
 
-	filesToContent := map[string]string{
-		"config/.local/folder1/file1.txt": "this is a test file 1",
-		"config/.local/folder2/file2.txt": "this is a test file 2",
-	}
-
-	for filename, content := range filesToContent {
-		putFile(t, s3client, bucket, filename, content)
-	}
-
-	workdir := fs.NewDir(t, t.Name())
-	defer workdir.Remove()
+	files := [...]string{"c/file2.txt", "file1.txt"}
 
-	srcpath := fmt.Sprintf("s3://%v/config/.local/*", bucket)
-	dstpath := filepath.Join(workdir.Path(), ".local")
+	putFile(t, s3client, bucket, files[0], "content")
+	putFile(t, s3client, bucket, files[1], "content")
 
-	dstpath = filepath.ToSlash(dstpath)
-	cmd := s5cmd("cp", "-u", "-s", srcpath, dstpath)
+	srcpath := fmt.Sprintf("s3://%s", bucket)
 
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	cmd := s5cmd("--dry-run", "cp", srcpath+"/*", "dir/")
+	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp s3://%v/config/.local/folder1/file1.txt %v/folder1/file1.txt`, bucket, dstpath),
-		1: equals(`cp s3://%v/config/.local/folder2/file2.txt %v/folder2/file2.txt`, bucket, dstpath),
+		0: equals("cp %v/c/file2.txt dir/%s", srcpath, files[0]),
+		1: equals("cp %v/file1.txt dir/%s", srcpath, files[1]),
+		2: prefix("would copy 2 objects, "),
 	}, sortInput(true))
 
-	// assert local filesystem
-	expectedFiles := []fs.PathOp{
-		fs.WithDir(
-			".local",
-			fs.WithMode(0755),
-			fs.WithDir("folder1", fs.WithMode(0755), fs.WithFile("file1.txt", "this is a test file 1")),
-			fs.WithDir("folder2", fs.WithMode(0755), fs.WithFile("file2.txt", "this is a test file 2")),
-		),
-	}
-
-	expectedResult := fs.Expected(t, expectedFiles...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expectedResult))
+	// not even outermost directory should be created
+	_, err := os.Stat(cmd.Dir + "/dir")
+	assert.Assert(t, os.IsNotExist(err))
 
-	// assert s3 objects
-	for filename, content := range filesToContent {
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	// assert s3
+	for _, f := range files {
+		assert.Assert(t, ensureS3Object(s3client, bucket, f, "content"))
 	}
 }
 
-// cp file s3://bucket (bucket/file exists)
-func TestCopyLocalFileToS3WithTheSameFilename(t *testing.T) {
+// cp --source-profile profile1 --destination-profile profile2 s3://bucket/object s3://bucket2/object
+func TestCopySingleS3ObjectToS3WithDifferentProfiles(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
+	srcbucket := s3BucketFromTestName(t)
+	dstbucket := "copy-" + srcbucket
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
+	createBucket(t, s3client, srcbucket)
+	createBucket(t, s3client, dstbucket)
+
 	const (
-		filename   = "testfile1.txt"
-		content    = "this is the content"
-		newContent = content + "\n"
+		filename = "testfile1.txt"
+		content  = "this is a file content"
 	)
 
-	createBucket(t, s3client, bucket)
-	putFile(t, s3client, bucket, filename, content)
+	putFile(t, s3client, srcbucket, filename, content)
 
-	// the file to be uploaded is modified
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, newContent))
+	credentials := fmt.Sprintf(`
+[source]
+aws_access_key_id = %v
+aws_secret_access_key = %v
+
+[destination]
+aws_access_key_id = %v
+aws_secret_access_key = %v
+`, defaultAccessKeyID, defaultSecretAccessKey, defaultAccessKeyID, defaultSecretAccessKey)
+
+	workdir := fs.NewDir(t, srcbucket, fs.WithFile("credentials", credentials))
 	defer workdir.Remove()
 
-	dst := "s3://" + bucket
-	cmd := s5cmd("cp", filename, dst)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	src := fmt.Sprintf("s3://%v/%v", srcbucket, filename)
+	dst := fmt.Sprintf("s3://%v/%v", dstbucket, filename)
+
+	cmd := s5cmd("cp", "--source-profile", "source", "--destination-profile", "destination", src, dst)
+	cmd.Env = append(cmd.Env, "AWS_SHARED_CREDENTIALS_FILE="+workdir.Join("credentials"))
+	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v/%v`, filename, dst, filename),
+		0: equals(`cp %v %v`, src, dst),
 	})
 
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, newContent))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(s3client, srcbucket, filename, content))
 
-	// expect s3 object to be updated with new content
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, newContent))
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(s3client, dstbucket, filename, content))
 }
 
-// -log=debug cp -n file s3://bucket (bucket/file exists)
-func TestCopyLocalFileToS3WithSameFilenameWithNoClobber(t *testing.T) {
+// cp --destination-endpoint-url <endpoint> s3://bucket/object s3://bucket2/object
+func TestCopySingleS3ObjectToS3WithDifferentEndpoints(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
+	srcclient, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
+	dstendpoint, _, dstcleanup := server(t, "bolt")
+	defer dstcleanup()
+
+	dstclient := s3client(t, storage.Options{
+		Endpoint:    dstendpoint,
+		NoVerifySSL: true,
+	})
+
+	createBucket(t, srcclient, bucket)
+	createBucket(t, dstclient, bucket)
+
 	const (
-		filename   = "testfile1.txt"
-		content    = "this is the content"
-		newContent = content + "\n"
+		filename = "testfile1.txt"
+		content  = "this is a file content"
 	)
 
-	createBucket(t, s3client, bucket)
-	putFile(t, s3client, bucket, filename, content)
+	putFile(t, srcclient, bucket, filename, content)
 
-	// the file to be uploaded is modified
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, newContent))
-	defer workdir.Remove()
+	src := fmt.Sprintf("s3://%v/%v", bucket, filename)
+	dst := fmt.Sprintf("s3://%v/%v", bucket, filename)
 
-	cmd := s5cmd("--log=debug", "cp", "-n", filename, "s3://"+bucket)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	cmd := s5cmd("cp", "--destination-endpoint-url", dstendpoint, src, dst)
+	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`DEBUG "cp %v s3://%v/%v": object already exists`, filename, bucket, filename),
+		0: equals(`cp %v %v`, src, dst),
 	})
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
-
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, newContent))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	// assert s3 source object
+	assert.Assert(t, ensureS3Object(srcclient, bucket, filename, content))
 
-	// expect s3 object is not overridden
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	// assert s3 destination object
+	assert.Assert(t, ensureS3Object(dstclient, bucket, filename, content))
 }
 
-// cp -n file s3://bucket
-func TestCopyLocalFileToS3WithNoClobber(t *testing.T) {
+// cp https://host/file s3://bucket/prefix/
+func TestCopyHTTPObjectToS3Prefix(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -2283,40 +4287,80 @@ func TestCopyLocalFileToS3WithNoClobber(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename   = "testfile1.txt"
-		content    = "this is the content"
-		newContent = content + "\n"
-	)
-
 	createBucket(t, s3client, bucket)
 
-	// the file to be uploaded is modified
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, newContent))
-	defer workdir.Remove()
+	const content = `{"this is": "a public dataset"}`
 
-	dst := "s3://" + bucket
-	cmd := s5cmd("cp", "-n", filename, dst)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+
+	src := server.URL + "/datasets/data.json"
+	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v/%v`, filename, dst, filename),
+		0: equals(`cp %v %vdata.json`, src, dst),
 	})
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/data.json", content))
+}
 
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, newContent))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+// cp https://host/file?query s3://bucket/object
+func TestCopyHTTPObjectToS3Object(t *testing.T) {
+	t.Parallel()
 
-	// expect s3 object is not overridden
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, newContent))
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	const content = "this is a file content"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("signature") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+
+	src := server.URL + "/download?signature=secret"
+	dst := fmt.Sprintf("s3://%v/object.txt", bucket)
+
+	cmd := s5cmd("--json", "cp", src, dst)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: json(`
+			{
+				"operation": "cp",
+				"success": true,
+				"source": "%v",
+				"destination": "%v",
+				"object": {
+					"type": "file",
+					"size": 22
+				}
+			}
+		`, src, dst),
+	}, jsonCheck(true))
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "object.txt", content))
 }
 
-// cp -n -s file s3://bucket (bucket/file exists)
-func TestCopyLocalFileToS3WithSameFilenameOverrideIfSizeDiffers(t *testing.T) {
+// cp https://host/missing s3://bucket/
+func TestCopyMissingHTTPObjectToS3(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -2324,38 +4368,29 @@ func TestCopyLocalFileToS3WithSameFilenameOverrideIfSizeDiffers(t *testing.T) {
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename        = "testfile1.txt"
-		content         = "this is the content"
-		expectedContent = content + "\n"
-	)
+	createBucket(t, s3client, bucket)
 
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, expectedContent))
-	defer workdir.Remove()
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
 
-	createBucket(t, s3client, bucket)
-	// upload a modified version of the file
-	putFile(t, s3client, bucket, filename, content)
+	src := server.URL + "/missing.txt"
+	dst := fmt.Sprintf("s3://%v/", bucket)
 
-	dst := "s3://" + bucket
-	cmd := s5cmd("cp", "-n", "-s", filename, dst)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	cmd := s5cmd("cp", src, dst)
+	result := icmd.RunCmd(cmd)
 
-	// '-n' prevents overriding the file, but '-s' overrides '-n' if the file
-	// size differs.
-	result.Assert(t, icmd.Success)
+	result.Assert(t, icmd.Expected{ExitCode: 1})
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v/%v`, filename, dst, filename),
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp %v %vmissing.txt": GET %v: 404 Not Found`, src, dst, src),
 	})
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
-
-	assert.NilError(t, ensureS3Object(s3client, bucket, filename, expectedContent))
+	err := ensureS3Object(s3client, bucket, "missing.txt", "")
+	assertError(t, err, errS3NoSuchKey)
 }
 
-// cp -n -u file s3://bucket (bucket/file exists, source is newer)
-func TestCopyLocalFileToS3WithSameFilenameOverrideIfSourceIsNewer(t *testing.T) {
+// --job-timeout 500ms cp https://host/file s3://bucket/object
+func TestCopyHTTPObjectWithJobTimeoutRetried(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -2363,44 +4398,38 @@ func TestCopyLocalFileToS3WithSameFilenameOverrideIfSourceIsNewer(t *testing.T)
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename        = "testfile1.txt"
-		content         = "this is the content"
-		expectedContent = content + "\n"
-	)
-
 	createBucket(t, s3client, bucket)
-	// upload a modified version of the file. also uploaded file is newer than
-	// the file on local fs.
-	putFile(t, s3client, bucket, filename, content)
 
-	now := time.Now().UTC()
-	timestamp := fs.WithTimestamps(
-		now.Add(time.Minute), // access time
-		now.Add(time.Minute), // mod time
-	)
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, expectedContent, timestamp))
-	defer workdir.Remove()
+	const content = "this is a file content"
 
-	dst := "s3://" + bucket
-	cmd := s5cmd("cp", "-n", "-u", filename, dst)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first request is stuck until the client gives up.
+		if atomic.AddInt32(&requests, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+
+	src := server.URL + "/file.txt"
+	dst := fmt.Sprintf("s3://%v/object.txt", bucket)
+
+	cmd := s5cmd("--job-timeout", "500ms", "cp", src, dst)
+	result := icmd.RunCmd(cmd)
 
-	// '-n' prevents overriding the file, but '-u' overrides '-n' if the file
-	// modtime differs.
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v/%v`, filename, dst, filename),
+		0: equals(`cp %v %v`, src, dst),
 	})
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
-
-	assert.NilError(t, ensureS3Object(s3client, bucket, filename, expectedContent))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "object.txt", content))
 }
 
-// cp -n -u file s3://bucket (bucket/file exists, source is older)
-func TestCopyLocalFileToS3WithSameFilenameDontOverrideIfS3ObjectIsOlder(t *testing.T) {
+// --job-timeout 500ms -r 0 cp https://host/file s3://bucket/object
+func TestCopyHTTPObjectWithJobTimeoutExceeded(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
@@ -2408,465 +4437,562 @@ func TestCopyLocalFileToS3WithSameFilenameDontOverrideIfS3ObjectIsOlder(t *testi
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename        = "testfile1.txt"
-		content         = "this is the content"
-		expectedContent = content + "\n"
-	)
-
 	createBucket(t, s3client, bucket)
-	// upload a modified version of the file. also uploaded file is newer than
-	// the file on local fs.
-	putFile(t, s3client, bucket, filename, content)
 
-	now := time.Now().UTC()
-	timestamp := fs.WithTimestamps(
-		now.Add(-time.Minute), // access time
-		now.Add(-time.Minute), // mod time
-	)
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, expectedContent, timestamp))
-	defer workdir.Remove()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
 
-	cmd := s5cmd("--log=debug", "cp", "-n", "-u", filename, "s3://"+bucket)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	src := server.URL + "/file.txt"
+	dst := fmt.Sprintf("s3://%v/object.txt", bucket)
 
-	// '-n' prevents overriding the file, but '-u' overrides '-n' if the file
-	// modtime differs.
-	result.Assert(t, icmd.Success)
+	cmd := s5cmd("--job-timeout", "500ms", "-r", "0", "cp", src, dst)
+	result := icmd.RunCmd(cmd)
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`DEBUG "cp %v s3://%v/%v": object is newer or same age`, filename, bucket, filename),
-	})
+	result.Assert(t, icmd.Expected{ExitCode: 1})
 
-	assertLines(t, result.Stderr(), map[int]compareFunc{})
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp %v %v": job timed out after 500ms: context deadline exceeded`, src, dst),
+	})
 
-	assert.NilError(t, ensureS3Object(s3client, bucket, filename, content))
+	err := ensureS3Object(s3client, bucket, "object.txt", "")
+	assertError(t, err, errS3NoSuchKey)
 }
 
-// cp file s3://bucket/
-func TestCopyLocalFileToS3WithFilePermissions(t *testing.T) {
+func TestCopyHTTPObjectInvalidDestination(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
+	testcases := []struct {
+		name          string
+		command       string
+		dst           string
+		expectedError string
+	}{
+		{
+			name:          "local_destination",
+			command:       "cp",
+			dst:           "dir/",
+			expectedError: "http sources can only be copied to a remote destination",
+		},
+		{
+			name:          "move",
+			command:       "mv",
+			dst:           "s3://bucket/",
+			expectedError: "http sources can not be moved",
+		},
+	}
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is the content"
-	)
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	fileModes := []os.FileMode{0400, 0440, 0444, 0600, 0640, 0644, 0700, 0750, 0755}
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
 
-	for _, fileMode := range fileModes {
-		s3client, s5cmd, cleanup := setup(t)
-		defer cleanup()
+			src := "https://example.com/file.txt"
 
-		createBucket(t, s3client, bucket)
+			cmd := s5cmd(tc.command, src, tc.dst)
+			result := icmd.RunCmd(cmd)
 
-		workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content, fs.WithMode(fileMode)))
-		defer workdir.Remove()
+			result.Assert(t, icmd.Expected{ExitCode: 2})
 
-		dstpath := fmt.Sprintf("s3://%v/%v", bucket, filename)
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: equals(`ERROR "%v %v %v": %v`, tc.command, src, tc.dst, tc.expectedError),
+			})
+		})
+	}
+}
 
-		cmd := s5cmd("cp", filename, dstpath)
-		result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+func TestCopyConflictingSymlinkFlags(t *testing.T) {
+	t.Parallel()
 
-		result.Assert(t, icmd.Success)
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
 
-		assertLines(t, result.Stdout(), map[int]compareFunc{
-			0: equals(`cp %v %v`, filename, dstpath),
-		})
+	cmd := s5cmd("cp", "--no-follow-symlinks", "--preserve-symlinks", "dir/", "s3://bucket/")
+	result := icmd.RunCmd(cmd)
 
-		// assert local filesystem
-		expected := fs.Expected(t, fs.WithFile(filename, content, fs.WithMode(fileMode)))
-		assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
-		// assert s3 object
-		assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
-	}
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp dir/ s3://bucket/": --no-follow-symlinks and --preserve-symlinks flags can not be used together`),
+	})
 }
 
-// cp file s3://bucket/object
-func TestCopyLocalFileToS3WithCustomName(t *testing.T) {
+// cp --scheduling largest-first s3://bucket/prefix/* dir/
+func TestCopyS3ObjectsToLocalWithScheduling(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
 	defer cleanup()
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is the content"
-	)
-
 	createBucket(t, s3client, bucket)
 
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
-	defer workdir.Remove()
+	putFile(t, s3client, bucket, "prefix/small.txt", "small")
+	putFile(t, s3client, bucket, "prefix/large.txt", "a larger file")
 
-	dstpath := fmt.Sprintf("s3://%v/%v", bucket, filename)
+	workdir := fs.NewDir(t, bucket)
+	defer workdir.Remove()
 
-	cmd := s5cmd("cp", filename, dstpath)
+	cmd := s5cmd("cp", "--scheduling", "largest-first", "s3://"+bucket+"/prefix/*", "dir/")
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v`, filename, dstpath),
-	})
+		0: equals(`cp s3://%v/prefix/large.txt dir/large.txt`, bucket),
+		1: equals(`cp s3://%v/prefix/small.txt dir/small.txt`, bucket),
+	}, sortInput(true))
 
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, content))
+	expected := fs.Expected(t,
+		fs.WithDir("dir",
+			fs.WithFile("large.txt", "a larger file", fs.WithMode(0644)),
+			fs.WithFile("small.txt", "small", fs.WithMode(0644)),
+		),
+	)
 	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert s3 object
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
 }
 
-// cp file s3://bucket/prefix/
-func TestCopyLocalFileToS3WithPrefix(t *testing.T) {
+func TestCopyWithUnknownScheduling(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
 
-	s3client, s5cmd, cleanup := setup(t)
+	_, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is the content"
-	)
-
-	createBucket(t, s3client, bucket)
-
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
-	defer workdir.Remove()
-
-	dstpath := fmt.Sprintf("s3://%v/s5cmdtest/", bucket)
-
-	cmd := s5cmd("cp", filename, dstpath)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	cmd := s5cmd("cp", "--scheduling", "random", "s3://"+bucket+"/prefix/*", "dir/")
+	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Success)
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v%v`, filename, dstpath, filename),
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp s3://%v/prefix/* dir/": unknown scheduling policy "random", expected one of fifo, smallest-first or largest-first`, bucket),
 	})
-
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, content))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert s3 object
-	assert.Assert(t, ensureS3Object(s3client, bucket, fmt.Sprintf("s5cmdtest/%v", filename), content))
 }
 
-// cp file s3://bucket
-func TestMultipleLocalFileToS3Bucket(t *testing.T) {
+// cp file s3://bucket/prefix/ s3://bucket2/
+func TestCopySingleLocalFileToMultipleS3Destinations(t *testing.T) {
 	t.Parallel()
 
 	bucket := s3BucketFromTestName(t)
+	bucket2 := bucket + "-2"
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const (
-		filename = "testfile1.txt"
-		content  = "this is the content"
-	)
-
 	createBucket(t, s3client, bucket)
+	createBucket(t, s3client, bucket2)
 
-	workdir := fs.NewDir(t, t.Name(), fs.WithFile(filename, content))
+	const content = "this is a file content"
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", content))
 	defer workdir.Remove()
 
-	dstpath := fmt.Sprintf("s3://%v", bucket)
-
-	cmd := s5cmd("cp", filename, dstpath)
+	cmd := s5cmd("cp", "file.txt", "s3://"+bucket+"/prefix/", "s3://"+bucket2+"/")
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v %v/%v`, filename, dstpath, filename),
-	})
-
-	// assert local filesystem
-	expected := fs.Expected(t, fs.WithFile(filename, content))
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+		0: equals(`cp file.txt s3://%v/file.txt`, bucket2),
+		1: equals(`cp file.txt s3://%v/prefix/file.txt`, bucket),
+	}, sortInput(true))
 
-	// assert s3 object
-	assert.Assert(t, ensureS3Object(s3client, bucket, filename, content))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/file.txt", content))
+	assert.Assert(t, ensureS3Object(s3client, bucket2, "file.txt", content))
 }
 
-// cp * s3://bucket/prefix/
-func TestCopyMultipleLocalNestedFilesToS3(t *testing.T) {
+// cp dir/ s3://bucket/ s3://bucket2/
+func TestCopyLocalDirToMultipleS3Destinations(t *testing.T) {
 	t.Parallel()
 
+	bucket := s3BucketFromTestName(t)
+	bucket2 := bucket + "-2"
+
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const bucket = "bucket"
 	createBucket(t, s3client, bucket)
-
-	// nested folder layout
-	//
-	// ├─a
-	// │ ├─readme.md
-	// │ └─file1.txt
-	// └─b
-	//   └─c
-	//     └─file2.txt
-	//
-	// after `s5cmd cp * s3://bucket/prefix/`, expect:
-	//
-	// prefix
-	//  ├─a
-	//  │ ├─readme.md
-	//  │ └─file1.txt
-	//  └─b
-	//    └─c
-	//      └─file2.txt
-
-	folderLayout := []fs.PathOp{
-		fs.WithDir(
-			"a",
-			fs.WithFile("file1.txt", "file1"),
-			fs.WithFile("readme.md", "readme"),
-		),
-		fs.WithDir(
-			"b",
-			fs.WithDir(
-				"c",
-				fs.WithFile("file2.txt", "file2"),
-			),
+	createBucket(t, s3client, bucket2)
+
+	// larger than the chunks of the fan-out, so the uploads read the file
+	// together.
+	large := strings.Repeat("s5cmd", 100*1024)
+	workdir := fs.NewDir(t, bucket,
+		fs.WithDir("dir",
+			fs.WithFile("a.txt", "content a"),
+			fs.WithFile("large.txt", large),
 		),
-	}
-
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	)
 	defer workdir.Remove()
 
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
-
-	cmd := s5cmd("cp", "*", dst)
+	cmd := s5cmd("cp", "dir/", "s3://"+bucket+"/", "s3://"+bucket2+"/")
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals("cp a/file1.txt %va/file1.txt", dst),
-		1: equals("cp a/readme.md %va/readme.md", dst),
-		2: equals("cp b/c/file2.txt %vb/c/file2.txt", dst),
+		0: equals(`cp dir/a.txt s3://%v/a.txt`, bucket2),
+		1: equals(`cp dir/a.txt s3://%v/a.txt`, bucket),
+		2: equals(`cp dir/large.txt s3://%v/large.txt`, bucket2),
+		3: equals(`cp dir/large.txt s3://%v/large.txt`, bucket),
 	}, sortInput(true))
-
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
-
-	// assert s3 objects
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/readme.md", "readme"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/file1.txt", "file1"))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/b/c/file2.txt", "file2"))
+
+	for _, b := range []string{bucket, bucket2} {
+		assert.Assert(t, ensureS3Object(s3client, b, "a.txt", "content a"))
+		assert.Assert(t, ensureS3Object(s3client, b, "large.txt", large))
+	}
 }
 
-// cp --no-follow-symlinks my_link s3://bucket/prefix/
-func TestCopyLinkToASingleFileWithFollowSymlinkDisabled(t *testing.T) {
+// cp file s3://non-existent-bucket/ s3://bucket/
+func TestCopySingleLocalFileToMultipleS3DestinationsWithFailedDestination(t *testing.T) {
 	t.Parallel()
 
+	bucket := s3BucketFromTestName(t)
+
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const bucket = "bucket"
 	createBucket(t, s3client, bucket)
 
-	fileContent := "CAFEBABE"
-	folderLayout := []fs.PathOp{
-		fs.WithDir(
-			"a",
-			fs.WithFile("f1.txt", fileContent),
-		),
-		fs.WithDir("b"),
-		fs.WithSymlink("b/my_link", "a/f1.txt"),
-	}
-
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	const content = "this is a file content"
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", content))
 	defer workdir.Remove()
 
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
-
-	cmd := s5cmd("cp", "--no-follow-symlinks", "b/my_link", dst)
+	cmd := s5cmd("cp", "file.txt", "s3://non-existent-bucket/", "s3://"+bucket+"/")
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	result.Assert(t, icmd.Success)
+	result.Assert(t, icmd.Expected{ExitCode: 1})
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{})
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp file.txt s3://%v/file.txt`, bucket),
+	})
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: prefix(`ERROR "cp file.txt s3://non-existent-bucket/file.txt"`),
+	})
+
+	// the failed destination doesn't stop the others.
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file.txt", content))
 }
 
-// cp * s3://bucket/prefix/
-func TestCopyWithFollowSymlink(t *testing.T) {
+// cp --destinations-file destinations.txt s3://bucket/prefix/* s3://bucket2/
+func TestCopyMultipleS3ObjectsToDestinationsFile(t *testing.T) {
 	t.Parallel()
 
+	bucket := s3BucketFromTestName(t)
+	bucket2 := bucket + "-2"
+
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
-	const bucket = "bucket"
 	createBucket(t, s3client, bucket)
+	createBucket(t, s3client, bucket2)
 
-	fileContent := "CAFEBABE"
-	folderLayout := []fs.PathOp{
-		fs.WithDir(
-			"a",
-			fs.WithFile("f1.txt", fileContent),
-		),
-		fs.WithDir("b"),
-		fs.WithDir("c"),
-		fs.WithSymlink("b/link1", "a/f1.txt"),
-		fs.WithSymlink("c/link2", "b/link1"),
-	}
+	putFile(t, s3client, bucket, "prefix/a.txt", "content a")
+	putFile(t, s3client, bucket, "prefix/b.txt", "content b")
 
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
+	workdir := fs.NewDir(t, bucket, fs.WithFile("destinations.txt", "backup/\n\n  "))
 	defer workdir.Remove()
 
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
-
-	cmd := s5cmd("cp", "*", dst)
+	cmd := s5cmd(
+		"--aws-debug", "http",
+		"cp",
+		"--destinations-file", "destinations.txt",
+		"s3://"+bucket+"/prefix/*",
+		"s3://"+bucket2+"/",
+	)
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
 	result.Assert(t, icmd.Success)
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals("cp a/f1.txt %va/f1.txt", dst),
-		1: equals("cp b/link1 %vb/link1", dst),
-		2: equals("cp c/link2 %vc/link2", dst),
-	}, sortInput(true))
+	// the source is listed once for all of the destinations.
+	out := result.Stdout()
+	assert.Equal(t, strings.Count(out, "DEBUG Request s3/ListObjectsV2 Details:"), 1, out)
 
-	// assert s3 objects
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/f1.txt", fileContent))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/b/link1", fileContent))
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/c/link2", fileContent))
+	assert.Assert(t, ensureS3Object(s3client, bucket2, "a.txt", "content a"))
+	assert.Assert(t, ensureS3Object(s3client, bucket2, "b.txt", "content b"))
+
+	expected := fs.Expected(t,
+		fs.WithFile("destinations.txt", "backup/\n\n  "),
+		fs.WithDir("backup",
+			fs.WithFile("a.txt", "content a", fs.WithMode(0644)),
+			fs.WithFile("b.txt", "content b", fs.WithMode(0644)),
+		),
+	)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
 }
 
-// cp --no-follow-symlinks * s3://bucket/prefix/
-func TestCopyWithNoFollowSymlink(t *testing.T) {
+func TestCopyToMultipleDestinationsWithInvalidFlags(t *testing.T) {
 	t.Parallel()
 
-	s3client, s5cmd, cleanup := setup(t)
-	defer cleanup()
+	testcases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "move",
+			args:          []string{"mv", "s3://bucket/a", "s3://bucket2/", "s3://bucket3/"},
+			expectedError: "objects can not be moved to multiple destinations",
+		},
+		{
+			name:          "journal",
+			args:          []string{"cp", "--journal", "journal.txt", "s3://bucket/a", "s3://bucket2/", "s3://bucket3/"},
+			expectedError: "--journal flag can not be used with multiple destinations",
+		},
+		{
+			name:          "wildcard_destination",
+			args:          []string{"cp", "s3://bucket/a", "s3://bucket2/", "s3://bucket3/*"},
+			expectedError: `target "s3://bucket3/*" can not contain glob characters`,
+		},
+	}
 
-	const bucket = "bucket"
-	createBucket(t, s3client, bucket)
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	fileContent := "CAFEBABE"
-	folderLayout := []fs.PathOp{
-		fs.WithDir(
-			"a",
-			fs.WithFile("f1.txt", fileContent),
-		),
-		fs.WithDir("b"),
-		fs.WithDir("c"),
-		fs.WithSymlink("b/link1", "a/f1.txt"),
-		fs.WithSymlink("c/link2", "b/link1"),
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd(tc.args...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: contains("%v", tc.expectedError),
+			})
+		})
 	}
+}
 
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
-	defer workdir.Remove()
+func TestCopySingleS3ObjectToLocalWithRange(t *testing.T) {
+	t.Parallel()
 
-	dst := fmt.Sprintf("s3://%v/prefix/", bucket)
+	const (
+		bucket      = "bucket"
+		filename    = "file.txt"
+		fileContent = "0123456789abcdefghij"
+	)
 
-	cmd := s5cmd("cp", "--no-follow-symlinks", "*", dst)
-	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	testcases := []struct {
+		name      string
+		byteRange string
+		expected  string
+	}{
+		{name: "start_end", byteRange: "0-9", expected: "0123456789"},
+		{name: "start", byteRange: "15-", expected: "fghij"},
+		{name: "suffix", byteRange: "-3", expected: "hij"},
+	}
 
-	result.Assert(t, icmd.Success)
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals("cp a/f1.txt %va/f1.txt", dst),
-	}, sortInput(true))
+			s3client, s5cmd, cleanup := setup(t)
+			defer cleanup()
 
-	// assert s3 objects
-	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/a/f1.txt", fileContent))
+			createBucket(t, s3client, bucket)
+			putFile(t, s3client, bucket, filename, fileContent)
+
+			cmd := s5cmd("cp", "--range", tc.byteRange, "s3://"+bucket+"/"+filename, "slice.txt")
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Success)
+
+			assertLines(t, result.Stdout(), map[int]compareFunc{
+				0: equals(`cp s3://%v/%v slice.txt`, bucket, filename),
+			})
+
+			expected := fs.Expected(t, fs.WithFile("slice.txt", tc.expected, fs.WithMode(0644)))
+			assert.Assert(t, fs.Equal(cmd.Dir, expected))
+
+			// the object is not modified.
+			assert.Assert(t, ensureS3Object(s3client, bucket, filename, fileContent))
+		})
+	}
 }
 
-// --dry-run cp dir/ s3://bucket/
-func TestCopyDirToS3DryRun(t *testing.T) {
+func TestCopyWithInvalidRange(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
+	testcases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "malformed",
+			args:          []string{"cp", "--range", "10", "s3://bucket/a", "."},
+			expectedError: `invalid --range "10", expected start-end, start- or -suffix`,
+		},
+		{
+			name:          "reversed",
+			args:          []string{"cp", "--range", "10-5", "s3://bucket/a", "."},
+			expectedError: `invalid --range "10-5", expected start-end, start- or -suffix`,
+		},
+		{
+			name:          "upload",
+			args:          []string{"cp", "--range", "0-10", "a.txt", "s3://bucket/"},
+			expectedError: "--range flag can only be used for downloads",
+		},
+		{
+			name:          "s3_copy",
+			args:          []string{"cp", "--range", "0-10", "s3://bucket/a", "s3://bucket2/"},
+			expectedError: "--range flag can only be used for downloads",
+		},
+		{
+			name:          "move",
+			args:          []string{"mv", "--range", "0-10", "s3://bucket/a", "."},
+			expectedError: "--range flag can not be used with mv command",
+		},
+	}
 
-	s3client, s5cmd, cleanup := setup(t)
-	defer cleanup()
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
 
-	createBucket(t, s3client, bucket)
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
 
-	folderLayout := []fs.PathOp{
-		fs.WithFile("file1.txt", "content"),
-		fs.WithDir(
-			"c",
-			fs.WithFile("file2.txt", "content"),
-		),
+			cmd := s5cmd(tc.args...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: contains("%v", tc.expectedError),
+			})
+		})
 	}
+}
 
-	workdir := fs.NewDir(t, t.Name(), folderLayout...)
-	defer workdir.Remove()
+func TestCopyS3ObjectsWithExec(t *testing.T) {
+	t.Parallel()
 
-	srcpath := filepath.ToSlash(workdir.Path())
-	dstpath := fmt.Sprintf("s3://%v/", bucket)
+	const bucket = "bucket"
 
-	cmd := s5cmd("--dry-run", "cp", workdir.Path()+"/", dstpath)
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "logs/a.txt", "hello\n")
+	putFile(t, s3client, bucket, "logs/b.txt", "world\n")
+
+	cmd := s5cmd("cp", "--exec", `echo "$S5CMD_SOURCE: $(cat)"`, "s3://"+bucket+"/logs/*")
 	result := icmd.RunCmd(cmd)
 
 	result.Assert(t, icmd.Success)
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals(`cp %v/c/file2.txt %vc/file2.txt`, srcpath, dstpath),
-		1: equals(`cp %v/file1.txt %vfile1.txt`, srcpath, dstpath),
+		0: equals(`cp s3://%v/logs/a.txt`, bucket),
+		1: equals(`cp s3://%v/logs/b.txt`, bucket),
+		2: equals(`s3://%v/logs/a.txt: hello`, bucket),
+		3: equals(`s3://%v/logs/b.txt: world`, bucket),
 	}, sortInput(true))
 
-	// assert no change in s3
-	objs := []string{"c/file2.txt", "file1.txt"}
-	for _, obj := range objs {
-		err := ensureS3Object(s3client, bucket, obj, "content")
-		assertError(t, err, errS3NoSuchKey)
-	}
-
-	// assert local filesystem
-	expected := fs.Expected(t, folderLayout...)
-	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+	// the objects are not written to the disk.
+	assert.Assert(t, fs.Equal(cmd.Dir, fs.Expected(t)))
 }
 
-// --dry-run cp s3://bucket/* dir/
-func TestCopyS3ToDirDryRun(t *testing.T) {
+func TestCopyS3ObjectWithExecAndRange(t *testing.T) {
 	t.Parallel()
 
-	bucket := s3BucketFromTestName(t)
+	const bucket = "bucket"
 
 	s3client, s5cmd, cleanup := setup(t)
 	defer cleanup()
 
 	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "0123456789")
 
-	files := [...]string{"c/file2.txt", "file1.txt"}
+	cmd := s5cmd("--log", "error", "cp", "--range", "2-4", "--exec", "cat", "s3://"+bucket+"/file.txt")
+	result := icmd.RunCmd(cmd)
 
-	putFile(t, s3client, bucket, files[0], "content")
-	putFile(t, s3client, bucket, files[1], "content")
+	result.Assert(t, icmd.Success)
+	assert.Equal(t, result.Stdout(), "234")
+}
 
-	srcpath := fmt.Sprintf("s3://%s", bucket)
+func TestCopyS3ObjectWithFailingExec(t *testing.T) {
+	t.Parallel()
 
-	cmd := s5cmd("--dry-run", "cp", srcpath+"/*", "dir/")
+	const bucket = "bucket"
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "a.txt", "content a")
+	putFile(t, s3client, bucket, "b.txt", "content b")
+
+	cmd := s5cmd("cp", "--exec", `grep -q "content a"`, "s3://"+bucket+"/*")
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Success)
+	result.Assert(t, icmd.Expected{ExitCode: 1})
 
 	assertLines(t, result.Stdout(), map[int]compareFunc{
-		0: equals("cp %v/c/file2.txt dir/%s", srcpath, files[0]),
-		1: equals("cp %v/file1.txt dir/%s", srcpath, files[1]),
-	}, sortInput(true))
+		0: equals(`cp s3://%v/a.txt`, bucket),
+	})
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp s3://%v/b.txt": command "grep -q \"content a\"" failed: exit status 1`, bucket),
+	})
+}
 
-	// not even outermost directory should be created
-	_, err := os.Stat(cmd.Dir + "/dir")
-	assert.Assert(t, os.IsNotExist(err))
+func TestCopyWithInvalidExec(t *testing.T) {
+	t.Parallel()
 
-	// assert s3
-	for _, f := range files {
-		assert.Assert(t, ensureS3Object(s3client, bucket, f, "content"))
+	testcases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "destination",
+			args:          []string{"cp", "--exec", "cat", "s3://bucket/a", "."},
+			expectedError: "destination can not be given with --exec flag",
+		},
+		{
+			name:          "local_source",
+			args:          []string{"cp", "--exec", "cat", "a.txt"},
+			expectedError: "--exec flag can only be used with remote sources",
+		},
+		{
+			name:          "prefix_source",
+			args:          []string{"cp", "--exec", "cat", "s3://bucket/prefix/"},
+			expectedError: "source argument must contain wildcard character",
+		},
+		{
+			name:          "move",
+			args:          []string{"mv", "--exec", "cat", "s3://bucket/a"},
+			expectedError: "--exec flag can not be used with mv command",
+		},
+		{
+			name:          "manifest",
+			args:          []string{"cp", "--exec", "cat", "--manifest", "manifest.csv", "s3://bucket/a"},
+			expectedError: "--exec and --manifest flags can not be used together",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			cmd := s5cmd(tc.args...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Expected{ExitCode: 2})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: contains("%v", tc.expectedError),
+			})
+		})
 	}
 }
@@ -183,3 +183,148 @@ func TestDiskUsageMissingObject(t *testing.T) {
 		0: suffix(`0 bytes in 0 objects: s3://%v/non-existent-file`, bucket),
 	})
 }
+
+func TestDiskUsageWildcardWithCSVFormat(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "testfile1.txt", "this is a file content")
+	putFile(t, s3client, bucket, "testfile2.txt", "this is also a file content")
+	putFile(t, s3client, bucket, "bar/testfile3.gz", "this is also a file content somehow")
+
+	cmd := s5cmd("du", "--format", "csv", "s3://"+bucket+"/*.txt")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`Source,StorageClass,Count,Size`),
+		1: equals(`s3://%v/*.txt,,2,639`, bucket),
+	})
+}
+
+func TestDiskUsageWildcardGroupedWithTableFormat(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "testfile1.txt", "this is a file content")
+	putFile(t, s3client, bucket, "testfile2.txt", "this is also a file content")
+
+	cmd := s5cmd("du", "--group", "--format", "table", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`Source StorageClass Count Size`),
+		// storage class is not reported by the test server
+		1: equals(`s3://%v/* 2 639`, bucket),
+	})
+}
+
+func TestDiskUsageGroupedByPrefix(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "testfile1.txt", "1")
+	putFile(t, s3client, bucket, "a/testfile2.txt", "22")
+	putFile(t, s3client, bucket, "a/b/testfile3.txt", "333")
+	putFile(t, s3client, bucket, "a/c/testfile4.txt", "4444")
+	putFile(t, s3client, bucket, "d/testfile5.txt", "55555")
+
+	testcases := []struct {
+		name     string
+		args     []string
+		expected map[int]compareFunc
+	}{
+		{
+			name: "first_level",
+			args: []string{"--group-by", "prefix", "--format", "csv"},
+			expected: map[int]compareFunc{
+				0: equals(`Source,Prefix,Count,Size`),
+				1: equals(`s3://%v/*,,1,1`, bucket),
+				2: equals(`s3://%v/*,a/,3,9`, bucket),
+				3: equals(`s3://%v/*,d/,1,5`, bucket),
+			},
+		},
+		{
+			name: "second_level",
+			args: []string{"--depth", "2"},
+			expected: map[int]compareFunc{
+				0: equals(`1 bytes in 1 objects: s3://%v/*`, bucket),
+				1: equals(`2 bytes in 1 objects: s3://%v/* [a/]`, bucket),
+				2: equals(`3 bytes in 1 objects: s3://%v/* [a/b/]`, bucket),
+				3: equals(`4 bytes in 1 objects: s3://%v/* [a/c/]`, bucket),
+				4: equals(`5 bytes in 1 objects: s3://%v/* [d/]`, bucket),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			args := append([]string{"du"}, tc.args...)
+			cmd := s5cmd(append(args, "s3://"+bucket+"/*")...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Success)
+
+			assertLines(t, result.Stdout(), tc.expected)
+		})
+	}
+}
+
+func TestDiskUsageWithDepthAndStorageClassGroup(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("du", "--group", "--depth", "2", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "du s3://%v/*": --depth flag can only be used with prefix grouping`, bucket),
+	})
+}
+
+func TestDiskUsageWithUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("du", "--format", "xml", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "du s3://%v/*": unknown output format "xml"`, bucket),
+	})
+}
@@ -0,0 +1,166 @@
+package e2e
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/icmd"
+)
+
+// find --name "*.gz" --size +10 s3://bucket/prefix/
+func TestFindS3ObjectsWithPredicates(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"prefix/a/large.gz":  "this is a large file",
+		"prefix/b/small.gz":  "small",
+		"prefix/b/large.txt": "this is a large file",
+		"other/large.gz":     "this is a large file",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("find", "--name", "*.gz", "--size", "+10", "--mtime", "-1d", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("s3://%v/prefix/a/large.gz", bucket),
+	})
+}
+
+// find --mtime +1d s3://bucket
+func TestFindS3ObjectsWithNoMatch(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "testfile.txt", "content")
+
+	cmd := s5cmd("find", "--mtime", "+1d", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{})
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
+}
+
+// find --name "*.txt" --delete s3://bucket/
+func TestFindS3ObjectsAndDelete(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "dir/testfile.txt", "content")
+	putFile(t, s3client, bucket, "dir/testfile.gz", "content")
+
+	cmd := s5cmd("find", "--name", "*.txt", "--delete", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("rm s3://%v/dir/testfile.txt", bucket),
+	})
+
+	err := ensureS3Object(s3client, bucket, "dir/testfile.txt", "content")
+	assertError(t, err, errS3NoSuchKey)
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "dir/testfile.gz", "content"))
+}
+
+// find --name "*.txt" --exec "cp {} s3://bucket/backup/" s3://bucket/dir/
+func TestFindS3ObjectsAndExec(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "dir/test file.txt", "content")
+	putFile(t, s3client, bucket, "dir/testfile.gz", "content")
+
+	cmd := s5cmd("find", "--name", "*.txt", "--exec", "cp {} s3://"+bucket+"/backup/", "s3://"+bucket+"/dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/dir/test file.txt s3://%v/backup/test file.txt`, bucket, bucket),
+	})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "backup/test file.txt", "content"))
+
+	err := ensureS3Object(s3client, bucket, "backup/testfile.gz", "content")
+	assertError(t, err, errS3NoSuchKey)
+}
+
+// find --exec "cp {} s3://bucket/backup/" s3://bucket/dir/
+func TestFindS3ObjectsAndExecWithHashInKey(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	// the part of the key after " #" is not a comment of the generated line.
+	putFile(t, s3client, bucket, "dir/a #b.txt", "content")
+
+	cmd := s5cmd("find", "--exec", "cp {} s3://"+bucket+"/backup/", "s3://"+bucket+"/dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/dir/a #b.txt s3://%v/backup/a #b.txt`, bucket, bucket),
+	})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "backup/a #b.txt", "content"))
+}
+
+// find --delete --exec "rm {}" s3://bucket/
+func TestFindWithDeleteAndExec(t *testing.T) {
+	t.Parallel()
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	bucket := s3BucketFromTestName(t)
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("find", "--delete", "--exec", "rm {}", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "find s3://%v/": --delete and --exec flags can not be used together`, bucket),
+	})
+}
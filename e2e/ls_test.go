@@ -1,9 +1,14 @@
 package e2e
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gotest.tools/v3/assert"
 	"gotest.tools/v3/icmd"
 )
 
@@ -342,6 +347,27 @@ func TestListNonexistingS3Object(t *testing.T) {
 	}, strictLineCheck(false))
 }
 
+// --json ls bucket/nosuchobject
+func TestListNonexistentObjectJSONWithErrorCode(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("--json", "ls", "s3://"+bucket+"/nosuchobject")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`{"operation":"ls","command":"ls s3://%v/nosuchobject","error":"no object found","code":"NoSuchKey"}`, bucket),
+	}, jsonCheck(true))
+}
+
 // ls -e bucket
 func TestListS3ObjectsWithDashE(t *testing.T) {
 	t.Parallel()
@@ -391,3 +417,260 @@ func TestListS3ObjectsWithDashH(t *testing.T) {
 		1: match(`^ 264.0K testfile2.txt$`),
 	}, trimMatch(dateRe), alignment(true))
 }
+
+// ls -H --summarize bucket/*
+func TestListS3ObjectsWithSummary(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "testfile1.txt", strings.Repeat("this is a file content", 10000))
+	putFile(t, s3client, bucket, "dir/testfile2.txt", strings.Repeat("this is also a file content", 10000))
+
+	cmd := s5cmd("ls", "-H", "--summarize", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: match(`^ 264.0K dir/testfile2.txt$`),
+		1: match(`^ 215.1K testfile1.txt$`),
+		2: equals("2 objects, 479.1K total"),
+	}, trimMatch(dateRe))
+}
+
+// ls --summarize bucket/
+func TestListS3ObjectsAndFoldersWithSummary(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "testfile.txt", "content")
+	putFile(t, s3client, bucket, "dir/file.txt", "content")
+
+	cmd := s5cmd("ls", "--summarize", "s3://"+bucket+"/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("DIR dir/"),
+		1: suffix("301 testfile.txt"),
+		2: equals("1 objects, 301 bytes total"),
+	})
+}
+
+// ls --sort size --reverse bucket/*
+func TestListS3ObjectsSortedBySize(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	putFile(t, s3client, bucket, "a.txt", strings.Repeat("a", 2000))
+	putFile(t, s3client, bucket, "b.txt", strings.Repeat("b", 1000))
+	putFile(t, s3client, bucket, "c.txt", strings.Repeat("c", 3000))
+
+	testcases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "size",
+			args:     []string{"--sort", "size"},
+			expected: []string{"b.txt", "a.txt", "c.txt"},
+		},
+		{
+			name:     "size_reverse",
+			args:     []string{"--sort", "size", "--reverse"},
+			expected: []string{"c.txt", "a.txt", "b.txt"},
+		},
+		{
+			name:     "name_reverse",
+			args:     []string{"--sort", "name", "--reverse"},
+			expected: []string{"c.txt", "b.txt", "a.txt"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			args := append([]string{"ls"}, tc.args...)
+			cmd := s5cmd(append(args, "s3://"+bucket+"/*")...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Success)
+
+			expected := map[int]compareFunc{}
+			for i, name := range tc.expected {
+				expected[i] = suffix(name)
+			}
+			assertLines(t, result.Stdout(), expected)
+		})
+	}
+}
+
+// ls --versions bucket/*
+func TestListS3ObjectVersions(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	_, err := s3client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(s3.BucketVersioningStatusEnabled),
+		},
+	})
+	assert.NilError(t, err)
+
+	putFile(t, s3client, bucket, "testfile.txt", "first version")
+	putFile(t, s3client, bucket, "testfile.txt", "second version")
+
+	cmd := s5cmd("ls", "--versions", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// each version is printed with its own version id and size.
+	versions := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(result.Stdout()), "\n") {
+		fields := strings.Fields(line)
+		assert.Equal(t, fields[len(fields)-1], "testfile.txt")
+		versions[fields[0]] = fields[len(fields)-2]
+	}
+	assert.Equal(t, len(versions), 2)
+
+	var sizes []string
+	for _, size := range versions {
+		sizes = append(sizes, size)
+	}
+	sort.Strings(sizes)
+	assert.DeepEqual(t, sizes, []string{"13", "14"})
+}
+
+// ls --limit 2 bucket/*
+func TestListS3ObjectsWithLimit(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for i := 1; i <= 5; i++ {
+		putFile(t, s3client, bucket, fmt.Sprintf("testfile%d.txt", i), "content")
+	}
+
+	cmd := s5cmd("ls", "--limit", "2", "--summarize", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("testfile1.txt"),
+		1: suffix("testfile2.txt"),
+		2: match(`^2 objects, \d+ bytes total$`),
+	})
+}
+
+// --max-keys 2 ls bucket/*
+func TestListS3ObjectsWithMaxKeys(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	// paging is not supported by the bolt backend.
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for i := 1; i <= 5; i++ {
+		putFile(t, s3client, bucket, fmt.Sprintf("testfile%d.txt", i), "content")
+	}
+
+	cmd := s5cmd("--max-keys", "2", "ls", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// all of the pages are listed.
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("testfile1.txt"),
+		1: suffix("testfile2.txt"),
+		2: suffix("testfile3.txt"),
+		3: suffix("testfile4.txt"),
+		4: suffix("testfile5.txt"),
+	})
+}
+
+// --start-after testfile3.txt ls bucket/*
+func TestListS3ObjectsStartAfter(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	// paging is not supported by the bolt backend.
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for i := 1; i <= 5; i++ {
+		putFile(t, s3client, bucket, fmt.Sprintf("testfile%d.txt", i), "content")
+	}
+
+	cmd := s5cmd("--start-after", "testfile3.txt", "ls", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("testfile4.txt"),
+		1: suffix("testfile5.txt"),
+	})
+}
+
+// ls --sort unknown bucket/*
+func TestListS3ObjectsWithUnknownSortKey(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("ls", "--sort", "owner", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: contains(`unknown sort key "owner"`),
+	})
+}
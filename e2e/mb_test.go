@@ -74,7 +74,7 @@ func TestMakeBucket_failure(t *testing.T) {
 
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`ERROR "mb %v": invalid s3 bucket`, src),
@@ -92,7 +92,7 @@ func TestMakeBucket_failure_json(t *testing.T) {
 
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`{"operation":"mb","command":"mb %v","error":"invalid s3 bucket"}`, src),
@@ -347,6 +347,7 @@ func TestMoveMultipleS3ObjectsToS3DryRun(t *testing.T) {
 		1: equals("mv s3://%v/filename-with-hypen.gz %vfilename-with-hypen.gz", bucket, dst),
 		2: equals("mv s3://%v/readme.md %vreadme.md", bucket, dst),
 		3: equals("mv s3://%v/testfile1.txt %vtestfile1.txt", bucket, dst),
+		4: prefix("would move 4 objects, "),
 	}, sortInput(true))
 
 	// expect no change on s3 source objects
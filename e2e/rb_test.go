@@ -80,7 +80,7 @@ func TestRemoveBucketFailure(t *testing.T) {
 	cmd := s5cmd("rb", src)
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`ERROR "rb %v": invalid s3 bucket`, src),
@@ -97,7 +97,7 @@ func TestRemoveBucketFailureJson(t *testing.T) {
 	cmd := s5cmd("--json", "rb", src)
 	result := icmd.RunCmd(cmd)
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`{"operation":"rb","command":"rb %v","error":"invalid s3 bucket"}`, src),
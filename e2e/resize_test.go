@@ -0,0 +1,61 @@
+// +build !windows
+
+package e2e
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/icmd"
+)
+
+func TestResizeWorkersWithSignals(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", "content"))
+	defer workdir.Remove()
+
+	stdin, stdinWriter := io.Pipe()
+
+	cmd := s5cmd("--numworkers", "4", "run")
+	cmd.Dir = workdir.Path()
+	cmd.Stdin = stdin
+
+	result := icmd.StartCmd(cmd)
+	assert.NilError(t, result.Error)
+
+	// signals are handled once the run is started.
+	_, err := fmt.Fprintf(stdinWriter, "cp file.txt s3://%v/file.txt\n", bucket)
+	assert.NilError(t, err)
+
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if ensureS3Object(s3client, bucket, "file.txt", "content") == nil {
+			break
+		}
+	}
+
+	assert.NilError(t, result.Cmd.Process.Signal(syscall.SIGUSR1))
+	time.Sleep(100 * time.Millisecond)
+	assert.NilError(t, result.Cmd.Process.Signal(syscall.SIGUSR2))
+	time.Sleep(100 * time.Millisecond)
+
+	stdinWriter.Close()
+	result = icmd.WaitOnCmd(10*time.Second, result)
+	result.Assert(t, icmd.Success)
+
+	assert.Assert(t, strings.Contains(result.Stderr(), "workers resized from 4 to 8 (signal)"), result.Stderr())
+	assert.Assert(t, strings.Contains(result.Stderr(), "workers resized from 8 to 4 (signal)"), result.Stderr())
+}
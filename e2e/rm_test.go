@@ -2,6 +2,7 @@ package e2e
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -126,6 +127,130 @@ func TestRemoveMultipleS3Objects(t *testing.T) {
 	}
 }
 
+// rm --batch-size 2 s3://bucket/*
+func TestRemoveMultipleS3ObjectsWithBatchSize(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filenames := []string{"file1.txt", "file2.txt", "file3.txt", "file4.txt", "file5.txt"}
+	for _, filename := range filenames {
+		putFile(t, s3client, bucket, filename, "content")
+	}
+
+	cmd := s5cmd("--stat", "rm", "--batch-size", "2", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
+
+	out := result.Stdout()
+	for _, filename := range filenames {
+		line := fmt.Sprintf("rm s3://%v/%v\n", bucket, filename)
+		assert.Assert(t, strings.Contains(out, line), out)
+	}
+
+	// 5 objects are deleted with 3 requests.
+	assert.Assert(t, strings.Contains(out, "delete\t3\t"), out)
+
+	for _, filename := range filenames {
+		err := ensureS3Object(s3client, bucket, filename, "content")
+		assertError(t, err, errS3NoSuchKey)
+	}
+}
+
+func TestRemoveMultipleS3ObjectsWithoutConfirmation(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	for _, args := range [][]string{
+		{"rm", "--force"},
+		{"--assume-yes", "rm"},
+		{"-y", "rm"},
+	} {
+		putFile(t, s3client, bucket, "file1.txt", "content")
+		putFile(t, s3client, bucket, "file2.txt", "content")
+
+		cmd := s5cmd(append(args, "s3://"+bucket+"/*")...)
+		result := icmd.RunCmd(cmd)
+
+		result.Assert(t, icmd.Success)
+
+		assertLines(t, result.Stderr(), map[int]compareFunc{})
+
+		assertLines(t, result.Stdout(), map[int]compareFunc{
+			0: equals(`rm s3://%v/file1.txt`, bucket),
+			1: equals(`rm s3://%v/file2.txt`, bucket),
+		}, sortInput(true))
+	}
+}
+
+func TestRemoveWithInvalidBatchSize(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("rm", "--batch-size", "1001", "s3://bucket/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "rm s3://bucket/*": batch size must be between 1 and 1000`),
+	})
+}
+
+// rm --files-from - s3://bucket/prefix/
+func TestRemoveS3ObjectsWithFilesFrom(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"prefix/testfile1.txt": "this is a test file 1",
+		"prefix/a/readme.md":   "this is a readme file",
+		"prefix/keep.txt":      "this file is kept",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("rm", "--files-from", "-", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd, icmd.WithStdin(strings.NewReader("testfile1.txt\r\na/readme.md\r\n")))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`rm s3://%v/prefix/a/readme.md`, bucket),
+		1: equals(`rm s3://%v/prefix/testfile1.txt`, bucket),
+	}, sortInput(true))
+
+	for _, filename := range []string{"prefix/testfile1.txt", "prefix/a/readme.md"} {
+		err := ensureS3Object(s3client, bucket, filename, filesToContent[filename])
+		assertError(t, err, errS3NoSuchKey)
+	}
+	assert.Assert(t, ensureS3Object(s3client, bucket, "prefix/keep.txt", "this file is kept"))
+}
+
 // --json rm s3://bucket/*
 func TestRemoveMultipleS3ObjectsJSON(t *testing.T) {
 	t.Parallel()
@@ -548,7 +673,7 @@ func TestRemoveMultipleMixedObjects(t *testing.T) {
 	cmd := s5cmd("rm", filename, remoteSource)
 	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
 
-	result.Assert(t, icmd.Expected{ExitCode: 1})
+	result.Assert(t, icmd.Expected{ExitCode: 2})
 
 	assertLines(t, result.Stderr(), map[int]compareFunc{
 		0: equals(`ERROR "rm %v %v": arguments cannot have both local and remote sources`, filename, remoteSource),
@@ -596,6 +721,7 @@ func TestRemoveMultipleS3ObjectsDryRun(t *testing.T) {
 		1: equals(`rm s3://%v/filename-with-hypen.gz`, bucket),
 		2: equals(`rm s3://%v/readme.md`, bucket),
 		3: equals(`rm s3://%v/testfile1.txt`, bucket),
+		4: prefix(`would delete 4 objects, `),
 	}, sortInput(true))
 
 	// assert s3 objects were not removed
@@ -2,6 +2,11 @@ package e2e
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -180,10 +185,11 @@ func TestRunWildcardCountGreaterEqualThanWorkerCount(t *testing.T) {
 	createBucket(t, s3client, bucket)
 	putFile(t, s3client, bucket, "file.txt", "content")
 
+	// identical commands are run once, the wildcards are different.
 	content := []string{
 		"cp s3://" + bucket + "/f*.txt .",
-		"cp s3://" + bucket + "/f*.txt .",
-		"cp s3://" + bucket + "/f*.txt .",
+		"cp s3://" + bucket + "/fi*.txt .",
+		"cp s3://" + bucket + "/fil*.txt .",
 	}
 	file := fs.NewFile(t, "prefix", fs.WithContent(strings.Join(content, "\n")))
 	defer file.Remove()
@@ -267,6 +273,7 @@ func TestRunDryRun(t *testing.T) {
 		0: equals(filecontent[0]),
 		1: equals(filecontent[1]),
 		2: equals(filecontent[2]),
+		3: equals("would copy 1 object; would move 1 object; would delete 1 object"),
 	}, sortInput(true))
 
 	// ensure no side effect for copy operation
@@ -282,3 +289,763 @@ func TestRunDryRun(t *testing.T) {
 	// ensure no side effect for remove operation
 	assert.Assert(t, ensureS3Object(s3client, bucket, files[2], "content"))
 }
+
+func TestRunWithPerCommandMultipartSettings(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	content := strings.Repeat("0123456789abcdef", 6*1024*1024/16)
+	workdir := fs.NewDir(t, bucket, fs.WithFile("big.bin", content))
+	defer workdir.Remove()
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp -c 2 -p 5M big.bin s3://%v/multipart.bin", bucket),
+		fmt.Sprintf("cp big.bin s3://%v/single.bin", bucket),
+		fmt.Sprintf("cp -p 0 big.bin s3://%v/invalid.bin", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "cp big.bin s3://%v/invalid.bin": part size must be a positive number`, bucket),
+	})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "multipart.bin", content))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "single.bin", content))
+
+	err := ensureS3Object(s3client, bucket, "invalid.bin", content)
+	assertError(t, err, errS3NoSuchKey)
+}
+
+func TestRunWithWaitDirective(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "content")
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp s3://%v/file1.txt s3://%v/copy/file1.txt", bucket, bucket),
+		fmt.Sprintf("cp s3://%v/file2.txt s3://%v/copy/file2.txt", bucket, bucket),
+		"!wait # copies must be completed before listing",
+		fmt.Sprintf("ls s3://%v/copy/", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		// copies are completed in any order, but before the listing.
+		0: prefix(`cp s3://%v/file`, bucket),
+		1: prefix(`cp s3://%v/file`, bucket),
+		2: suffix("file1.txt"),
+		3: suffix("file2.txt"),
+	})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
+}
+
+func TestRunWithUnknownDirective(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+
+	filecontent := strings.Join([]string{
+		"!sleep",
+		fmt.Sprintf("ls s3://%v/file1.txt", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("file1.txt"),
+	})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "run %v": "!sleep" directive (line: 0) not found`, file.Path()),
+	})
+}
+
+func TestRunWithVariables(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "2020-03-18/file.txt", "content")
+
+	filecontent := strings.Join([]string{
+		"ls s3://${BUCKET}/${DATE}/",
+		"ls s3://${BUCKET}/${UNDEFINED}/",
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", "--var", "DATE=2020-03-18", file.Path())
+	cmd.Env = append(cmd.Env, "BUCKET="+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("file.txt"),
+	})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "run %v": variable "UNDEFINED" is not defined (line: 1)`, file.Path()),
+	})
+}
+
+// the values of the variables are not split into multiple arguments.
+func TestRunWithVariablesWithSpaces(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file with spaces.txt", "content")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(fmt.Sprintf("cp s3://%v/${NAME} s3://%v/copy.txt", bucket, bucket)))
+	defer file.Remove()
+
+	cmd := s5cmd("run", "--var", "NAME=file with spaces.txt", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assert.Equal(t, result.Stderr(), "")
+	assert.Assert(t, ensureS3Object(s3client, bucket, "copy.txt", "content"))
+}
+
+// variables are replaced with the environment variables without --var flag.
+func TestRunWithEnvironmentVariables(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent("ls s3://${BUCKET}/file.txt"))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	cmd.Env = append(cmd.Env, "BUCKET="+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assert.Equal(t, result.Stderr(), "")
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("file.txt"),
+	})
+}
+
+// variables are not replaced with --no-vars flag.
+func TestRunWithoutVariables(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "${literal}.txt", "content")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(fmt.Sprintf("ls s3://%v/${literal}.txt", bucket)))
+	defer file.Remove()
+
+	// the undefined variable is an error unless --no-vars flag is given.
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "run %v": variable "literal" is not defined (line: 0)`, file.Path()),
+	})
+
+	cmd = s5cmd("run", "--no-vars", file.Path())
+	result = icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assert.Equal(t, result.Stderr(), "")
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("${literal}.txt"),
+	})
+}
+
+func TestRunWithIncludeDirective(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "content")
+
+	workdir := fs.NewDir(t, bucket,
+		fs.WithFile("main.txt", strings.Join([]string{
+			fmt.Sprintf("ls s3://%v/file1.txt", bucket),
+			"!include fragments/ls.txt",
+			"!include missing.txt",
+		}, "\n")),
+		fs.WithDir("fragments",
+			fs.WithFile("ls.txt", strings.Join([]string{
+				fmt.Sprintf("ls s3://%v/file2.txt", bucket),
+				// included files are relative to the including one.
+				"!include ../main.txt",
+			}, "\n")),
+		),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("run", workdir.Join("main.txt"))
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("file1.txt"),
+		1: suffix("file2.txt"),
+	}, sortInput(true))
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "run %v": "../main.txt" is already included (line: 1)`, workdir.Join("main.txt")),
+		1: equals(`ERROR "run %v": open %v: no such file or directory (line: 2)`, workdir.Join("main.txt"), workdir.Join("missing.txt")),
+	})
+}
+
+func TestRunFromStdinWithFileFlag(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", "content"))
+	defer workdir.Remove()
+
+	stdin, stdinWriter := io.Pipe()
+
+	cmd := s5cmd("-f", "-")
+	cmd.Dir = workdir.Path()
+	cmd.Stdin = stdin
+
+	result := icmd.StartCmd(cmd)
+	assert.NilError(t, result.Error)
+
+	_, err := fmt.Fprintf(stdinWriter, "cp file.txt s3://%v/file.txt\n", bucket)
+	assert.NilError(t, err)
+
+	// the command is run as soon as its line is read, before the input ends.
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if err = ensureS3Object(s3client, bucket, "file.txt", "content"); err == nil {
+			break
+		}
+	}
+	assert.NilError(t, err)
+
+	stdinWriter.Close()
+	icmd.WaitOnCmd(10*time.Second, result).Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp file.txt s3://%v/file.txt`, bucket),
+	})
+}
+
+func TestRunFromFileWithFileFlag(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(fmt.Sprintf("ls s3://%v/file1.txt", bucket)))
+	defer file.Remove()
+
+	cmd := s5cmd("-f", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("file1.txt"),
+	})
+}
+
+func TestRunWithStartLine(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "content")
+	putFile(t, s3client, bucket, "file3.txt", "content")
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("ls s3://%v/file1.txt", bucket),
+		fmt.Sprintf("ls s3://%v/file2.txt", bucket),
+		fmt.Sprintf("ls s3://%v/file3.txt", bucket),
+	}, "\n")
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("commands.txt", filecontent))
+	defer workdir.Remove()
+
+	cmd := s5cmd("run", "--start-line", "1", workdir.Join("commands.txt"))
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: suffix("file2.txt"),
+		1: suffix("file3.txt"),
+	}, sortInput(true))
+
+	// the progress marker is removed once all of the lines are completed.
+	expected := fs.Expected(t, fs.WithFile("commands.txt", filecontent))
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
+
+func TestRunWithMaxErrors(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("ls s3://%v/missing1.txt", bucket),
+		"!wait",
+		fmt.Sprintf("ls s3://%v/missing2.txt", bucket),
+		"!wait",
+		fmt.Sprintf("ls s3://%v/file.txt", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("--max-errors", "2", "run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "ls s3://%v/missing1.txt": no object found`, bucket),
+		1: equals(`ERROR "ls s3://%v/missing2.txt": no object found`, bucket),
+		2: equals(`ERROR max errors (2) reached, no more jobs will be run`),
+	})
+}
+
+func TestRunWithExitOnError(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("file.txt", "new content"))
+	defer workdir.Remove()
+
+	filecontent := strings.Join([]string{
+		// skipped objects are not failures.
+		fmt.Sprintf("cp -n file.txt s3://%v/file.txt", bucket),
+		"!wait",
+		fmt.Sprintf("ls s3://%v/missing.txt", bucket),
+		"!wait",
+		fmt.Sprintf("ls s3://%v/file.txt", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("--exit-on-error", "run", file.Path())
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "ls s3://%v/missing.txt": no object found`, bucket),
+		1: equals(`ERROR exiting on error, running jobs are canceled`),
+	})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file.txt", "content"))
+}
+
+func TestRunWithDeadline(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	const content = "this is a file content"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+		fmt.Fprint(w, content)
+	}))
+	defer server.Close()
+
+	src := server.URL + "/slow.txt"
+	dst := fmt.Sprintf("s3://%v/slow.txt", bucket)
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp %v %v", src, dst),
+		"!wait",
+		"# comments are not counted",
+		fmt.Sprintf("ls s3://%v/file.txt", bucket),
+		fmt.Sprintf("ls s3://%v/slow.txt", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("--deadline", "500ms", "run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	// the running job is completed.
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v`, src, dst),
+	})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR deadline (500ms) exceeded, no more jobs will be run`),
+		1: equals(`ERROR deadline (500ms) exceeded, 2 jobs are not run`),
+	})
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "slow.txt", content))
+}
+
+// the rest of a chain is skipped once the run is stopped, and the chain is
+// counted as a single job which is not run.
+func TestRunWithDeadlineInChain(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+		fmt.Fprint(w, "content")
+	}))
+	defer server.Close()
+
+	src := server.URL + "/slow.txt"
+	dst := fmt.Sprintf("s3://%v/slow.txt", bucket)
+
+	line := fmt.Sprintf("cp %v %v && (ls s3://%v/file.txt || ls s3://%v/slow.txt)", src, dst, bucket, bucket)
+	file := fs.NewFile(t, "prefix", fs.WithContent(line))
+	defer file.Remove()
+
+	cmd := s5cmd("--deadline", "500ms", "run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp %v %v`, src, dst),
+	})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR deadline (500ms) exceeded, no more jobs will be run`),
+		1: equals(`ERROR deadline (500ms) exceeded, 1 jobs are not run`),
+	})
+}
+
+func TestRunWithDuplicateCommands(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	// identical commands are run once until a wait directive, regardless of
+	// their quotes and spaces.
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp s3://%v/file.txt s3://%v/copy.txt", bucket, bucket),
+		fmt.Sprintf("cp  's3://%v/file.txt'   s3://%v/copy.txt", bucket, bucket),
+		"!wait",
+		fmt.Sprintf("cp s3://%v/file.txt s3://%v/copy.txt", bucket, bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/file.txt s3://%v/copy.txt`, bucket, bucket),
+		1: equals(`cp s3://%v/file.txt s3://%v/copy.txt`, bucket, bucket),
+		2: equals(`skip "cp s3://%v/file.txt s3://%v/copy.txt": duplicate of the command (line: 0)`, bucket, bucket),
+	}, sortInput(true))
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{})
+}
+
+func TestRunWithCachedListings(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file1.txt", "content")
+	putFile(t, s3client, bucket, "file2.txt", "content")
+
+	input := strings.NewReader(
+		strings.Join([]string{
+			fmt.Sprintf("ls s3://%v/*.txt", bucket),
+			fmt.Sprintf("cp s3://%v/*.txt dir/", bucket),
+		}, "\n"),
+	)
+	cmd := s5cmd("--cache-listings", "--aws-debug", "http", "run")
+	result := icmd.RunCmd(cmd, icmd.WithStdin(input))
+
+	result.Assert(t, icmd.Success)
+
+	// the wildcard is listed once for both of the commands.
+	out := result.Stdout()
+	assert.Equal(t, strings.Count(out, "DEBUG Request s3/ListObjectsV2 Details:"), 1, out)
+	for _, expected := range []string{
+		fmt.Sprintf("cp s3://%v/file1.txt dir/file1.txt", bucket),
+		fmt.Sprintf("cp s3://%v/file2.txt dir/file2.txt", bucket),
+	} {
+		assert.Assert(t, strings.Contains(out, expected), out)
+	}
+}
+
+func TestRunWithChainedCommands(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+	putFile(t, s3client, bucket, "moved.txt", "moved content")
+
+	src := fmt.Sprintf("s3://%v/file.txt", bucket)
+	missing := fmt.Sprintf("s3://%v/missing.txt", bucket)
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp s3://%v/moved.txt s3://%v/copy.txt && rm s3://%v/moved.txt", bucket, bucket, bucket),
+		fmt.Sprintf("ls %v && cp %v s3://%v/not-copied.txt", missing, src, bucket),
+		fmt.Sprintf("ls %v || cp %v s3://%v/fallback.txt", missing, src, bucket),
+		fmt.Sprintf("(ls %v || ls %v) && cp %v s3://%v/grouped.txt", missing, src, src, bucket),
+		fmt.Sprintf("ls %v || (cp %v s3://%v/not-run.txt && rm %v)", src, src, bucket, src),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assert.Assert(t, ensureS3Object(s3client, bucket, "copy.txt", "moved content"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "fallback.txt", "content"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "grouped.txt", "content"))
+	assert.Assert(t, ensureS3Object(s3client, bucket, "file.txt", "content"))
+
+	for _, key := range []string{"moved.txt", "not-copied.txt", "not-run.txt"} {
+		err := ensureS3Object(s3client, bucket, key, "content")
+		assertError(t, err, errS3NoSuchKey)
+	}
+}
+
+func TestRunWithInvalidChains(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "file.txt", "content")
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("ls s3://%v/file.txt &&", bucket),
+		fmt.Sprintf("(ls s3://%v/file.txt || ls s3://%v/", bucket, bucket),
+		fmt.Sprintf("ls s3://%v/file.txt && unknown s3://%v/", bucket, bucket),
+		fmt.Sprintf("ls s3://%v/file.txt && !wait", bucket),
+	}, "\n")
+
+	file := fs.NewFile(t, "prefix", fs.WithContent(filecontent))
+	defer file.Remove()
+
+	cmd := s5cmd("run", file.Path())
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	// none of the commands of the invalid chains are run.
+	assertLines(t, result.Stdout(), map[int]compareFunc{})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "run %v": missing command at the end of the line (line: 0)`, file.Path()),
+		1: equals(`ERROR "run %v": missing ")" (line: 1)`, file.Path()),
+		2: equals(`ERROR "run %v": "unknown" command (line: 2) not found`, file.Path()),
+		3: equals(`ERROR "run %v": "!wait" directive (line: 3) can not be chained`, file.Path()),
+	})
+}
+
+func TestRunWithResume(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "first.txt", "first")
+	putFile(t, s3client, bucket, "dir/a.txt", "a")
+	putFile(t, s3client, bucket, "dir/b.txt", "b")
+
+	filecontent := strings.Join([]string{
+		fmt.Sprintf("cp s3://%v/first.txt first.txt", bucket),
+		fmt.Sprintf("cp 's3://%v/dir/*' out/", bucket),
+	}, "\n")
+
+	workdir := fs.NewDir(t, bucket, fs.WithFile("commands.txt", filecontent))
+	defer workdir.Remove()
+
+	cmd := s5cmd("run", "--resume", "commands.checkpoint", "commands.txt")
+	result := icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Success)
+
+	checkpoint := workdir.Join("commands.checkpoint")
+	content, err := ioutil.ReadFile(checkpoint)
+	assert.NilError(t, err)
+
+	assertLines(t, string(content), map[int]compareFunc{
+		0: prefix("command 0 "),
+		1: prefix("command 1 "),
+		2: match(fmt.Sprintf(`^job 0 .* s3://%v/first.txt$`, bucket)),
+		3: match(fmt.Sprintf(`^job 1 .* s3://%v/dir/a.txt$`, bucket)),
+		4: match(fmt.Sprintf(`^job 1 .* s3://%v/dir/b.txt$`, bucket)),
+	}, sortInput(true))
+
+	// the run is interrupted after the transfer of a.txt.
+	var interrupted []string
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		if strings.HasPrefix(line, "command 1 ") || strings.HasSuffix(line, "b.txt") {
+			continue
+		}
+		interrupted = append(interrupted, line)
+	}
+	err = ioutil.WriteFile(checkpoint, []byte(strings.Join(interrupted, "\n")+"\n"), 0644)
+	assert.NilError(t, err)
+
+	for _, name := range []string{"first.txt", "out/a.txt", "out/b.txt"} {
+		assert.NilError(t, os.Remove(workdir.Join(name)))
+	}
+
+	result = icmd.RunCmd(cmd, withWorkingDir(workdir))
+	result.Assert(t, icmd.Success)
+
+	// only the transfer which is not completed is run again.
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`cp s3://%v/dir/b.txt out/b.txt`, bucket),
+		1: equals(`skip "cp s3://%v/first.txt first.txt": command (line: 0) is completed by a previous run`, bucket),
+	}, sortInput(true))
+
+	expected := fs.Expected(t,
+		fs.WithFile("commands.txt", filecontent),
+		fs.WithFile("commands.checkpoint", "", fs.MatchAnyFileContent),
+		fs.WithDir("out", fs.WithFile("b.txt", "b")),
+	)
+	assert.Assert(t, fs.Equal(workdir.Path(), expected))
+}
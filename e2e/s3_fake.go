@@ -1,6 +1,8 @@
 package e2e
 
 import (
+	"fmt"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -34,7 +36,7 @@ func s3ServerEndpoint(t *testing.T, testdir *fs.Dir, loglvl, backend string) (st
 	)
 
 	faker := gofakes3.New(s3backend, withLogger)
-	s3srv := httptest.NewServer(faker.Server())
+	s3srv := httptest.NewServer(conditionalWrites(s3backend, faker.Server()))
 
 	cleanup := func() {
 		s3srv.Close()
@@ -44,3 +46,27 @@ func s3ServerEndpoint(t *testing.T, testdir *fs.Dir, loglvl, backend string) (st
 
 	return s3srv.URL, cleanup
 }
+
+// conditionalWrites emulates the writes with If-None-Match header, which are
+// not supported by gofakes3. Objects are written only if they don't exist.
+func conditionalWrites(backend gofakes3.Backend, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isWrite := r.Method == http.MethodPut || (r.Method == http.MethodPost && r.URL.Query().Get("uploadId") != "")
+		if !isWrite || r.Header.Get("If-None-Match") != "*" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+		if len(parts) == 2 {
+			if obj, err := backend.HeadObject(parts[0], parts[1]); err == nil {
+				obj.Contents.Close()
+
+				w.WriteHeader(http.StatusPreconditionFailed)
+				fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
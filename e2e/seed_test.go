@@ -0,0 +1,201 @@
+package e2e
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/icmd"
+)
+
+func TestSeedObjects(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("seed", "--count", "3", "--size", "2K", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`seed s3://%v/prefix/object0`, bucket),
+		1: equals(`seed s3://%v/prefix/object1`, bucket),
+		2: equals(`seed s3://%v/prefix/object2`, bucket),
+	}, sortInput(true))
+
+	for _, key := range []string{"prefix/object0", "prefix/object1", "prefix/object2"} {
+		head, err := s3client.HeadObject(&s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, aws.Int64Value(head.ContentLength), int64(2048))
+	}
+}
+
+func TestSeedObjectsIntoDirs(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("seed", "-n", "4", "--dirs", "2", "--content", "pattern", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals(`seed s3://%v/dir0/object0`, bucket),
+		1: equals(`seed s3://%v/dir0/object2`, bucket),
+		2: equals(`seed s3://%v/dir1/object1`, bucket),
+		3: equals(`seed s3://%v/dir1/object3`, bucket),
+	}, sortInput(true))
+}
+
+func TestSeedObjectsWithSizeRangeIsReproducible(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	seed := func(prefix string) [][]byte {
+		cmd := s5cmd("seed", "-n", "5", "--size", "1..1K", "--random-seed", "42", "s3://"+bucket+"/"+prefix+"/")
+		result := icmd.RunCmd(cmd)
+		result.Assert(t, icmd.Success)
+
+		var contents [][]byte
+		for i := 0; i < 5; i++ {
+			obj, err := s3client.GetObject(&s3.GetObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(fmt.Sprintf("%v/object%v", prefix, i)),
+			})
+			assert.NilError(t, err)
+
+			content, err := ioutil.ReadAll(obj.Body)
+			obj.Body.Close()
+			assert.NilError(t, err)
+
+			size := len(content)
+			assert.Assert(t, size >= 1 && size <= 1024, "unexpected size %v", size)
+			contents = append(contents, content)
+		}
+		return contents
+	}
+
+	assert.DeepEqual(t, seed("first"), seed("again"))
+}
+
+func TestSeedWithInvalidSizeRange(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("seed", "--size", "1M..1K", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "seed s3://%v": invalid size range "1M..1K": minimum is greater than maximum`, bucket),
+	})
+}
+
+func TestSeedToLocalDestination(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("seed", "dir/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "seed dir/": destination must be a bucket or a prefix`),
+	})
+}
+
+func TestSeedToObjectDestination(t *testing.T) {
+	t.Parallel()
+
+	_, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	cmd := s5cmd("seed", "s3://bucket/key")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "seed s3://bucket/key": destination must be a bucket or a prefix`),
+	})
+}
+
+func TestGenObjectsWithCountSuffix(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("gen", "--count", "1K", "--size", "16", "s3://"+bucket+"/prefix/")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assert.Equal(t, strings.Count(result.Stdout(), "\n"), 1000)
+
+	for _, key := range []string{"prefix/object000", "prefix/object999"} {
+		head, err := s3client.HeadObject(&s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, aws.Int64Value(head.ContentLength), int64(16))
+	}
+}
+
+func TestSeedWithInvalidCount(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("seed", "--count", "1KB", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "seed s3://%v": invalid count "1KB"`, bucket),
+	})
+}
@@ -0,0 +1,144 @@
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/icmd"
+)
+
+// stat s3://bucket/object
+func TestStatS3Object(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	_, err := s3client.PutObject(&s3.PutObjectInput{
+		Body:        strings.NewReader("this is a file content"),
+		Bucket:      aws.String(bucket),
+		Key:         aws.String("testfile.txt"),
+		ContentType: aws.String("text/plain"),
+		Metadata:    map[string]*string{"Owner": aws.String("s5cmd")},
+	})
+	assert.NilError(t, err)
+
+	cmd := s5cmd("stat", "s3://"+bucket+"/testfile.txt")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("Name: s3://%v/testfile.txt", bucket),
+		// fake s3 server doesn't store content types.
+		1: prefix("ContentType:"),
+		2: equals("ContentLength: 22"),
+		3: prefix("LastModified: "),
+		4: prefix("ETag: "),
+		5: equals("StorageClass: STANDARD"),
+		6: equals("Metadata: owner=s5cmd"),
+	})
+}
+
+// --json head s3://bucket/object
+func TestStatS3ObjectJSON(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "testfile.txt", "content")
+
+	cmd := s5cmd("--json", "head", "s3://"+bucket+"/testfile.txt")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: prefix(`{"key":"s3://%v/testfile.txt",`, bucket),
+	}, jsonCheck(true))
+}
+
+// stat s3://bucket/nonexistentobject
+func TestStatNonexistentS3Object(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("stat", "s3://"+bucket+"/nonexistentobject")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 1})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "stat s3://%v/nonexistentobject": given object not found`, bucket),
+	})
+}
+
+// stat --format csv s3://bucket/object
+func TestStatS3ObjectWithCSVFormat(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	_, err := s3client.PutObject(&s3.PutObjectInput{
+		Body:     strings.NewReader("content"),
+		Bucket:   aws.String(bucket),
+		Key:      aws.String("testfile.txt"),
+		Metadata: map[string]*string{"Owner": aws.String("s5cmd"), "Team": aws.String("storage")},
+	})
+	assert.NilError(t, err)
+
+	cmd := s5cmd("stat", "--format", "csv", "s3://"+bucket+"/testfile.txt")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("Name,ContentType,ContentLength,LastModified,ETag,StorageClass,CacheControl,SSE,SSEKMSKeyID,Metadata,Tags"),
+		1: match(fmt.Sprintf(`^s3://%v/testfile.txt,[^,]*,7,[^,]+,[^,]+,STANDARD,,,,"owner=s5cmd,team=storage",$`, bucket)),
+	})
+}
+
+// stat --format xml s3://bucket/object
+func TestStatS3ObjectWithUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, "testfile.txt", "content")
+
+	cmd := s5cmd("stat", "--format", "xml", "s3://"+bucket+"/testfile.txt")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "stat s3://%v/testfile.txt": unknown output format "xml"`, bucket),
+	})
+}
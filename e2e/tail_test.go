@@ -0,0 +1,169 @@
+package e2e
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/icmd"
+)
+
+func TestTailS3Object(t *testing.T) {
+	t.Parallel()
+
+	const (
+		bucket   = "bucket"
+		filename = "file.log"
+	)
+
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	content := strings.Join(lines, "\n") + "\n"
+
+	testcases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "default_lines",
+			args:     []string{"tail"},
+			expected: strings.Join(lines[10:], "\n") + "\n",
+		},
+		{
+			name:     "lines",
+			args:     []string{"tail", "-n", "3"},
+			expected: "line 17\nline 18\nline 19\n",
+		},
+		{
+			name:     "more_lines_than_object",
+			args:     []string{"tail", "--lines", "100"},
+			expected: content,
+		},
+		{
+			name:     "bytes",
+			args:     []string{"tail", "-c", "8"},
+			expected: "line 19\n",
+		},
+		{
+			name:     "more_bytes_than_object",
+			args:     []string{"tail", "--bytes", "1M"},
+			expected: content,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s3client, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			createBucket(t, s3client, bucket)
+			putFile(t, s3client, bucket, filename, content)
+
+			cmd := s5cmd(append(tc.args, fmt.Sprintf("s3://%v/%v", bucket, filename))...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Success)
+			assert.Equal(t, result.Stdout(), tc.expected)
+		})
+	}
+}
+
+func TestTailLargeS3ObjectFetchesTrailingRanges(t *testing.T) {
+	t.Parallel()
+
+	const (
+		bucket   = "bucket"
+		filename = "file.log"
+	)
+
+	// the lines span more than one of the ranges fetched from the end.
+	line := strings.Repeat("x", 1023) + "\n"
+	content := "first\n" + strings.Repeat(line, 300)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+	putFile(t, s3client, bucket, filename, content)
+
+	cmd := s5cmd("tail", "-n", "200", fmt.Sprintf("s3://%v/%v", bucket, filename))
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assert.Equal(t, result.Stdout(), strings.Repeat(line, 200))
+
+	cmd = s5cmd("--log", "debug", "--aws-debug", "http", "tail", "-c", "1K", fmt.Sprintf("s3://%v/%v", bucket, filename))
+	result = icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+	assert.Assert(t, strings.Contains(result.Stdout(), "Range: bytes=306182-307205"), result.Stdout())
+}
+
+func TestTailS3ObjectFail(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name          string
+		args          []string
+		exitCode      int
+		expectedError string
+	}{
+		{
+			name:          "non_existent_object",
+			args:          []string{"tail", "s3://bucket/file.log"},
+			exitCode:      1,
+			expectedError: `ERROR "tail s3://bucket/file.log": given object not found`,
+		},
+		{
+			name:          "bucket",
+			args:          []string{"tail", "s3://bucket"},
+			exitCode:      2,
+			expectedError: `ERROR "tail s3://bucket": remote source must be an object`,
+		},
+		{
+			name:          "local_file",
+			args:          []string{"tail", "file.log"},
+			exitCode:      2,
+			expectedError: `ERROR "tail file.log": source must be a remote object`,
+		},
+		{
+			name:          "bytes_and_lines",
+			args:          []string{"tail", "-c", "1K", "-n", "5", "s3://bucket/file.log"},
+			exitCode:      2,
+			expectedError: "--bytes and --lines flags can not be used together",
+		},
+		{
+			name:          "invalid_bytes",
+			args:          []string{"tail", "-c", "big", "s3://bucket/file.log"},
+			exitCode:      2,
+			expectedError: `invalid --bytes "big"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s3client, s5cmd, cleanup := setup(t)
+			defer cleanup()
+
+			createBucket(t, s3client, "bucket")
+
+			cmd := s5cmd(tc.args...)
+			result := icmd.RunCmd(cmd)
+
+			result.Assert(t, icmd.Expected{ExitCode: tc.exitCode})
+			assertLines(t, result.Stderr(), map[int]compareFunc{
+				0: contains("%v", tc.expectedError),
+			})
+		})
+	}
+}
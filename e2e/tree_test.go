@@ -0,0 +1,104 @@
+package e2e
+
+import (
+	"testing"
+
+	"gotest.tools/v3/icmd"
+)
+
+// tree s3://bucket
+func TestTreeS3Bucket(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"root.txt":            "1",
+		"a/file1.txt":         "22",
+		"a/b/file2.txt":       "333",
+		"a/b/c/file3.txt":     "4444",
+		"d/file4.txt":         "55555",
+		"d/e/f/g/deepest.txt": "666666",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("tree", "s3://"+bucket)
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("s3://%v (6 objects, 21 bytes)", bucket),
+		1: equals("├── a/ (3 objects, 9 bytes)"),
+		2: equals("│ └── b/ (2 objects, 7 bytes)"),
+		3: equals("│ └── c/ (1 objects, 4 bytes)"),
+		4: equals("└── d/ (2 objects, 11 bytes)"),
+		5: equals(" └── e/ (1 objects, 6 bytes)"),
+		6: equals(" └── f/ (1 objects, 6 bytes)"),
+		7: equals(" └── g/ (1 objects, 6 bytes)"),
+	})
+}
+
+// tree --depth 1 s3://bucket/prefix
+func TestTreeS3PrefixWithDepth(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t, withS3Backend("mem"))
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	filesToContent := map[string]string{
+		"prefix/a/file1.txt":     "22",
+		"prefix/a/b/file2.txt":   "333",
+		"prefix/a/b/c/file3.txt": "4444",
+		"prefix/d/file4.txt":     "55555",
+		"other/file5.txt":        "666666",
+	}
+
+	for filename, content := range filesToContent {
+		putFile(t, s3client, bucket, filename, content)
+	}
+
+	cmd := s5cmd("tree", "--depth", "1", "s3://"+bucket+"/prefix")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Success)
+
+	assertLines(t, result.Stdout(), map[int]compareFunc{
+		0: equals("s3://%v/prefix/ (4 objects, 14 bytes)", bucket),
+		1: equals("├── a/ (3 objects, 9 bytes)"),
+		2: equals("└── d/ (1 objects, 5 bytes)"),
+	})
+}
+
+// tree s3://bucket/*
+func TestTreeWithWildcard(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	cmd := s5cmd("tree", "s3://"+bucket+"/*")
+	result := icmd.RunCmd(cmd)
+
+	result.Assert(t, icmd.Expected{ExitCode: 2})
+
+	assertLines(t, result.Stderr(), map[int]compareFunc{
+		0: equals(`ERROR "tree s3://%v/*": source can not contain wildcards`, bucket),
+	})
+}
@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+	"gotest.tools/v3/icmd"
+)
+
+// watch --interval 100ms --debounce 0s --exclude "*.tmp" dir/ s3://bucket/prefix/
+func TestWatchUploadsNewAndModifiedFiles(t *testing.T) {
+	t.Parallel()
+
+	bucket := s3BucketFromTestName(t)
+
+	s3client, s5cmd, cleanup := setup(t)
+	defer cleanup()
+
+	createBucket(t, s3client, bucket)
+
+	workdir := fs.NewDir(t, bucket,
+		fs.WithDir("dir",
+			fs.WithFile("existing.log", "existing file"),
+			fs.WithFile("ignored.tmp", "temporary file"),
+		),
+	)
+	defer workdir.Remove()
+
+	cmd := s5cmd("watch", "--interval", "100ms", "--debounce", "0s", "--exclude", "*.tmp", "dir/", "s3://"+bucket+"/prefix/")
+	withWorkingDir(workdir)(&cmd)
+
+	result := icmd.StartCmd(cmd)
+	assert.NilError(t, result.Error)
+
+	waitForObject := func(key, content string) {
+		t.Helper()
+
+		var err error
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); {
+			if err = ensureS3Object(s3client, bucket, key, content); err == nil {
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		t.Fatalf("object %q is not uploaded: %v", key, err)
+	}
+
+	waitForObject("prefix/existing.log", "existing file")
+
+	newfile := filepath.Join(workdir.Path(), "dir", "a", "new.log")
+	assert.NilError(t, os.MkdirAll(filepath.Dir(newfile), 0755))
+	assert.NilError(t, ioutil.WriteFile(newfile, []byte("new file"), 0644))
+
+	waitForObject("prefix/a/new.log", "new file")
+
+	existing := filepath.Join(workdir.Path(), "dir", "existing.log")
+	assert.NilError(t, ioutil.WriteFile(existing, []byte("modified existing file"), 0644))
+
+	waitForObject("prefix/existing.log", "modified existing file")
+
+	assert.NilError(t, result.Cmd.Process.Signal(os.Interrupt))
+	icmd.WaitOnCmd(10*time.Second, result).Assert(t, icmd.Success)
+
+	err := ensureS3Object(s3client, bucket, "prefix/ignored.tmp", "temporary file")
+	assertError(t, err, errS3NoSuchKey)
+}
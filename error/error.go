@@ -26,6 +26,9 @@ type Error struct {
 
 // FullCommand returns the command string that occurred at.
 func (e *Error) FullCommand() string {
+	if e.Dst == nil {
+		return fmt.Sprintf("%v %v", e.Op, e.Src)
+	}
 	return fmt.Sprintf("%v %v %v", e.Op, e.Src, e.Dst)
 }
 
@@ -76,13 +79,22 @@ var (
 
 	// ErrObjectSizesMatch indicates the sizes of objects match.
 	ErrObjectSizesMatch = fmt.Errorf("object size matches")
+
+	// ErrObjectContentsMatch indicates the sizes and the checksums of objects
+	// match.
+	ErrObjectContentsMatch = fmt.Errorf("object content matches")
+
+	// ErrObjectInJournal indicates a specified object is already transferred
+	// by a previous run.
+	ErrObjectInJournal = fmt.Errorf("object is already transferred")
 )
 
 // IsWarning checks if given error is either ErrObjectExists,
-// ErrObjectIsNewer or ErrObjectSizesMatch.
+// ErrObjectIsNewer, ErrObjectSizesMatch, ErrObjectContentsMatch or
+// ErrObjectInJournal.
 func IsWarning(err error) bool {
 	switch err {
-	case ErrObjectExists, ErrObjectIsNewer, ErrObjectSizesMatch:
+	case ErrObjectExists, ErrObjectIsNewer, ErrObjectSizesMatch, ErrObjectContentsMatch, ErrObjectInJournal:
 		return true
 	}
 
@@ -14,11 +14,11 @@ require (
 	github.com/kr/pretty v0.2.0 // indirect
 	github.com/posener/complete v1.2.3
 	github.com/stretchr/testify v1.4.0
-	github.com/termie/go-shutil v0.0.0-20140729215957-bcacb06fecae
 	github.com/urfave/cli/v2 v2.2.0
 	golang.org/x/sys v0.0.0-20190422165155-953cdadca894 // indirect
 	golang.org/x/text v0.3.2 // indirect
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
+	gopkg.in/yaml.v2 v2.2.8
 	gotest.tools/v3 v3.0.2
 )
 
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// ANSI escape codes of the colors.
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorCyan   = "\x1b[36m"
+)
+
+// colorizer is implemented by the messages which highlight their parts, e.g.
+// the keys, in colored output.
+type colorizer interface {
+	ColorString() string
+}
+
+// SetColor enables colored output for the standard output and the standard
+// error, if they are terminals. Colors are disabled for JSON output, for the
+// log sinks, on Windows consoles, or if NO_COLOR environment variable is set.
+func SetColor(enabled bool) {
+	if !enabled || global.json || global.sink != nil || runtime.GOOS == "windows" || os.Getenv("NO_COLOR") != "" {
+		return
+	}
+	global.colorStdout = isTerminal(os.Stdout)
+	global.colorStderr = isTerminal(os.Stderr)
+}
+
+// colored reports whether the messages written to std are colored.
+func (l *Logger) colored(std *os.File) bool {
+	switch std {
+	case os.Stdout:
+		return l.colorStdout
+	case os.Stderr:
+		return l.colorStderr
+	default:
+		return false
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return st.Mode()&os.ModeCharDevice != 0
+}
+
+func colorize(s, color string) string {
+	return color + s + colorReset
+}
+
+// colorString returns the colored representation of the level.
+func (l logLevel) colorString() string {
+	switch l {
+	case levelError:
+		return colorize("ERROR", colorRed) + " "
+	case levelDebug:
+		return colorize("DEBUG", colorYellow) + " "
+	default:
+		return l.String()
+	}
+}
+
+// ColorString is the colored representation of InfoMessage, which highlights
+// the keys.
+func (i InfoMessage) ColorString() string {
+	if i.Destination != nil {
+		return fmt.Sprintf("%v %v %v", i.Operation, colorize(i.Source.String(), colorCyan), colorize(i.Destination.String(), colorCyan))
+	}
+	return fmt.Sprintf("%v %v", i.Operation, colorize(i.Source.String(), colorCyan))
+}
+
+// ColorString is the colored representation of ErrorMessage, which highlights
+// the failed command.
+func (e ErrorMessage) ColorString() string {
+	if e.Command == "" {
+		return e.Err
+	}
+	return fmt.Sprintf("%v: %v", colorize(fmt.Sprintf("%q", e.Command), colorCyan), e.Err)
+}
+
+// ColorString is the colored representation of DebugMessage, which highlights
+// the command.
+func (d DebugMessage) ColorString() string {
+	if d.Command == "" {
+		return d.Err
+	}
+	return fmt.Sprintf("%v: %v", colorize(fmt.Sprintf("%q", d.Command), colorCyan), d.Err)
+}
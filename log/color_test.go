@@ -0,0 +1,72 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func TestColorString(t *testing.T) {
+	t.Parallel()
+
+	src, err := url.New("s3://bucket/key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst, err := url.New("dir/key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		message colorizer
+		want    string
+	}{
+		{
+			name:    "info",
+			message: InfoMessage{Operation: "cp", Source: src, Destination: dst},
+			want:    "cp \x1b[36ms3://bucket/key\x1b[0m \x1b[36mdir/key\x1b[0m",
+		},
+		{
+			name:    "info without destination",
+			message: InfoMessage{Operation: "rm", Source: src},
+			want:    "rm \x1b[36ms3://bucket/key\x1b[0m",
+		},
+		{
+			name:    "error",
+			message: ErrorMessage{Command: "rm s3://bucket/key", Err: "access denied"},
+			want:    "\x1b[36m\"rm s3://bucket/key\"\x1b[0m: access denied",
+		},
+		{
+			name:    "error without command",
+			message: ErrorMessage{Err: "access denied"},
+			want:    "access denied",
+		},
+	}
+
+	for _, tc := range tests {
+		if got := tc.message.ColorString(); got != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLevelColorString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level logLevel
+		want  string
+	}{
+		{level: levelInfo, want: ""},
+		{level: levelError, want: "\x1b[31mERROR\x1b[0m "},
+		{level: levelDebug, want: "\x1b[33mDEBUG\x1b[0m "},
+	}
+
+	for _, tc := range tests {
+		if got := tc.level.colorString(); got != tc.want {
+			t.Errorf("got %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -8,7 +8,12 @@ import (
 // output is an internal container for messages to be logged.
 type output struct {
 	std     *os.File
+	level   logLevel
 	message string
+
+	// sink replaces the sink of the logger if done is set.
+	sink sink
+	done chan struct{}
 }
 
 // outputCh is used to synchronize writes to standard output. Multi-line
@@ -27,11 +32,34 @@ func Debug(msg Message) {
 	global.printf(levelDebug, msg, os.Stdout)
 }
 
-// Info prints message in info mode.
+// SetQuiet makes the global logger skip the messages of Info, so only the
+// errors, the summaries and the progress of a run are printed.
+func SetQuiet(quiet bool) {
+	global.quiet = quiet
+}
+
+// Info prints message in info mode. Messages are not even formatted if the
+// logger is quiet.
 func Info(msg Message) {
+	if global.quiet {
+		return
+	}
 	global.printf(levelInfo, msg, os.Stdout)
 }
 
+// Summary prints message in info mode, even if the logger is quiet. It is
+// used for the summaries of the runs and the outputs of the queries, such as
+// ls and stat, instead of the results of the jobs.
+func Summary(msg Message) {
+	global.printf(levelInfo, msg, os.Stdout)
+}
+
+// Progress prints message in info mode to standard error, so the progress
+// and the status of a run are not mixed with its output.
+func Progress(msg Message) {
+	global.printf(levelInfo, msg, os.Stderr)
+}
+
 // Error prints message in error mode.
 func Error(msg Message) {
 	global.printf(levelError, msg, os.Stderr)
@@ -48,6 +76,13 @@ type Logger struct {
 	donech chan struct{}
 	json   bool
 	level  logLevel
+	quiet  bool
+	sink   sink
+
+	// colorStdout and colorStderr are set if the messages written to the
+	// standard output and the standard error are colored.
+	colorStdout bool
+	colorStderr bool
 }
 
 // New creates new logger.
@@ -71,11 +106,23 @@ func (l *Logger) printf(level logLevel, message Message, std *os.File) {
 	if l.json {
 		outputCh <- output{
 			message: message.JSON(),
+			level:   level,
+			std:     std,
+		}
+	} else if l.colored(std) {
+		text := message.String()
+		if c, ok := message.(colorizer); ok {
+			text = c.ColorString()
+		}
+		outputCh <- output{
+			message: level.colorString() + text,
+			level:   level,
 			std:     std,
 		}
 	} else {
 		outputCh <- output{
 			message: fmt.Sprintf("%v%v", level, message.String()),
+			level:   level,
 			std:     std,
 		}
 	}
@@ -86,7 +133,16 @@ func (l *Logger) out() {
 	defer close(l.donech)
 
 	for output := range outputCh {
-		_, _ = fmt.Fprintln(output.std, output.message)
+		if output.done != nil {
+			l.sink = output.sink
+			close(output.done)
+			continue
+		}
+		l.writeOutput(output)
+	}
+
+	if l.sink != nil {
+		_ = l.sink.close()
 	}
 }
 
@@ -37,10 +37,13 @@ func (i InfoMessage) JSON() string {
 }
 
 // ErrorMessage is a generic message structure for unsuccessful operations.
+// Code is the normalized error code of the failure, if it is known, and it is
+// only printed in JSON.
 type ErrorMessage struct {
 	Operation string `json:"operation,omitempty"`
 	Command   string `json:"command,omitempty"`
 	Err       string `json:"error"`
+	Code      string `json:"code,omitempty"`
 }
 
 // String is the string representation of ErrorMessage.
@@ -75,3 +78,22 @@ func (d DebugMessage) String() string {
 func (d DebugMessage) JSON() string {
 	return strutil.JSON(d)
 }
+
+// SkipMessage is a message structure for the commands which are not run,
+// such as the duplicates of other commands.
+type SkipMessage struct {
+	Command string `json:"command"`
+	Skipped bool   `json:"skipped"`
+	Reason  string `json:"reason"`
+}
+
+// String is the string representation of SkipMessage.
+func (s SkipMessage) String() string {
+	return fmt.Sprintf("skip %q: %v", s.Command, s.Reason)
+}
+
+// JSON is the JSON representation of SkipMessage.
+func (s SkipMessage) JSON() string {
+	s.Skipped = true
+	return strutil.JSON(s)
+}
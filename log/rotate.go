@@ -0,0 +1,184 @@
+package log
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupTimeFormat is the format of the timestamps appended to the names of
+// the rotated log files. They are sorted in the order of the rotations.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Rotation is the rotation policy of the log files. A zero value field
+// disables the related rotation.
+type Rotation struct {
+	// MaxSize is the size of a log file in bytes which rotates it.
+	MaxSize int64
+
+	// Interval is the age of a log file which rotates it.
+	Interval time.Duration
+
+	// MaxBackups is the number of the rotated log files to keep. Older ones
+	// are removed.
+	MaxBackups int
+}
+
+// SetFileSink makes the global logger write the messages to the file, which
+// is rotated with given policy.
+func SetFileSink(path string, rotation Rotation) error {
+	s, err := newFileSink(path, rotation, time.Now)
+	if err != nil {
+		return fmt.Errorf("log file: %w", err)
+	}
+	global.setSink(s)
+	return nil
+}
+
+// fileSink appends the messages to a file. The file is renamed with the
+// timestamp of the rotation and a new one is created once it is rotated.
+type fileSink struct {
+	path     string
+	rotation Rotation
+	now      func() time.Time
+
+	f        *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newFileSink(path string, rotation Rotation, now func() time.Time) (*fileSink, error) {
+	s := &fileSink{
+		path:     path,
+		rotation: rotation,
+		now:      now,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	s.f, s.size, s.openedAt = f, st.Size(), s.now()
+	return nil
+}
+
+func (s *fileSink) write(_ logLevel, message string) error {
+	line := message + "\n"
+	if s.shouldRotate(int64(len(line))) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.f.WriteString(line)
+	s.size += int64(n)
+	return err
+}
+
+// shouldRotate reports whether the file is rotated before n bytes are
+// written. An empty file is not rotated, so a message larger than the
+// maximum size is still written.
+func (s *fileSink) shouldRotate(n int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.rotation.MaxSize > 0 && s.size+n > s.rotation.MaxSize {
+		return true
+	}
+	return s.rotation.Interval > 0 && s.now().Sub(s.openedAt) >= s.rotation.Interval
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(s.path, s.backupPath()); err != nil {
+		return err
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	return s.removeOldBackups()
+}
+
+// backupPath returns the path of the rotated file. The timestamp is moved
+// forward if a file is already rotated with it, so it is not replaced.
+func (s *fileSink) backupPath() string {
+	t := s.now()
+	for {
+		path := s.path + "." + t.Format(backupTimeFormat)
+		if _, err := os.Stat(path); err != nil {
+			return path
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// removeOldBackups removes the rotated files except for the latest ones,
+// if the number of backups is limited.
+func (s *fileSink) removeOldBackups() error {
+	if s.rotation.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := s.backups()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= s.rotation.MaxBackups {
+		return nil
+	}
+
+	for _, backup := range backups[:len(backups)-s.rotation.MaxBackups] {
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// backups returns the rotated files of the log file, the oldest first.
+func (s *fileSink) backups() ([]string, error) {
+	dir, base := filepath.Split(s.path)
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base+".") {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(name, base+".")); err == nil {
+			backups = append(backups, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func (s *fileSink) close() error { return s.f.Close() }
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSinkRotation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rotation Rotation
+		elapsed  time.Duration
+		// lines of the log file and of its backups, the oldest backup first.
+		want []string
+	}{
+		{
+			name:     "no rotation",
+			rotation: Rotation{},
+			want:     []string{"line 0\nline 1\nline 2\nline 3\n"},
+		},
+		{
+			name:     "max size",
+			rotation: Rotation{MaxSize: 14},
+			want:     []string{"line 0\nline 1\n", "line 2\nline 3\n"},
+		},
+		{
+			name:     "interval",
+			rotation: Rotation{Interval: 3 * time.Minute},
+			elapsed:  time.Minute,
+			want:     []string{"line 0\nline 1\n", "line 2\nline 3\n"},
+		},
+		{
+			name:     "max backups",
+			rotation: Rotation{MaxSize: 7, MaxBackups: 2},
+			want:     []string{"line 1\n", "line 2\n", "line 3\n"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir, err := ioutil.TempDir("", "s5cmd-log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+			clock := func() time.Time { return now }
+
+			path := filepath.Join(dir, "s5cmd.log")
+			s, err := newFileSink(path, tc.rotation, clock)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, line := range []string{"line 0", "line 1", "line 2", "line 3"} {
+				// each write is a second later at least, so the backups
+				// are not named with the same timestamp.
+				now = now.Add(time.Second + tc.elapsed)
+				if err := s.write(levelInfo, line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.close(); err != nil {
+				t.Fatal(err)
+			}
+
+			backups, err := s.backups()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, name := range append(backups, path) {
+				content, err := ioutil.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, string(content))
+			}
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("file %v: got %q, want %q", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
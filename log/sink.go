@@ -0,0 +1,67 @@
+package log
+
+import (
+	"fmt"
+	"os"
+)
+
+// names of the log sinks.
+const (
+	SinkStdout   = "stdout"
+	SinkSyslog   = "syslog"
+	SinkJournald = "journald"
+)
+
+// sink is a destination of the logs other than the standard output and the
+// standard error. Messages are written by the output loop of the logger, so
+// a sink is never written concurrently.
+type sink interface {
+	write(level logLevel, message string) error
+	close() error
+}
+
+// SetSink makes the global logger write the messages to the sink with given
+// name, instead of the standard output and the standard error.
+func SetSink(name string) error {
+	var (
+		s   sink
+		err error
+	)
+	switch name {
+	case "", SinkStdout:
+		return nil
+	case SinkSyslog:
+		s, err = newSyslogSink()
+	case SinkJournald:
+		s, err = newJournaldSink(journaldSocket)
+	default:
+		return fmt.Errorf("unknown log sink %q, expected one of %v, %v or %v", name, SinkStdout, SinkSyslog, SinkJournald)
+	}
+	if err != nil {
+		return fmt.Errorf("%v log sink: %w", name, err)
+	}
+	global.setSink(s)
+	return nil
+}
+
+// setSink replaces the sink of the logger. Messages are flushed with the
+// previous sink first, since the output loop might still be writing them.
+func (l *Logger) setSink(s sink) {
+	done := make(chan struct{})
+	outputCh <- output{sink: s, done: done}
+	<-done
+}
+
+// writeOutput writes the output to the sink of the logger, or to its
+// standard output or error. Messages which can not be written to the sink
+// are printed to the standard error, so they are not lost.
+func (l *Logger) writeOutput(o output) {
+	if l.sink != nil {
+		if err := l.sink.write(o.level, o.message); err == nil {
+			return
+		}
+		_, _ = fmt.Fprintln(os.Stderr, o.message)
+		return
+	}
+	_, _ = fmt.Fprintln(o.std, o.message)
+}
@@ -0,0 +1,96 @@
+// +build !windows
+
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/syslog"
+	"net"
+	"strings"
+)
+
+// identifier is the tag of the messages in syslog and journald.
+const identifier = "s5cmd"
+
+// journaldSocket is the native protocol socket of systemd-journald.
+const journaldSocket = "/run/systemd/journal/socket"
+
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink() (sink, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, identifier)
+	if err != nil {
+		return nil, err
+	}
+	return syslogSink{w: w}, nil
+}
+
+func (s syslogSink) write(level logLevel, message string) error {
+	switch level {
+	case levelError:
+		return s.w.Err(message)
+	case levelDebug:
+		return s.w.Debug(message)
+	default:
+		return s.w.Info(message)
+	}
+}
+
+func (s syslogSink) close() error { return s.w.Close() }
+
+// journaldSink writes the messages to journald with its native protocol, so
+// the priorities and the multi-line messages are kept.
+type journaldSink struct {
+	conn *net.UnixConn
+}
+
+func newJournaldSink(path string) (sink, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return journaldSink{conn: conn}, nil
+}
+
+func (s journaldSink) write(level logLevel, message string) error {
+	_, err := s.conn.Write(journaldEntry(level, message))
+	return err
+}
+
+func (s journaldSink) close() error { return s.conn.Close() }
+
+// journaldEntry encodes the message with the syslog priority of the level.
+// Multi-line values are prefixed with their lengths as the protocol
+// requires.
+func journaldEntry(level logLevel, message string) []byte {
+	var buf bytes.Buffer
+	field := func(name, value string) {
+		if !strings.Contains(value, "\n") {
+			buf.WriteString(name + "=" + value + "\n")
+			return
+		}
+		buf.WriteString(name + "\n")
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value + "\n")
+	}
+
+	field("PRIORITY", level.priority())
+	field("SYSLOG_IDENTIFIER", identifier)
+	field("MESSAGE", message)
+	return buf.Bytes()
+}
+
+// priority returns the syslog priority of the level.
+func (l logLevel) priority() string {
+	switch l {
+	case levelError:
+		return "3"
+	case levelDebug:
+		return "7"
+	default:
+		return "6"
+	}
+}
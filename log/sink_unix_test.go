@@ -0,0 +1,66 @@
+// +build !windows
+
+package log
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournaldSink(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "s5cmd-journald")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	s, err := newJournaldSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.close()
+
+	tests := []struct {
+		level   logLevel
+		message string
+		want    string
+	}{
+		{
+			level:   levelError,
+			message: `ERROR "cp s3://bucket/key dir/": access denied`,
+			want:    "PRIORITY=3\nSYSLOG_IDENTIFIER=s5cmd\nMESSAGE=ERROR \"cp s3://bucket/key dir/\": access denied\n",
+		},
+		{
+			level:   levelInfo,
+			message: "line 1\nline 2",
+			want:    "PRIORITY=6\nSYSLOG_IDENTIFIER=s5cmd\nMESSAGE\n\x0d\x00\x00\x00\x00\x00\x00\x00line 1\nline 2\n",
+		},
+	}
+
+	buf := make([]byte, 1024)
+	for _, tc := range tests {
+		if err := s.write(tc.level, tc.message); err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := string(buf[:n]); got != tc.want {
+			t.Errorf("got %q, want %q", got, tc.want)
+		}
+	}
+}
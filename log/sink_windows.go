@@ -0,0 +1,14 @@
+// +build windows
+
+package log
+
+import "errors"
+
+// journaldSocket is not used on Windows, since there is no journald.
+const journaldSocket = ""
+
+var errSinkNotSupported = errors.New("not supported on Windows")
+
+func newSyslogSink() (sink, error) { return nil, errSinkNotSupported }
+
+func newJournaldSink(string) (sink, error) { return nil, errSinkNotSupported }
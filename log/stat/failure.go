@@ -0,0 +1,45 @@
+package stat
+
+import "sync"
+
+var failures = struct {
+	sync.Mutex
+	list []Failure
+}{}
+
+// Failure is a failed job of the run.
+type Failure struct {
+	Command   string `json:"command,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Err       string `json:"error"`
+}
+
+// AddFailure records a failed job.
+func AddFailure(command, op, err string) {
+	if !enabled {
+		return
+	}
+
+	failures.Lock()
+	defer failures.Unlock()
+
+	failures.list = append(failures.list, Failure{
+		Command:   command,
+		Operation: op,
+		Err:       err,
+	})
+}
+
+// Failures returns the failed jobs recorded so far.
+func Failures() []Failure {
+	if !enabled {
+		return []Failure{}
+	}
+
+	failures.Lock()
+	defer failures.Unlock()
+
+	result := make([]Failure, len(failures.list))
+	copy(result, failures.list)
+	return result
+}
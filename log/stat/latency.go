@@ -0,0 +1,158 @@
+package stat
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/peak/s5cmd/strutil"
+)
+
+const (
+	// latencies are counted in exponential buckets, each bucket is about 9%
+	// wider than the previous one, starting from histogramBase.
+	histogramBase    = 100 * time.Microsecond
+	histogramFactor  = 1.0905077326652577 // 2^(1/8)
+	histogramBuckets = 240
+)
+
+var latencies = struct {
+	sync.Mutex
+	histograms map[string]*histogram
+}{}
+
+// histogram is a latency distribution with bounded memory usage.
+type histogram struct {
+	count   int64
+	buckets [histogramBuckets]int64
+}
+
+func bucketOf(d time.Duration) int {
+	if d < histogramBase {
+		return 0
+	}
+	i := int(math.Log(float64(d)/float64(histogramBase))/math.Log(histogramFactor)) + 1
+	if i >= histogramBuckets {
+		return histogramBuckets - 1
+	}
+	return i
+}
+
+// upperBound returns the upper bound of the bucket.
+func upperBound(i int) time.Duration {
+	return time.Duration(float64(histogramBase) * math.Pow(histogramFactor, float64(i)))
+}
+
+func (h *histogram) add(d time.Duration) {
+	h.count++
+	h.buckets[bucketOf(d)]++
+}
+
+// percentile returns the upper bound of the bucket which contains the p-th
+// percentile of the latencies.
+func (h *histogram) percentile(p float64) time.Duration {
+	rank := int64(math.Ceil(float64(h.count) * p / 100))
+	var cumulative int64
+	for i, n := range h.buckets {
+		cumulative += n
+		if cumulative >= rank {
+			return upperBound(i)
+		}
+	}
+	return upperBound(histogramBuckets - 1)
+}
+
+// AddLatency records the latency of a request of the operation.
+func AddLatency(op string, d time.Duration) {
+	if !enabled {
+		return
+	}
+
+	latencies.Lock()
+	defer latencies.Unlock()
+
+	h, ok := latencies.histograms[op]
+	if !ok {
+		h = &histogram{}
+		latencies.histograms[op] = h
+	}
+	h.add(d)
+}
+
+// Latency is the latency distribution of an operation. Percentiles are in
+// milliseconds.
+type Latency struct {
+	Operation string  `json:"operation"`
+	Count     int64   `json:"count"`
+	P50       float64 `json:"p50_ms"`
+	P95       float64 `json:"p95_ms"`
+	P99       float64 `json:"p99_ms"`
+}
+
+// Latencies implements log.Message interface.
+type Latencies []Latency
+
+func (l Latencies) String() string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 8, 1, '\t', tabwriter.AlignRight)
+
+	fmt.Fprintf(w, "\n%s\t%s\t%s\t%s\t%s\t\n", "Request", "Count", "p50", "p95", "p99")
+	for _, latency := range l {
+		fmt.Fprintf(w, "%s\t%d\t%.2fms\t%.2fms\t%.2fms\t\n",
+			latency.Operation,
+			latency.Count,
+			latency.P50,
+			latency.P95,
+			latency.P99,
+		)
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+func (l Latencies) JSON() string {
+	var builder strings.Builder
+
+	for _, latency := range l {
+		builder.WriteString(strutil.JSON(latency) + "\n")
+	}
+	return builder.String()
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*100) / 100
+}
+
+// LatencyStatistics returns the latency distributions of the operations
+// collected so far, sorted by operation names.
+func LatencyStatistics() Latencies {
+	if !enabled {
+		return Latencies{}
+	}
+
+	latencies.Lock()
+	defer latencies.Unlock()
+
+	result := Latencies{}
+	for op, h := range latencies.histograms {
+		result = append(result, Latency{
+			Operation: op,
+			Count:     h.count,
+			P50:       milliseconds(h.percentile(50)),
+			P95:       milliseconds(h.percentile(95)),
+			P99:       milliseconds(h.percentile(99)),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Operation < result[j].Operation
+	})
+	return result
+}
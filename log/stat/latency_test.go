@@ -0,0 +1,47 @@
+package stat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramPercentile(t *testing.T) {
+	t.Parallel()
+
+	var h histogram
+	for i := 1; i <= 100; i++ {
+		h.add(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		percentile float64
+		want       time.Duration
+	}{
+		{percentile: 50, want: 50 * time.Millisecond},
+		{percentile: 95, want: 95 * time.Millisecond},
+		{percentile: 99, want: 99 * time.Millisecond},
+	}
+
+	for _, tc := range tests {
+		got := h.percentile(tc.percentile)
+
+		// the result is the upper bound of the bucket of the percentile.
+		if got < tc.want || float64(got) > float64(tc.want)*histogramFactor {
+			t.Errorf("p%v: got %v, want between %v and %v", tc.percentile, got, tc.want, time.Duration(float64(tc.want)*histogramFactor))
+		}
+	}
+}
+
+func TestHistogramBucketBounds(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []time.Duration{0, time.Microsecond, histogramBase, time.Millisecond, time.Second, 24 * time.Hour} {
+		i := bucketOf(d)
+		if i < histogramBuckets-1 && d > upperBound(i) {
+			t.Errorf("%v: upper bound of bucket %v is %v", d, i, upperBound(i))
+		}
+		if i > 0 && d < upperBound(i-1) {
+			t.Errorf("%v: lower bound of bucket %v is %v", d, i, upperBound(i-1))
+		}
+	}
+}
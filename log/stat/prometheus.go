@@ -0,0 +1,71 @@
+package stat
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WritePrometheus writes the statistics collected so far in Prometheus text
+// exposition format.
+func WritePrometheus(w io.Writer) {
+	if !enabled {
+		return
+	}
+
+	total := stats[totalCount].snapshot()
+	success := stats[succCount].snapshot()
+
+	errors := make(map[string]int64, len(total))
+	for op, n := range total {
+		errors[op] = n - success[op]
+	}
+
+	writeCounter(w, "s5cmd_operations_total", "Number of completed operations.", "operation", map[string]map[string]int64{
+		`status="success"`: success,
+		`status="error"`:   errors,
+	})
+	writeCounter(w, "s5cmd_transferred_bytes_total", "Number of bytes transferred by operations.", "operation", map[string]map[string]int64{
+		"": stats[byteCount].snapshot(),
+	})
+	writeCounter(w, "s5cmd_s3_requests_total", "Number of completed S3 API requests.", "api", map[string]map[string]int64{
+		"": stats[requestCount].snapshot(),
+	})
+	writeCounter(w, "s5cmd_s3_request_errors_total", "Number of failed S3 API requests.", "api", map[string]map[string]int64{
+		"": stats[requestErrCount].snapshot(),
+	})
+	writeCounter(w, "s5cmd_s3_retries_total", "Number of retried S3 API requests.", "api", map[string]map[string]int64{
+		"": stats[retryCount].snapshot(),
+	})
+}
+
+// WritePrometheusGauge writes a gauge in Prometheus text exposition format.
+func WritePrometheusGauge(w io.Writer, name, help string, value int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, value)
+}
+
+// writeCounter writes a counter whose samples are labeled with the given
+// label and the extra labels of the groups. Samples are sorted for a stable
+// output.
+func writeCounter(w io.Writer, name, help, label string, groups map[string]map[string]int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+
+	var lines []string
+	for extra, values := range groups {
+		for key, value := range values {
+			labels := fmt.Sprintf("%s=%q", label, key)
+			if extra != "" {
+				labels += "," + extra
+			}
+			lines = append(lines, fmt.Sprintf("%s{%s} %d", name, labels, value))
+		}
+	}
+	sort.Strings(lines)
+
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync"
 	"text/tabwriter"
+	"time"
 
 	"github.com/peak/s5cmd/strutil"
 )
@@ -13,18 +14,43 @@ import (
 const (
 	totalCount = iota
 	succCount
+	byteCount
+	uploadCount
+	downloadCount
+
+	// counters of S3 API requests, keyed by API operation names.
+	requestCount
+	requestErrCount
+	retryCount
 )
 
 var (
 	enabled bool
 	stats   statistics
+	started time.Time
 )
 
-type statistics [2]syncMapStrInt64
+type statistics [8]syncMapStrInt64
+
+// Direction is the direction of transferred bytes.
+type Direction int
+
+const (
+	// Upload is a transfer from the host to the remote server.
+	Upload Direction = iota
+	// Download is a transfer from the remote server to the host.
+	Download
+	// Copy is a transfer which does not pass through the host, such as a
+	// server-side copy or a local copy.
+	Copy
+)
 
 // InitStat initializes collecting program statistics.
 func InitStat() {
 	enabled = true
+	started = time.Now()
+	latencies.histograms = map[string]*histogram{}
+	failures.list = nil
 	for i := range stats {
 		stats[i] = syncMapStrInt64{
 			Mutex:       sync.Mutex{},
@@ -46,11 +72,28 @@ func (s *syncMapStrInt64) add(key string, val int64) {
 	s.mapStrInt64[key] += val
 }
 
-// Stat is for storing a particular statistics.
+// snapshot returns a copy of the map.
+func (s *syncMapStrInt64) snapshot() map[string]int64 {
+	s.Lock()
+	defer s.Unlock()
+
+	m := make(map[string]int64, len(s.mapStrInt64))
+	for k, v := range s.mapStrInt64 {
+		m[k] = v
+	}
+	return m
+}
+
+// Stat is for storing a particular statistics. Throughput is the average
+// number of transferred bytes per second since the statistics are started.
 type Stat struct {
-	Operation string `json:"operation"`
-	Success   int64  `json:"success"`
-	Error     int64  `json:"error"`
+	Operation  string `json:"operation"`
+	Success    int64  `json:"success"`
+	Error      int64  `json:"error"`
+	Bytes      int64  `json:"bytes"`
+	Uploaded   int64  `json:"uploaded_bytes"`
+	Downloaded int64  `json:"downloaded_bytes"`
+	Throughput int64  `json:"bytes_per_second"`
 }
 
 // Collect collects function execution data.
@@ -66,6 +109,49 @@ func Collect(op string, err *error) func() {
 	}
 }
 
+// AddBytes adds the number of bytes transferred by the operation in given
+// direction.
+func AddBytes(op string, n int64, dir Direction) {
+	if !enabled {
+		return
+	}
+	stats[byteCount].add(op, n)
+	switch dir {
+	case Upload:
+		stats[uploadCount].add(op, n)
+	case Download:
+		stats[downloadCount].add(op, n)
+	}
+}
+
+// AddRequest counts a completed S3 API request and its retries.
+func AddRequest(op string, failed bool, retries int) {
+	if !enabled {
+		return
+	}
+	stats[requestCount].add(op, 1)
+	if failed {
+		stats[requestErrCount].add(op, 1)
+	}
+	if retries > 0 {
+		stats[retryCount].add(op, int64(retries))
+	}
+}
+
+// TransferredBytes returns the number of bytes transferred by all
+// operations so far.
+func TransferredBytes() int64 {
+	if !enabled {
+		return 0
+	}
+
+	var total int64
+	for _, n := range stats[byteCount].snapshot() {
+		total += n
+	}
+	return total
+}
+
 // Stats implements log.Message interface.
 type Stats []Stat
 
@@ -74,9 +160,17 @@ func (s Stats) String() string {
 
 	w := tabwriter.NewWriter(&buf, 0, 8, 1, '\t', tabwriter.AlignRight)
 
-	fmt.Fprintf(w, "\n%s\t%s\t%s\t%s\t\n", "Operation", "Total", "Error", "Success")
+	fmt.Fprintf(w, "\n%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", "Operation", "Total", "Error", "Success", "Uploaded", "Downloaded", "Throughput")
 	for _, stat := range s {
-		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t\n", stat.Operation, stat.Error+stat.Success, stat.Error, stat.Success)
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\t%s/s\t\n",
+			stat.Operation,
+			stat.Error+stat.Success,
+			stat.Error,
+			stat.Success,
+			strutil.HumanizeBytes(stat.Uploaded),
+			strutil.HumanizeBytes(stat.Downloaded),
+			strutil.HumanizeBytes(stat.Throughput),
+		)
 	}
 
 	w.Flush()
@@ -98,14 +192,26 @@ func Statistics() Stats {
 		return Stats{}
 	}
 
+	elapsed := time.Since(started).Seconds()
+
 	var result Stats
 	for op, total := range stats[totalCount].mapStrInt64 {
 		success := stats[succCount].mapStrInt64[op]
+		bytes := stats[byteCount].mapStrInt64[op]
+
+		var throughput int64
+		if elapsed > 0 {
+			throughput = int64(float64(bytes) / elapsed)
+		}
 
 		result = append(result, Stat{
-			Operation: op,
-			Success:   success,
-			Error:     total - success,
+			Operation:  op,
+			Success:    success,
+			Error:      total - success,
+			Bytes:      bytes,
+			Uploaded:   stats[uploadCount].mapStrInt64[op],
+			Downloaded: stats[downloadCount].mapStrInt64[op],
+			Throughput: throughput,
 		})
 	}
 	return result
@@ -21,6 +21,6 @@ func main() {
 	}()
 
 	if err := command.Main(ctx, os.Args); err != nil {
-		os.Exit(1)
+		os.Exit(command.ExitCode(err))
 	}
 }
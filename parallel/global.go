@@ -11,9 +11,76 @@ func Init(workercount int) {
 	global = New(workercount)
 }
 
-// Close waits all jobs to finish and
-// closes the semaphore of global ParallelManager.
+// Close waits all jobs of global ParallelManager to finish.
 func Close() { global.Close() }
 
+// WorkerCount returns the number of workers of global ParallelManager.
+func WorkerCount() int {
+	if global == nil {
+		return 0
+	}
+	return global.Workers()
+}
+
+// Resize changes the number of workers of global ParallelManager and returns
+// the new number of workers.
+func Resize(workercount int) int {
+	if global == nil {
+		return 0
+	}
+	return global.Resize(workercount)
+}
+
+// BusyWorkerCount returns the number of running tasks of global
+// ParallelManager.
+func BusyWorkerCount() int {
+	if global == nil {
+		return 0
+	}
+	return global.Busy()
+}
+
+// WaitingTaskCount returns the number of tasks waiting for a worker of
+// global ParallelManager.
+func WaitingTaskCount() int {
+	if global == nil {
+		return 0
+	}
+	return global.Waiting()
+}
+
+// CompletedTaskCount returns the number of finished tasks of global
+// ParallelManager.
+func CompletedTaskCount() int {
+	if global == nil {
+		return 0
+	}
+	return global.Completed()
+}
+
+// SkippedTaskCount returns the number of tasks of global ParallelManager
+// which are not run since it is stopped.
+func SkippedTaskCount() int {
+	if global == nil {
+		return 0
+	}
+	return global.Skipped()
+}
+
+// Stop stops running new tasks of global ParallelManager.
+func Stop() {
+	if global != nil {
+		global.Stop()
+	}
+}
+
+// Stopped reports whether global ParallelManager is stopped.
+func Stopped() bool {
+	if global == nil {
+		return false
+	}
+	return global.Stopped()
+}
+
 // Run runs global ParallelManager.
 func Run(task Task, waiter *Waiter) { global.Run(task, waiter) }
@@ -3,6 +3,7 @@ package parallel
 import (
 	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -14,8 +15,27 @@ type Task func() error
 
 // Manager is a structure for running tasks in parallel.
 type Manager struct {
-	wg        *sync.WaitGroup
-	semaphore chan bool
+	wg *sync.WaitGroup
+
+	// running tasks are limited with workers, which can be resized while
+	// tasks are running. Tasks waiting for a worker are queued by their
+	// waiters, and idle workers are handed to the queues in turn, so the
+	// tasks of a command can not starve the tasks of the other commands.
+	mu      sync.Mutex
+	workers int
+	running int
+	queues  []*taskQueue
+	waiters map[*Waiter]*taskQueue
+
+	// waiting is the number of tasks waiting for a worker and completed is
+	// the number of finished tasks. skipped is the number of tasks which are
+	// not run since the manager is stopped.
+	waiting   int64
+	completed int64
+	skipped   int64
+
+	// stopped is set once new tasks are not run anymore.
+	stopped int32
 }
 
 // New creates a new parallel.Manager.
@@ -29,41 +49,161 @@ func New(workercount int) *Manager {
 	}
 
 	return &Manager{
-		wg:        &sync.WaitGroup{},
-		semaphore: make(chan bool, workercount),
+		wg:      &sync.WaitGroup{},
+		workers: workercount,
+		waiters: map[*Waiter]*taskQueue{},
 	}
 }
 
-// acquire limits concurrency by trying to acquire the semaphore.
-func (p *Manager) acquire() {
-	p.semaphore <- true
+// taskQueue is the tasks of a waiter which are waiting for a worker.
+type taskQueue struct {
+	waiter *Waiter
+	tasks  []chan struct{}
+}
+
+// acquire limits concurrency by waiting for an idle worker. Tasks are queued
+// if other tasks are already waiting, even if a worker is idle, so they can
+// not take the turn of the waiting ones.
+func (p *Manager) acquire(waiter *Waiter) {
 	p.wg.Add(1)
+
+	p.mu.Lock()
+	if p.running < p.workers && len(p.queues) == 0 {
+		p.running++
+		p.mu.Unlock()
+		return
+	}
+
+	ready := make(chan struct{})
+	queue, ok := p.waiters[waiter]
+	if !ok {
+		queue = &taskQueue{waiter: waiter}
+		p.waiters[waiter] = queue
+		p.queues = append(p.queues, queue)
+	}
+	queue.tasks = append(queue.tasks, ready)
+	p.mu.Unlock()
+
+	<-ready
 }
 
-// release releases the acquired semaphore to signal that a task is finished.
+// release signals that a task is finished and its worker is idle.
 func (p *Manager) release() {
+	p.mu.Lock()
+	p.running--
+	p.dispatch()
+	p.mu.Unlock()
 	p.wg.Done()
-	<-p.semaphore
 }
 
-// Run runs the given task while limiting the concurrency.
+// dispatch hands the idle workers to the waiting tasks, taking one task from
+// each queue in turn. It must be called with the lock held.
+func (p *Manager) dispatch() {
+	for p.running < p.workers && len(p.queues) > 0 {
+		queue := p.queues[0]
+		p.queues = p.queues[1:]
+
+		ready := queue.tasks[0]
+		queue.tasks = queue.tasks[1:]
+		if len(queue.tasks) > 0 {
+			p.queues = append(p.queues, queue)
+		} else {
+			delete(p.waiters, queue.waiter)
+		}
+
+		p.running++
+		close(ready)
+	}
+}
+
+// Resize changes the number of workers. Running tasks are not interrupted
+// when the workers are shrunk, new tasks wait for them to finish instead.
+func (p *Manager) Resize(workercount int) int {
+	if workercount < minNumWorkers {
+		workercount = minNumWorkers
+	}
+
+	p.mu.Lock()
+	p.workers = workercount
+	p.dispatch()
+	p.mu.Unlock()
+	return workercount
+}
+
+// Workers returns the number of workers.
+func (p *Manager) Workers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.workers
+}
+
+// Run runs the given task while limiting the concurrency. The task is not
+// run if the manager is stopped.
 func (p *Manager) Run(fn Task, waiter *Waiter) {
+	if p.Stopped() {
+		atomic.AddInt64(&p.skipped, 1)
+		return
+	}
+
 	waiter.wg.Add(1)
-	p.acquire()
+	atomic.AddInt64(&p.waiting, 1)
+	p.acquire(waiter)
+	atomic.AddInt64(&p.waiting, -1)
 	go func() {
 		defer waiter.wg.Done()
 		defer p.release()
 
-		if err := fn(); err != nil {
+		// the manager might be stopped while waiting for a worker.
+		if p.Stopped() {
+			atomic.AddInt64(&p.skipped, 1)
+			return
+		}
+
+		err := fn()
+		atomic.AddInt64(&p.completed, 1)
+		if err != nil {
 			waiter.errch <- err
 		}
 	}()
 }
 
+// Stop stops running new tasks, including the ones waiting for a worker.
+// Running tasks are not interrupted.
+func (p *Manager) Stop() {
+	atomic.StoreInt32(&p.stopped, 1)
+}
+
+// Stopped reports whether the manager is stopped.
+func (p *Manager) Stopped() bool {
+	return atomic.LoadInt32(&p.stopped) == 1
+}
+
+// Busy returns the number of running tasks.
+func (p *Manager) Busy() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running
+}
+
+// Waiting returns the number of tasks waiting for a worker.
+func (p *Manager) Waiting() int {
+	return int(atomic.LoadInt64(&p.waiting))
+}
+
+// Completed returns the number of finished tasks.
+func (p *Manager) Completed() int {
+	return int(atomic.LoadInt64(&p.completed))
+}
+
+// Skipped returns the number of tasks which are not run since the manager is
+// stopped.
+func (p *Manager) Skipped() int {
+	return int(atomic.LoadInt64(&p.skipped))
+}
+
 // Close waits all tasks to finish.
 func (p *Manager) Close() {
 	p.wg.Wait()
-	close(p.semaphore)
 }
 
 // Waiter is a structure for waiting and reading
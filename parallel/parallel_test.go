@@ -0,0 +1,226 @@
+package parallel
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// runBlocking runs n tasks which block until release is closed and returns
+// a function reporting the maximum number of tasks running at the same time.
+func runBlocking(p *Manager, waiter *Waiter, n int, release <-chan struct{}) func() int64 {
+	var running, max int64
+	var mu sync.Mutex
+
+	go func() {
+		for i := 0; i < n; i++ {
+			p.Run(func() error {
+				current := atomic.AddInt64(&running, 1)
+				mu.Lock()
+				if current > max {
+					max = current
+				}
+				mu.Unlock()
+
+				<-release
+				atomic.AddInt64(&running, -1)
+				return nil
+			}, waiter)
+		}
+	}()
+
+	return func() int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return max
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+	t.Fatal("condition is not met in time")
+}
+
+func TestManagerResizeGrow(t *testing.T) {
+	t.Parallel()
+
+	p := New(2)
+	waiter := NewWaiter()
+	release := make(chan struct{})
+
+	maxRunning := runBlocking(p, waiter, 8, release)
+	waitFor(t, func() bool { return p.Busy() == 2 })
+
+	if got := p.Resize(6); got != 6 {
+		t.Fatalf("Resize() = %v, expected 6", got)
+	}
+	waitFor(t, func() bool { return p.Busy() == 6 })
+
+	close(release)
+	go func() {
+		for range waiter.Err() {
+		}
+	}()
+	waitFor(t, func() bool { return p.Completed() == 8 })
+	waiter.Wait()
+	p.Close()
+
+	if max := maxRunning(); max != 6 {
+		t.Errorf("maximum running tasks = %v, expected 6", max)
+	}
+}
+
+func TestManagerResizeShrink(t *testing.T) {
+	t.Parallel()
+
+	p := New(4)
+	if got := p.Resize(0); got != minNumWorkers {
+		t.Fatalf("Resize(0) = %v, expected %v", got, minNumWorkers)
+	}
+	if got := p.Workers(); got != minNumWorkers {
+		t.Fatalf("Workers() = %v, expected %v", got, minNumWorkers)
+	}
+
+	waiter := NewWaiter()
+	release := make(chan struct{})
+
+	maxRunning := runBlocking(p, waiter, 6, release)
+	waitFor(t, func() bool { return p.Busy() == minNumWorkers })
+
+	// tasks waiting for a worker must not start until a task is finished.
+	time.Sleep(50 * time.Millisecond)
+	if busy := p.Busy(); busy != minNumWorkers {
+		t.Fatalf("Busy() = %v, expected %v", busy, minNumWorkers)
+	}
+
+	close(release)
+	go func() {
+		for range waiter.Err() {
+		}
+	}()
+	waitFor(t, func() bool { return p.Completed() == 6 })
+	waiter.Wait()
+	p.Close()
+
+	if max := maxRunning(); max != minNumWorkers {
+		t.Errorf("maximum running tasks = %v, expected %v", max, minNumWorkers)
+	}
+}
+
+func TestManagerFairScheduling(t *testing.T) {
+	t.Parallel()
+
+	p := New(2)
+
+	// a worker is kept busy until the end of the test, so the queued tasks
+	// run one at a time with the other worker.
+	block, unblock := make(chan struct{}), make(chan struct{})
+	blocker := NewWaiter()
+	go func() {
+		for range blocker.Err() {
+		}
+	}()
+	p.Run(func() error { <-block; return nil }, blocker)
+	p.Run(func() error { <-unblock; return nil }, blocker)
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) Task {
+		return func() error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	first, second := NewWaiter(), NewWaiter()
+	for _, waiter := range []*Waiter{first, second} {
+		w := waiter
+		go func() {
+			for range w.Err() {
+			}
+		}()
+	}
+
+	// the first command queues all of its tasks before the second one.
+	for i := 0; i < 4; i++ {
+		go p.Run(record("first"), first)
+		waitFor(t, func() bool { return p.Waiting() == i+1 })
+	}
+	for i := 0; i < 2; i++ {
+		go p.Run(record("second"), second)
+		waitFor(t, func() bool { return p.Waiting() == 4+i+1 })
+	}
+
+	close(unblock)
+	waitFor(t, func() bool { return p.Completed() == 7 })
+
+	close(block)
+	first.Wait()
+	second.Wait()
+	blocker.Wait()
+	p.Close()
+
+	expected := []string{"first", "second", "first", "second", "first", "first"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(order) != len(expected) {
+		t.Fatalf("order = %v, expected %v", order, expected)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("order = %v, expected %v", order, expected)
+		}
+	}
+}
+
+func TestManagerStop(t *testing.T) {
+	t.Parallel()
+
+	p := New(2)
+	waiter := NewWaiter()
+	go func() {
+		for range waiter.Err() {
+		}
+	}()
+
+	var completed int64
+	block := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		go p.Run(func() error {
+			<-block
+			atomic.AddInt64(&completed, 1)
+			return nil
+		}, waiter)
+	}
+	waitFor(t, func() bool { return p.Busy() == 2 && p.Waiting() == 2 })
+
+	p.Stop()
+	p.Run(func() error {
+		atomic.AddInt64(&completed, 1)
+		return nil
+	}, waiter)
+	close(block)
+
+	// running tasks are completed, waiting and new ones are not run nor
+	// counted as completed.
+	waitFor(t, func() bool { return p.Skipped() == 3 && p.Busy() == 0 })
+	if got := p.Completed(); got != 2 {
+		t.Fatalf("completed count = %v, expected 2", got)
+	}
+	if got := atomic.LoadInt64(&completed); got != 2 {
+		t.Fatalf("completed tasks = %v, expected 2", got)
+	}
+	if got := p.Skipped(); got != 3 {
+		t.Fatalf("skipped tasks = %v, expected 3", got)
+	}
+}
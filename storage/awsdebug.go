@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/peak/s5cmd/log"
+)
+
+// debug modes of the AWS SDK.
+const (
+	AWSDebugHTTP    = "http"
+	AWSDebugSigning = "signing"
+	AWSDebugRetries = "retries"
+)
+
+// AWSLogLevel returns the log level of the AWS SDK which prints the requests
+// of given debug modes. http prints the requests and the responses with
+// their bodies, signing prints the signatures of the requests and retries
+// prints the failed and the retried requests.
+func AWSLogLevel(modes []string) (aws.LogLevelType, error) {
+	if len(modes) == 0 {
+		return aws.LogOff, nil
+	}
+
+	level := aws.LogDebug
+	for _, mode := range modes {
+		switch strings.TrimSpace(mode) {
+		case AWSDebugHTTP:
+			level |= aws.LogDebugWithHTTPBody
+		case AWSDebugSigning:
+			level |= aws.LogDebugWithSigning
+		case AWSDebugRetries:
+			level |= aws.LogDebugWithRequestRetries | aws.LogDebugWithRequestErrors
+		default:
+			return aws.LogOff, fmt.Errorf("unknown aws debug mode %q, expected one of %v, %v or %v",
+				mode, AWSDebugHTTP, AWSDebugSigning, AWSDebugRetries)
+		}
+	}
+	return level, nil
+}
+
+// awsLogger routes the logs of the AWS SDK to the debug logs. The level
+// prefix of the SDK is trimmed, since the logger adds its own.
+var awsLogger = aws.LoggerFunc(func(args ...interface{}) {
+	msg := strings.TrimPrefix(fmt.Sprint(args...), "DEBUG: ")
+	log.Debug(log.DebugMessage{
+		Operation: "aws",
+		Err:       strings.TrimSpace(msg),
+	})
+})
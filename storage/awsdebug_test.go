@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestAWSLogLevel(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name      string
+		modes     []string
+		expected  aws.LogLevelType
+		expectErr bool
+	}{
+		{name: "none", modes: nil, expected: aws.LogOff},
+		{name: "http", modes: []string{"http"}, expected: aws.LogDebugWithHTTPBody},
+		{name: "signing", modes: []string{"signing"}, expected: aws.LogDebugWithSigning},
+		{name: "retries", modes: []string{"retries"}, expected: aws.LogDebugWithRequestRetries | aws.LogDebugWithRequestErrors},
+		{name: "multiple", modes: []string{"http", " signing"}, expected: aws.LogDebugWithHTTPBody | aws.LogDebugWithSigning},
+		{name: "unknown", modes: []string{"http", "headers"}, expectErr: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := AWSLogLevel(tc.modes)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("AWSLogLevel(%q): expected error, got %v", tc.modes, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AWSLogLevel(%q): unexpected error: %v", tc.modes, err)
+			}
+			if got != tc.expected {
+				t.Errorf("AWSLogLevel(%q) = %v, expected %v", tc.modes, got, tc.expected)
+			}
+		})
+	}
+}
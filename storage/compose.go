@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+const (
+	// composeMinPartSize is the minimum size of the parts of a multipart
+	// upload, except the last one.
+	composeMinPartSize = 5 * 1024 * 1024
+
+	// composeMaxPartSize is the maximum size of a part copied from an
+	// object.
+	composeMaxPartSize = 5 * 1024 * 1024 * 1024
+
+	// composeMaxParts is the maximum number of parts of a multipart upload.
+	composeMaxParts = 10000
+)
+
+// composeRange is a range of the content of an object.
+type composeRange struct {
+	url    *url.URL
+	offset int64
+	length int64
+}
+
+// composePart is a part of a composed object. A part is copied from a range
+// of an object on the server side if it is large enough, otherwise the
+// ranges of the small objects are read and uploaded together as a part.
+type composePart struct {
+	ranges []composeRange
+	copy   bool
+}
+
+// size returns the size of the part.
+func (p composePart) size() int64 {
+	var size int64
+	for _, r := range p.ranges {
+		size += r.length
+	}
+	return size
+}
+
+// planCompose splits the objects into the parts of the composed object, in
+// the given order. The objects which are at least minPartSize are copied,
+// in parts of at most maxPartSize. The small objects are coalesced with the
+// following objects until the part is minPartSize, so that only the last
+// part can be smaller.
+func planCompose(objects []*Object, minPartSize, maxPartSize int64) []composePart {
+	var (
+		parts   []composePart
+		pending composePart
+	)
+	for _, obj := range objects {
+		var offset int64
+		for offset < obj.Size {
+			rest := obj.Size - offset
+
+			if len(pending.ranges) == 0 && rest >= minPartSize {
+				length := rest
+				if length > maxPartSize {
+					length = maxPartSize
+				}
+				parts = append(parts, composePart{
+					ranges: []composeRange{{url: obj.URL, offset: offset, length: length}},
+					copy:   true,
+				})
+				offset += length
+				continue
+			}
+
+			length := minPartSize - pending.size()
+			if length > rest {
+				length = rest
+			}
+			pending.ranges = append(pending.ranges, composeRange{url: obj.URL, offset: offset, length: length})
+			offset += length
+
+			if pending.size() >= minPartSize {
+				parts = append(parts, pending)
+				pending = composePart{}
+			}
+		}
+	}
+
+	// a multipart upload has at least one part, even if it is empty.
+	if len(pending.ranges) > 0 || len(parts) == 0 {
+		parts = append(parts, pending)
+	}
+	return parts
+}
+
+// Compose concatenates the objects into the destination object, in the given
+// order, with a multipart upload. The large objects are copied on the server
+// side, only the small ones are read to be uploaded together. The sizes of
+// the objects should be known. Parts are composed with the given
+// concurrency.
+func (s *S3) Compose(ctx context.Context, objects []*Object, to *url.URL, metadata Metadata, concurrency int) error {
+	if s.dryRun {
+		return nil
+	}
+
+	parts := planCompose(objects, composeMinPartSize, composeMaxPartSize)
+	if len(parts) > composeMaxParts {
+		return fmt.Errorf("objects can not be composed into more than %d parts", composeMaxParts)
+	}
+
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(to.Bucket),
+		Key:    aws.String(to.Path),
+	}
+	if contentType := metadata.ContentType(); contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if storageClass := metadata.StorageClass(); storageClass != "" {
+		input.StorageClass = aws.String(storageClass)
+	}
+	if acl := metadata.ACL(); acl != "" {
+		input.ACL = aws.String(acl)
+	}
+	if sseEncryption := metadata.SSE(); sseEncryption != "" {
+		input.ServerSideEncryption = aws.String(sseEncryption)
+		if sseKmsKeyID := metadata.SSEKeyID(); sseKmsKeyID != "" {
+			input.SSEKMSKeyId = aws.String(sseKmsKeyID)
+		}
+	}
+
+	upload, err := s.api.CreateMultipartUploadWithContext(ctx, input)
+	if err != nil {
+		return err
+	}
+	defer s.invalidateCaches(to.Bucket, to.Path)
+
+	completed, err := s.composeParts(ctx, to, upload.UploadId, parts, concurrency)
+	if err != nil {
+		// the context might be canceled, abort the upload regardless.
+		_, _ = s.api.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(to.Bucket),
+			Key:      aws.String(to.Path),
+			UploadId: upload.UploadId,
+		})
+		return err
+	}
+
+	_, err = s.api.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(to.Bucket),
+		Key:             aws.String(to.Path),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	return err
+}
+
+// composeParts copies or uploads the parts of the upload concurrently, and
+// returns the completed parts in order.
+func (s *S3) composeParts(
+	ctx context.Context,
+	to *url.URL,
+	uploadID *string,
+	parts []composePart,
+	concurrency int,
+) ([]*s3.CompletedPart, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		completed = make([]*s3.CompletedPart, len(parts))
+		sem       = make(chan struct{}, concurrency)
+		wg        sync.WaitGroup
+		once      sync.Once
+		firstErr  error
+	)
+	for i, part := range parts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(number int64, part composePart) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			etag, err := s.composePart(ctx, to, uploadID, number, part)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			completed[number-1] = &s3.CompletedPart{
+				ETag:       etag,
+				PartNumber: aws.Int64(number),
+			}
+		}(int64(i+1), part)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return completed, nil
+}
+
+// composePart copies or uploads a part of the upload, and returns its ETag.
+func (s *S3) composePart(
+	ctx context.Context,
+	to *url.URL,
+	uploadID *string,
+	number int64,
+	part composePart,
+) (*string, error) {
+	if part.copy {
+		r := part.ranges[0]
+		output, err := s.api.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(to.Bucket),
+			Key:             aws.String(to.Path),
+			UploadId:        uploadID,
+			PartNumber:      aws.Int64(number),
+			CopySource:      aws.String(r.url.EscapedPath()),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", r.offset, r.offset+r.length-1)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return output.CopyPartResult.ETag, nil
+	}
+
+	var buf bytes.Buffer
+	for _, r := range part.ranges {
+		if err := s.readRange(ctx, r, &buf); err != nil {
+			return nil, err
+		}
+	}
+
+	output, err := s.api.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(to.Bucket),
+		Key:        aws.String(to.Path),
+		UploadId:   uploadID,
+		PartNumber: aws.Int64(number),
+		Body:       bytes.NewReader(buf.Bytes()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.ETag, nil
+}
+
+// readRange reads the range of an object into w.
+func (s *S3) readRange(ctx context.Context, r composeRange, w io.Writer) error {
+	body, err := s.ReadRange(ctx, r.url, fmt.Sprintf("bytes=%d-%d", r.offset, r.offset+r.length-1))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return err
+	}
+	if n != r.length {
+		return fmt.Errorf("read %d bytes of %v, expected %d", n, r.url, r.length)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func TestPlanCompose(t *testing.T) {
+	object := func(key string, size int64) *Object {
+		u, err := url.New("s3://bucket/" + key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return &Object{URL: u, Size: size}
+	}
+
+	type part struct {
+		copy   bool
+		ranges []string
+	}
+
+	testcases := []struct {
+		name     string
+		objects  []*Object
+		expected []part
+	}{
+		{
+			name:     "small_objects",
+			objects:  []*Object{object("a", 3), object("b", 4)},
+			expected: []part{{ranges: []string{"a:0-3", "b:0-2"}}, {ranges: []string{"b:2-2"}}},
+		},
+		{
+			name:     "large_objects",
+			objects:  []*Object{object("a", 5), object("b", 12)},
+			expected: []part{{copy: true, ranges: []string{"a:0-5"}}, {copy: true, ranges: []string{"b:0-10"}}, {ranges: []string{"b:10-2"}}},
+		},
+		{
+			name:    "small_object_before_large_object",
+			objects: []*Object{object("a", 2), object("b", 9), object("c", 1)},
+			expected: []part{
+				{ranges: []string{"a:0-2", "b:0-3"}},
+				{copy: true, ranges: []string{"b:3-6"}},
+				{ranges: []string{"c:0-1"}},
+			},
+		},
+		{
+			name:     "empty_objects",
+			objects:  []*Object{object("a", 0), object("b", 0)},
+			expected: []part{{}},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got []part
+			for _, p := range planCompose(tc.objects, 5, 10) {
+				gp := part{copy: p.copy}
+				for _, r := range p.ranges {
+					gp.ranges = append(gp.ranges, fmt.Sprintf("%v:%d-%d", r.url.Path, r.offset, r.length))
+				}
+				got = append(got, gp)
+			}
+			assert.DeepEqual(t, got, tc.expected, cmp.AllowUnexported(part{}))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type hostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// dnsCache caches the addresses of hosts for a fixed duration. Go resolver
+// does not expose the TTL of the records, so the TTL of the cache is an upper
+// bound given by the user. Concurrent lookups of the same host are merged into
+// one and failed lookups are not cached.
+type dnsCache struct {
+	ttl      time.Duration
+	resolver hostResolver
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*dnsCacheEntry
+}
+
+type dnsCacheEntry struct {
+	// done is closed when the lookup is finished.
+	done    chan struct{}
+	addrs   []string
+	err     error
+	expires time.Time
+
+	// next is the index of the address to dial first. Connections are
+	// spread over all addresses of the host.
+	next uint32
+}
+
+func newDNSCache(ttl time.Duration) *dnsCache {
+	return &dnsCache{
+		ttl:      ttl,
+		resolver: net.DefaultResolver,
+		now:      time.Now,
+		entries:  map[string]*dnsCacheEntry{},
+	}
+}
+
+// dialContext wraps the given dial function to connect to the cached
+// addresses of the host. Addresses are tried in order until a connection is
+// established.
+func (c *dnsCache) dialContext(
+	dial func(ctx context.Context, network, addr string) (net.Conn, error),
+) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		entry, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		start := int(atomic.AddUint32(&entry.next, 1))
+		for i := range entry.addrs {
+			ip := entry.addrs[(start+i)%len(entry.addrs)]
+
+			var conn net.Conn
+			conn, err = dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+func (c *dnsCache) lookup(ctx context.Context, host string) (*dnsCacheEntry, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	if ok && (!entry.isDone() || c.now().Before(entry.expires)) {
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return entry, nil
+	}
+
+	entry = &dnsCacheEntry{done: make(chan struct{})}
+	c.entries[host] = entry
+	c.mu.Unlock()
+
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	c.mu.Lock()
+	entry.addrs = addrs
+	entry.err = err
+	entry.expires = c.now().Add(c.ttl)
+	if err != nil {
+		delete(c.entries, host)
+	}
+	c.mu.Unlock()
+	close(entry.done)
+
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (e *dnsCacheEntry) isDone() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type fakeResolver struct {
+	lookups int32
+	addrs   []string
+	err     error
+	delay   time.Duration
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	atomic.AddInt32(&r.lookups, 1)
+	time.Sleep(r.delay)
+	return r.addrs, r.err
+}
+
+func newTestDNSCache(resolver *fakeResolver, now *time.Time) *dnsCache {
+	cache := newDNSCache(time.Minute)
+	cache.resolver = resolver
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+func TestDNSCacheLookupIsCachedUntilTTL(t *testing.T) {
+	now := time.Now()
+	resolver := &fakeResolver{addrs: []string{"10.0.0.1"}}
+	cache := newTestDNSCache(resolver, &now)
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.lookup(context.Background(), "s3.amazonaws.com")
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, atomic.LoadInt32(&resolver.lookups), int32(1))
+
+	now = now.Add(time.Minute)
+
+	_, err := cache.lookup(context.Background(), "s3.amazonaws.com")
+	assert.NilError(t, err)
+	assert.Equal(t, atomic.LoadInt32(&resolver.lookups), int32(2))
+}
+
+func TestDNSCacheConcurrentLookupsAreMerged(t *testing.T) {
+	now := time.Now()
+	resolver := &fakeResolver{addrs: []string{"10.0.0.1"}, delay: 50 * time.Millisecond}
+	cache := newTestDNSCache(resolver, &now)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.lookup(context.Background(), "s3.amazonaws.com")
+			assert.Check(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, atomic.LoadInt32(&resolver.lookups), int32(1))
+}
+
+func TestDNSCacheFailedLookupIsNotCached(t *testing.T) {
+	now := time.Now()
+	resolver := &fakeResolver{err: fmt.Errorf("temporary failure")}
+	cache := newTestDNSCache(resolver, &now)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.lookup(context.Background(), "s3.amazonaws.com")
+		assert.ErrorContains(t, err, "temporary failure")
+	}
+	assert.Equal(t, atomic.LoadInt32(&resolver.lookups), int32(2))
+}
+
+func TestDNSCacheDialSpreadsConnections(t *testing.T) {
+	now := time.Now()
+	resolver := &fakeResolver{addrs: []string{"10.0.0.1", "10.0.0.2"}}
+	cache := newTestDNSCache(resolver, &now)
+
+	var dialed []string
+	dial := cache.dialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, nil
+	})
+
+	for i := 0; i < 4; i++ {
+		_, err := dial(context.Background(), "tcp", "s3.amazonaws.com:443")
+		assert.NilError(t, err)
+	}
+
+	assert.DeepEqual(t, dialed, []string{
+		"10.0.0.2:443",
+		"10.0.0.1:443",
+		"10.0.0.2:443",
+		"10.0.0.1:443",
+	})
+}
+
+func TestDNSCacheDialFallsBackToNextAddress(t *testing.T) {
+	now := time.Now()
+	resolver := &fakeResolver{addrs: []string{"10.0.0.1", "10.0.0.2"}}
+	cache := newTestDNSCache(resolver, &now)
+
+	var dialed []string
+	dial := cache.dialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		if addr == "10.0.0.2:443" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return nil, nil
+	})
+
+	_, err := dial(context.Background(), "tcp", "s3.amazonaws.com:443")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dialed, []string{"10.0.0.2:443", "10.0.0.1:443"})
+}
+
+func TestDNSCacheDialIPAddressDirectly(t *testing.T) {
+	now := time.Now()
+	resolver := &fakeResolver{}
+	cache := newTestDNSCache(resolver, &now)
+
+	var dialed []string
+	dial := cache.dialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, nil
+	})
+
+	_, err := dial(context.Background(), "tcp", "127.0.0.1:9000")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dialed, []string{"127.0.0.1:9000"})
+	assert.Equal(t, atomic.LoadInt32(&resolver.lookups), int32(0))
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// normalized error codes which are not the same as the ones of S3.
+const (
+	// ErrCodeSlowDown is the error code of the throttled requests.
+	ErrCodeSlowDown = "SlowDown"
+
+	// ErrCodeTimeout is the error code of the timed out requests.
+	ErrCodeTimeout = "Timeout"
+)
+
+// errorCodes maps the error codes of the services to the normalized ones.
+var errorCodes = map[string]string{
+	"SlowDown":                               ErrCodeSlowDown,
+	"Throttling":                             ErrCodeSlowDown,
+	"ThrottlingException":                    ErrCodeSlowDown,
+	"ThrottledException":                     ErrCodeSlowDown,
+	"RequestThrottled":                       ErrCodeSlowDown,
+	"RequestThrottledException":              ErrCodeSlowDown,
+	"RequestLimitExceeded":                   ErrCodeSlowDown,
+	"TooManyRequestsException":               ErrCodeSlowDown,
+	"ProvisionedThroughputExceededException": ErrCodeSlowDown,
+	"RequestTimeout":                         ErrCodeTimeout,
+	"RequestTimeoutException":                ErrCodeTimeout,
+	"NotFound":                               "NoSuchKey",
+}
+
+// ErrorCode returns the normalized error code of the error, e.g. NoSuchKey,
+// AccessDenied, SlowDown or Timeout, so the failures can be handled without
+// parsing the error messages. It returns an empty string if the error has no
+// code.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var multiUploadErr s3manager.MultiUploadFailure
+	if errors.As(err, &multiUploadErr) {
+		if code := ErrorCode(multiUploadErr.OrigErr()); code != "" {
+			return code
+		}
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		code := awsErr.Code()
+		if normalized, ok := errorCodes[code]; ok {
+			return normalized
+		}
+
+		// the codes of the client side errors are not specific, the cause
+		// might be.
+		if code == request.ErrCodeRequestError || code == request.ErrCodeResponseTimeout {
+			if cause := ErrorCode(awsErr.OrigErr()); cause != "" {
+				return cause
+			}
+			if code == request.ErrCodeResponseTimeout {
+				return ErrCodeTimeout
+			}
+		}
+		return code
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrCodeTimeout
+	}
+
+	switch {
+	case errors.Is(err, ErrNoObjectFound), errors.Is(err, os.ErrNotExist):
+		return "NoSuchKey"
+	case errors.Is(err, os.ErrPermission):
+		return "AccessDenied"
+	}
+	return ""
+}
+
+// IsTransientError reports whether the error is a transient failure of the
+// service or the network, such as throttling, a timeout or an internal error
+// of the service, so the failed operation might succeed if it is retried
+// later.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || IsCancelationError(err) {
+		return false
+	}
+
+	switch ErrorCode(err) {
+	case ErrCodeSlowDown, ErrCodeTimeout, "InternalError", "ServiceUnavailable":
+		return true
+	}
+
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) && reqErr.StatusCode() >= 500 {
+		return true
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return request.IsErrorRetryable(awsErr) || request.IsErrorThrottle(awsErr)
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "nil", err: nil, expected: ""},
+		{name: "unknown", err: fmt.Errorf("unknown"), expected: ""},
+		{name: "no_such_key", err: awserr.New("NoSuchKey", "", nil), expected: "NoSuchKey"},
+		{name: "not_found", err: awserr.New("NotFound", "", nil), expected: "NoSuchKey"},
+		{name: "access_denied", err: awserr.New("AccessDenied", "", nil), expected: "AccessDenied"},
+		{name: "throttling", err: awserr.New("Throttling", "", nil), expected: ErrCodeSlowDown},
+		{name: "request_timeout", err: awserr.New("RequestTimeout", "", nil), expected: ErrCodeTimeout},
+		{name: "other_code", err: awserr.New("InvalidBucketName", "", nil), expected: "InvalidBucketName"},
+		{name: "request_error_timeout", err: awserr.New(request.ErrCodeRequestError, "", timeoutError{}), expected: ErrCodeTimeout},
+		{name: "request_error", err: awserr.New(request.ErrCodeRequestError, "", fmt.Errorf("connection refused")), expected: request.ErrCodeRequestError},
+		{name: "response_timeout", err: awserr.New(request.ErrCodeResponseTimeout, "", nil), expected: ErrCodeTimeout},
+		{name: "wrapped", err: fmt.Errorf("cp: %w", awserr.New("SlowDown", "", nil)), expected: ErrCodeSlowDown},
+		{name: "deadline_exceeded", err: context.DeadlineExceeded, expected: ErrCodeTimeout},
+		{name: "no_object_found", err: ErrNoObjectFound, expected: "NoSuchKey"},
+		{name: "file_not_exist", err: &os.PathError{Op: "open", Path: "file", Err: os.ErrNotExist}, expected: "NoSuchKey"},
+		{name: "permission_denied", err: &os.PathError{Op: "open", Path: "file", Err: os.ErrPermission}, expected: "AccessDenied"},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ErrorCode(tc.err); got != tc.expected {
+				t.Errorf("ErrorCode() = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	testcases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "unknown", err: fmt.Errorf("unknown"), expected: false},
+		{name: "slow_down", err: awserr.New("SlowDown", "", nil), expected: true},
+		{name: "wrapped_throttling", err: fmt.Errorf("cp: %w", awserr.New("Throttling", "", nil)), expected: true},
+		{name: "request_timeout", err: awserr.New("RequestTimeout", "", nil), expected: true},
+		{name: "internal_error", err: awserr.New("InternalError", "", nil), expected: true},
+		{name: "server_error", err: awserr.NewRequestFailure(awserr.New("BadGateway", "", nil), 502, ""), expected: true},
+		{name: "connection_reset", err: awserr.New(request.ErrCodeRequestError, "", fmt.Errorf("connection reset")), expected: true},
+		{name: "deadline_exceeded", err: context.DeadlineExceeded, expected: true},
+		{name: "no_such_key", err: awserr.NewRequestFailure(awserr.New("NoSuchKey", "", nil), 404, ""), expected: false},
+		{name: "access_denied", err: awserr.New("AccessDenied", "", nil), expected: false},
+		{name: "canceled", err: context.Canceled, expected: false},
+		{name: "request_canceled", err: awserr.New(request.CanceledErrorCode, "", nil), expected: false},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsTransientError(tc.err); got != tc.expected {
+				t.Errorf("IsTransientError() = %v, expected %v", got, tc.expected)
+			}
+		})
+	}
+}
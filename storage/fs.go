@@ -3,18 +3,23 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/karrick/godirwalk"
-	"github.com/termie/go-shutil"
 
 	"github.com/peak/s5cmd/storage/url"
 )
 
 // Filesystem is the Storage implementation of a local filesystem.
 type Filesystem struct {
-	dryRun bool
+	dryRun           bool
+	preserveSymlinks bool
+	keepEmptyDirs    bool
 }
 
 // Stat returns the Object structure describing object.
@@ -65,53 +70,100 @@ func (f *Filesystem) listSingleObject(ctx context.Context, src *url.URL) <-chan
 	return ch
 }
 
+// expandGlob walks the directory of the non-wildcard part of src and sends
+// the objects matching its wildcards, as the keys of a bucket are matched.
+// Wildcards match across directory boundaries, e.g. "dir/*.txt" matches
+// "dir/a/b.txt", and the relative paths of the objects start from the
+// directory of the wildcard.
 func (f *Filesystem) expandGlob(ctx context.Context, src *url.URL, followSymlinks bool) <-chan *Object {
 	ch := make(chan *Object)
 
 	go func() {
 		defer close(ch)
 
-		matchedFiles, err := filepath.Glob(src.Absolute())
+		// the directory to walk, in the form it is given.
+		var dir string
+		if i := strings.LastIndex(src.Prefix, "/"); i >= 0 {
+			dir = src.Prefix[:i+1]
+		}
+
+		root := dir
+		if root == "" {
+			root = "."
+		}
+
+		rooturl, err := url.New(root)
 		if err != nil {
 			sendError(ctx, err, ch)
 			return
 		}
-		if len(matchedFiles) == 0 {
-			err := fmt.Errorf("no match found for %q", src)
-			sendError(ctx, err, ch)
+
+		var matched bool
+		defer func() {
+			if !matched {
+				err := fmt.Errorf("no match found for %q", src)
+				sendError(ctx, err, ch)
+			}
+		}()
+
+		if st, err := os.Stat(root); err != nil || !st.IsDir() {
 			return
 		}
 
-		for _, filename := range matchedFiles {
-			filename := filename
-
-			fileurl, _ := url.New(filename)
-			fileurl.SetRelative(src.Absolute())
+		walkDir(ctx, f, rooturl, followSymlinks, func(obj *Object) {
+			if obj.Err != nil {
+				sendObject(ctx, obj, ch)
+				return
+			}
 
-			obj, _ := f.Stat(ctx, fileurl)
+			rel, err := filepath.Rel(filepath.Clean(root), obj.URL.Absolute())
+			if err != nil {
+				sendError(ctx, err, ch)
+				return
+			}
 
-			if !obj.Type.IsDir() {
-				sendObject(ctx, obj, ch)
-				continue
+			key := dir + filepath.ToSlash(rel)
+			if !src.Match(key) {
+				return
 			}
 
-			walkDir(ctx, f, fileurl, followSymlinks, func(obj *Object) {
-				sendObject(ctx, obj, ch)
-			})
-		}
+			objurl := src.Clone()
+			objurl.Path = key
+			obj.URL = objurl
+
+			matched = true
+			sendObject(ctx, obj, ch)
+		})
 	}()
 	return ch
 }
 
 func walkDir(ctx context.Context, fs *Filesystem, src *url.URL, followSymlinks bool, fn func(o *Object)) {
+	// symbolic links are uploaded as links if they are preserved.
+	followSymlinks = followSymlinks && !fs.preserveSymlinks
+
 	//skip if symlink is pointing to a dir and --no-follow-symlink
-	if !ShouldProcessUrl(src, followSymlinks) {
+	if !fs.preserveSymlinks && !ShouldProcessUrl(src, followSymlinks) {
 		return
 	}
 	err := godirwalk.Walk(src.Absolute(), &godirwalk.Options{
 		Callback: func(pathname string, dirent *godirwalk.Dirent) error {
-			// we're interested in files
+			// we're interested in files, and empty directories if they are
+			// kept.
 			if dirent.IsDir() {
+				if fs.keepEmptyDirs && pathname != filepath.Clean(src.Absolute()) && isEmptyDir(pathname) {
+					dirurl, err := url.New(pathname)
+					if err != nil {
+						return err
+					}
+					dirurl.SetRelative(src.Absolute())
+
+					obj, err := fs.Stat(ctx, dirurl)
+					if err != nil {
+						return err
+					}
+					fn(obj)
+				}
 				return nil
 			}
 
@@ -122,16 +174,35 @@ func walkDir(ctx context.Context, fs *Filesystem, src *url.URL, followSymlinks b
 
 			fileurl.SetRelative(src.Absolute())
 
+			if dirent.IsSymlink() && fs.preserveSymlinks {
+				obj, err := fs.lstat(fileurl)
+				if err != nil {
+					return err
+				}
+				fn(obj)
+				return nil
+			}
+
 			//skip if symlink is pointing to a file and --no-follow-symlink
 			if !ShouldProcessUrl(fileurl, followSymlinks) {
 				return nil
 			}
 
 			obj, err := fs.Stat(ctx, fileurl)
-
 			if err != nil {
 				return err
 			}
+
+			if obj.Type.IsDir() {
+				// a followed symbolic link to a directory, which is walked if
+				// it doesn't point to one of its parents.
+				if isSymlinkLoop(pathname) {
+					fn(&Object{Err: fmt.Errorf("%q: symbolic link loop is skipped", pathname)})
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			fn(obj)
 			return nil
 		},
@@ -144,6 +215,58 @@ func walkDir(ctx context.Context, fs *Filesystem, src *url.URL, followSymlinks b
 	}
 }
 
+// isEmptyDir reports whether the directory at the given path has no entries.
+func isEmptyDir(pathname string) bool {
+	dir, err := os.Open(pathname)
+	if err != nil {
+		return false
+	}
+	defer dir.Close()
+
+	_, err = dir.Readdirnames(1)
+	return err == io.EOF
+}
+
+// isSymlinkLoop reports whether the symbolic link at the given path points to
+// the directory containing the link, or to one of its parents. Following such
+// a link would walk the same directories forever.
+func isSymlinkLoop(pathname string) bool {
+	target, err := filepath.EvalSymlinks(pathname)
+	if err != nil {
+		return false
+	}
+	parent, err := filepath.EvalSymlinks(filepath.Dir(pathname))
+	if err != nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(target, parent)
+	if err != nil {
+		return false
+	}
+	return rel == "." || !strings.HasPrefix(rel, "..")
+}
+
+// lstat returns the object of the given path without following symbolic
+// links.
+func (f *Filesystem) lstat(url *url.URL) (*Object, error) {
+	st, err := os.Lstat(url.Absolute())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrGivenObjectNotFound
+		}
+		return nil, err
+	}
+
+	mod := st.ModTime()
+	return &Object{
+		URL:     url,
+		Type:    ObjectType{st.Mode()},
+		Size:    st.Size(),
+		ModTime: &mod,
+	}, nil
+}
+
 func (f *Filesystem) walkDir(ctx context.Context, src *url.URL, followSymlinks bool) <-chan *Object {
 	ch := make(chan *Object)
 	go func() {
@@ -165,8 +288,45 @@ func (f *Filesystem) Copy(ctx context.Context, src, dst *url.URL, _ Metadata) er
 	if err := os.MkdirAll(dst.Dir(), os.ModePerm); err != nil {
 		return err
 	}
-	_, err := shutil.Copy(src.Absolute(), dst.Absolute(), true)
-	return err
+	return copyFile(src.Absolute(), dst.Absolute())
+}
+
+// copyFile copies the contents and the permission bits of src to dst. Copying
+// between files lets the runtime use copy_file_range on Linux, which copies
+// the data in the kernel, or clones it on filesystems supporting reflinks. It
+// falls back to copying through a userspace buffer on other platforms.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	if dfi, err := os.Stat(dst); err == nil && os.SameFile(fi, dfi) {
+		return fmt.Errorf("%q and %q are the same file", src, dst)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	// permissions are not changed by OpenFile if the file already exists.
+	if err := out.Chmod(fi.Mode().Perm()); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
 }
 
 // Delete deletes given file.
@@ -213,6 +373,77 @@ func (f *Filesystem) Create(path string) (*os.File, error) {
 	return os.Create(path)
 }
 
+// Sync flushes the contents of the file to durable storage. The directory of
+// the file is flushed as well, so that a newly created file survives a power
+// loss.
+func (f *Filesystem) Sync(file *os.File) error {
+	if f.dryRun {
+		return nil
+	}
+
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	// directories can't be opened for syncing on Windows.
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	dir, err := os.Open(filepath.Dir(file.Name()))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+// Truncate changes the size of the file.
+func (f *Filesystem) Truncate(file *os.File, size int64) error {
+	if f.dryRun {
+		return nil
+	}
+	return file.Truncate(size)
+}
+
+// Chtimes changes the access and modification times of the given path.
+func (f *Filesystem) Chtimes(path string, atime, mtime time.Time) error {
+	if f.dryRun {
+		return nil
+	}
+	return os.Chtimes(path, atime, mtime)
+}
+
+// Readlink returns the target of the symbolic link at the given path. It
+// returns an empty string if the path is not a symbolic link.
+func (f *Filesystem) Readlink(path string) (string, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	if fi.Mode()&os.ModeSymlink == 0 {
+		return "", nil
+	}
+	return os.Readlink(path)
+}
+
+// Symlink creates a symbolic link at the given path pointing to target. An
+// existing file at the path is replaced.
+func (f *Filesystem) Symlink(target, path string) error {
+	if f.dryRun {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.Symlink(target, path)
+}
+
 // Open opens the given source.
 func (f *Filesystem) Open(path string) (*os.File, error) {
 	file, err := os.OpenFile(path, os.O_RDONLY, 0644)
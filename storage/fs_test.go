@@ -1,6 +1,19 @@
 package storage
 
-import "testing"
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/peak/s5cmd/storage/url"
+)
 
 func TestFilesystemImplementsStorageInterface(t *testing.T) {
 	var i interface{} = new(Filesystem)
@@ -8,3 +21,190 @@ func TestFilesystemImplementsStorageInterface(t *testing.T) {
 		t.Errorf("expected %t to implement Storage interface", i)
 	}
 }
+
+func TestFilesystemCopy(t *testing.T) {
+	const content = "this is a file content"
+
+	testdir := fs.NewDir(t, "fs-copy",
+		fs.WithFile("src.txt", content, fs.WithMode(0640)),
+		fs.WithFile("existing.txt", "existing content which is longer than the source", fs.WithMode(0600)),
+	)
+	defer testdir.Remove()
+
+	testcases := []struct {
+		name string
+		dst  string
+	}{
+		{name: "new_file_in_new_dir", dst: testdir.Join("dir", "dst.txt")},
+		{name: "existing_file", dst: testdir.Join("existing.txt")},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			srcurl, err := url.New(testdir.Join("src.txt"))
+			assert.NilError(t, err)
+			dsturl, err := url.New(tc.dst)
+			assert.NilError(t, err)
+
+			err = NewLocalClient(Options{}).Copy(context.Background(), srcurl, dsturl, nil)
+			assert.NilError(t, err)
+
+			got, err := ioutil.ReadFile(tc.dst)
+			assert.NilError(t, err)
+			assert.Equal(t, string(got), content)
+
+			if runtime.GOOS != "windows" {
+				fi, err := os.Stat(tc.dst)
+				assert.NilError(t, err)
+				assert.Equal(t, fi.Mode().Perm(), os.FileMode(0640))
+			}
+		})
+	}
+}
+
+func TestFilesystemCopySameFile(t *testing.T) {
+	testdir := fs.NewDir(t, "fs-copy", fs.WithFile("file.txt", "content"))
+	defer testdir.Remove()
+
+	u, err := url.New(testdir.Join("file.txt"))
+	assert.NilError(t, err)
+
+	err = NewLocalClient(Options{}).Copy(context.Background(), u, u, nil)
+	assert.ErrorContains(t, err, "are the same file")
+
+	got, err := ioutil.ReadFile(testdir.Join("file.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), "content")
+}
+
+func TestFilesystemSync(t *testing.T) {
+	testdir := fs.NewDir(t, "fs-sync")
+	defer testdir.Remove()
+
+	client := NewLocalClient(Options{})
+
+	file, err := client.Create(testdir.Join("file.txt"))
+	assert.NilError(t, err)
+	defer file.Close()
+
+	_, err = file.WriteString("content")
+	assert.NilError(t, err)
+
+	assert.NilError(t, client.Sync(file))
+
+	got, err := ioutil.ReadFile(testdir.Join("file.txt"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), "content")
+}
+
+func TestFilesystemListKeepEmptyDirs(t *testing.T) {
+	testdir := fs.NewDir(t, "fs-list",
+		fs.WithFile("file.txt", "content"),
+		fs.WithDir("empty"),
+		fs.WithDir("nonempty", fs.WithDir("nested_empty")),
+	)
+	defer testdir.Remove()
+
+	srcurl, err := url.New(testdir.Path() + "/")
+	assert.NilError(t, err)
+
+	testcases := []struct {
+		name          string
+		keepEmptyDirs bool
+		expected      []string
+	}{
+		{
+			name:     "empty_dirs_are_skipped",
+			expected: []string{"file.txt"},
+		},
+		{
+			name:          "empty_dirs_are_listed",
+			keepEmptyDirs: true,
+			expected:      []string{"empty/", "file.txt", "nonempty/nested_empty/"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewLocalClient(Options{KeepEmptyDirs: tc.keepEmptyDirs})
+
+			var got []string
+			for obj := range client.List(context.Background(), srcurl, true) {
+				assert.NilError(t, obj.Err)
+
+				name := filepath.ToSlash(obj.URL.Relative())
+				if obj.Type.IsDir() {
+					name += "/"
+				}
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			assert.DeepEqual(t, got, tc.expected)
+		})
+	}
+}
+
+func TestFilesystemListWildcard(t *testing.T) {
+	testdir := fs.NewDir(t, "fs-list-wildcard",
+		fs.WithFile("file.txt", "content"),
+		fs.WithFile("readme.md", "content"),
+		fs.WithDir("empty"),
+		fs.WithDir("a1", fs.WithFile("file.txt", "content"), fs.WithDir("b", fs.WithFile("nested.txt", "content"))),
+		fs.WithDir("a2", fs.WithFile("file.md", "content")),
+	)
+	defer testdir.Remove()
+
+	testcases := []struct {
+		name          string
+		pattern       string
+		keepEmptyDirs bool
+		expected      []string
+	}{
+		{
+			name:     "wildcard_matches_across_directories",
+			pattern:  "*.txt",
+			expected: []string{"a1/b/nested.txt", "a1/file.txt", "file.txt"},
+		},
+		{
+			name:     "wildcard_in_directory",
+			pattern:  "a*/file.*",
+			expected: []string{"a1/file.txt", "a2/file.md"},
+		},
+		{
+			name:     "single_character_wildcard",
+			pattern:  "a?/file.txt",
+			expected: []string{"a1/file.txt"},
+		},
+		{
+			name:          "empty_dirs_are_matched",
+			pattern:       "e*",
+			keepEmptyDirs: true,
+			expected:      []string{"empty/"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			srcurl, err := url.New(filepath.ToSlash(testdir.Path()) + "/" + tc.pattern)
+			assert.NilError(t, err)
+
+			client := NewLocalClient(Options{KeepEmptyDirs: tc.keepEmptyDirs})
+
+			var got []string
+			for obj := range client.List(context.Background(), srcurl, true) {
+				assert.NilError(t, obj.Err)
+
+				name := filepath.ToSlash(obj.URL.Relative())
+				if obj.Type.IsDir() {
+					name += "/"
+				}
+				got = append(got, name)
+			}
+			sort.Strings(got)
+			assert.DeepEqual(t, got, tc.expected)
+		})
+	}
+}
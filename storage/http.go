@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+const maxHTTPRetryDelay = 30 * time.Second
+
+// HTTP is a read-only storage for objects served over HTTP(S).
+type HTTP struct {
+	client     *http.Client
+	maxRetries int
+
+	// retryDelay is the time to wait before the first retry. It is doubled
+	// on each retry.
+	retryDelay time.Duration
+}
+
+// NewHTTPStorage creates a new HTTP storage. The HTTP client uses the same
+// TLS, proxy and connection settings with the S3 storage.
+func NewHTTPStorage(opts Options) (*HTTP, error) {
+	client, err := newHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTTP{
+		client:     client,
+		maxRetries: opts.MaxRetries,
+		retryDelay: time.Second,
+	}, nil
+}
+
+// Stat retrieves metadata of the object with a HEAD request.
+func (h *HTTP) Stat(ctx context.Context, src *url.URL) (*Object, error) {
+	req, err := http.NewRequest(http.MethodHead, src.Absolute(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrGivenObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HEAD %v: %v", src, resp.Status)
+	}
+
+	obj := &Object{
+		URL:  src,
+		Etag: resp.Header.Get("ETag"),
+		Size: resp.ContentLength,
+	}
+	if mod, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		obj.ModTime = &mod
+	}
+	return obj, nil
+}
+
+// Read fetches the object and returns a reader of its content, with the size
+// of the object, or -1 if the server does not report it. If the transfer
+// fails with a transient error and the server supports range requests, the
+// reader resumes the transfer from the last received byte.
+func (h *HTTP) Read(ctx context.Context, src *url.URL) (io.ReadCloser, int64, error) {
+	r := &httpReader{
+		ctx:    ctx,
+		http:   h,
+		src:    src,
+		size:   -1,
+		offset: 0,
+	}
+
+	if err := r.open(); err != nil {
+		return nil, 0, err
+	}
+	return r, r.size, nil
+}
+
+// httpReader is an io.ReadCloser which resumes reading the object with range
+// requests on transient errors.
+type httpReader struct {
+	ctx  context.Context
+	http *HTTP
+	src  *url.URL
+
+	body         io.ReadCloser
+	size         int64
+	offset       int64
+	etag         string
+	acceptRanges bool
+	retries      int
+}
+
+// open sends a request to read the object starting from the current offset.
+// Requests failed with a network error or a server error are retried.
+func (r *httpReader) open() error {
+	for {
+		err := r.tryOpen()
+		if err == nil {
+			return nil
+		}
+
+		if statusErr, ok := err.(*httpStatusError); ok && !isRetryableStatus(statusErr.code) {
+			return err
+		}
+
+		if retryErr := r.wait(err); retryErr != nil {
+			return retryErr
+		}
+	}
+}
+
+func (r *httpReader) tryOpen() error {
+	req, err := http.NewRequest(http.MethodGet, r.src.Absolute(), nil)
+	if err != nil {
+		return err
+	}
+
+	resuming := r.offset > 0
+	if resuming {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", r.offset))
+		// make sure the rest of the same object is received.
+		if r.etag != "" {
+			req.Header.Set("If-Range", r.etag)
+		}
+	}
+
+	resp, err := r.http.client.Do(req.WithContext(r.ctx))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !resuming && resp.StatusCode == http.StatusOK:
+		r.size = resp.ContentLength
+		r.etag = resp.Header.Get("ETag")
+		r.acceptRanges = resp.Header.Get("Accept-Ranges") == "bytes"
+	case resuming && resp.StatusCode == http.StatusPartialContent:
+		// the object has been transferred partially. the rest of it is being
+		// received.
+	case resuming && resp.StatusCode == http.StatusOK:
+		// the server ignored the range, because the object has changed.
+		resp.Body.Close()
+		return fmt.Errorf("GET %v: object has changed during the transfer", r.src)
+	default:
+		resp.Body.Close()
+		return &httpStatusError{code: resp.StatusCode, status: resp.Status, src: r.src}
+	}
+
+	r.body = resp.Body
+	return nil
+}
+
+// Read implements io.Reader.
+func (r *httpReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+
+		if err == io.EOF && r.size >= 0 && r.offset < r.size {
+			err = io.ErrUnexpectedEOF
+		}
+
+		if err == nil || err == io.EOF || n > 0 {
+			if err != nil && err != io.EOF {
+				// return received bytes first, the error will be
+				// encountered again on the next read.
+				err = nil
+			}
+			return n, err
+		}
+
+		if !r.acceptRanges || r.ctx.Err() != nil {
+			return 0, err
+		}
+
+		r.body.Close()
+		if retryErr := r.wait(err); retryErr != nil {
+			return 0, retryErr
+		}
+		if openErr := r.open(); openErr != nil {
+			return 0, openErr
+		}
+	}
+}
+
+// Close implements io.Closer.
+func (r *httpReader) Close() error {
+	return r.body.Close()
+}
+
+// wait blocks until it is time to retry the failed request. It returns the
+// error if the retries are exhausted or the context is canceled.
+func (r *httpReader) wait(err error) error {
+	if r.retries >= r.http.maxRetries {
+		return err
+	}
+
+	delay := r.http.retryDelay << uint(r.retries)
+	if delay > maxHTTPRetryDelay {
+		delay = maxHTTPRetryDelay
+	}
+	r.retries++
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+type httpStatusError struct {
+	code   int
+	status string
+	src    *url.URL
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("GET %v: %v", e.src, e.status)
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// List returns the object itself. HTTP URLs have no directory semantics.
+func (h *HTTP) List(ctx context.Context, src *url.URL, _ bool) <-chan *Object {
+	ch := make(chan *Object, 1)
+	ch <- &Object{URL: src}
+	close(ch)
+	return ch
+}
+
+// Delete is not supported for HTTP objects.
+func (h *HTTP) Delete(ctx context.Context, src *url.URL) error {
+	return errHTTPReadOnly
+}
+
+// MultiDelete is not supported for HTTP objects.
+func (h *HTTP) MultiDelete(ctx context.Context, urls <-chan *url.URL) <-chan *Object {
+	ch := make(chan *Object)
+	go func() {
+		defer close(ch)
+		for u := range urls {
+			ch <- &Object{URL: u, Err: errHTTPReadOnly}
+		}
+	}()
+	return ch
+}
+
+// Copy is not supported for HTTP objects.
+func (h *HTTP) Copy(ctx context.Context, src, dst *url.URL, _ Metadata) error {
+	return errHTTPReadOnly
+}
+
+var errHTTPReadOnly = fmt.Errorf("http storage is read-only")
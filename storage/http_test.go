@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+const httpTestContent = "this is the content of the object served over http"
+
+func newTestHTTPStorage(t *testing.T, maxRetries int) *HTTP {
+	t.Helper()
+
+	h, err := NewHTTPStorage(Options{MaxRetries: maxRetries})
+	assert.NilError(t, err)
+	h.retryDelay = time.Millisecond
+	return h
+}
+
+func httpTestURL(t *testing.T, server *httptest.Server) *url.URL {
+	t.Helper()
+
+	u, err := url.NewHTTP(server.URL + "/dir/object.txt")
+	assert.NilError(t, err)
+	return u
+}
+
+// interruptedHandler serves the first half of the content on the first
+// request and drops the connection. The rest is served with range requests.
+func interruptedHandler(t *testing.T, requests *int32, acceptRanges bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(requests, 1)
+
+		w.Header().Set("ETag", `"etag"`)
+		if acceptRanges {
+			w.Header().Set("Accept-Ranges", "bytes")
+		}
+
+		if n == 1 {
+			w.Header().Set("Content-Length", fmt.Sprint(len(httpTestContent)))
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, httpTestContent[:len(httpTestContent)/2])
+			w.(http.Flusher).Flush()
+
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.Check(t, err)
+			conn.Close()
+			return
+		}
+
+		assert.Check(t, r.Header.Get("If-Range") == `"etag"`)
+
+		var offset int
+		_, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset)
+		assert.Check(t, err)
+
+		w.Header().Set("Content-Length", fmt.Sprint(len(httpTestContent)-offset))
+		w.WriteHeader(http.StatusPartialContent)
+		fmt.Fprint(w, httpTestContent[offset:])
+	}
+}
+
+func TestHTTPRead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Check(t, r.URL.Path == "/dir/object.txt")
+		fmt.Fprint(w, httpTestContent)
+	}))
+	defer server.Close()
+
+	h := newTestHTTPStorage(t, 0)
+
+	rc, size, err := h.Read(context.Background(), httpTestURL(t, server))
+	assert.NilError(t, err)
+	defer rc.Close()
+
+	content, err := ioutil.ReadAll(rc)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), httpTestContent)
+	assert.Equal(t, size, int64(len(httpTestContent)))
+}
+
+func TestHTTPReadResumesInterruptedTransfer(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(interruptedHandler(t, &requests, true))
+	defer server.Close()
+
+	h := newTestHTTPStorage(t, 3)
+
+	rc, _, err := h.Read(context.Background(), httpTestURL(t, server))
+	assert.NilError(t, err)
+	defer rc.Close()
+
+	content, err := ioutil.ReadAll(rc)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), httpTestContent)
+	assert.Equal(t, atomic.LoadInt32(&requests), int32(2))
+}
+
+func TestHTTPReadFailsWithoutRangeSupport(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(interruptedHandler(t, &requests, false))
+	defer server.Close()
+
+	h := newTestHTTPStorage(t, 3)
+
+	rc, _, err := h.Read(context.Background(), httpTestURL(t, server))
+	assert.NilError(t, err)
+	defer rc.Close()
+
+	_, err = ioutil.ReadAll(rc)
+	assert.Assert(t, err != nil, "expected the interrupted transfer to fail")
+	assert.Equal(t, atomic.LoadInt32(&requests), int32(1))
+}
+
+func TestHTTPReadRetriesServerErrors(t *testing.T) {
+	testcases := []struct {
+		name             string
+		status           int
+		maxRetries       int
+		expectedErr      string
+		expectedRequests int32
+	}{
+		{
+			name:             "server_error_is_retried",
+			status:           http.StatusServiceUnavailable,
+			maxRetries:       3,
+			expectedRequests: 2,
+		},
+		{
+			name:             "retries_exhausted",
+			status:           http.StatusServiceUnavailable,
+			maxRetries:       0,
+			expectedErr:      "503 Service Unavailable",
+			expectedRequests: 1,
+		},
+		{
+			name:             "client_error_is_not_retried",
+			status:           http.StatusForbidden,
+			maxRetries:       3,
+			expectedErr:      "403 Forbidden",
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				fmt.Fprint(w, httpTestContent)
+			}))
+			defer server.Close()
+
+			h := newTestHTTPStorage(t, tc.maxRetries)
+
+			rc, _, err := h.Read(context.Background(), httpTestURL(t, server))
+			assert.Equal(t, atomic.LoadInt32(&requests), tc.expectedRequests)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			assert.NilError(t, err)
+			defer rc.Close()
+
+			content, err := ioutil.ReadAll(rc)
+			assert.NilError(t, err)
+			assert.Equal(t, string(content), httpTestContent)
+		})
+	}
+}
+
+func TestHTTPReadFailsIfObjectChanges(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Content-Length", fmt.Sprint(len(httpTestContent)))
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("ETag", `"old"`)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, httpTestContent[:10])
+			w.(http.Flusher).Flush()
+
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+
+		// If-Range doesn't match, whole object is returned.
+		w.Header().Set("ETag", `"new"`)
+		fmt.Fprint(w, strings.ToUpper(httpTestContent))
+	}))
+	defer server.Close()
+
+	h := newTestHTTPStorage(t, 3)
+
+	rc, _, err := h.Read(context.Background(), httpTestURL(t, server))
+	assert.NilError(t, err)
+	defer rc.Close()
+
+	_, err = ioutil.ReadAll(rc)
+	assert.ErrorContains(t, err, "object has changed during the transfer")
+}
+
+func TestHTTPStat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dir/object.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Check(t, r.Method == http.MethodHead)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("Content-Length", fmt.Sprint(len(httpTestContent)))
+	}))
+	defer server.Close()
+
+	h := newTestHTTPStorage(t, 0)
+
+	obj, err := h.Stat(context.Background(), httpTestURL(t, server))
+	assert.NilError(t, err)
+	assert.Equal(t, obj.Size, int64(len(httpTestContent)))
+	assert.Equal(t, obj.ModTime.UTC(), time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC))
+
+	missing, err := url.NewHTTP(server.URL + "/missing")
+	assert.NilError(t, err)
+
+	_, err = h.Stat(context.Background(), missing)
+	assert.Equal(t, err, ErrGivenObjectNotFound)
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+// InventoryManifest is the manifest.json of an S3 Inventory report. Only the
+// fields required to read the data files are declared.
+type InventoryManifest struct {
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"`
+	FileFormat        string `json:"fileFormat"`
+	FileSchema        string `json:"fileSchema"`
+	Files             []struct {
+		Key string `json:"key"`
+	} `json:"files"`
+}
+
+// ListInventory is a non-blocking operation which reads the keys of the
+// objects from the data files of an S3 Inventory report instead of listing
+// the bucket. Only the keys matching the src url are sent to the object
+// channel. Only CSV inventories are supported.
+func (s *S3) ListInventory(ctx context.Context, manifest *url.URL, src *url.URL) <-chan *Object {
+	objCh := make(chan *Object)
+
+	go func() {
+		defer close(objCh)
+
+		m, err := s.readInventoryManifest(ctx, manifest)
+		if err != nil {
+			objCh <- &Object{Err: err}
+			return
+		}
+
+		if m.SourceBucket != src.Bucket {
+			objCh <- &Object{Err: fmt.Errorf("inventory is for bucket %q, not %q", m.SourceBucket, src.Bucket)}
+			return
+		}
+
+		// destination bucket is given as an ARN.
+		bucket := m.DestinationBucket
+		if i := strings.LastIndex(bucket, ":"); i >= 0 {
+			bucket = bucket[i+1:]
+		}
+
+		objectFound := false
+		for _, file := range m.Files {
+			dataurl, err := url.New(fmt.Sprintf("s3://%v/%v", bucket, file.Key))
+			if err != nil {
+				objCh <- &Object{Err: err}
+				return
+			}
+
+			found, err := s.readInventoryFile(ctx, dataurl, m.FileSchema, src, objCh)
+			if err != nil {
+				objCh <- &Object{Err: err}
+				return
+			}
+			objectFound = objectFound || found
+		}
+
+		if !objectFound {
+			objCh <- &Object{Err: ErrNoObjectFound}
+		}
+	}()
+
+	return objCh
+}
+
+func (s *S3) readInventoryManifest(ctx context.Context, manifest *url.URL) (*InventoryManifest, error) {
+	rc, err := s.Read(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var m InventoryManifest
+	if err := json.NewDecoder(rc).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid inventory manifest %v: %v", manifest, err)
+	}
+
+	if !strings.EqualFold(m.FileFormat, "CSV") {
+		return nil, fmt.Errorf("inventory file format %q is not supported, only CSV inventories can be read", m.FileFormat)
+	}
+	return &m, nil
+}
+
+func (s *S3) readInventoryFile(ctx context.Context, dataurl *url.URL, schema string, src *url.URL, objCh chan<- *Object) (bool, error) {
+	rc, err := s.Read(ctx, dataurl)
+	if err != nil {
+		return false, err
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if strings.HasSuffix(dataurl.Path, ".gz") {
+		gz, err := gzip.NewReader(rc)
+		if err != nil {
+			return false, fmt.Errorf("%v: %v", dataurl, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	found, err := readInventoryCSV(r, schema, src, objCh)
+	if err != nil {
+		return found, fmt.Errorf("%v: %v", dataurl, err)
+	}
+	return found, nil
+}
+
+// readInventoryCSV sends the objects in the CSV data file, which match the
+// src url, to the object channel. Deleted objects and non-current versions
+// are skipped.
+func readInventoryCSV(r io.Reader, schema string, src *url.URL, objCh chan<- *Object) (bool, error) {
+	columns := map[string]int{}
+	for i, column := range strings.Split(schema, ",") {
+		columns[strings.TrimSpace(column)] = i
+	}
+
+	keyIndex, ok := columns["Key"]
+	if !ok {
+		return false, fmt.Errorf("inventory schema %q does not have Key field", schema)
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	objectFound := false
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return objectFound, nil
+		}
+		if err != nil {
+			return objectFound, err
+		}
+
+		if keyIndex >= len(record) {
+			continue
+		}
+
+		if field(record, "IsLatest") == "false" || field(record, "IsDeleteMarker") == "true" {
+			continue
+		}
+
+		// keys are URL-encoded in inventory reports.
+		key, err := neturl.QueryUnescape(record[keyIndex])
+		if err != nil {
+			return objectFound, fmt.Errorf("invalid key %q: %v", record[keyIndex], err)
+		}
+
+		if !src.Match(key) {
+			continue
+		}
+
+		var objtype os.FileMode
+		if strings.HasSuffix(key, "/") {
+			objtype = os.ModeDir
+		}
+
+		newurl := src.Clone()
+		newurl.Path = key
+
+		obj := &Object{
+			URL:          newurl,
+			Etag:         field(record, "ETag"),
+			Type:         ObjectType{objtype},
+			StorageClass: StorageClass(field(record, "StorageClass")),
+			VersionID:    field(record, "VersionId"),
+		}
+		if size, err := strconv.ParseInt(field(record, "Size"), 10, 64); err == nil {
+			obj.Size = size
+		}
+		if mod, err := time.Parse(time.RFC3339, field(record, "LastModifiedDate")); err == nil {
+			mod = mod.UTC()
+			obj.ModTime = &mod
+		}
+
+		objCh <- obj
+		objectFound = true
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func TestReadInventoryCSV(t *testing.T) {
+	const schema = "Bucket, Key, VersionId, IsLatest, IsDeleteMarker, Size, LastModifiedDate, ETag, StorageClass"
+
+	data := strings.Join([]string{
+		`"bucket","prefix/file1.txt","v1","true","false","10","2020-03-18T12:00:00.000Z","etag1","STANDARD"`,
+		`"bucket","prefix/old.txt","v0","false","false","10","2020-03-17T12:00:00.000Z","etag0","STANDARD"`,
+		`"bucket","prefix/deleted.txt","v2","true","true","","2020-03-18T12:00:00.000Z","",""`,
+		`"bucket","prefix/dir/file%202.txt","v3","true","false","20","2020-03-18T13:00:00.000Z","etag3","GLACIER"`,
+		`"bucket","other/file.txt","v4","true","false","30","2020-03-18T14:00:00.000Z","etag4","STANDARD"`,
+	}, "\n")
+
+	src, err := url.New("s3://bucket/prefix/*")
+	assert.NilError(t, err)
+
+	objCh := make(chan *Object, 10)
+	found, err := readInventoryCSV(strings.NewReader(data), schema, src, objCh)
+	close(objCh)
+	assert.NilError(t, err)
+	assert.Assert(t, found)
+
+	var objects []*Object
+	for obj := range objCh {
+		objects = append(objects, obj)
+	}
+	assert.Equal(t, len(objects), 2)
+
+	assert.Equal(t, objects[0].URL.String(), "s3://bucket/prefix/file1.txt")
+	assert.Equal(t, objects[0].URL.Relative(), "file1.txt")
+	assert.Equal(t, objects[0].Size, int64(10))
+	assert.Equal(t, objects[0].Etag, "etag1")
+	assert.Equal(t, *objects[0].ModTime, time.Date(2020, 3, 18, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, objects[1].URL.String(), "s3://bucket/prefix/dir/file 2.txt")
+	assert.Equal(t, objects[1].URL.Relative(), "dir/file 2.txt")
+	assert.Equal(t, objects[1].StorageClass, StorageClass("GLACIER"))
+}
+
+func TestReadInventoryCSVWithoutKey(t *testing.T) {
+	src, err := url.New("s3://bucket/*")
+	assert.NilError(t, err)
+
+	_, err = readInventoryCSV(strings.NewReader(""), "Bucket, Size", src, make(chan *Object))
+	assert.ErrorContains(t, err, `inventory schema "Bucket, Size" does not have Key field`)
+}
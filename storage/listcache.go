@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// globalListCache keeps the listings of S3 for the lifetime of the run, if
+// the listings are cached.
+var globalListCache = newListCache()
+
+// listCacheKey identifies the listing of a prefix.
+type listCacheKey struct {
+	endpoint   string
+	bucket     string
+	prefix     string
+	delimiter  string
+	startAfter string
+	maxKeys    int64
+}
+
+// listCache keeps the pages of the completed listings, so that a prefix is
+// listed once however many commands expand it. Concurrent listings of the
+// same prefix wait for the first one instead of listing it again. It is safe
+// for concurrent use.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[listCacheKey]*listCacheEntry
+}
+
+type listCacheEntry struct {
+	done     chan struct{}
+	pages    []*s3.ListObjectsV2Output
+	complete bool
+
+	// stale is set if an object under the prefix is changed while the
+	// prefix is listed, so the listing is not kept.
+	stale bool
+}
+
+func newListCache() *listCache {
+	return &listCache{
+		entries: map[listCacheKey]*listCacheEntry{},
+	}
+}
+
+// paginate calls fn with the pages of the listing of the key. The pages are
+// listed with list for the first listing of the key, and replayed from the
+// cache for the next ones. Listings which fail or are stopped by fn are not
+// kept.
+func (c *listCache) paginate(
+	ctx context.Context,
+	key listCacheKey,
+	list func(func(*s3.ListObjectsV2Output, bool) bool) error,
+	fn func(*s3.ListObjectsV2Output, bool) bool,
+) error {
+	for {
+		c.mu.Lock()
+		entry, ok := c.entries[key]
+		if !ok {
+			entry = &listCacheEntry{done: make(chan struct{})}
+			c.entries[key] = entry
+			c.mu.Unlock()
+			return c.fill(key, entry, list, fn)
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if !entry.complete {
+			// the listing is not kept, list the prefix again.
+			continue
+		}
+
+		for i, page := range entry.pages {
+			if !fn(page, i == len(entry.pages)-1) {
+				break
+			}
+		}
+		return nil
+	}
+}
+
+// fill lists the key for the entry, and keeps its pages if the listing is
+// completed.
+func (c *listCache) fill(
+	key listCacheKey,
+	entry *listCacheEntry,
+	list func(func(*s3.ListObjectsV2Output, bool) bool) error,
+	fn func(*s3.ListObjectsV2Output, bool) bool,
+) error {
+	var (
+		pages    []*s3.ListObjectsV2Output
+		complete bool
+	)
+	err := list(func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		pages = append(pages, page)
+		complete = lastPage
+		if !fn(page, lastPage) {
+			return false
+		}
+		return !lastPage
+	})
+
+	c.mu.Lock()
+	if err == nil && complete && !entry.stale {
+		entry.pages = pages
+		entry.complete = true
+	} else if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+
+	close(entry.done)
+	return err
+}
+
+// invalidate drops the listings which might have the key of the bucket, since
+// the object of the key is changed.
+func (c *listCache) invalidate(bucket, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, entry := range c.entries {
+		if k.bucket == bucket && strings.HasPrefix(key, k.prefix) {
+			entry.stale = true
+			delete(c.entries, k)
+		}
+	}
+}
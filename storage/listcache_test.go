@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"gotest.tools/v3/assert"
+)
+
+func TestListCache(t *testing.T) {
+	pages := []*s3.ListObjectsV2Output{
+		{Contents: []*s3.Object{{Key: aws.String("logs/a.gz")}}},
+		{Contents: []*s3.Object{{Key: aws.String("logs/b.gz")}}},
+	}
+
+	var calls int32
+	list := func(fn func(*s3.ListObjectsV2Output, bool) bool) error {
+		atomic.AddInt32(&calls, 1)
+		for i, page := range pages {
+			if !fn(page, i == len(pages)-1) {
+				break
+			}
+		}
+		return nil
+	}
+
+	keys := func(c *listCache, key listCacheKey) []string {
+		var got []string
+		err := c.paginate(context.Background(), key, list, func(p *s3.ListObjectsV2Output, lastPage bool) bool {
+			for _, c := range p.Contents {
+				got = append(got, aws.StringValue(c.Key))
+			}
+			return !lastPage
+		})
+		assert.NilError(t, err)
+		return got
+	}
+
+	c := newListCache()
+	key := listCacheKey{bucket: "bucket", prefix: "logs/"}
+	expected := []string{"logs/a.gz", "logs/b.gz"}
+
+	// concurrent listings wait for the first one.
+	results := make([][]string, 10)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = keys(c, key)
+		}(i)
+	}
+	wg.Wait()
+	for _, got := range results {
+		assert.DeepEqual(t, got, expected)
+	}
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(1))
+
+	// other prefixes and buckets are listed on their own.
+	keys(c, listCacheKey{bucket: "bucket", prefix: "logs/2020/"})
+	keys(c, listCacheKey{bucket: "other", prefix: "logs/"})
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(3))
+
+	// changes outside of the prefix keep the listing.
+	c.invalidate("bucket", "backup/a.gz")
+	c.invalidate("other", "logs/c.gz")
+	assert.DeepEqual(t, keys(c, key), expected)
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(3))
+
+	c.invalidate("bucket", "logs/c.gz")
+	assert.DeepEqual(t, keys(c, key), expected)
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(4))
+}
+
+func TestListCacheIncompleteListing(t *testing.T) {
+	page := &s3.ListObjectsV2Output{Contents: []*s3.Object{{Key: aws.String("a")}}}
+	errList := errors.New("list error")
+
+	var calls int32
+	list := func(fn func(*s3.ListObjectsV2Output, bool) bool) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return errList
+		}
+		for i := 0; i < 2; i++ {
+			if !fn(page, i == 1) {
+				break
+			}
+		}
+		return nil
+	}
+
+	c := newListCache()
+	key := listCacheKey{bucket: "bucket"}
+	all := func(*s3.ListObjectsV2Output, bool) bool { return true }
+
+	// failed listings are not kept.
+	err := c.paginate(context.Background(), key, list, all)
+	assert.Equal(t, err, errList)
+
+	// listings stopped before the last page are not kept.
+	err = c.paginate(context.Background(), key, list, func(*s3.ListObjectsV2Output, bool) bool { return false })
+	assert.NilError(t, err)
+
+	assert.NilError(t, c.paginate(context.Background(), key, list, all))
+	assert.NilError(t, c.paginate(context.Background(), key, list, all))
+	assert.Equal(t, atomic.LoadInt32(&calls), int32(3))
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+// User-defined metadata keys to store POSIX permissions and ownership of
+// uploaded files.
+const (
+	MetadataMode  = "mode"
+	MetadataUID   = "uid"
+	MetadataGID   = "gid"
+	MetadataUser  = "user"
+	MetadataGroup = "group"
+)
+
+// Permissions are the POSIX permissions and ownership of a file.
+type Permissions struct {
+	Mode os.FileMode
+
+	// ownership is unknown on platforms without POSIX ownership.
+	HasOwner bool
+	UID      int
+	GID      int
+	User     string
+	Group    string
+}
+
+// Permissions returns the permissions and ownership of the given path.
+func (f *Filesystem) Permissions(path string) (*Permissions, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	perm := &Permissions{Mode: fi.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)}
+	perm.UID, perm.GID, perm.HasOwner = fileOwner(fi)
+	if perm.HasOwner {
+		if u, err := user.LookupId(strconv.Itoa(perm.UID)); err == nil {
+			perm.User = u.Username
+		}
+		if g, err := user.LookupGroupId(strconv.Itoa(perm.GID)); err == nil {
+			perm.Group = g.Name
+		}
+	}
+	return perm, nil
+}
+
+// SetPermissions changes the mode of the given path. The ownership is
+// changed only if the process runs as root, as other users can't give away
+// their files. Owners are mapped by user and group names to the local ids,
+// unless numericIDs is set or the names don't exist on this host.
+func (f *Filesystem) SetPermissions(path string, perm *Permissions, numericIDs bool) error {
+	if f.dryRun {
+		return nil
+	}
+
+	if perm.HasOwner && os.Geteuid() == 0 {
+		uid, gid := perm.UID, perm.GID
+		if !numericIDs {
+			if u, err := user.Lookup(perm.User); perm.User != "" && err == nil {
+				uid, _ = strconv.Atoi(u.Uid)
+			}
+			if g, err := user.LookupGroup(perm.Group); perm.Group != "" && err == nil {
+				gid, _ = strconv.Atoi(g.Gid)
+			}
+		}
+		if err := os.Chown(path, uid, gid); err != nil {
+			return err
+		}
+	}
+
+	// chmod after chown, since chown clears setuid and setgid bits.
+	return os.Chmod(path, perm.Mode)
+}
+
+// SetMetadata stores the permissions as user-defined metadata.
+func (p *Permissions) SetMetadata(metadata Metadata) {
+	metadata.SetUserDefined(MetadataMode, fmt.Sprintf("%04o", unixMode(p.Mode)))
+	if !p.HasOwner {
+		return
+	}
+
+	metadata.SetUserDefined(MetadataUID, strconv.Itoa(p.UID))
+	metadata.SetUserDefined(MetadataGID, strconv.Itoa(p.GID))
+	if p.User != "" {
+		metadata.SetUserDefined(MetadataUser, p.User)
+	}
+	if p.Group != "" {
+		metadata.SetUserDefined(MetadataGroup, p.Group)
+	}
+}
+
+// PermissionsFromMetadata parses the permissions stored by SetMetadata. It
+// returns nil if the metadata has no permissions.
+func PermissionsFromMetadata(metadata map[string]string) (*Permissions, error) {
+	value, ok := metadata[MetadataMode]
+	if !ok {
+		return nil, nil
+	}
+
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mode %q", value)
+	}
+	perm := &Permissions{Mode: fileMode(uint32(mode))}
+
+	uid, uidErr := strconv.Atoi(metadata[MetadataUID])
+	gid, gidErr := strconv.Atoi(metadata[MetadataGID])
+	if uidErr == nil && gidErr == nil {
+		perm.HasOwner = true
+		perm.UID = uid
+		perm.GID = gid
+		perm.User = metadata[MetadataUser]
+		perm.Group = metadata[MetadataGroup]
+	}
+	return perm, nil
+}
+
+// unixMode converts the permission bits of os.FileMode to the POSIX mode.
+func unixMode(mode os.FileMode) uint32 {
+	m := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		m |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		m |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		m |= 01000
+	}
+	return m
+}
+
+// fileMode converts the POSIX mode to os.FileMode.
+func fileMode(m uint32) os.FileMode {
+	mode := os.FileMode(m) & os.ModePerm
+	if m&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if m&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if m&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
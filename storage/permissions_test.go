@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+func TestPermissionsMetadataRoundTrip(t *testing.T) {
+	perm := &Permissions{
+		Mode:     0750 | os.ModeSetgid,
+		HasOwner: true,
+		UID:      1000,
+		GID:      100,
+		User:     "alice",
+		Group:    "users",
+	}
+
+	metadata := NewMetadata()
+	perm.SetMetadata(metadata)
+
+	assert.DeepEqual(t, metadata.UserDefined(), map[string]string{
+		"mode":  "2750",
+		"uid":   "1000",
+		"gid":   "100",
+		"user":  "alice",
+		"group": "users",
+	})
+
+	got, err := PermissionsFromMetadata(metadata.UserDefined())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, perm)
+}
+
+func TestPermissionsFromMetadata(t *testing.T) {
+	testcases := []struct {
+		name        string
+		metadata    map[string]string
+		expected    *Permissions
+		expectedErr string
+	}{
+		{
+			name:     "no_permissions",
+			metadata: map[string]string{"mtime": "1577934245"},
+		},
+		{
+			name:     "mode_only",
+			metadata: map[string]string{"mode": "0644"},
+			expected: &Permissions{Mode: 0644},
+		},
+		{
+			name:     "partial_ownership_is_ignored",
+			metadata: map[string]string{"mode": "0644", "uid": "1000"},
+			expected: &Permissions{Mode: 0644},
+		},
+		{
+			name:        "invalid_mode",
+			metadata:    map[string]string{"mode": "rw-r--r--"},
+			expectedErr: `invalid mode "rw-r--r--"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PermissionsFromMetadata(tc.metadata)
+			if tc.expectedErr != "" {
+				assert.Error(t, err, tc.expectedErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, got, tc.expected)
+		})
+	}
+}
+
+func TestFilesystemSetPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+
+	testdir := fs.NewDir(t, "permissions", fs.WithFile("file", "content", fs.WithMode(0644)))
+	defer testdir.Remove()
+
+	client := NewLocalClient(Options{})
+
+	perm, err := client.Permissions(testdir.Join("file"))
+	assert.NilError(t, err)
+	assert.Equal(t, perm.Mode, os.FileMode(0644))
+	assert.Assert(t, perm.HasOwner)
+
+	perm.Mode = 0600
+	assert.NilError(t, client.SetPermissions(testdir.Join("file"), perm, false))
+
+	fi, err := os.Stat(testdir.Join("file"))
+	assert.NilError(t, err)
+	assert.Equal(t, fi.Mode().Perm(), os.FileMode(0600))
+}
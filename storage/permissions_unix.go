@@ -0,0 +1,17 @@
+// +build !windows
+
+package storage
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the owner user and group ids of the file.
+func fileOwner(fi os.FileInfo) (int, int, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
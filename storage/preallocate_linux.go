@@ -0,0 +1,19 @@
+// +build linux
+
+package storage
+
+import (
+	"os"
+	"syscall"
+)
+
+// preallocate allocates disk space for the file up to the given size. It
+// falls back to setting the file size if the filesystem doesn't support
+// allocation.
+func preallocate(file *os.File, size int64) error {
+	err := syscall.Fallocate(int(file.Fd()), 0, 0, size)
+	if err == syscall.EOPNOTSUPP || err == syscall.ENOSYS {
+		return file.Truncate(size)
+	}
+	return err
+}
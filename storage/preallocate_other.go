@@ -0,0 +1,11 @@
+// +build !linux
+
+package storage
+
+import "os"
+
+// preallocate sets the size of the file. Disk space is not allocated on this
+// platform.
+func preallocate(file *os.File, size int64) error {
+	return file.Truncate(size)
+}
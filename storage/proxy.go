@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	urlpkg "net/url"
+	"os"
+	"strings"
+)
+
+// proxyConfig holds the proxy settings of the HTTP transport. Explicitly
+// given settings override the ones read from the environment.
+type proxyConfig struct {
+	httpProxy  string
+	httpsProxy string
+	noProxy    string
+}
+
+// newProxyConfig creates proxy settings from environment variables,
+// overriding each setting if it is given in options.
+func newProxyConfig(opts Options) proxyConfig {
+	cfg := proxyConfig{
+		httpProxy:  getEnvAny("HTTP_PROXY", "http_proxy"),
+		httpsProxy: getEnvAny("HTTPS_PROXY", "https_proxy"),
+		noProxy:    getEnvAny("NO_PROXY", "no_proxy"),
+	}
+
+	if opts.HTTPProxy != "" {
+		cfg.httpProxy = opts.HTTPProxy
+	}
+	if opts.HTTPSProxy != "" {
+		cfg.httpsProxy = opts.HTTPSProxy
+	}
+	if opts.NoProxy != "" {
+		cfg.noProxy = opts.NoProxy
+	}
+	return cfg
+}
+
+// proxyFunc returns a function to be used as http.Transport.Proxy. It
+// returns an error if any of the proxy addresses is invalid.
+func (p proxyConfig) proxyFunc() (func(*http.Request) (*urlpkg.URL, error), error) {
+	httpProxy, err := parseProxy(p.httpProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	httpsProxy, err := parseProxy(p.httpsProxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(req *http.Request) (*urlpkg.URL, error) {
+		proxy := httpProxy
+		if req.URL.Scheme == "https" {
+			proxy = httpsProxy
+		}
+
+		if proxy == nil || !p.useProxy(req.URL.Host) {
+			return nil, nil
+		}
+		return proxy, nil
+	}, nil
+}
+
+// useProxy reports whether requests to the given address should be sent
+// through the proxy. Loopback addresses and the hosts matching a NO_PROXY
+// entry are accessed directly.
+func (p proxyConfig) useProxy(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	host = strings.ToLower(host)
+
+	if host == "localhost" {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return false
+	}
+
+	for _, entry := range strings.Split(p.noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+
+		if entry == "*" {
+			return false
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return false
+			}
+			continue
+		}
+
+		entryHost, entryPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			entryHost, entryPort = entry, ""
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		// "example.com" and ".example.com" match example.com and all of
+		// its subdomains.
+		entryHost = strings.TrimPrefix(entryHost, ".")
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseProxy parses the given proxy address. Addresses without a scheme are
+// assumed to be HTTP proxies.
+func parseProxy(proxy string) (*urlpkg.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	u, err := urlpkg.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy address %q", proxy)
+	}
+	return u, nil
+}
+
+func getEnvAny(names ...string) string {
+	for _, name := range names {
+		if val := os.Getenv(name); val != "" {
+			return val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProxyFunc(t *testing.T) {
+	testcases := []struct {
+		name          string
+		config        proxyConfig
+		url           string
+		expectedProxy string
+	}{
+		{
+			name:   "no_proxy_configured",
+			config: proxyConfig{},
+			url:    "https://s3.amazonaws.com/bucket",
+		},
+		{
+			name:          "https_proxy_for_https_request",
+			config:        proxyConfig{httpProxy: "http://proxy1:3128", httpsProxy: "http://proxy2:3128"},
+			url:           "https://s3.amazonaws.com/bucket",
+			expectedProxy: "http://proxy2:3128",
+		},
+		{
+			name:          "http_proxy_for_http_request",
+			config:        proxyConfig{httpProxy: "http://proxy1:3128", httpsProxy: "http://proxy2:3128"},
+			url:           "http://s3.amazonaws.com/bucket",
+			expectedProxy: "http://proxy1:3128",
+		},
+		{
+			name:          "proxy_without_scheme",
+			config:        proxyConfig{httpsProxy: "proxy:3128"},
+			url:           "https://s3.amazonaws.com/bucket",
+			expectedProxy: "http://proxy:3128",
+		},
+		{
+			name:   "loopback_is_accessed_directly",
+			config: proxyConfig{httpProxy: "http://proxy:3128"},
+			url:    "http://127.0.0.1:9000/bucket",
+		},
+		{
+			name:   "no_proxy_wildcard",
+			config: proxyConfig{httpsProxy: "http://proxy:3128", noProxy: "*"},
+			url:    "https://s3.amazonaws.com/bucket",
+		},
+		{
+			name:   "no_proxy_domain_matches_subdomain",
+			config: proxyConfig{httpsProxy: "http://proxy:3128", noProxy: "example.com, .amazonaws.com"},
+			url:    "https://bucket.s3.amazonaws.com/key",
+		},
+		{
+			name:          "no_proxy_domain_does_not_match_suffix",
+			config:        proxyConfig{httpsProxy: "http://proxy:3128", noProxy: "amazonaws.com"},
+			url:           "https://notamazonaws.com/key",
+			expectedProxy: "http://proxy:3128",
+		},
+		{
+			name:   "no_proxy_cidr",
+			config: proxyConfig{httpProxy: "http://proxy:3128", noProxy: "10.0.0.0/8"},
+			url:    "http://10.1.2.3:9000/bucket",
+		},
+		{
+			name:          "no_proxy_port_mismatch",
+			config:        proxyConfig{httpProxy: "http://proxy:3128", noProxy: "minio.local:9000"},
+			url:           "http://minio.local:9001/bucket",
+			expectedProxy: "http://proxy:3128",
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			proxy, err := tc.config.proxyFunc()
+			assert.NilError(t, err)
+
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			assert.NilError(t, err)
+
+			got, err := proxy(req)
+			assert.NilError(t, err)
+
+			if tc.expectedProxy == "" {
+				assert.Assert(t, got == nil, "expected no proxy, got %v", got)
+				return
+			}
+			assert.Assert(t, got != nil, "expected proxy %v, got none", tc.expectedProxy)
+			assert.Equal(t, got.String(), tc.expectedProxy)
+		})
+	}
+}
+
+func TestNewProxyConfigOverridesEnvironment(t *testing.T) {
+	os.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	os.Setenv("NO_PROXY", "example.com")
+	defer os.Unsetenv("HTTPS_PROXY")
+	defer os.Unsetenv("NO_PROXY")
+
+	cfg := newProxyConfig(Options{HTTPSProxy: "http://flag-proxy:3128"})
+
+	assert.Equal(t, cfg.httpsProxy, "http://flag-proxy:3128")
+	assert.Equal(t, cfg.noProxy, "example.com")
+}
+
+func TestProxyFuncInvalidProxy(t *testing.T) {
+	_, err := proxyConfig{httpProxy: "http://"}.proxyFunc()
+	assert.ErrorContains(t, err, "invalid proxy address")
+}
@@ -3,20 +3,25 @@ package storage
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	urlpkg "net/url"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
@@ -27,6 +32,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
 	"github.com/peak/s5cmd/log"
+	"github.com/peak/s5cmd/log/stat"
 	"github.com/peak/s5cmd/storage/url"
 )
 
@@ -37,11 +43,19 @@ const (
 	// request.
 	deleteObjectsMax = 1000
 
+	// defaultDeleteConcurrency is the number of concurrent DeleteObjects
+	// requests, if it is not given.
+	defaultDeleteConcurrency = 10
+
 	// Amazon Accelerated Transfer endpoint
 	transferAccelEndpoint = "s3-accelerate.amazonaws.com"
 
 	// Google Cloud Storage endpoint
 	gcsEndpoint = "storage.googleapis.com"
+
+	// defaults of the dialer of http.DefaultTransport
+	defaultConnectTimeout = 30 * time.Second
+	defaultKeepAlive      = 30 * time.Second
 )
 
 // Re-used AWS sessions dramatically improve performance.
@@ -57,6 +71,33 @@ type S3 struct {
 	uploader    s3manageriface.UploaderAPI
 	endpointURL urlpkg.URL
 	dryRun      bool
+
+	// profile and region are the shared config profile and the region the
+	// client is created with. The region is empty if it is resolved from the
+	// bucket.
+	profile string
+	region  string
+
+	// deleteBatchSize is the number of keys deleted with a single request,
+	// and deleteConcurrency is the number of concurrent delete requests.
+	deleteBatchSize   int
+	deleteConcurrency int
+
+	// listShards is the number of concurrent paginations of a wildcard
+	// listing. Listings are not sharded if it is less than 2.
+	listShards int
+
+	// listMaxKeys is the number of keys of a listing page, and
+	// listStartAfter is the key after which the listings start.
+	listMaxKeys    int64
+	listStartAfter string
+
+	// listCache keeps the listings of the run, if the listings are cached.
+	listCache *listCache
+
+	// statCache keeps the metadata of the objects of the run, if the
+	// metadata are cached.
+	statCache *statCache
 }
 
 func parseEndpoint(endpoint string) (urlpkg.URL, error) {
@@ -88,17 +129,104 @@ func newS3Storage(ctx context.Context, opts Options) (*S3, error) {
 		return nil, err
 	}
 
+	var listCache *listCache
+	if opts.CacheListings {
+		listCache = globalListCache
+	}
+
+	var statCache *statCache
+	if opts.CacheStats {
+		statCache = globalStatCache
+		statCache.enable()
+	}
+
 	return &S3{
-		api:         s3.New(awsSession),
-		downloader:  s3manager.NewDownloader(awsSession),
-		uploader:    s3manager.NewUploader(awsSession),
-		endpointURL: endpointURL,
-		dryRun:      opts.DryRun,
+		api:               s3.New(awsSession),
+		downloader:        s3manager.NewDownloader(awsSession),
+		uploader:          s3manager.NewUploader(awsSession),
+		endpointURL:       endpointURL,
+		dryRun:            opts.DryRun,
+		profile:           opts.profile,
+		region:            opts.region,
+		deleteBatchSize:   opts.DeleteBatchSize,
+		deleteConcurrency: opts.DeleteConcurrency,
+		listShards:        opts.ListShards,
+		listMaxKeys:       opts.ListMaxKeys,
+		listStartAfter:    opts.ListStartAfter,
+		listCache:         listCache,
+		statCache:         statCache,
 	}, nil
 }
 
 // Stat retrieves metadata from S3 object without returning the object itself.
+// The metadata are retrieved once for the run if they are cached.
 func (s *S3) Stat(ctx context.Context, url *url.URL) (*Object, error) {
+	if s.statCache == nil {
+		return s.stat(ctx, url)
+	}
+
+	key := s.statCacheKey(url.Bucket, url.Path)
+	if entry, ok := s.statCache.get(key); ok {
+		if entry.notFound {
+			return nil, ErrGivenObjectNotFound
+		}
+		obj := *entry.object
+		obj.URL = url
+		return &obj, nil
+	}
+
+	version := s.statCache.currentVersion()
+	obj, err := s.stat(ctx, url)
+	s.statCache.put(key, version, obj, err)
+	return obj, err
+}
+
+// statCacheKey returns the key of the object in the stat cache. The objects
+// seen with other credentials or endpoints are cached separately.
+func (s *S3) statCacheKey(bucket, key string) statCacheKey {
+	return statCacheKey{
+		profile:  s.profile,
+		region:   s.region,
+		endpoint: s.endpointURL.String(),
+		bucket:   bucket,
+		key:      key,
+	}
+}
+
+// PrefetchStats lists the objects under the prefix of the url, so that their
+// metadata are retrieved from a few listing requests instead of a HEAD request
+// each. The keys under the prefix which are not listed are known not to exist.
+// It does nothing if the metadata are not cached.
+func (s *S3) PrefetchStats(ctx context.Context, prefix *url.URL) error {
+	if s.statCache == nil {
+		return nil
+	}
+
+	listInput := s3.ListObjectsV2Input{
+		Bucket: aws.String(prefix.Bucket),
+		Prefix: aws.String(prefix.Path),
+	}
+
+	version := s.statCache.currentVersion()
+
+	var objects []*Object
+	err := s.api.ListObjectsV2PagesWithContext(ctx, &listInput, func(p *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, c := range p.Contents {
+			mod := aws.TimeValue(c.LastModified).UTC()
+			objects = append(objects, listedObject(prefix, c, mod))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return err
+	}
+
+	s.statCache.putListed(s.statCacheKey(prefix.Bucket, prefix.Path), version, objects)
+	return nil
+}
+
+// stat retrieves the metadata of the object with a HEAD request.
+func (s *S3) stat(ctx context.Context, url *url.URL) (*Object, error) {
 	output, err := s.api.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
 		Bucket: aws.String(url.Bucket),
 		Key:    aws.String(url.Path),
@@ -112,33 +240,273 @@ func (s *S3) Stat(ctx context.Context, url *url.URL) (*Object, error) {
 
 	etag := aws.StringValue(output.ETag)
 	mod := aws.TimeValue(output.LastModified)
+
+	// SDK canonicalizes the keys as HTTP headers.
+	var userMetadata map[string]string
+	if len(output.Metadata) > 0 {
+		userMetadata = map[string]string{}
+		for key, value := range output.Metadata {
+			userMetadata[strings.ToLower(key)] = aws.StringValue(value)
+		}
+	}
+
 	return &Object{
-		URL:     url,
-		Etag:    strings.Trim(etag, `"`),
-		ModTime: &mod,
-		Size:    aws.Int64Value(output.ContentLength),
+		URL:          url,
+		Etag:         strings.Trim(etag, `"`),
+		ModTime:      &mod,
+		Size:         aws.Int64Value(output.ContentLength),
+		StorageClass: StorageClass(aws.StringValue(output.StorageClass)),
+		UserMetadata: userMetadata,
+		ContentType:  aws.StringValue(output.ContentType),
+		CacheControl: aws.StringValue(output.CacheControl),
+		SSE:          aws.StringValue(output.ServerSideEncryption),
+		SSEKeyID:     aws.StringValue(output.SSEKMSKeyId),
 	}, nil
 }
 
+// Tags retrieves the tags of a single object.
+func (s *S3) Tags(ctx context.Context, url *url.URL) (map[string]string, error) {
+	output, err := s.api.GetObjectTaggingWithContext(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(url.Bucket),
+		Key:    aws.String(url.Path),
+	})
+	if err != nil {
+		if errHasCode(err, "NoSuchKey") {
+			return nil, ErrGivenObjectNotFound
+		}
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, tag := range output.TagSet {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+// encodeTags encodes the tags as URL query parameters, in the format of the
+// tagging header.
+func encodeTags(tags map[string]string) string {
+	values := urlpkg.Values{}
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
+
+// ACL is the access control list of an object.
+type ACL struct {
+	grants []*s3.Grant
+}
+
+// ACL retrieves the access control list of the object.
+func (s *S3) ACL(ctx context.Context, url *url.URL) (*ACL, error) {
+	output, err := s.api.GetObjectAclWithContext(ctx, &s3.GetObjectAclInput{
+		Bucket: aws.String(url.Bucket),
+		Key:    aws.String(url.Path),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ACL{grants: output.Grants}, nil
+}
+
+// SetACL replaces the access control list of the object with the grants of
+// the given one. The owner of the object is kept, since it might differ from
+// the owner of the object the list is retrieved from.
+func (s *S3) SetACL(ctx context.Context, url *url.URL, acl *ACL) error {
+	if s.dryRun {
+		return nil
+	}
+
+	current, err := s.api.GetObjectAclWithContext(ctx, &s3.GetObjectAclInput{
+		Bucket: aws.String(url.Bucket),
+		Key:    aws.String(url.Path),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.api.PutObjectAclWithContext(ctx, &s3.PutObjectAclInput{
+		Bucket: aws.String(url.Bucket),
+		Key:    aws.String(url.Path),
+		AccessControlPolicy: &s3.AccessControlPolicy{
+			Owner:  current.Owner,
+			Grants: acl.grants,
+		},
+	})
+	return err
+}
+
 // List is a non-blocking S3 list operation which paginates and filters S3
 // keys. If no object found or an error is encountered during this period,
 // it sends these errors to object channel.
 func (s *S3) List(ctx context.Context, url *url.URL, _ bool) <-chan *Object {
+	list := s.listObjectsV2
 	if isGoogleEndpoint(s.endpointURL) {
-		return s.listObjects(ctx, url)
+		list = s.listObjects
+	}
+
+	prefixes := url.ListPrefixes()
+	if s.listShards > 1 && url.Delimiter == "" && !isGoogleEndpoint(s.endpointURL) {
+		return s.listSharded(ctx, url, prefixes)
+	}
+	if len(prefixes) == 1 {
+		return list(ctx, url, prefixes[0])
 	}
-	return s.listObjectsV2(ctx, url)
+	return listPrefixes(ctx, url, prefixes, list)
 }
 
-func (s *S3) listObjectsV2(ctx context.Context, url *url.URL) <-chan *Object {
+// listSharded lists the objects matching the url with up to s.listShards
+// concurrent ListObjectsV2 paginations. The keyspace of each prefix is
+// sharded by its common prefixes, i.e. the directories right under the
+// prefix, and each shard is paginated on its own. Keys are not sent in order.
+func (s *S3) listSharded(ctx context.Context, url *url.URL, prefixes []string) <-chan *Object {
+	objCh := make(chan *Object)
+
+	go func() {
+		defer close(objCh)
+		var objectFound int32
+
+		send := func(obj *Object) {
+			if obj.Err == ErrNoObjectFound {
+				return
+			}
+			objCh <- obj
+			atomic.StoreInt32(&objectFound, 1)
+		}
+
+		shardCh := make(chan string)
+		var wg sync.WaitGroup
+		for i := 0; i < s.listShards; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for shard := range shardCh {
+					for obj := range s.listObjectsV2(ctx, url, shard) {
+						send(obj)
+					}
+				}
+			}()
+		}
+
+		var err error
+		for _, prefix := range prefixes {
+			if err = s.listShardsOf(ctx, url, prefix, shardCh, send); err != nil {
+				break
+			}
+		}
+		close(shardCh)
+		wg.Wait()
+
+		if err != nil {
+			objCh <- &Object{Err: err}
+			return
+		}
+		if ctx.Err() == nil && atomic.LoadInt32(&objectFound) == 0 {
+			objCh <- &Object{Err: ErrNoObjectFound}
+		}
+	}()
+
+	return objCh
+}
+
+// listShardsOf sends the common prefixes of the prefix to shardCh. The keys
+// right under the prefix don't belong to any of the shards, so the ones
+// matching the url are sent as they are listed.
+func (s *S3) listShardsOf(
+	ctx context.Context,
+	url *url.URL,
+	prefix string,
+	shardCh chan<- string,
+	send func(*Object),
+) error {
+	listInput := s3.ListObjectsV2Input{
+		Bucket:    aws.String(url.Bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	}
+	s.setListPage(&listInput)
+
+	var now time.Time
+
+	return s.listObjectsV2Pages(ctx, &listInput, func(p *s3.ListObjectsV2Output, lastPage bool) bool {
+		if now.IsZero() {
+			now = time.Now().UTC()
+		}
+
+		for _, c := range p.Contents {
+			if !url.Match(aws.StringValue(c.Key)) {
+				continue
+			}
+
+			mod := aws.TimeValue(c.LastModified).UTC()
+			if mod.After(now) {
+				continue
+			}
+			send(listedObject(url, c, mod))
+		}
+
+		for _, c := range p.CommonPrefixes {
+			select {
+			case shardCh <- aws.StringValue(c.Prefix):
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		return !lastPage
+	})
+}
+
+// listPrefixes lists the objects matching the url with each of the prefixes
+// one after another, so the keys are sent in order.
+func listPrefixes(
+	ctx context.Context,
+	url *url.URL,
+	prefixes []string,
+	list func(context.Context, *url.URL, string) <-chan *Object,
+) <-chan *Object {
+	objCh := make(chan *Object)
+
+	go func() {
+		defer close(objCh)
+		objectFound := false
+
+		for _, prefix := range prefixes {
+			for obj := range list(ctx, url, prefix) {
+				if obj.Err == ErrNoObjectFound {
+					continue
+				}
+				objCh <- obj
+				objectFound = true
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		if !objectFound {
+			objCh <- &Object{Err: ErrNoObjectFound}
+		}
+	}()
+
+	return objCh
+}
+
+// listObjectsV2 lists the objects matching the url whose keys start with the
+// prefix. The prefix might be narrower than the one of the url if the url
+// has alternatives.
+func (s *S3) listObjectsV2(ctx context.Context, url *url.URL, prefix string) <-chan *Object {
 	listInput := s3.ListObjectsV2Input{
 		Bucket: aws.String(url.Bucket),
-		Prefix: aws.String(url.Prefix),
+		Prefix: aws.String(prefix),
 	}
 
 	if url.Delimiter != "" {
 		listInput.SetDelimiter(url.Delimiter)
 	}
+	s.setListPage(&listInput)
 
 	objCh := make(chan *Object)
 
@@ -148,7 +516,7 @@ func (s *S3) listObjectsV2(ctx context.Context, url *url.URL) <-chan *Object {
 
 		var now time.Time
 
-		err := s.api.ListObjectsV2PagesWithContext(ctx, &listInput, func(p *s3.ListObjectsV2Output, lastPage bool) bool {
+		err := s.listObjectsV2Pages(ctx, &listInput, func(p *s3.ListObjectsV2Output, lastPage bool) bool {
 			for _, c := range p.CommonPrefixes {
 				prefix := aws.StringValue(c.Prefix)
 				if !url.Match(prefix) {
@@ -182,22 +550,86 @@ func (s *S3) listObjectsV2(ctx context.Context, url *url.URL) <-chan *Object {
 					continue
 				}
 
+				objCh <- listedObject(url, c, mod)
+
+				objectFound = true
+			}
+
+			return !lastPage
+		})
+
+		if err != nil {
+			objCh <- &Object{Err: err}
+			return
+		}
+
+		if !objectFound {
+			objCh <- &Object{Err: ErrNoObjectFound}
+		}
+	}()
+
+	return objCh
+}
+
+// ListVersions lists all versions of the objects which match the given url.
+// Delete markers are not returned.
+func (s *S3) ListVersions(ctx context.Context, url *url.URL) <-chan *Object {
+	listInput := s3.ListObjectVersionsInput{
+		Bucket: aws.String(url.Bucket),
+		Prefix: aws.String(url.Prefix),
+	}
+
+	if url.Delimiter != "" {
+		listInput.SetDelimiter(url.Delimiter)
+	}
+
+	objCh := make(chan *Object)
+
+	go func() {
+		defer close(objCh)
+		objectFound := false
+
+		err := s.api.ListObjectVersionsPagesWithContext(ctx, &listInput, func(p *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			for _, c := range p.CommonPrefixes {
+				prefix := aws.StringValue(c.Prefix)
+				if !url.Match(prefix) {
+					continue
+				}
+
+				newurl := url.Clone()
+				newurl.Path = prefix
+				objCh <- &Object{
+					URL:  newurl,
+					Type: ObjectType{os.ModeDir},
+				}
+
+				objectFound = true
+			}
+
+			for _, v := range p.Versions {
+				key := aws.StringValue(v.Key)
+				if !url.Match(key) {
+					continue
+				}
+
 				var objtype os.FileMode
 				if strings.HasSuffix(key, "/") {
 					objtype = os.ModeDir
 				}
 
+				mod := aws.TimeValue(v.LastModified).UTC()
 				newurl := url.Clone()
-				newurl.Path = aws.StringValue(c.Key)
-				etag := aws.StringValue(c.ETag)
+				newurl.Path = key
+				etag := aws.StringValue(v.ETag)
 
 				objCh <- &Object{
 					URL:          newurl,
 					Etag:         strings.Trim(etag, `"`),
 					ModTime:      &mod,
 					Type:         ObjectType{objtype},
-					Size:         aws.Int64Value(c.Size),
-					StorageClass: StorageClass(aws.StringValue(c.StorageClass)),
+					Size:         aws.Int64Value(v.Size),
+					StorageClass: StorageClass(aws.StringValue(v.StorageClass)),
+					VersionID:    aws.StringValue(v.VersionId),
 				}
 
 				objectFound = true
@@ -219,17 +651,93 @@ func (s *S3) listObjectsV2(ctx context.Context, url *url.URL) <-chan *Object {
 	return objCh
 }
 
+// listObjectsV2Pages paginates the listing of the input with fn. The pages
+// of an earlier listing of the run are replayed if the listings are cached.
+func (s *S3) listObjectsV2Pages(
+	ctx context.Context,
+	input *s3.ListObjectsV2Input,
+	fn func(*s3.ListObjectsV2Output, bool) bool,
+) error {
+	list := func(fn func(*s3.ListObjectsV2Output, bool) bool) error {
+		return s.api.ListObjectsV2PagesWithContext(ctx, input, fn)
+	}
+	if s.listCache == nil {
+		return list(fn)
+	}
+
+	key := listCacheKey{
+		endpoint:   s.endpointURL.String(),
+		bucket:     aws.StringValue(input.Bucket),
+		prefix:     aws.StringValue(input.Prefix),
+		delimiter:  aws.StringValue(input.Delimiter),
+		startAfter: aws.StringValue(input.StartAfter),
+		maxKeys:    aws.Int64Value(input.MaxKeys),
+	}
+	return s.listCache.paginate(ctx, key, list, fn)
+}
+
+// invalidateCaches drops the cached listings which might have the key, and
+// the cached metadata of the object of the key.
+func (s *S3) invalidateCaches(bucket, key string) {
+	if s.listCache != nil {
+		s.listCache.invalidate(bucket, key)
+	}
+	// the clients which don't cache the metadata still change the objects
+	// of the ones which do.
+	globalStatCache.invalidate(s.statCacheKey(bucket, key))
+}
+
+// setListPage sets the page size and the start key of the listing.
+func (s *S3) setListPage(input *s3.ListObjectsV2Input) {
+	if s.listMaxKeys > 0 {
+		input.SetMaxKeys(s.listMaxKeys)
+	}
+	if s.listStartAfter != "" {
+		input.SetStartAfter(s.listStartAfter)
+	}
+}
+
+// listedObject returns the object of the listed key c of the url. mod is the
+// modification time of the key in UTC.
+func listedObject(url *url.URL, c *s3.Object, mod time.Time) *Object {
+	key := aws.StringValue(c.Key)
+
+	var objtype os.FileMode
+	if strings.HasSuffix(key, "/") {
+		objtype = os.ModeDir
+	}
+
+	newurl := url.Clone()
+	newurl.Path = key
+	etag := aws.StringValue(c.ETag)
+
+	return &Object{
+		URL:          newurl,
+		Etag:         strings.Trim(etag, `"`),
+		ModTime:      &mod,
+		Type:         ObjectType{objtype},
+		Size:         aws.Int64Value(c.Size),
+		StorageClass: StorageClass(aws.StringValue(c.StorageClass)),
+	}
+}
+
 // listObjects is used for cloud services that does not support S3
 // ListObjectsV2 API. I'm looking at you GCS.
-func (s *S3) listObjects(ctx context.Context, url *url.URL) <-chan *Object {
+func (s *S3) listObjects(ctx context.Context, url *url.URL, prefix string) <-chan *Object {
 	listInput := s3.ListObjectsInput{
 		Bucket: aws.String(url.Bucket),
-		Prefix: aws.String(url.Prefix),
+		Prefix: aws.String(prefix),
 	}
 
 	if url.Delimiter != "" {
 		listInput.SetDelimiter(url.Delimiter)
 	}
+	if s.listMaxKeys > 0 {
+		listInput.SetMaxKeys(s.listMaxKeys)
+	}
+	if s.listStartAfter != "" {
+		listInput.SetMarker(s.listStartAfter)
+	}
 
 	objCh := make(chan *Object)
 
@@ -273,23 +781,7 @@ func (s *S3) listObjects(ctx context.Context, url *url.URL) <-chan *Object {
 					continue
 				}
 
-				var objtype os.FileMode
-				if strings.HasSuffix(key, "/") {
-					objtype = os.ModeDir
-				}
-
-				newurl := url.Clone()
-				newurl.Path = aws.StringValue(c.Key)
-				etag := aws.StringValue(c.ETag)
-
-				objCh <- &Object{
-					URL:          newurl,
-					Etag:         strings.Trim(etag, `"`),
-					ModTime:      &mod,
-					Type:         ObjectType{objtype},
-					Size:         aws.Int64Value(c.Size),
-					StorageClass: StorageClass(aws.StringValue(c.StorageClass)),
-				}
+				objCh <- listedObject(url, c, mod)
 
 				objectFound = true
 			}
@@ -345,7 +837,28 @@ func (s *S3) Copy(ctx context.Context, from, to *url.URL, metadata Metadata) err
 		input.ACL = aws.String(acl)
 	}
 
-	_, err := s.api.CopyObject(input)
+	// the metadata of the source are copied unless they are replaced, and
+	// all of them should be given to replace any.
+	if directive := metadata.MetadataDirective(); directive != "" {
+		input.MetadataDirective = aws.String(directive)
+		if contentType := metadata.ContentType(); contentType != "" {
+			input.ContentType = aws.String(contentType)
+		}
+		if cacheControl := metadata.CacheControl(); cacheControl != "" {
+			input.CacheControl = aws.String(cacheControl)
+		}
+		if userMetadata := metadata.UserDefined(); len(userMetadata) > 0 {
+			input.Metadata = aws.StringMap(userMetadata)
+		}
+	}
+
+	if tags := metadata.Tags(); len(tags) > 0 {
+		input.TaggingDirective = aws.String(s3.TaggingDirectiveReplace)
+		input.Tagging = aws.String(encodeTags(tags))
+	}
+
+	_, err := s.api.CopyObjectWithContext(ctx, input)
+	s.invalidateCaches(to.Bucket, to.Path)
 	return err
 }
 
@@ -361,6 +874,20 @@ func (s *S3) Read(ctx context.Context, src *url.URL) (io.ReadCloser, error) {
 	return resp.Body, nil
 }
 
+// ReadRange fetches the byte range of the remote object, e.g. "bytes=0-1023",
+// and returns its contents as an io.ReadCloser.
+func (s *S3) ReadRange(ctx context.Context, src *url.URL, byteRange string) (io.ReadCloser, error) {
+	resp, err := s.api.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(src.Bucket),
+		Key:    aws.String(src.Path),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 // Get is a multipart download operation which downloads S3 objects into any
 // destination that implements io.WriterAt interface.
 // Makes a single 'GetObject' call if 'concurrency' is 1 and ignores 'partSize'.
@@ -381,9 +908,80 @@ func (s *S3) Get(
 	}, func(u *s3manager.Downloader) {
 		u.PartSize = partSize
 		u.Concurrency = concurrency
+
+		if file, ok := to.(*os.File); ok {
+			u.RequestOptions = append(u.RequestOptions, withPreallocation(file))
+		}
 	})
 }
 
+// GetRange downloads the byte range of the S3 object, e.g. "bytes=0-1023",
+// into the start of the destination with a single 'GetObject' call. It
+// returns the number of bytes downloaded.
+func (s *S3) GetRange(
+	ctx context.Context,
+	from *url.URL,
+	to io.WriterAt,
+	byteRange string,
+) (int64, error) {
+	if s.dryRun {
+		return 0, nil
+	}
+
+	return s.downloader.DownloadWithContext(ctx, to, &s3.GetObjectInput{
+		Bucket: aws.String(from.Bucket),
+		Key:    aws.String(from.Path),
+		Range:  aws.String(byteRange),
+	})
+}
+
+// withPreallocation returns a request option which preallocates the file
+// with the object size, once the size is known from the response of the first
+// part. Parts are written concurrently to the file, preallocation avoids
+// fragmentation and repeated metadata updates on the filesystem.
+func withPreallocation(file *os.File) request.Option {
+	var once sync.Once
+	return func(r *request.Request) {
+		r.Handlers.Send.PushBack(func(r *request.Request) {
+			if r.Error != nil || r.HTTPResponse == nil {
+				return
+			}
+
+			once.Do(func() {
+				size := objectSizeFromResponse(r.HTTPResponse)
+				if size <= 0 {
+					return
+				}
+				if err := preallocate(file, size); err != nil {
+					r.Error = err
+				}
+			})
+		})
+	}
+}
+
+// objectSizeFromResponse returns the total size of the object from the
+// response of a ranged GetObject request. It returns -1 if the size is
+// unknown.
+func objectSizeFromResponse(resp *http.Response) int64 {
+	if resp.StatusCode == http.StatusOK {
+		return resp.ContentLength
+	}
+
+	// Content-Range: bytes 0-1023/146515
+	contentRange := resp.Header.Get("Content-Range")
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return -1
+	}
+
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
 type SelectQuery struct {
 	ExpressionType  string
 	Expression      string
@@ -470,6 +1068,7 @@ func (s *S3) Put(
 	if s.dryRun {
 		return nil
 	}
+	defer s.invalidateCaches(to.Bucket, to.Path)
 
 	contentType := metadata.ContentType()
 	if contentType == "" {
@@ -492,6 +1091,11 @@ func (s *S3) Put(
 		input.ACL = aws.String(acl)
 	}
 
+	cacheControl := metadata.CacheControl()
+	if cacheControl != "" {
+		input.CacheControl = aws.String(cacheControl)
+	}
+
 	sseEncryption := metadata.SSE()
 	if sseEncryption != "" {
 		input.ServerSideEncryption = aws.String(sseEncryption)
@@ -501,14 +1105,75 @@ func (s *S3) Put(
 		}
 	}
 
+	userMetadata := metadata.UserDefined()
+	if len(userMetadata) > 0 {
+		input.Metadata = aws.StringMap(userMetadata)
+	}
+
+	if tags := metadata.Tags(); len(tags) > 0 {
+		input.Tagging = aws.String(encodeTags(tags))
+	}
+
+	var opts []request.Option
+	if ifNoneMatch := metadata.IfNoneMatch(); ifNoneMatch != "" {
+		opts = append(opts, withIfNoneMatch(ifNoneMatch))
+	}
+
+	// objects that fit into a single part are uploaded with a single
+	// PutObject request. It bypasses the setup cost of the multipart
+	// uploader, which dominates the upload time of small objects.
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		size, err := seekerSize(seeker)
+		if err == nil && size < partSize {
+			params := &s3.PutObjectInput{}
+			awsutil.Copy(params, input)
+			params.Body = seeker
+
+			_, err := s.api.PutObjectWithContext(ctx, params, opts...)
+			return err
+		}
+	}
+
 	_, err := s.uploader.UploadWithContext(ctx, input, func(u *s3manager.Uploader) {
 		u.PartSize = partSize
 		u.Concurrency = concurrency
+		u.RequestOptions = append(u.RequestOptions, opts...)
 	})
 
 	return err
 }
 
+// withIfNoneMatch makes the write conditional with If-None-Match header. The
+// header is only sent with the requests which write the object, the parts of
+// a multipart upload are not conditional.
+func withIfNoneMatch(etag string) request.Option {
+	return func(r *request.Request) {
+		switch r.Operation.Name {
+		case "PutObject", "CompleteMultipartUpload":
+			r.HTTPRequest.Header.Set("If-None-Match", etag)
+		}
+	}
+}
+
+// seekerSize returns the number of bytes left to read from the current offset
+// of given seeker. The offset is restored before returning.
+func seekerSize(seeker io.Seeker) (int64, error) {
+	cur, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := seeker.Seek(cur, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - cur, nil
+}
+
 // chunk is an object identifier container which is used on MultiDelete
 // operations. Since DeleteObjects API allows deleting objects up to 1000,
 // splitting keys into multiple chunks is required.
@@ -518,25 +1183,38 @@ type chunk struct {
 }
 
 // calculateChunks calculates chunks for given URL channel and returns
-// read-only chunk channel.
+// read-only chunk channel. A chunk has the keys of a single bucket, up to the
+// batch size.
 func (s *S3) calculateChunks(ch <-chan *url.URL) <-chan chunk {
 	chunkch := make(chan chunk)
 
+	batchSize := s.deleteBatchSize
+	if batchSize <= 0 || batchSize > deleteObjectsMax {
+		batchSize = deleteObjectsMax
+	}
+
 	go func() {
 		defer close(chunkch)
 
 		var keys []*s3.ObjectIdentifier
 		initKeys := func() {
-			keys = make([]*s3.ObjectIdentifier, 0)
+			keys = make([]*s3.ObjectIdentifier, 0, batchSize)
 		}
 
 		var bucket string
 		for url := range ch {
+			if len(keys) > 0 && url.Bucket != bucket {
+				chunkch <- chunk{
+					Bucket: bucket,
+					Keys:   keys,
+				}
+				initKeys()
+			}
 			bucket = url.Bucket
 
 			objid := &s3.ObjectIdentifier{Key: aws.String(url.Path)}
 			keys = append(keys, objid)
-			if len(keys) == deleteObjectsMax {
+			if len(keys) == batchSize {
 				chunkch <- chunk{
 					Bucket: bucket,
 					Keys:   keys,
@@ -590,6 +1268,9 @@ func (s *S3) doDelete(ctx context.Context, chunk chunk, resultch chan *Object) {
 		Bucket: aws.String(bucket),
 		Delete: &s3.Delete{Objects: chunk.Keys},
 	})
+	for _, k := range chunk.Keys {
+		s.invalidateCaches(bucket, aws.StringValue(k.Key))
+	}
 	if err != nil {
 		resultch <- &Object{Err: err}
 		return
@@ -619,8 +1300,13 @@ func (s *S3) doDelete(ctx context.Context, chunk chunk, resultch chan *Object) {
 func (s *S3) MultiDelete(ctx context.Context, urlch <-chan *url.URL) <-chan *Object {
 	resultch := make(chan *Object)
 
+	concurrency := s.deleteConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultDeleteConcurrency
+	}
+
 	go func() {
-		sem := make(chan bool, 10)
+		sem := make(chan bool, concurrency)
 		defer close(sem)
 		defer close(resultch)
 
@@ -732,9 +1418,9 @@ func (sc *SessionCache) newSession(ctx context.Context, opts Options) (*session.
 		endpointURL = sentinelURL
 	}
 
-	var httpClient *http.Client
-	if opts.NoVerifySSL {
-		httpClient = insecureHTTPClient
+	httpClient, err := newHTTPClient(opts)
+	if err != nil {
+		return nil, err
 	}
 
 	awsCfg = awsCfg.
@@ -745,6 +1431,10 @@ func (sc *SessionCache) newSession(ctx context.Context, opts Options) (*session.
 
 	awsCfg.Retryer = newCustomRetryer(opts.MaxRetries)
 
+	if opts.AWSLogLevel != aws.LogOff {
+		awsCfg = awsCfg.WithLogLevel(opts.AWSLogLevel).WithLogger(awsLogger)
+	}
+
 	useSharedConfig := session.SharedConfigEnable
 	{
 		// Reverse of what the SDK does: if AWS_SDK_LOAD_CONFIG is 0 (or a
@@ -761,12 +1451,18 @@ func (sc *SessionCache) newSession(ctx context.Context, opts Options) (*session.
 		session.Options{
 			Config:            *awsCfg,
 			SharedConfigState: useSharedConfig,
+			Profile:           opts.profile,
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	sess.Handlers.Complete.PushBack(func(r *request.Request) {
+		stat.AddRequest(r.Operation.Name, r.Error != nil, r.RetryCount)
+		stat.AddLatency(requestType(r.Operation.Name), time.Since(r.Time))
+	})
+
 	// get region of the bucket and create session accordingly. if the region
 	// is not provided, it means we want region-independent session
 	// for operations such as listing buckets, making a new bucket etc.
@@ -817,6 +1513,27 @@ func setSessionRegion(ctx context.Context, sess *session.Session, bucket string)
 	return nil
 }
 
+// requestType returns the type of the S3 API operation, which latencies
+// are grouped by.
+func requestType(op string) string {
+	switch op {
+	case "GetObject":
+		return "get"
+	case "PutObject", "CreateMultipartUpload", "UploadPart", "CompleteMultipartUpload":
+		return "put"
+	case "CopyObject", "UploadPartCopy":
+		return "copy"
+	case "DeleteObject", "DeleteObjects":
+		return "delete"
+	case "ListObjects", "ListObjectsV2", "ListObjectVersions", "ListBuckets":
+		return "list"
+	case "HeadObject", "HeadBucket":
+		return "head"
+	default:
+		return "other"
+	}
+}
+
 // customRetryer wraps the SDK's built in DefaultRetryer adding additional
 // error codes. Such as, retry for S3 InternalError code.
 type customRetryer struct {
@@ -848,10 +1565,95 @@ func (c *customRetryer) ShouldRetry(req *request.Request) bool {
 	return shouldRetry
 }
 
-var insecureHTTPClient = &http.Client{
-	Transport: &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	},
+// newHTTPClient creates the HTTP client used by AWS sessions, configuring
+// its transport according to the given options.
+func newHTTPClient(opts Options) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.NoVerifySSL,
+	}
+
+	if opts.CABundle != "" {
+		pool, err := loadCABundle(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	setTransportOptions(transport, opts)
+
+	// proxy settings are read from the environment by default. Replace the
+	// proxy function only if any of the settings is overridden.
+	if opts.HTTPProxy != "" || opts.HTTPSProxy != "" || opts.NoProxy != "" {
+		proxy, err := newProxyConfig(opts).proxyFunc()
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxy
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// setTransportOptions overrides the connection settings of the transport with
+// the ones given in options.
+func setTransportOptions(transport *http.Transport, opts Options) {
+	if opts.ConnectTimeout != 0 || opts.KeepAlive != 0 || opts.DNSCacheTTL > 0 {
+		dialer := &net.Dialer{
+			Timeout:   defaultConnectTimeout,
+			KeepAlive: defaultKeepAlive,
+		}
+		if opts.ConnectTimeout != 0 {
+			dialer.Timeout = opts.ConnectTimeout
+		}
+		if opts.KeepAlive != 0 {
+			dialer.KeepAlive = opts.KeepAlive
+		}
+		transport.DialContext = dialer.DialContext
+
+		if opts.DNSCacheTTL > 0 {
+			transport.DialContext = newDNSCache(opts.DNSCacheTTL).dialContext(dialer.DialContext)
+		}
+	}
+
+	if opts.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		// all connections of a run are usually opened to the same host.
+		// Don't let the total limit close the idle connections of the
+		// host.
+		if transport.MaxIdleConns < opts.MaxIdleConnsPerHost {
+			transport.MaxIdleConns = opts.MaxIdleConnsPerHost
+		}
+	}
+
+	if opts.ReadTimeout != 0 {
+		transport.ResponseHeaderTimeout = opts.ReadTimeout
+	}
+
+	if opts.TLSHandshakeTimeout != 0 {
+		transport.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+
+	if opts.IdleConnTimeout != 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+}
+
+// loadCABundle reads the PEM encoded certificates from the given file and
+// returns a certificate pool which contains only these certificates.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load ca bundle: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("load ca bundle: no certificate found in %q", path)
+	}
+	return pool, nil
 }
 
 func supportsTransferAcceleration(endpoint urlpkg.URL) bool {
@@ -890,8 +1692,38 @@ func errHasCode(err error, code string) bool {
 
 }
 
+// IsPreconditionFailed reports whether given error is caused by a condition
+// of the request, e.g. the object already exists for an If-None-Match write.
+func IsPreconditionFailed(err error) bool {
+	return errHasCode(err, "PreconditionFailed")
+}
+
 // IsCancelationError reports whether given error is a storage related
 // cancelation error.
 func IsCancelationError(err error) bool {
 	return errHasCode(err, request.CanceledErrorCode)
 }
+
+// authErrorCodes are the error codes of the authentication and authorization
+// failures.
+var authErrorCodes = []string{
+	"AccessDenied",
+	"AccountProblem",
+	"AllAccessDisabled",
+	"ExpiredToken",
+	"InvalidAccessKeyId",
+	"InvalidToken",
+	"NoCredentialProviders",
+	"SignatureDoesNotMatch",
+}
+
+// IsAuthError reports whether given error is an authentication or an
+// authorization failure.
+func IsAuthError(err error) bool {
+	for _, code := range authErrorCodes {
+		if errHasCode(err, code) {
+			return true
+		}
+	}
+	return false
+}
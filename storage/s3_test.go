@@ -3,15 +3,19 @@ package storage
 import (
 	"bytes"
 	"context"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	urlpkg "net/url"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -26,6 +30,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/google/go-cmp/cmp"
 	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
 
 	"github.com/peak/s5cmd/log"
 	"github.com/peak/s5cmd/storage/url"
@@ -632,6 +637,7 @@ func TestS3PutEncryptionRequest(t *testing.T) {
 			})
 
 			mockS3 := &S3{
+				api:      mockApi,
 				uploader: s3manager.NewUploaderWithClient(mockApi),
 			}
 
@@ -646,6 +652,335 @@ func TestS3PutEncryptionRequest(t *testing.T) {
 	}
 }
 
+func TestS3PutSinglePartObject(t *testing.T) {
+	u, err := url.New("s3://bucket/key")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	const content = "this is a file content"
+
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	var operations []string
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+
+		operations = append(operations, r.Operation.Name)
+
+		assert.Equal(t, valueAtPath(r.Params, "ContentType"), "text/plain")
+		assert.Equal(t, valueAtPath(r.Params, "StorageClass"), "STANDARD_IA")
+
+		body, err := ioutil.ReadAll(valueAtPath(r.Params, "Body").(io.Reader))
+		assert.NilError(t, err)
+		assert.Equal(t, string(body), content)
+	})
+
+	// uploader is deliberately not set. objects smaller than the part size
+	// must be uploaded with a single PutObject request.
+	mockS3 := &S3{
+		api: mockApi,
+	}
+
+	metadata := NewMetadata().SetContentType("text/plain").SetStorageClass("STANDARD_IA")
+
+	err = mockS3.Put(context.Background(), strings.NewReader(content), u, metadata, 5, 5242880)
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, operations, []string{"PutObject"})
+}
+
+func TestS3PutIfNoneMatch(t *testing.T) {
+	u, err := url.New("s3://bucket/key")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	const partSize = 5242880
+
+	testcases := []struct {
+		name     string
+		size     int
+		expected map[string]string
+	}{
+		{
+			name: "single part",
+			size: 1,
+			expected: map[string]string{
+				"PutObject": "*",
+			},
+		},
+		{
+			name: "multipart",
+			size: partSize + 1,
+			expected: map[string]string{
+				"CreateMultipartUpload":   "",
+				"UploadPart":              "",
+				"CompleteMultipartUpload": "*",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockApi := s3.New(unit.Session)
+
+			mockApi.Handlers.Unmarshal.Clear()
+			mockApi.Handlers.UnmarshalMeta.Clear()
+			mockApi.Handlers.UnmarshalError.Clear()
+			mockApi.Handlers.Send.Clear()
+
+			var mu sync.Mutex
+			headers := map[string]string{}
+			mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+				// responses of CompleteMultipartUpload can not be empty.
+				body := ""
+				if r.Operation.Name == "CompleteMultipartUpload" {
+					body = "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>"
+				}
+				r.HTTPResponse = &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader(body)),
+				}
+
+				mu.Lock()
+				headers[r.Operation.Name] = r.HTTPRequest.Header.Get("If-None-Match")
+				mu.Unlock()
+			})
+			mockApi.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+				if output, ok := r.Data.(*s3.CreateMultipartUploadOutput); ok {
+					output.UploadId = aws.String("upload-id")
+				}
+			})
+
+			mockS3 := &S3{
+				api:      mockApi,
+				uploader: s3manager.NewUploaderWithClient(mockApi),
+			}
+
+			// the reader is not seekable to be uploaded in parts.
+			reader := ioutil.NopCloser(bytes.NewReader(make([]byte, tc.size)))
+			metadata := NewMetadata().SetIfNoneMatch("*")
+
+			err := mockS3.Put(context.Background(), reader, u, metadata, 1, partSize)
+			assert.NilError(t, err)
+
+			assert.DeepEqual(t, headers, tc.expected)
+		})
+	}
+}
+
+func TestS3MultiDeleteBatches(t *testing.T) {
+	testcases := []struct {
+		name      string
+		batchSize int
+		keys      []string
+		expected  []int
+	}{
+		{
+			name:     "default batch size",
+			keys:     manyKeys("bucket", 2500),
+			expected: []int{1000, 1000, 500},
+		},
+		{
+			name:      "custom batch size",
+			batchSize: 10,
+			keys:      manyKeys("bucket", 25),
+			expected:  []int{10, 10, 5},
+		},
+		{
+			name:      "batch size above the limit",
+			batchSize: 5000,
+			keys:      manyKeys("bucket", 1500),
+			expected:  []int{1000, 500},
+		},
+		{
+			name:      "batches of a bucket",
+			batchSize: 10,
+			keys:      append(manyKeys("bucket", 3), manyKeys("other-bucket", 2)...),
+			expected:  []int{3, 2},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mockApi := s3.New(unit.Session)
+
+			mockApi.Handlers.Unmarshal.Clear()
+			mockApi.Handlers.UnmarshalMeta.Clear()
+			mockApi.Handlers.UnmarshalError.Clear()
+			mockApi.Handlers.Send.Clear()
+
+			var (
+				mu      sync.Mutex
+				batches []int
+			)
+			mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+				r.HTTPResponse = &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}
+
+				input := r.Params.(*s3.DeleteObjectsInput)
+				keys := input.Delete.Objects
+				for _, key := range keys {
+					assert.Assert(t, strings.HasPrefix(aws.StringValue(key.Key), aws.StringValue(input.Bucket)))
+				}
+
+				mu.Lock()
+				batches = append(batches, len(keys))
+				mu.Unlock()
+			})
+
+			// batches are deleted one by one to keep their order.
+			mockS3 := &S3{
+				api:               mockApi,
+				deleteBatchSize:   tc.batchSize,
+				deleteConcurrency: 1,
+			}
+
+			urlch := make(chan *url.URL)
+			go func() {
+				defer close(urlch)
+				for _, key := range tc.keys {
+					u, err := url.New(key)
+					assert.NilError(t, err)
+					urlch <- u
+				}
+			}()
+
+			for range mockS3.MultiDelete(context.Background(), urlch) {
+			}
+
+			assert.DeepEqual(t, batches, tc.expected)
+		})
+	}
+}
+
+// manyKeys returns n object urls of the bucket. Keys are prefixed with the
+// bucket name.
+func manyKeys(bucket string, n int) []string {
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, fmt.Sprintf("s3://%v/%v-%v", bucket, bucket, i))
+	}
+	return keys
+}
+
+func TestS3GetPreallocatesFile(t *testing.T) {
+	u, err := url.New("s3://bucket/key")
+	assert.NilError(t, err)
+
+	const (
+		content  = "this is a file content which is downloaded in parts"
+		partSize = 10
+	)
+
+	file, err := ioutil.TempFile("", "s5cmd-preallocate")
+	assert.NilError(t, err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	var requests int
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		requests++
+
+		// the file must be preallocated before the rest of the parts are
+		// downloaded.
+		if requests > 1 {
+			fi, err := file.Stat()
+			assert.Check(t, err)
+			assert.Check(t, fi.Size() == int64(len(content)), "file is not preallocated, size: %v", fi.Size())
+		}
+
+		var start, end int
+		_, err := fmt.Sscanf(aws.StringValue(r.Params.(*s3.GetObjectInput).Range), "bytes=%d-%d", &start, &end)
+		assert.Check(t, err)
+		if end >= len(content) {
+			end = len(content) - 1
+		}
+
+		contentRange := fmt.Sprintf("bytes %d-%d/%d", start, end, len(content))
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusPartialContent,
+			Header:     http.Header{"Content-Range": []string{contentRange}},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+
+		output := r.Data.(*s3.GetObjectOutput)
+		output.ContentRange = aws.String(contentRange)
+		output.Body = ioutil.NopCloser(strings.NewReader(content[start : end+1]))
+	})
+
+	mockS3 := &S3{
+		api:        mockApi,
+		downloader: s3manager.NewDownloaderWithClient(mockApi),
+	}
+
+	size, err := mockS3.Get(context.Background(), u, file, 1, partSize)
+	assert.NilError(t, err)
+	assert.Equal(t, size, int64(len(content)))
+	assert.Assert(t, requests > 1, "expected the object to be downloaded in parts")
+
+	got, err := ioutil.ReadFile(file.Name())
+	assert.NilError(t, err)
+	assert.Equal(t, string(got), content)
+}
+
+func TestObjectSizeFromResponse(t *testing.T) {
+	testcases := []struct {
+		name     string
+		resp     *http.Response
+		expected int64
+	}{
+		{
+			name: "partial_content",
+			resp: &http.Response{
+				StatusCode: http.StatusPartialContent,
+				Header:     http.Header{"Content-Range": []string{"bytes 0-1023/146515"}},
+			},
+			expected: 146515,
+		},
+		{
+			name: "unknown_total_size",
+			resp: &http.Response{
+				StatusCode: http.StatusPartialContent,
+				Header:     http.Header{"Content-Range": []string{"bytes 0-1023/*"}},
+			},
+			expected: -1,
+		},
+		{
+			name:     "whole_object",
+			resp:     &http.Response{StatusCode: http.StatusOK, ContentLength: 42},
+			expected: 42,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, objectSizeFromResponse(tc.resp), tc.expected)
+		})
+	}
+}
+
 func TestS3listObjectsV2(t *testing.T) {
 	const (
 		numObjectsToReturn = 10100
@@ -714,7 +1049,7 @@ func TestS3listObjectsV2(t *testing.T) {
 		api: mockApi,
 	}
 
-	ouputCh := mockS3.listObjectsV2(context.Background(), u)
+	ouputCh := mockS3.listObjectsV2(context.Background(), u, u.Prefix)
 
 	for obj := range ouputCh {
 		if _, ok := mapReturnObjNameToModtime[obj.String()]; ok {
@@ -726,6 +1061,277 @@ func TestS3listObjectsV2(t *testing.T) {
 	assert.Equal(t, len(mapReturnObjNameToModtime), 0)
 }
 
+func TestS3ListAlternatives(t *testing.T) {
+	u, err := url.New("s3://bucket/logs/{2019,2020}-*/x/*.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := map[string][]string{
+		"logs/2019-": {"logs/2019-01/x/a.gz", "logs/2019-01/y/b.gz"},
+		"logs/2020-": {"logs/2020-02/x/c.gz"},
+	}
+
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	var prefixes []string
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		prefix := aws.StringValue(r.Params.(*s3.ListObjectsV2Input).Prefix)
+		prefixes = append(prefixes, prefix)
+
+		var contents []*s3.Object
+		for _, key := range keys[prefix] {
+			contents = append(contents, &s3.Object{
+				Key:          aws.String(key),
+				LastModified: aws.Time(time.Now().Add(-time.Minute)),
+			})
+		}
+
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+		r.Data = &s3.ListObjectsV2Output{Contents: contents}
+	})
+
+	mockS3 := &S3{api: mockApi}
+
+	var got []string
+	for obj := range mockS3.List(context.Background(), u, false) {
+		if obj.Err != nil {
+			t.Fatalf("unexpected error: %v", obj.Err)
+		}
+		got = append(got, obj.URL.Path)
+	}
+
+	// each alternative is listed with its own prefix.
+	assert.DeepEqual(t, prefixes, []string{"logs/2019-", "logs/2020-"})
+	assert.DeepEqual(t, got, []string{"logs/2019-01/x/a.gz", "logs/2020-02/x/c.gz"})
+}
+
+func TestS3ListSharded(t *testing.T) {
+	u, err := url.New("s3://bucket/logs/*.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	keys := []string{
+		"logs/a.gz",
+		"logs/b.txt",
+		"logs/2019/x.gz",
+		"logs/2020/y.gz",
+		"logs/2020/z.txt",
+	}
+
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	var (
+		mu       sync.Mutex
+		prefixes []string
+	)
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		input := r.Params.(*s3.ListObjectsV2Input)
+		prefix := aws.StringValue(input.Prefix)
+		delimiter := aws.StringValue(input.Delimiter)
+
+		mu.Lock()
+		prefixes = append(prefixes, prefix+delimiter)
+		mu.Unlock()
+
+		output := &s3.ListObjectsV2Output{}
+		seen := map[string]bool{}
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if delimiter != "" {
+				if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+					common := key[:len(prefix)+i+1]
+					if !seen[common] {
+						seen[common] = true
+						output.CommonPrefixes = append(output.CommonPrefixes, &s3.CommonPrefix{Prefix: aws.String(common)})
+					}
+					continue
+				}
+			}
+			output.Contents = append(output.Contents, &s3.Object{
+				Key:          aws.String(key),
+				LastModified: aws.Time(time.Now().Add(-time.Minute)),
+			})
+		}
+
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+		r.Data = output
+	})
+
+	mockS3 := &S3{api: mockApi, listShards: 2}
+
+	var got []string
+	for obj := range mockS3.List(context.Background(), u, false) {
+		if obj.Err != nil {
+			t.Fatalf("unexpected error: %v", obj.Err)
+		}
+		got = append(got, obj.URL.Path)
+	}
+	sort.Strings(got)
+	sort.Strings(prefixes)
+
+	// the prefix is listed with the delimiter to find the shards, and each
+	// shard is listed on its own.
+	assert.DeepEqual(t, prefixes, []string{"logs//", "logs/2019/", "logs/2020/"})
+	assert.DeepEqual(t, got, []string{"logs/2019/x.gz", "logs/2020/y.gz", "logs/a.gz"})
+}
+
+func TestS3ListShardedNoMatch(t *testing.T) {
+	u, err := url.New("s3://bucket/logs/*.gz")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+		r.Data = &s3.ListObjectsV2Output{}
+	})
+
+	mockS3 := &S3{api: mockApi, listShards: 4}
+
+	var errs []error
+	for obj := range mockS3.List(context.Background(), u, false) {
+		errs = append(errs, obj.Err)
+	}
+	if len(errs) != 1 || errs[0] != ErrNoObjectFound {
+		t.Fatalf("expected only %v, got %v", ErrNoObjectFound, errs)
+	}
+}
+
+func TestS3StatCached(t *testing.T) {
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	var heads int
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		heads++
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+		r.Data.(*s3.HeadObjectOutput).ContentLength = aws.Int64(5)
+	})
+
+	mockS3 := &S3{api: mockApi, statCache: newStatCache()}
+	mockS3.statCache.enable()
+
+	for _, path := range []string{"a.txt", "a.txt", "b.txt"} {
+		u, err := url.New("s3://bucket/" + path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		obj, err := mockS3.Stat(context.Background(), u)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, obj.URL, u)
+		assert.Equal(t, obj.Size, int64(5))
+	}
+
+	// each object is retrieved once.
+	assert.Equal(t, heads, 2)
+}
+
+func TestS3Compose(t *testing.T) {
+	mockApi := s3.New(unit.Session)
+
+	mockApi.Handlers.Unmarshal.Clear()
+	mockApi.Handlers.UnmarshalMeta.Clear()
+	mockApi.Handlers.UnmarshalError.Clear()
+	mockApi.Handlers.Send.Clear()
+
+	var requests []string
+	mockApi.Handlers.Send.PushBack(func(r *request.Request) {
+		r.HTTPResponse = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+
+		switch data := r.Data.(type) {
+		case *s3.CreateMultipartUploadOutput:
+			data.UploadId = aws.String("upload")
+			requests = append(requests, r.Operation.Name)
+		case *s3.UploadPartCopyOutput:
+			data.CopyPartResult = &s3.CopyPartResult{ETag: aws.String("etag-1")}
+			requests = append(requests, fmt.Sprintf("%v %v %v", r.Operation.Name,
+				valueAtPath(r.Params, "CopySource"), valueAtPath(r.Params, "CopySourceRange")))
+		case *s3.GetObjectOutput:
+			data.Body = ioutil.NopCloser(strings.NewReader("z"))
+			requests = append(requests, fmt.Sprintf("%v %v %v", r.Operation.Name,
+				valueAtPath(r.Params, "Key"), valueAtPath(r.Params, "Range")))
+		case *s3.UploadPartOutput:
+			data.ETag = aws.String("etag-2")
+			requests = append(requests, fmt.Sprintf("%v %v", r.Operation.Name, valueAtPath(r.Params, "PartNumber")))
+		case *s3.CompleteMultipartUploadOutput:
+			parts := r.Params.(*s3.CompleteMultipartUploadInput).MultipartUpload.Parts
+			requests = append(requests, fmt.Sprintf("%v %v %v", r.Operation.Name,
+				aws.StringValue(parts[0].ETag), aws.StringValue(parts[1].ETag)))
+		}
+	})
+	mockApi.Handlers.Unmarshal.PushBack(func(r *request.Request) {
+		if awsErr, ok := r.Error.(awserr.Error); ok && awsErr.Code() == request.ErrCodeSerialization {
+			r.Error = nil
+		}
+	})
+
+	object := func(key string, size int64) *Object {
+		u, err := url.New("s3://bucket/" + key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return &Object{URL: u, Size: size}
+	}
+	objects := []*Object{object("large", composeMinPartSize+1), object("small", 1)}
+	to := object("merged", 0).URL
+
+	mockS3 := &S3{api: mockApi}
+	err := mockS3.Compose(context.Background(), objects, to, NewMetadata(), 1)
+	assert.NilError(t, err)
+
+	// the large object is copied on the server side, the small one is read.
+	assert.DeepEqual(t, requests, []string{
+		"CreateMultipartUpload",
+		fmt.Sprintf("UploadPartCopy bucket/large bytes=0-%d", composeMinPartSize),
+		"GetObject small bytes=0-0",
+		"UploadPart 2",
+		"CompleteMultipartUpload etag-1 etag-2",
+	})
+}
+
 func TestSessionCreateAndCachingWithDifferentBuckets(t *testing.T) {
 	log.Init("error", false)
 	testcases := []struct {
@@ -860,6 +1466,129 @@ func TestSessionAutoRegion(t *testing.T) {
 	}
 }
 
+func TestNewHTTPClientWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	certificate := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+
+	testdir := fs.NewDir(t, "ca-bundle",
+		fs.WithFile("bundle.pem", string(certificate)),
+		fs.WithFile("invalid.pem", "not a certificate"),
+	)
+	defer testdir.Remove()
+
+	testcases := []struct {
+		name              string
+		caBundle          string
+		expectedClientErr bool
+		expectedGetErr    bool
+	}{
+		{
+			name:           "unknown_authority_without_ca_bundle",
+			expectedGetErr: true,
+		},
+		{
+			name:     "known_authority_with_ca_bundle",
+			caBundle: testdir.Join("bundle.pem"),
+		},
+		{
+			name:              "invalid_ca_bundle",
+			caBundle:          testdir.Join("invalid.pem"),
+			expectedClientErr: true,
+		},
+		{
+			name:              "missing_ca_bundle",
+			caBundle:          testdir.Join("missing.pem"),
+			expectedClientErr: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := newHTTPClient(Options{CABundle: tc.caBundle})
+			if tc.expectedClientErr {
+				assert.Assert(t, err != nil, "expected an error for ca bundle %q", tc.caBundle)
+				return
+			}
+			assert.NilError(t, err)
+
+			resp, err := client.Get(server.URL)
+			if tc.expectedGetErr {
+				assert.Assert(t, err != nil, "expected a certificate verification error")
+				return
+			}
+			assert.NilError(t, err)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestNewHTTPClientTransportOptions(t *testing.T) {
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+
+	testcases := []struct {
+		name     string
+		opts     Options
+		validate func(t *testing.T, transport *http.Transport)
+	}{
+		{
+			name: "defaults",
+			opts: Options{},
+			validate: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, transport.MaxIdleConns, defaultTransport.MaxIdleConns)
+				assert.Equal(t, transport.MaxIdleConnsPerHost, defaultTransport.MaxIdleConnsPerHost)
+				assert.Equal(t, transport.ResponseHeaderTimeout, defaultTransport.ResponseHeaderTimeout)
+				assert.Equal(t, transport.TLSHandshakeTimeout, defaultTransport.TLSHandshakeTimeout)
+				assert.Equal(t, transport.IdleConnTimeout, defaultTransport.IdleConnTimeout)
+			},
+		},
+		{
+			name: "max_idle_conns_per_host_above_total_limit",
+			opts: Options{MaxIdleConnsPerHost: 256},
+			validate: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, transport.MaxIdleConnsPerHost, 256)
+				assert.Equal(t, transport.MaxIdleConns, 256)
+			},
+		},
+		{
+			name: "max_idle_conns_per_host_below_total_limit",
+			opts: Options{MaxIdleConnsPerHost: 8},
+			validate: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, transport.MaxIdleConnsPerHost, 8)
+				assert.Equal(t, transport.MaxIdleConns, defaultTransport.MaxIdleConns)
+			},
+		},
+		{
+			name: "timeouts",
+			opts: Options{
+				ReadTimeout:         time.Minute,
+				TLSHandshakeTimeout: 5 * time.Second,
+				IdleConnTimeout:     time.Second,
+			},
+			validate: func(t *testing.T, transport *http.Transport) {
+				assert.Equal(t, transport.ResponseHeaderTimeout, time.Minute)
+				assert.Equal(t, transport.TLSHandshakeTimeout, 5*time.Second)
+				assert.Equal(t, transport.IdleConnTimeout, time.Second)
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := newHTTPClient(tc.opts)
+			assert.NilError(t, err)
+
+			tc.validate(t, client.Transport.(*http.Transport))
+		})
+	}
+}
+
 func valueAtPath(i interface{}, s string) interface{} {
 	v, err := awsutil.ValuesAtPath(i, s)
 	if err != nil || len(v) == 0 {
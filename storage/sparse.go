@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// sparseBlockSize is the granularity of detecting zero runs. It matches the
+// block size of most filesystems, smaller holes are not worth creating.
+const sparseBlockSize = 4096
+
+// SparseWriter is an io.WriterAt which skips writing blocks of zeros to the
+// file. The file should be newly created or truncated, so that skipped blocks
+// are left as holes which read back as zeros. The file must be truncated to
+// its final size after all writes, to keep trailing holes.
+type SparseWriter struct {
+	file *os.File
+}
+
+// NewSparseWriter creates a new SparseWriter for the given file.
+func NewSparseWriter(file *os.File) *SparseWriter {
+	return &SparseWriter{file: file}
+}
+
+// WriteAt implements io.WriterAt. Only the blocks containing non-zero bytes
+// are written to the file. Blocks are aligned to the file offset, so that
+// the holes are aligned to the filesystem blocks.
+func (w *SparseWriter) WriteAt(p []byte, off int64) (int, error) {
+	var (
+		start = -1
+		i     = 0
+	)
+
+	for i < len(p) {
+		// end of the block containing p[i]
+		end := i + sparseBlockSize - int((off+int64(i))%sparseBlockSize)
+		if end > len(p) {
+			end = len(p)
+		}
+
+		if isZero(p[i:end]) {
+			if start >= 0 {
+				if _, err := w.file.WriteAt(p[start:i], off+int64(start)); err != nil {
+					return start, err
+				}
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+		i = end
+	}
+
+	if start >= 0 {
+		if _, err := w.file.WriteAt(p[start:], off+int64(start)); err != nil {
+			return start, err
+		}
+	}
+	return len(p), nil
+}
+
+func isZero(p []byte) bool {
+	for _, b := range p {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// extent is a region of a file which contains data.
+type extent struct {
+	offset int64
+	length int64
+}
+
+func (e extent) end() int64 { return e.offset + e.length }
+
+// SparseReader reads a sparse file without reading its holes from the disk.
+// Holes are filled with zeros in memory. It implements io.ReaderAt and
+// io.Seeker, so the file is uploaded without buffering.
+type SparseReader struct {
+	file    *os.File
+	size    int64
+	offset  int64
+	extents []extent
+}
+
+// NewSparseReader creates a new SparseReader for the given file. The data
+// regions of the file are discovered once, the file should not be modified
+// while it is being read.
+func NewSparseReader(file *os.File) (*SparseReader, error) {
+	fi, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	extents, err := dataExtents(file, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SparseReader{
+		file:    file,
+		size:    fi.Size(),
+		extents: extents,
+	}, nil
+}
+
+// Read implements io.Reader.
+func (r *SparseReader) Read(p []byte) (int, error) {
+	n, err := r.ReadAt(p, r.offset)
+	r.offset += int64(n)
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *SparseReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= r.size {
+		return 0, io.EOF
+	}
+
+	var err error
+	if remaining := r.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		err = io.EOF
+	}
+
+	// first extent which ends after the offset
+	i := sort.Search(len(r.extents), func(i int) bool {
+		return r.extents[i].end() > off
+	})
+
+	pos := off
+	end := off + int64(len(p))
+	for pos < end {
+		if i >= len(r.extents) || r.extents[i].offset >= end {
+			zero(p[pos-off:])
+			break
+		}
+
+		e := r.extents[i]
+		if pos < e.offset {
+			zero(p[pos-off : e.offset-off])
+			pos = e.offset
+		}
+
+		dataEnd := e.end()
+		if dataEnd > end {
+			dataEnd = end
+		}
+		if _, rerr := r.file.ReadAt(p[pos-off:dataEnd-off], pos); rerr != nil && rerr != io.EOF {
+			return int(pos - off), rerr
+		}
+		pos = dataEnd
+		i++
+	}
+
+	return len(p), err
+}
+
+// Seek implements io.Seeker.
+func (r *SparseReader) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = r.offset + offset
+	case io.SeekEnd:
+		abs = r.size + offset
+	default:
+		return 0, fmt.Errorf("invalid whence %v", whence)
+	}
+
+	if abs < 0 {
+		return 0, fmt.Errorf("negative position %v", abs)
+	}
+	r.offset = abs
+	return abs, nil
+}
+
+func zero(p []byte) {
+	for i := range p {
+		p[i] = 0
+	}
+}
@@ -0,0 +1,58 @@
+// +build linux
+
+package storage
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// whence values of lseek(2) which are not defined in the syscall package.
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+// dataExtents returns the regions of the file containing data, using
+// SEEK_DATA and SEEK_HOLE. Filesystems that don't support them report the
+// whole file as data.
+func dataExtents(file *os.File, size int64) ([]extent, error) {
+	fd := int(file.Fd())
+
+	var (
+		extents []extent
+		offset  int64
+	)
+	for offset < size {
+		start, err := syscall.Seek(fd, offset, seekData)
+		if err == syscall.ENXIO {
+			// no data after offset
+			break
+		}
+		if err == syscall.EINVAL {
+			// SEEK_DATA is not supported
+			return []extent{{offset: 0, length: size}}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := syscall.Seek(fd, start, seekHole)
+		if err != nil {
+			return nil, err
+		}
+		if end > size {
+			end = size
+		}
+
+		extents = append(extents, extent{offset: start, length: end - start})
+		offset = end
+	}
+
+	// restore the file offset
+	if _, err := syscall.Seek(fd, 0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return extents, nil
+}
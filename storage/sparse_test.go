@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+// sparseContent returns a buffer with data at the start and in the middle,
+// and zeros everywhere else.
+func sparseContent() []byte {
+	content := make([]byte, 8*sparseBlockSize)
+	copy(content, "data at the start")
+	copy(content[3*sparseBlockSize+100:], "data in the middle crossing the block boundary")
+	return content
+}
+
+func TestSparseWriter(t *testing.T) {
+	testdir := fs.NewDir(t, "sparse")
+	defer testdir.Remove()
+
+	content := sparseContent()
+
+	file, err := os.Create(testdir.Join("file"))
+	assert.NilError(t, err)
+	defer file.Close()
+
+	w := NewSparseWriter(file)
+
+	// write in unaligned parts, like a downloader with an odd part size.
+	const partSize = 3000
+	for off := 0; off < len(content); off += partSize {
+		end := off + partSize
+		if end > len(content) {
+			end = len(content)
+		}
+		n, err := w.WriteAt(content[off:end], int64(off))
+		assert.NilError(t, err)
+		assert.Equal(t, n, end-off)
+	}
+	assert.NilError(t, file.Truncate(int64(len(content))))
+
+	got, err := ioutil.ReadFile(testdir.Join("file"))
+	assert.NilError(t, err)
+	assert.Assert(t, bytes.Equal(got, content))
+}
+
+func TestSparseReader(t *testing.T) {
+	testdir := fs.NewDir(t, "sparse")
+	defer testdir.Remove()
+
+	content := sparseContent()
+
+	file, err := os.Create(testdir.Join("file"))
+	assert.NilError(t, err)
+	defer file.Close()
+
+	_, err = NewSparseWriter(file).WriteAt(content, 0)
+	assert.NilError(t, err)
+	assert.NilError(t, file.Truncate(int64(len(content))))
+
+	r, err := NewSparseReader(file)
+	assert.NilError(t, err)
+
+	got, err := ioutil.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Assert(t, bytes.Equal(got, content))
+
+	// read a section starting in a hole and ending in data.
+	section := make([]byte, 2*sparseBlockSize)
+	off := int64(2*sparseBlockSize + 50)
+	n, err := r.ReadAt(section, off)
+	assert.NilError(t, err)
+	assert.Equal(t, n, len(section))
+	assert.Assert(t, bytes.Equal(section, content[off:off+int64(len(section))]))
+
+	// read past the end.
+	_, err = r.Seek(-10, io.SeekEnd)
+	assert.NilError(t, err)
+	tail, err := ioutil.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Equal(t, len(tail), 10)
+}
+
+func TestSparseReaderEmptyFile(t *testing.T) {
+	testdir := fs.NewDir(t, "sparse", fs.WithFile("file", ""))
+	defer testdir.Remove()
+
+	file, err := os.Open(testdir.Join("file"))
+	assert.NilError(t, err)
+	defer file.Close()
+
+	r, err := NewSparseReader(file)
+	assert.NilError(t, err)
+
+	got, err := ioutil.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), 0)
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"strings"
+	"sync"
+)
+
+// globalStatCache keeps the metadata of S3 objects for the lifetime of the
+// run, if the metadata are cached.
+var globalStatCache = newStatCache()
+
+// statCacheKey identifies an object as it is seen by a client. The clients
+// with different profiles, regions or endpoints don't share the metadata of
+// the objects.
+type statCacheKey struct {
+	profile  string
+	region   string
+	endpoint string
+	bucket   string
+	key      string
+}
+
+// bucketKey returns the key of the bucket of the object.
+func (k statCacheKey) bucketKey() statCacheKey {
+	k.key = ""
+	return k
+}
+
+// objectKey returns the key of the object, whichever client sees it.
+func (k statCacheKey) objectKey() statObjectKey {
+	return statObjectKey{bucket: k.bucket, key: k.key}
+}
+
+// statObjectKey identifies an object for all of the clients. The changes made
+// by a client are seen by the others.
+type statObjectKey struct {
+	bucket string
+	key    string
+}
+
+// statEntry is the cached metadata of an object.
+type statEntry struct {
+	object   *Object
+	notFound bool
+
+	// version is the version the retrieval of the object is started at.
+	version int64
+}
+
+// statCache keeps the metadata of the objects retrieved by HEAD requests or
+// listed in bulk, so that each object is retrieved once. It is safe for
+// concurrent use.
+type statCache struct {
+	mu      sync.Mutex
+	entries map[statCacheKey]statEntry
+
+	// listed has the prefixes, indexed by their buckets, whose all objects
+	// are in the cache with the versions their listings are started at. The
+	// keys under them which are not cached don't exist.
+	listed map[statCacheKey]map[string]int64
+
+	// changed has the versions the objects are changed at. The entries
+	// retrieved before are retrieved again.
+	changed map[statObjectKey]int64
+
+	// version is incremented as the objects are changed, so the results of
+	// the retrievals racing with the changes are not kept.
+	version int64
+
+	// enabled is set once a client caches the metadata. The changes made
+	// before are not recorded.
+	enabled bool
+}
+
+func newStatCache() *statCache {
+	return &statCache{
+		entries: map[statCacheKey]statEntry{},
+		listed:  map[statCacheKey]map[string]int64{},
+		changed: map[statObjectKey]int64{},
+	}
+}
+
+// get returns the cached entry of the object. ok is false if the object is
+// not cached or it is changed.
+func (c *statCache) get(key statCacheKey) (entry statEntry, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	changed := c.changed[key.objectKey()]
+	if entry, found := c.entries[key]; found && entry.version >= changed {
+		return entry, true
+	}
+
+	for prefix, version := range c.listed[key.bucketKey()] {
+		if version >= changed && strings.HasPrefix(key.key, prefix) {
+			return statEntry{notFound: true, version: version}, true
+		}
+	}
+	return statEntry{}, false
+}
+
+// put caches the result of the retrieval of the object started at version.
+// Only the found objects and the not found errors are kept.
+func (c *statCache) put(key statCacheKey, version int64, obj *Object, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.changed[key.objectKey()] > version {
+		return
+	}
+
+	switch {
+	case err == nil && obj != nil:
+		c.entries[key] = statEntry{object: obj, version: version}
+	case err == ErrGivenObjectNotFound:
+		c.entries[key] = statEntry{notFound: true, version: version}
+	}
+}
+
+// putListed caches the objects listed with the prefix. The listing is
+// started at version.
+func (c *statCache) putListed(prefix statCacheKey, version int64, objects []*Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the objects changed after the listing started are retrieved again.
+	for _, obj := range objects {
+		key := prefix
+		key.key = obj.URL.Path
+		if c.changed[key.objectKey()] > version {
+			continue
+		}
+		c.entries[key] = statEntry{object: obj, version: version}
+	}
+
+	bucket := prefix.bucketKey()
+	if c.listed[bucket] == nil {
+		c.listed[bucket] = map[string]int64{}
+	}
+	c.listed[bucket][prefix.key] = version
+}
+
+// enable starts recording the changes of the objects.
+func (c *statCache) enable() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = true
+}
+
+// currentVersion returns the version to start a retrieval at.
+func (c *statCache) currentVersion() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+// invalidate marks the object of the key as changed for all of the clients.
+func (c *statCache) invalidate(key statCacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.enabled {
+		return
+	}
+
+	c.version++
+	c.changed[key.objectKey()] = c.version
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/peak/s5cmd/storage/url"
+)
+
+func TestStatCache(t *testing.T) {
+	c := newStatCache()
+	c.enable()
+
+	key := func(k string) statCacheKey {
+		return statCacheKey{bucket: "bucket", key: k}
+	}
+	object := func(k string) *Object {
+		u, err := url.New("s3://bucket/" + k)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return &Object{URL: u, Size: 5}
+	}
+
+	_, ok := c.get(key("a"))
+	assert.Assert(t, !ok)
+
+	c.put(key("a"), c.currentVersion(), object("a"), nil)
+	c.put(key("b"), c.currentVersion(), nil, ErrGivenObjectNotFound)
+	c.put(key("c"), c.currentVersion(), nil, errors.New("access denied"))
+
+	entry, ok := c.get(key("a"))
+	assert.Assert(t, ok)
+	assert.Equal(t, entry.object.Size, int64(5))
+
+	entry, ok = c.get(key("b"))
+	assert.Assert(t, ok && entry.notFound)
+
+	// other errors are not kept.
+	_, ok = c.get(key("c"))
+	assert.Assert(t, !ok)
+
+	// the objects changed during the retrieval are not kept.
+	version := c.currentVersion()
+	c.invalidate(key("a"))
+	c.put(key("a"), version, object("a"), nil)
+	_, ok = c.get(key("a"))
+	assert.Assert(t, !ok)
+}
+
+func TestStatCacheListed(t *testing.T) {
+	c := newStatCache()
+	c.enable()
+
+	key := func(k string) statCacheKey {
+		return statCacheKey{bucket: "bucket", key: k}
+	}
+	u, err := url.New("s3://bucket/prefix/a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	version := c.currentVersion()
+	c.invalidate(key("prefix/b"))
+	c.putListed(key("prefix/"), version, []*Object{{URL: u}})
+
+	_, ok := c.get(key("prefix/a"))
+	assert.Assert(t, ok)
+
+	// the keys under the prefix which are not listed don't exist.
+	entry, ok := c.get(key("prefix/c"))
+	assert.Assert(t, ok && entry.notFound)
+
+	// the keys changed during the listing are retrieved again.
+	_, ok = c.get(key("prefix/b"))
+	assert.Assert(t, !ok)
+
+	_, ok = c.get(key("other/c"))
+	assert.Assert(t, !ok)
+	_, ok = c.get(statCacheKey{bucket: "other", key: "prefix/c"})
+	assert.Assert(t, !ok)
+}
+
+func TestStatCacheClients(t *testing.T) {
+	c := newStatCache()
+	c.enable()
+
+	key := func(profile, endpoint, k string) statCacheKey {
+		return statCacheKey{profile: profile, endpoint: endpoint, bucket: "bucket", key: k}
+	}
+	u, err := url.New("s3://bucket/prefix/a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.put(key("default", "", "a"), c.currentVersion(), &Object{URL: u, Size: 5}, nil)
+	c.putListed(key("default", "", "prefix/"), c.currentVersion(), []*Object{{URL: u}})
+
+	// the clients with other profiles or endpoints don't share the entries.
+	_, ok := c.get(key("other", "", "a"))
+	assert.Assert(t, !ok)
+	_, ok = c.get(key("default", "http://localhost:9000", "a"))
+	assert.Assert(t, !ok)
+	_, ok = c.get(key("other", "", "prefix/c"))
+	assert.Assert(t, !ok)
+
+	// the changes made by a client are seen by the others.
+	c.invalidate(key("other", "", "a"))
+	_, ok = c.get(key("default", "", "a"))
+	assert.Assert(t, !ok)
+
+	c.invalidate(key("other", "", "prefix/c"))
+	_, ok = c.get(key("default", "", "prefix/c"))
+	assert.Assert(t, !ok)
+	entry, ok := c.get(key("default", "", "prefix/d"))
+	assert.Assert(t, ok && entry.notFound)
+}
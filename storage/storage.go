@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
+
 	"github.com/peak/s5cmd/storage/url"
 	"github.com/peak/s5cmd/strutil"
 )
@@ -43,18 +47,46 @@ type Storage interface {
 }
 
 func NewLocalClient(opts Options) *Filesystem {
-	return &Filesystem{dryRun: opts.DryRun}
+	return &Filesystem{
+		dryRun:           opts.DryRun,
+		preserveSymlinks: opts.PreserveSymlinks,
+		keepEmptyDirs:    opts.KeepEmptyDirs,
+	}
 }
 
 func NewRemoteClient(ctx context.Context, url *url.URL, opts Options) (*S3, error) {
 	newOpts := Options{
-		MaxRetries:  opts.MaxRetries,
-		Endpoint:    opts.Endpoint,
-		NoVerifySSL: opts.NoVerifySSL,
-		DryRun:      opts.DryRun,
+		MaxRetries:    opts.MaxRetries,
+		Endpoint:      opts.Endpoint,
+		NoVerifySSL:   opts.NoVerifySSL,
+		CABundle:      opts.CABundle,
+		HTTPProxy:     opts.HTTPProxy,
+		HTTPSProxy:    opts.HTTPSProxy,
+		NoProxy:       opts.NoProxy,
+		DryRun:        opts.DryRun,
 		NoSignRequest: opts.NoSignRequest,
-		bucket:      url.Bucket,
-		region:      opts.region,
+		bucket:        url.Bucket,
+		region:        opts.region,
+		profile:       opts.profile,
+
+		MaxIdleConnsPerHost: opts.MaxIdleConnsPerHost,
+		ConnectTimeout:      opts.ConnectTimeout,
+		ReadTimeout:         opts.ReadTimeout,
+		TLSHandshakeTimeout: opts.TLSHandshakeTimeout,
+		KeepAlive:           opts.KeepAlive,
+		IdleConnTimeout:     opts.IdleConnTimeout,
+		DNSCacheTTL:         opts.DNSCacheTTL,
+
+		DeleteBatchSize:   opts.DeleteBatchSize,
+		DeleteConcurrency: opts.DeleteConcurrency,
+
+		ListShards:     opts.ListShards,
+		ListMaxKeys:    opts.ListMaxKeys,
+		ListStartAfter: opts.ListStartAfter,
+		CacheListings:  opts.CacheListings,
+		CacheStats:     opts.CacheStats,
+
+		AWSLogLevel: opts.AWSLogLevel,
 	}
 	return newS3Storage(ctx, newOpts)
 }
@@ -63,24 +95,84 @@ func NewClient(ctx context.Context, url *url.URL, opts Options) (Storage, error)
 	if url.IsRemote() {
 		return NewRemoteClient(ctx, url, opts)
 	}
+	if url.IsHTTP() {
+		return NewHTTPStorage(opts)
+	}
 	return NewLocalClient(opts), nil
 }
 
 // Options stores configuration for storage.
 type Options struct {
-	MaxRetries  int
-	Endpoint    string
-	NoVerifySSL bool
-	DryRun      bool
+	MaxRetries    int
+	Endpoint      string
+	NoVerifySSL   bool
+	CABundle      string
+	HTTPProxy     string
+	HTTPSProxy    string
+	NoProxy       string
+	DryRun        bool
 	NoSignRequest bool
-	bucket      string
-	region      string
+	bucket        string
+	region        string
+	profile       string
+
+	// HTTP transport settings. Zero values keep the defaults of the
+	// transport.
+	MaxIdleConnsPerHost int
+	ConnectTimeout      time.Duration
+	ReadTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+	KeepAlive           time.Duration
+	IdleConnTimeout     time.Duration
+	DNSCacheTTL         time.Duration
+
+	// PreserveSymlinks makes the local storage list symbolic links as
+	// links, instead of following or skipping them.
+	PreserveSymlinks bool
+
+	// KeepEmptyDirs makes the local storage list empty directories, so that
+	// they can be created on the destination.
+	KeepEmptyDirs bool
+
+	// DeleteBatchSize is the number of keys deleted with a single request,
+	// up to 1000. DeleteConcurrency is the number of concurrent delete
+	// requests. Zero values keep the defaults.
+	DeleteBatchSize   int
+	DeleteConcurrency int
+
+	// ListShards is the number of concurrent paginations of the wildcard
+	// listings of S3. Zero value doesn't shard the listings.
+	ListShards int
+
+	// ListMaxKeys is the number of keys of a listing page of S3, up to 1000.
+	// ListStartAfter is the key after which the listings of S3 start. Zero
+	// values keep the defaults.
+	ListMaxKeys    int64
+	ListStartAfter string
+
+	// CacheListings makes the listings of S3 kept for the run, so that the
+	// commands expanding the same prefix list it once.
+	CacheListings bool
+
+	// CacheStats makes the metadata of S3 objects kept for the run, so that
+	// the metadata of an object is retrieved once.
+	CacheStats bool
+
+	// AWSLogLevel is the log level of the AWS SDK, whose logs are printed as
+	// the debug logs.
+	AWSLogLevel aws.LogLevelType
 }
 
 func (o *Options) SetRegion(region string) {
 	o.region = region
 }
 
+// SetProfile sets the shared config profile which is used to load
+// credentials and settings of the session.
+func (o *Options) SetProfile(profile string) {
+	o.profile = profile
+}
+
 // Object is a generic type which contains metadata for storage items.
 type Object struct {
 	URL          *url.URL     `json:"key,omitempty"`
@@ -89,7 +181,22 @@ type Object struct {
 	Type         ObjectType   `json:"type,omitempty"`
 	Size         int64        `json:"size,omitempty"`
 	StorageClass StorageClass `json:"storage_class,omitempty"`
+	VersionID    string       `json:"version_id,omitempty"`
 	Err          error        `json:"error,omitempty"`
+
+	// UserMetadata is the user-defined metadata of the object with
+	// lowercase keys. It is only retrieved by Stat.
+	UserMetadata map[string]string `json:"metadata,omitempty"`
+
+	// Content and encryption settings of the object. They are only
+	// retrieved by Stat.
+	ContentType  string `json:"content_type,omitempty"`
+	CacheControl string `json:"cache_control,omitempty"`
+	SSE          string `json:"sse,omitempty"`
+	SSEKeyID     string `json:"sse_kms_key_id,omitempty"`
+
+	// Tags are the tags of the object. They are only retrieved by Tags.
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // String returns the string representation of Object.
@@ -143,7 +250,7 @@ func ShouldProcessUrl(url *url.URL, followSymlinks bool) bool {
 		return true
 	}
 
-	if url.IsRemote() {
+	if url.IsRemote() || url.IsHTTP() {
 		return true
 	}
 	fi, err := os.Lstat(url.Absolute())
@@ -226,6 +333,15 @@ func (m Metadata) SetContentType(contentType string) Metadata {
 	return m
 }
 
+func (m Metadata) CacheControl() string {
+	return m["CacheControl"]
+}
+
+func (m Metadata) SetCacheControl(cacheControl string) Metadata {
+	m["CacheControl"] = cacheControl
+	return m
+}
+
 func (m Metadata) SSE() string {
 	return m["EncryptionMethod"]
 }
@@ -243,3 +359,108 @@ func (m Metadata) SetSSEKeyID(kid string) Metadata {
 	m["EncryptionKeyID"] = kid
 	return m
 }
+
+// IfNoneMatch returns the condition of the write, e.g. "*" to write only if
+// the object doesn't exist.
+func (m Metadata) IfNoneMatch() string {
+	return m["IfNoneMatch"]
+}
+
+func (m Metadata) SetIfNoneMatch(etag string) Metadata {
+	m["IfNoneMatch"] = etag
+	return m
+}
+
+// UserDefined returns the user-defined metadata, which is stored with the
+// x-amz-meta- prefix on S3.
+func (m Metadata) UserDefined() map[string]string {
+	userDefined := map[string]string{}
+	for key, value := range m {
+		if strings.HasPrefix(key, userMetadataPrefix) {
+			userDefined[strings.TrimPrefix(key, userMetadataPrefix)] = value
+		}
+	}
+	return userDefined
+}
+
+func (m Metadata) SetUserDefined(key, value string) Metadata {
+	m[userMetadataPrefix+strings.ToLower(key)] = value
+	return m
+}
+
+// directives of the metadata of the copies.
+const (
+	MetadataDirectiveCopy    = "COPY"
+	MetadataDirectiveReplace = "REPLACE"
+)
+
+// MetadataDirective returns the directive of the metadata of a copy, i.e.
+// REPLACE to set the metadata instead of copying the ones of the source.
+func (m Metadata) MetadataDirective() string {
+	return m["MetadataDirective"]
+}
+
+func (m Metadata) SetMetadataDirective(directive string) Metadata {
+	m["MetadataDirective"] = directive
+	return m
+}
+
+// Tags returns the tags of the object. The tags of the source of a copy are
+// replaced if any is set.
+func (m Metadata) Tags() map[string]string {
+	tags := map[string]string{}
+	for key, value := range m {
+		if strings.HasPrefix(key, tagPrefix) {
+			tags[strings.TrimPrefix(key, tagPrefix)] = value
+		}
+	}
+	return tags
+}
+
+func (m Metadata) SetTag(key, value string) Metadata {
+	m[tagPrefix+key] = value
+	return m
+}
+
+// userMetadataPrefix separates the keys of user-defined metadata from the
+// keys of system metadata.
+const userMetadataPrefix = "UserDefined-"
+
+// tagPrefix separates the keys of the tags from the other keys.
+const tagPrefix = "Tag-"
+
+// MetadataSymlinkTarget is the user-defined metadata key to store the target
+// of uploaded symbolic links.
+const MetadataSymlinkTarget = "symlink-target"
+
+// MetadataModTime is the user-defined metadata key to store the
+// modification time of uploaded files. It is compatible with rclone.
+const MetadataModTime = "mtime"
+
+// FormatModTime formats the modification time as seconds since the Unix
+// epoch, with nanosecond precision.
+func FormatModTime(t time.Time) string {
+	return fmt.Sprintf("%d.%09d", t.Unix(), t.Nanosecond())
+}
+
+// ParseModTime parses the modification time formatted by FormatModTime. The
+// fractional part is optional.
+func ParseModTime(s string) (time.Time, error) {
+	parts := strings.SplitN(s, ".", 2)
+
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid modification time %q", s)
+	}
+
+	var nsec int64
+	if len(parts) == 2 {
+		// right pad the fraction to nanoseconds
+		fraction := (parts[1] + "000000000")[:9]
+		nsec, err = strconv.ParseInt(fraction, 10, 64)
+		if err != nil || nsec < 0 {
+			return time.Time{}, fmt.Errorf("invalid modification time %q", s)
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
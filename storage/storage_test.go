@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseModTime(t *testing.T) {
+	testcases := []struct {
+		value       string
+		expected    time.Time
+		expectedErr bool
+	}{
+		{value: "1577934245.123456789", expected: time.Unix(1577934245, 123456789)},
+		{value: "1577934245.5", expected: time.Unix(1577934245, 500000000)},
+		{value: "1577934245", expected: time.Unix(1577934245, 0)},
+		{value: "1577934245.1234567891", expected: time.Unix(1577934245, 123456789)},
+		{value: "", expectedErr: true},
+		{value: "2020-01-02T03:04:05Z", expectedErr: true},
+		{value: "1577934245.-1", expectedErr: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.value, func(t *testing.T) {
+			got, err := ParseModTime(tc.value)
+			if tc.expectedErr {
+				assert.Assert(t, err != nil, "expected an error")
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, got.Equal(tc.expected), "expected %v, got %v", tc.expected, got)
+		})
+	}
+}
+
+func TestFormatModTime(t *testing.T) {
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	value := FormatModTime(mtime)
+	assert.Equal(t, value, "1577934245.000000006")
+
+	got, err := ParseModTime(value)
+	assert.NilError(t, err)
+	assert.Assert(t, got.Equal(mtime))
+}
+
+func TestMetadataUserDefined(t *testing.T) {
+	metadata := NewMetadata().
+		SetContentType("text/plain").
+		SetUserDefined("Mtime", "1577934245")
+
+	assert.DeepEqual(t, metadata.UserDefined(), map[string]string{"mtime": "1577934245"})
+}
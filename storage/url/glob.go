@@ -0,0 +1,146 @@
+package url
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxListPrefixes is the maximum number of the prefixes the alternatives of
+// a wildcard url are expanded to. Urls with more alternatives are listed
+// with their common prefix.
+const maxListPrefixes = 100
+
+// globIndex returns the index of the first wildcard of s, i.e. "*", "?" or
+// a group of alternatives such as "{2019,2020}", or -1 if s has none.
+func globIndex(s string) int {
+	loc := strings.IndexAny(s, globCharacters)
+	for i := 0; i < len(s) && (loc < 0 || i < loc); i++ {
+		if s[i] == '{' && alternativesEnd(s[i:]) > 0 {
+			return i
+		}
+	}
+	return loc
+}
+
+// alternativesEnd returns the index of the closing brace of the group of
+// alternatives at the start of s, or -1 if s doesn't start with a group. A
+// group has at least two comma separated alternatives, and groups can not
+// be nested.
+func alternativesEnd(s string) int {
+	if !strings.HasPrefix(s, "{") {
+		return -1
+	}
+	end := strings.IndexAny(s[1:], "{}")
+	if end < 0 || s[end+1] != '}' || !strings.Contains(s[1:end+1], ",") {
+		return -1
+	}
+	return end + 1
+}
+
+// globRegex converts the wildcards of s to a regular expression. "*" and
+// "?" match any characters including "/", "**/" matches any number of
+// directories and "{a,b}" matches either of the alternatives.
+func globRegex(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(s[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case s[i] == '*':
+			b.WriteString(".*?")
+			i++
+		case s[i] == '?':
+			b.WriteString(".")
+			i++
+		case alternativesEnd(s[i:]) > 0:
+			end := i + alternativesEnd(s[i:])
+			alternatives := strings.Split(s[i+1:end], ",")
+			for j, alternative := range alternatives {
+				alternatives[j] = globRegex(alternative)
+			}
+			b.WriteString("(?:" + strings.Join(alternatives, "|") + ")")
+			i = end + 1
+		default:
+			j := i + 1
+			for j < len(s) && !strings.ContainsRune(globCharacters+"{", rune(s[j])) {
+				j++
+			}
+			b.WriteString(regexp.QuoteMeta(s[i:j]))
+			i = j
+		}
+	}
+	return b.String()
+}
+
+// ListPrefixes returns the prefixes to list the objects matching the url
+// with. The alternatives before the first "*" or "?" are expanded, so each
+// of them is listed with a narrower prefix instead of listing their common
+// prefix and filtering the keys. e.g. "logs/{2019,2020}-*/x/*.gz" is listed
+// with "logs/2019-" and "logs/2020-" prefixes.
+func (u *URL) ListPrefixes() []string {
+	prefixes := []string{u.Prefix}
+
+	// the filter starts with the first wildcard.
+	rest := u.filter
+	for {
+		end := alternativesEnd(rest)
+		if end < 0 {
+			break
+		}
+
+		alternatives := strings.Split(rest[1:end], ",")
+		if len(prefixes)*len(alternatives) > maxListPrefixes {
+			return []string{u.Prefix}
+		}
+
+		// the literal part up to the next wildcard.
+		rest = rest[end+1:]
+		literal := rest
+		if loc := globIndex(rest); loc >= 0 {
+			literal = rest[:loc]
+		}
+		rest = rest[len(literal):]
+
+		var (
+			expanded []string
+			stop     bool
+		)
+		for _, prefix := range prefixes {
+			for _, alternative := range alternatives {
+				// alternatives with wildcards are expanded up to the
+				// wildcard.
+				if loc := globIndex(alternative); loc >= 0 {
+					expanded = append(expanded, prefix+alternative[:loc])
+					stop = true
+					continue
+				}
+				expanded = append(expanded, prefix+alternative+literal)
+			}
+		}
+		prefixes = expanded
+		if stop {
+			break
+		}
+	}
+	return coverPrefixes(prefixes)
+}
+
+// coverPrefixes removes the duplicate prefixes and the ones which start with
+// another prefix, since their keys are listed with the shorter one.
+func coverPrefixes(prefixes []string) []string {
+	sort.Strings(prefixes)
+
+	var covered []string
+	for _, prefix := range prefixes {
+		if n := len(covered); n > 0 && strings.HasPrefix(prefix, covered[n-1]) {
+			continue
+		}
+		covered = append(covered, prefix)
+	}
+	return covered
+}
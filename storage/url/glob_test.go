@@ -0,0 +1,87 @@
+package url
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGlobRegex(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "literal", input: "a/b.txt", want: `a/b\.txt`},
+		{name: "star", input: "*/c/*.tsv", want: `.*?/c/.*?\.tsv`},
+		{name: "question_mark", input: "a?c", want: `a.c`},
+		{name: "double_star", input: "**/errors/**", want: `(?:.*/)?errors/.*`},
+		{name: "alternatives", input: "{2019,2020}-*", want: `(?:2019|2020)-.*?`},
+		{name: "alternatives_with_wildcards", input: "{a*,b.txt}", want: `(?:a.*?|b\.txt)`},
+		{name: "not_alternatives", input: "{a}/{b", want: `\{a\}/\{b`},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if got := globRegex(tc.input); got != tc.want {
+				t.Errorf("globRegex(%q) = %q, want %q", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestListPrefixes(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{
+			name: "no_wildcard",
+			url:  "s3://bucket/logs/2020/",
+			want: []string{"logs/2020/"},
+		},
+		{
+			name: "star",
+			url:  "s3://bucket/logs/2020-*/x/*.gz",
+			want: []string{"logs/2020-"},
+		},
+		{
+			name: "alternatives",
+			url:  "s3://bucket/logs/{2019,2020}-*/x/*.gz",
+			want: []string{"logs/2019-", "logs/2020-"},
+		},
+		{
+			name: "alternatives_with_literal_suffix",
+			url:  "s3://bucket/logs/{a,b}/x/*.gz",
+			want: []string{"logs/a/x/", "logs/b/x/"},
+		},
+		{
+			name: "multiple_alternatives",
+			url:  "s3://bucket/{a,b}/{c,d}/*",
+			want: []string{"a/c/", "a/d/", "b/c/", "b/d/"},
+		},
+		{
+			name: "alternative_with_wildcard",
+			url:  "s3://bucket/{a*,b}/x/{c,d}",
+			want: []string{"a", "b/x/"},
+		},
+		{
+			name: "covered_alternatives",
+			url:  "s3://bucket/{a,ab}*",
+			want: []string{"a"},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := New(tc.url)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if got := u.ListPrefixes(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ListPrefixes() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
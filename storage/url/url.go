@@ -30,6 +30,7 @@ type urlType int
 const (
 	remoteObject urlType = iota
 	localObject
+	httpObject
 )
 
 // URL is the canonical representation of an object, either on local or remote
@@ -45,6 +46,9 @@ type URL struct {
 	relativePath string
 	filter       string
 	filterRegex  *regexp.Regexp
+
+	// raw is the original address of HTTP URLs, including the query.
+	raw string
 }
 
 // New creates a new URL from given path string.
@@ -106,6 +110,42 @@ func New(s string) (*URL, error) {
 	return url, nil
 }
 
+// HasHTTPScheme reports whether the given string is an HTTP or HTTPS address.
+func HasHTTPScheme(s string) bool {
+	s = strings.ToLower(s)
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// NewHTTP creates a new URL from an HTTP or HTTPS address. HTTP URLs have no
+// wildcard semantics and can only be read from.
+func NewHTTP(s string) (*URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("http url should start with %q or %q", "http://", "https://")
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("http url should have a host")
+	}
+
+	return &URL{
+		Type:   httpObject,
+		Scheme: u.Scheme,
+		Bucket: u.Host,
+		Path:   strings.TrimPrefix(u.Path, s3Separator),
+		raw:    s,
+	}, nil
+}
+
+// IsHTTP reports whether the object is served over HTTP(S).
+func (u *URL) IsHTTP() bool {
+	return u.Type == httpObject
+}
+
 // IsRemote reports whether the object is stored on a remote storage system.
 func (u *URL) IsRemote() bool {
 	return u.Type == remoteObject
@@ -124,6 +164,10 @@ func (u *URL) IsBucket() bool {
 
 // Absolute returns the absolute URL format of the object.
 func (u *URL) Absolute() string {
+	if u.IsHTTP() {
+		return u.raw
+	}
+
 	if !u.IsRemote() {
 		return u.Path
 	}
@@ -142,7 +186,7 @@ func (u *URL) Relative() string {
 // Base returns the last element of object path.
 func (u *URL) Base() string {
 	basefn := filepath.Base
-	if u.IsRemote() {
+	if u.IsRemote() || u.IsHTTP() {
 		basefn = path.Base
 	}
 
@@ -153,7 +197,7 @@ func (u *URL) Base() string {
 // directory.
 func (u *URL) Dir() string {
 	basefn := filepath.Dir
-	if u.IsRemote() {
+	if u.IsRemote() || u.IsHTTP() {
 		basefn = path.Dir
 	}
 
@@ -202,6 +246,15 @@ func (u *URL) remoteURL() string {
 //		regex: ^a/b/test./c/.*?\\.tsv$
 //		delimiter: ""
 //
+// "**/" matches any number of directories, including none. The prefix still
+// ends at the first wildcard, so the listing is limited as far as possible.
+//
+// Example:
+//		key: logs/**/errors/*.gz
+//		prefix: logs/
+//		filter: **/errors/*.gz
+//		regex: ^logs/(?:.*/)?errors/.*?\\.gz$
+//
 // It prepares delimiter, prefix and regex for regular strings.
 // These are used in S3 listing operations.
 // See: https://docs.aws.amazon.com/AmazonS3/latest/dev/ListingKeysHierarchy.html
@@ -214,7 +267,7 @@ func (u *URL) remoteURL() string {
 //		delimiter: "/"
 //
 func (u *URL) setPrefixAndFilter() error {
-	loc := strings.IndexAny(u.Path, globCharacters)
+	loc := globIndex(u.Path)
 	wildOperation := loc > -1
 	if !wildOperation {
 		u.Delimiter = s3Separator
@@ -226,9 +279,7 @@ func (u *URL) setPrefixAndFilter() error {
 
 	filterRegex := matchAllRe
 	if u.filter != "" {
-		filterRegex = regexp.QuoteMeta(u.filter)
-		filterRegex = strings.Replace(filterRegex, "\\?", ".", -1)
-		filterRegex = strings.Replace(filterRegex, "\\*", ".*?", -1)
+		filterRegex = globRegex(u.filter)
 	}
 	filterRegex = regexp.QuoteMeta(u.Prefix) + filterRegex
 	r, err := regexp.Compile("^" + filterRegex + "$")
@@ -252,6 +303,7 @@ func (u *URL) Clone() *URL {
 		relativePath: u.relativePath,
 		filter:       u.filter,
 		filterRegex:  u.filterRegex,
+		raw:          u.raw,
 	}
 }
 
@@ -291,6 +343,9 @@ func (u *URL) MarshalJSON() ([]byte, error) {
 
 // HasGlob reports whether if a string contains any wildcard chars.
 func (u *URL) HasGlob() bool {
+	if u.IsHTTP() {
+		return false
+	}
 	return hasGlobCharacter(u.Path)
 }
 
@@ -346,7 +401,7 @@ func parseNonBatch(prefix string, key string) string {
 
 // hasGlobCharacter reports whether if a string contains any wildcard chars.
 func hasGlobCharacter(s string) bool {
-	return strings.ContainsAny(s, globCharacters)
+	return globIndex(s) >= 0
 }
 
 func (u *URL) EscapedPath() string {
@@ -25,6 +25,16 @@ func TestHasWild(t *testing.T) {
 			s:    "s3://a/?/c",
 			want: true,
 		},
+		{
+			name: "string_has_alternatives",
+			s:    "s3://a/{b,c}/d",
+			want: true,
+		},
+		{
+			name: "string_has_braces_without_alternatives",
+			s:    "s3://a/{b}/c",
+			want: false,
+		},
 		{
 			name: "string_has_no_wildcard",
 			s:    "s3://a/b/c",
@@ -137,6 +147,19 @@ func TestURLSetPrefixAndFilter(t *testing.T) {
 				filterRegex: regexp.MustCompile("^a/b_c/.*?/de/.*?/test$"),
 			},
 		},
+		{
+			name: "double_star_wild_operation",
+			before: &URL{
+				Path: "logs/**/errors/*.gz",
+			},
+			after: &URL{
+				Path:        "logs/**/errors/*.gz",
+				Prefix:      "logs/",
+				Delimiter:   "",
+				filter:      "**/errors/*.gz",
+				filterRegex: regexp.MustCompile(`^logs/(?:.*/)?errors/.*?\.gz$`),
+			},
+		},
 		{
 			name: "not_wild_operation",
 			before: &URL{
@@ -245,6 +268,37 @@ func TestCheckMatch(t *testing.T) {
 				"prefix/dummy/a":          {},
 			},
 		},
+		{
+			name: "match_if_double_star_matches_any_number_of_directories",
+			url:  "s3://bucket/logs/**/errors/*.gz",
+			keys: map[string]matchResult{
+				"logs/errors/a.gz":            {true, "errors/a.gz"},
+				"logs/2020/errors/b.gz":       {true, "2020/errors/b.gz"},
+				"logs/2020/03/18/errors/c.gz": {true, "2020/03/18/errors/c.gz"},
+				"logs/2020/myerrors/d.gz":     {},
+				"logs/2020/errors.gz":         {},
+				"other/errors/e.gz":           {},
+			},
+		},
+		{
+			name: "match_if_double_star_is_at_the_end",
+			url:  "s3://bucket/logs/2020/**",
+			keys: map[string]matchResult{
+				"logs/2020/a.gz":    {true, "a.gz"},
+				"logs/2020/03/b.gz": {true, "03/b.gz"},
+				"logs/2021/03/c.gz": {},
+			},
+		},
+		{
+			name: "match_if_key_matches_one_of_the_alternatives",
+			url:  "s3://bucket/logs/{2019,2020}-*/x/*.gz",
+			keys: map[string]matchResult{
+				"logs/2019-01/x/a.gz": {true, "2019-01/x/a.gz"},
+				"logs/2020-02/x/b.gz": {true, "2020-02/x/b.gz"},
+				"logs/2021-01/x/c.gz": {},
+				"logs/2019-01/y/d.gz": {},
+			},
+		},
 		{
 			name: "not_match_if_single_wildcard_does_not_match_with_key",
 			url:  "s3://bucket/*.tsv",
@@ -402,3 +456,51 @@ func TestURLIsBucket(t *testing.T) {
 		}
 	}
 }
+
+func TestNewHTTP(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantBase     string
+		wantAbsolute string
+		wantError    bool
+	}{
+		{
+			input:        "https://example.com/dir/file.csv",
+			wantBase:     "file.csv",
+			wantAbsolute: "https://example.com/dir/file.csv",
+		},
+		{
+			input:        "http://example.com:8080/file?*=1&b=2",
+			wantBase:     "file",
+			wantAbsolute: "http://example.com:8080/file?*=1&b=2",
+		},
+		{input: "https:///file", wantError: true},
+		{input: "ftp://example.com/file", wantError: true},
+	}
+	for _, tc := range tests {
+		url, err := NewHTTP(tc.input)
+		if tc.wantError {
+			if err == nil {
+				t.Errorf("expecting error for input %s", tc.input)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("unexpected error: %v for input %s", err, tc.input)
+			continue
+		}
+
+		if !url.IsHTTP() || url.IsRemote() || url.HasGlob() {
+			t.Errorf("unexpected url type for %s", tc.input)
+		}
+
+		if got := url.Base(); got != tc.wantBase {
+			t.Errorf("Base() = %v, want %v", got, tc.wantBase)
+		}
+
+		if got := url.Absolute(); got != tc.wantAbsolute {
+			t.Errorf("Absolute() = %v, want %v", got, tc.wantAbsolute)
+		}
+	}
+}
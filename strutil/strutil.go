@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 var humanDivisors = [...]struct {
@@ -35,6 +36,53 @@ func HumanizeBytes(b int64) string {
 	return fmt.Sprintf("%.1f%s", float64(b)/float64(div), suffix)
 }
 
+// ParseBytes parses a human-readable byte-size such as "4K" or "1.5M" and
+// returns the number of bytes it represents. Suffixes are in powers of 1024
+// and an optional trailing "B" or "iB" is accepted.
+func ParseBytes(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	str = strings.TrimSuffix(str, "IB")
+	if len(str) > 1 {
+		str = strings.TrimSuffix(str, "B")
+	}
+
+	mul := int64(1)
+	for _, f := range humanDivisors {
+		if strings.HasSuffix(str, f.suffix) {
+			str = strings.TrimSuffix(str, f.suffix)
+			mul = f.div
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+	return int64(n * float64(mul)), nil
+}
+
+// ParseCount parses a count such as "100", "10K" or "1.5M" and returns the
+// number it represents. Suffixes are in powers of 1000.
+func ParseCount(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+
+	mul := int64(1)
+	for suffix, m := range map[string]int64{"K": 1e3, "M": 1e6, "G": 1e9} {
+		if strings.HasSuffix(str, suffix) {
+			str = strings.TrimSuffix(str, suffix)
+			mul = m
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(str, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %q", s)
+	}
+	return int64(n * float64(mul)), nil
+}
+
 // JSON is a helper function for creating JSON-encoded strings.
 func JSON(v interface{}) string {
 	bytes, _ := json.Marshal(v)
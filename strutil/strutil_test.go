@@ -0,0 +1,78 @@
+package strutil
+
+import "testing"
+
+func TestParseBytes(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "0", expected: 0},
+		{input: "512", expected: 512},
+		{input: "512B", expected: 512},
+		{input: "4K", expected: 4 << 10},
+		{input: "4kb", expected: 4 << 10},
+		{input: "1.5M", expected: 3 << 19},
+		{input: "2MiB", expected: 2 << 20},
+		{input: "1G", expected: 1 << 30},
+		{input: "1T", expected: 1 << 40},
+		{input: "", wantErr: true},
+		{input: "K", wantErr: true},
+		{input: "-1K", wantErr: true},
+		{input: "10X", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		got, err := ParseBytes(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseBytes(%q): expected error, got %v", tc.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseBytes(%q): unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("ParseBytes(%q) = %v, expected %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	testcases := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "0", expected: 0},
+		{input: "100", expected: 100},
+		{input: "10K", expected: 10000},
+		{input: "1.5m", expected: 1500000},
+		{input: "1M", expected: 1000000},
+		{input: "2G", expected: 2000000000},
+		{input: "", wantErr: true},
+		{input: "M", wantErr: true},
+		{input: "-1K", wantErr: true},
+		{input: "1KB", wantErr: true},
+	}
+
+	for _, tc := range testcases {
+		got, err := ParseCount(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseCount(%q): expected error, got %v", tc.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseCount(%q): unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got != tc.expected {
+			t.Errorf("ParseCount(%q) = %v, expected %v", tc.input, got, tc.expected)
+		}
+	}
+}